@@ -158,6 +158,9 @@ func main() {
 					r.Post("/new", compositionHandler.CreateComposition)
 					r.Get("/{compositionId}", compositionHandler.ViewComposition)
 					r.Get("/{compositionId}/status", compositionHandler.GetCompositionStatus)
+					r.Get("/{compositionId}/gcode", compositionHandler.DownloadGcodeFromStep)
+					r.Get("/{compositionId}/calibration", compositionHandler.DownloadCalibrationGcode)
+					r.Post("/{compositionId}/cancel", compositionHandler.CancelComposition)
 					r.Delete("/{compositionId}", compositionHandler.DeleteComposition)
 				})
 			})
@@ -81,6 +81,7 @@ func (h *CompositionHandler) NewCompositionForm(w http.ResponseWriter, r *http.R
 		BrightnessFactor:  128,
 		ImageContrast:     1.2,
 		PhysicalRadius:    200.0,
+		GcodeDialect:      pb.GcodeDialect_GCODE_DIALECT_FLUIDNC,
 		Errors:            make(map[string][]string),
 		Success:           false,
 	}
@@ -107,6 +108,7 @@ func (h *CompositionHandler) NewCompositionForm(w http.ResponseWriter, r *http.R
 			formData.BrightnessFactor = sourceComposition.GetBrightnessFactor()
 			formData.ImageContrast = sourceComposition.GetImageContrast()
 			formData.PhysicalRadius = sourceComposition.GetPhysicalRadius()
+			formData.GcodeDialect = sourceComposition.GetGcodeDialect()
 		}
 	}
 
@@ -148,6 +150,7 @@ func (h *CompositionHandler) CreateComposition(w http.ResponseWriter, r *http.Re
 	brightnessFactor, _ := strconv.ParseInt(r.FormValue("brightness_factor"), 10, 32)
 	imageContrast, _ := strconv.ParseFloat(r.FormValue("image_contrast"), 32)
 	physicalRadius, _ := strconv.ParseFloat(r.FormValue("physical_radius"), 32)
+	gcodeDialect := pb.GcodeDialect(pb.GcodeDialect_value[r.FormValue("gcode_dialect")])
 
 	// Note: image_contrast comes from slider scaled by 10
 	imageContrast = imageContrast / 10.0
@@ -162,6 +165,7 @@ func (h *CompositionHandler) CreateComposition(w http.ResponseWriter, r *http.Re
 		BrightnessFactor:  int32(brightnessFactor),
 		ImageContrast:     float32(imageContrast),
 		PhysicalRadius:    float32(physicalRadius),
+		GcodeDialect:      gcodeDialect,
 		Errors:            make(map[string][]string),
 		Success:           false,
 	}
@@ -212,6 +216,7 @@ func (h *CompositionHandler) CreateComposition(w http.ResponseWriter, r *http.Re
 			BrightnessFactor:  formData.BrightnessFactor,
 			ImageContrast:     formData.ImageContrast,
 			PhysicalRadius:    formData.PhysicalRadius,
+			GcodeDialect:      formData.GcodeDialect,
 		},
 	}
 
@@ -383,6 +388,67 @@ func (h *CompositionHandler) GetCompositionStatus(w http.ResponseWriter, r *http
 	}
 }
 
+// CancelComposition handles cancelling a pending or processing composition and
+// renders the detail page with its new status
+func (h *CompositionHandler) CancelComposition(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, _ := middleware.UserFromContext(r.Context())
+
+	// Extract IDs from URL
+	artID := chi.URLParam(r, "artId")
+	compositionID := chi.URLParam(r, "compositionId")
+	if artID == "" || compositionID == "" {
+		http.Error(w, "Invalid IDs", http.StatusBadRequest)
+		return
+	}
+
+	// Get internal user ID
+	currentUser, err := h.generatorService.GetCurrentUser(r.Context(), r)
+	if err != nil {
+		log.Error().Err(err).Str("firebase_uid", user.ID).Msg("Failed to get current user for CancelComposition")
+		http.Error(w, "Failed to get user information", http.StatusInternalServerError)
+		return
+	}
+
+	// Parse the user resource name to extract internal user ID
+	userResource, err := resource.ParseResourceName(currentUser.ID)
+	if err != nil {
+		log.Error().Err(err).Str("user_resource_name", currentUser.ID).Msg("Failed to parse user resource name")
+		http.Error(w, "Invalid user resource", http.StatusInternalServerError)
+		return
+	}
+
+	internalUserID := userResource.(*resource.User).ID
+
+	art, err := h.generatorService.GetArt(r.Context(), internalUserID, artID)
+	if err != nil {
+		log.Error().Err(err).Str("internal_user_id", internalUserID).Str("art_id", artID).Msg("Failed to get art for cancel")
+		http.Error(w, "Art not found", http.StatusNotFound)
+		return
+	}
+
+	// Cancel the composition
+	compositionResourceName := resource.BuildCompositionResourceName(internalUserID, artID, compositionID)
+	composition, err := h.generatorService.CancelComposition(r.Context(), compositionResourceName)
+	if err != nil {
+		log.Error().Err(err).
+			Str("internal_user_id", internalUserID).
+			Str("art_id", artID).
+			Str("composition_id", compositionID).
+			Msg("Failed to cancel composition")
+		http.Error(w, "Failed to cancel composition", http.StatusInternalServerError)
+		return
+	}
+
+	// Render the entire composition detail page for HTMX to swap
+	pageData := templates.NewPageDataFromRequest(r, fmt.Sprintf("Composition - %s - ThreadArt", art.GetTitle()), "composition")
+	err = templates.CompositionDetailPage(pageData, art, composition).Render(r.Context(), w)
+	if err != nil {
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render cancelled composition")
+	}
+}
+
 // DeleteComposition handles deleting a composition
 func (h *CompositionHandler) DeleteComposition(w http.ResponseWriter, r *http.Request) {
 	// Get user from context
@@ -432,4 +498,113 @@ func (h *CompositionHandler) DeleteComposition(w http.ResponseWriter, r *http.Re
 
 	// Return success - the HTMX target will remove the element
 	w.WriteHeader(http.StatusOK)
-}
\ No newline at end of file
+}
+
+// DownloadGcodeFromStep serves the stringing G-code resuming at the step given in the query string
+func (h *CompositionHandler) DownloadGcodeFromStep(w http.ResponseWriter, r *http.Request) {
+	artID := chi.URLParam(r, "artId")
+	compositionID := chi.URLParam(r, "compositionId")
+	if artID == "" || compositionID == "" {
+		http.Error(w, "Invalid composition", http.StatusBadRequest)
+		return
+	}
+
+	step, err := strconv.Atoi(r.URL.Query().Get("step"))
+	if err != nil || step < 0 {
+		http.Error(w, "Invalid step", http.StatusBadRequest)
+		return
+	}
+
+	// Get user from context
+	user, _ := middleware.UserFromContext(r.Context())
+
+	// Get internal user ID
+	currentUser, err := h.generatorService.GetCurrentUser(r.Context(), r)
+	if err != nil {
+		log.Error().Err(err).Str("firebase_uid", user.ID).Msg("Failed to get current user for DownloadGcodeFromStep")
+		http.Error(w, "Failed to get user information", http.StatusInternalServerError)
+		return
+	}
+
+	// Parse the user resource name to extract internal user ID
+	userResource, err := resource.ParseResourceName(currentUser.ID)
+	if err != nil {
+		log.Error().Err(err).Str("user_resource_name", currentUser.ID).Msg("Failed to parse user resource name")
+		http.Error(w, "Invalid user resource", http.StatusInternalServerError)
+		return
+	}
+
+	internalUserID := userResource.(*resource.User).ID
+
+	response, err := h.generatorService.GetCompositionGcodeFromStep(r.Context(), internalUserID, artID, compositionID, step)
+	if err != nil {
+		log.Error().Err(err).
+			Str("internal_user_id", internalUserID).
+			Str("composition_id", compositionID).
+			Int("step", step).
+			Msg("Failed to get gcode from step")
+		http.Error(w, "Failed to generate G-code from this step", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"gcode-from-step-%d.txt\"", step))
+	if _, err := w.Write([]byte(response.GetGcode())); err != nil {
+		log.Error().Err(err).Msg("Failed to write gcode response")
+	}
+}
+
+// DownloadCalibrationGcode serves the calibration program given in the query string
+func (h *CompositionHandler) DownloadCalibrationGcode(w http.ResponseWriter, r *http.Request) {
+	artID := chi.URLParam(r, "artId")
+	compositionID := chi.URLParam(r, "compositionId")
+	if artID == "" || compositionID == "" {
+		http.Error(w, "Invalid composition", http.StatusBadRequest)
+		return
+	}
+
+	routineName := r.URL.Query().Get("routine")
+	routine, ok := pb.CalibrationRoutine_value["CALIBRATION_ROUTINE_"+strings.ToUpper(routineName)]
+	if !ok || routine == int32(pb.CalibrationRoutine_CALIBRATION_ROUTINE_UNSPECIFIED) {
+		http.Error(w, "Invalid calibration routine", http.StatusBadRequest)
+		return
+	}
+
+	// Get user from context
+	user, _ := middleware.UserFromContext(r.Context())
+
+	// Get internal user ID
+	currentUser, err := h.generatorService.GetCurrentUser(r.Context(), r)
+	if err != nil {
+		log.Error().Err(err).Str("firebase_uid", user.ID).Msg("Failed to get current user for DownloadCalibrationGcode")
+		http.Error(w, "Failed to get user information", http.StatusInternalServerError)
+		return
+	}
+
+	// Parse the user resource name to extract internal user ID
+	userResource, err := resource.ParseResourceName(currentUser.ID)
+	if err != nil {
+		log.Error().Err(err).Str("user_resource_name", currentUser.ID).Msg("Failed to parse user resource name")
+		http.Error(w, "Invalid user resource", http.StatusInternalServerError)
+		return
+	}
+
+	internalUserID := userResource.(*resource.User).ID
+
+	response, err := h.generatorService.GetCompositionCalibrationGcode(r.Context(), internalUserID, artID, compositionID, pb.CalibrationRoutine(routine))
+	if err != nil {
+		log.Error().Err(err).
+			Str("internal_user_id", internalUserID).
+			Str("composition_id", compositionID).
+			Str("routine", routineName).
+			Msg("Failed to get calibration gcode")
+		http.Error(w, "Failed to generate the calibration G-code", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"calibration-%s.txt\"", strings.ToLower(routineName)))
+	if _, err := w.Write([]byte(response.GetGcode())); err != nil {
+		log.Error().Err(err).Msg("Failed to write calibration gcode response")
+	}
+}
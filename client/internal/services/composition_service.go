@@ -89,6 +89,27 @@ func (s *CompositionService) DeleteComposition(ctx context.Context, compositionN
 	return nil
 }
 
+// CancelComposition stops a pending or processing composition
+func (s *CompositionService) CancelComposition(ctx context.Context, compositionName string) (*pb.Composition, error) {
+	req := connect.NewRequest(&pb.CancelCompositionRequest{
+		Name: compositionName,
+	})
+
+	resp, err := s.client.CancelComposition(ctx, req)
+	if err != nil {
+		standardErr := s.parseErrorForLogging(err)
+		log.Error().
+			Err(err).
+			Str("errorType", string(standardErr.Type)).
+			Str("message", standardErr.Message).
+			Str("compositionName", compositionName).
+			Msg("Failed to cancel composition")
+		return nil, fmt.Errorf("failed to cancel composition: %s", standardErr.Message)
+	}
+
+	return resp.Msg, nil
+}
+
 // CreateComposition creates a new composition
 func (s *CompositionService) CreateComposition(ctx context.Context, createRequest *pb.CreateCompositionRequest) (*pb.Composition, map[string][]string, error) {
 	req := connect.NewRequest(createRequest)
@@ -125,3 +146,53 @@ func (s *CompositionService) GetComposition(ctx context.Context, userID, artID,
 
 	return resp.Msg, nil
 }
+
+// GetCompositionGcodeFromStep gets the stringing G-code of a composition resuming at a path index
+func (s *CompositionService) GetCompositionGcodeFromStep(ctx context.Context, userID, artID, compositionID string, step int) (*pb.GetCompositionGcodeFromStepResponse, error) {
+	compositionName := fmt.Sprintf("users/%s/arts/%s/compositions/%s", userID, artID, compositionID)
+
+	req := connect.NewRequest(&pb.GetCompositionGcodeFromStepRequest{
+		Name: compositionName,
+		Step: int32(step),
+	})
+
+	resp, err := s.client.GetCompositionGcodeFromStep(ctx, req)
+	if err != nil {
+		standardErr := s.parseErrorForLogging(err)
+		log.Error().
+			Err(err).
+			Str("errorType", string(standardErr.Type)).
+			Str("message", standardErr.Message).
+			Str("compositionName", compositionName).
+			Int("step", step).
+			Msg("Failed to get composition gcode from step")
+		return nil, fmt.Errorf("failed to get composition gcode from step: %s", standardErr.Message)
+	}
+
+	return resp.Msg, nil
+}
+
+// GetCompositionCalibrationGcode gets a calibration program for the nail count and dialect of a composition
+func (s *CompositionService) GetCompositionCalibrationGcode(ctx context.Context, userID, artID, compositionID string, routine pb.CalibrationRoutine) (*pb.GetCompositionCalibrationGcodeResponse, error) {
+	compositionName := fmt.Sprintf("users/%s/arts/%s/compositions/%s", userID, artID, compositionID)
+
+	req := connect.NewRequest(&pb.GetCompositionCalibrationGcodeRequest{
+		Name:    compositionName,
+		Routine: routine,
+	})
+
+	resp, err := s.client.GetCompositionCalibrationGcode(ctx, req)
+	if err != nil {
+		standardErr := s.parseErrorForLogging(err)
+		log.Error().
+			Err(err).
+			Str("errorType", string(standardErr.Type)).
+			Str("message", standardErr.Message).
+			Str("compositionName", compositionName).
+			Str("routine", routine.String()).
+			Msg("Failed to get composition calibration gcode")
+		return nil, fmt.Errorf("failed to get composition calibration gcode: %s", standardErr.Message)
+	}
+
+	return resp.Msg, nil
+}
@@ -90,6 +90,18 @@ func (s *GeneratorService) GetComposition(ctx context.Context, userID, artID, co
 	return s.CompositionService.GetComposition(ctx, userID, artID, compositionID)
 }
 
+func (s *GeneratorService) GetCompositionGcodeFromStep(ctx context.Context, userID, artID, compositionID string, step int) (*pb.GetCompositionGcodeFromStepResponse, error) {
+	return s.CompositionService.GetCompositionGcodeFromStep(ctx, userID, artID, compositionID, step)
+}
+
+func (s *GeneratorService) GetCompositionCalibrationGcode(ctx context.Context, userID, artID, compositionID string, routine pb.CalibrationRoutine) (*pb.GetCompositionCalibrationGcodeResponse, error) {
+	return s.CompositionService.GetCompositionCalibrationGcode(ctx, userID, artID, compositionID, routine)
+}
+
+func (s *GeneratorService) CancelComposition(ctx context.Context, compositionName string) (*pb.Composition, error) {
+	return s.CompositionService.CancelComposition(ctx, compositionName)
+}
+
 func (s *GeneratorService) DeleteComposition(ctx context.Context, compositionName string) error {
 	return s.CompositionService.DeleteComposition(ctx, compositionName)
 }
@@ -0,0 +1,58 @@
+// Command fluidnc generates a FluidNC config.yaml from a machine profile.
+//
+//	go run ./cmd/fluidnc -profile machine/electronic/MKS_TinyBee_1_profile.yml -out config.yaml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+	"github.com/Damione1/thread-art-generator/threadGenerator/fluidnc"
+)
+
+func main() {
+	defaults := threadGenerator.DefaultConfig()
+
+	profilePath := flag.String("profile", "", "Path of the YAML machine profile")
+	outPath := flag.String("out", "", "Where to write the FluidNC config, stdout when empty")
+	nails := flag.Int("nails", defaults.NailsQuantity, "Nails quantity the generator uses")
+	rotationAxis := flag.String("rotation-axis", defaults.RotationAxis, "Rotation axis letter the generator uses")
+	needleAxis := flag.String("needle-axis", defaults.NeedleAxis, "Needle axis letter the generator uses")
+	spindleAxis := flag.String("spindle-axis", defaults.SpindleAxis, "Spindle axis letter the generator uses")
+	flag.Parse()
+
+	if *profilePath == "" {
+		fmt.Fprintln(os.Stderr, "-profile is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	profile, err := fluidnc.LoadProfile(*profilePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	config := defaults
+	config.NailsQuantity = *nails
+	config.RotationAxis = *rotationAxis
+	config.NeedleAxis = *needleAxis
+	config.SpindleAxis = *spindleAxis
+
+	output, err := fluidnc.Generate(profile, config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *outPath == "" {
+		os.Stdout.Write(output)
+		return
+	}
+	if err := os.WriteFile(*outPath, output, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
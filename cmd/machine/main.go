@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/rs/cors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Damione1/thread-art-generator/core/interceptors"
+	"github.com/Damione1/thread-art-generator/core/machine"
+	"github.com/Damione1/thread-art-generator/core/pb/pbconnect"
+	"github.com/Damione1/thread-art-generator/core/util"
+)
+
+func main() {
+	config, err := util.LoadConfig()
+	if err != nil {
+		log.Fatal().Err(err).Msg("👋 Failed to load config")
+	}
+	if config.Machine.ControllerAddress == "" {
+		log.Fatal().Msg("👋 MACHINE_CONTROLLER_ADDRESS is required")
+	}
+	if config.InternalAPIKey == "" {
+		log.Fatal().Msg("👋 INTERNAL_API_KEY is required")
+	}
+
+	conn, err := machine.Dial(config.Machine.ControllerAddress)
+	if err != nil {
+		log.Fatal().Err(err).Str("address", config.Machine.ControllerAddress).Msg("👋 Failed to connect to the controller")
+	}
+
+	store, err := machine.NewFileJobStore(config.Machine.StateDir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("👋 Failed to open the machine state")
+	}
+
+	controller, err := machine.NewController(conn, store, config.Machine.RxBufferSize)
+	if err != nil {
+		log.Fatal().Err(err).Msg("👋 Failed to create the machine controller")
+	}
+	defer controller.Close()
+	log.Print("🧵 Connected to the controller at " + config.Machine.ControllerAddress)
+
+	runConnectServer(config, controller)
+}
+
+func runConnectServer(config util.Config, controller *machine.Controller) {
+	interceptorChain := connect.WithInterceptors(
+		interceptors.ConnectLogger(),
+		interceptors.APIKeyAuth(config.InternalAPIKey),
+	)
+
+	path, handler := pbconnect.NewMachineServiceHandler(machine.NewConnectHandler(controller), interceptorChain)
+
+	corsHandler := cors.New(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+		AllowedHeaders: []string{
+			"Accept",
+			"Authorization",
+			"Content-Type",
+			"Connect-Protocol-Version",
+		},
+		ExposedHeaders: []string{
+			"Connect-Protocol-Version",
+			"Grpc-Status",
+			"Grpc-Message",
+		},
+		MaxAge: 86400,
+		Debug:  config.Environment == "development",
+	})
+
+	mux := http.NewServeMux()
+
+	// Health check endpoint
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"ok"}`))
+	})
+
+	mux.Handle(path, corsHandler.Handler(handler))
+
+	addr := fmt.Sprintf("0.0.0.0:%s", config.Machine.ServerPort)
+	log.Print("🍩 Starting to listen on " + addr)
+
+	err := http.ListenAndServe(addr, interceptors.HttpLogger(mux))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to start server")
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+	"github.com/Damione1/thread-art-generator/threadGenerator/gcodesim"
+)
+
+// imageExtensions are the formats picked up in batch mode
+var imageExtensions = []string{".png", ".jpg", ".jpeg", ".gif", ".bmp", ".tif", ".tiff", ".svg"}
+
+type (
+	runOptions struct {
+		Config      threadGenerator.Config
+		Verify      bool
+		PathsFormat threadGenerator.PathListFormat
+	}
+
+	// runStats is written to stats.json next to the outputs
+	runStats struct {
+		Image            string                         `json:"image"`
+		ContentHash      string                         `json:"content_hash"`
+		Paths            int                            `json:"paths"`
+		ThreadLength     int                            `json:"thread_length_m"`
+		GenerationTime   string                         `json:"generation_time"`
+		Moves            int                            `json:"moves"`
+		MergedMoves      int                            `json:"merged_moves"`
+		RotaryTurns      float64                        `json:"rotary_turns"`
+		MaxTwistTurns    float64                        `json:"max_twist_turns"`
+		EstimatedRunTime string                         `json:"estimated_run_time"`
+		Segments         []threadGenerator.SpoolSegment `json:"segments"`
+		Analytics        threadGenerator.PathAnalytics  `json:"analytics"`
+		Config           threadGenerator.Config         `json:"config"`
+	}
+)
+
+// generateImage generates one image and writes every output to outputDir
+func generateImage(imagePath, outputDir string, options runOptions) (*runStats, error) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	log.Info().Str("image", imagePath).Msg("Generating thread art")
+	startTime := time.Now()
+
+	generator := threadGenerator.NewThreadGenerator(options.Config)
+	stats, err := generator.Generate(threadGenerator.Args{ImageName: imagePath})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate thread art: %w", err)
+	}
+
+	previewImage, err := generator.GeneratePathsImage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate preview image: %w", err)
+	}
+	previewFile, err := os.Create(filepath.Join(outputDir, "preview.png"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create preview file: %w", err)
+	}
+	defer previewFile.Close()
+	if err := png.Encode(previewFile, previewImage); err != nil {
+		return nil, fmt.Errorf("failed to encode preview image: %w", err)
+	}
+
+	heatmapImage, err := generator.GenerateDensityHeatmap()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate heatmap image: %w", err)
+	}
+	heatmapFile, err := os.Create(filepath.Join(outputDir, "heatmap.png"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create heatmap file: %w", err)
+	}
+	defer heatmapFile.Close()
+	if err := png.Encode(heatmapFile, heatmapImage); err != nil {
+		return nil, fmt.Errorf("failed to encode heatmap image: %w", err)
+	}
+
+	segments, err := generator.GetSpoolSegments()
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan spool segments: %w", err)
+	}
+
+	gcode := generator.GetGcode()
+	if options.Verify {
+		verification, err := gcodesim.Verify(gcode, options.Config.GcodeDialect, gcodesim.MachineFromConfig(options.Config), generator.GetPathsList())
+		if err == nil {
+			err = verification.Err()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("generated gcode failed verification: %w", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "gcode.txt"), []byte(strings.Join(gcode, "\n")), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write gcode file: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "drill_gcode.txt"), []byte(strings.Join(generator.GenerateHolesGcode(), "\n")), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write drill gcode file: %w", err)
+	}
+
+	var pathsDocument bytes.Buffer
+	if err := threadGenerator.WritePathList(&pathsDocument, generator.GetPathListDocument(), options.PathsFormat); err != nil {
+		return nil, fmt.Errorf("failed to encode paths list: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "paths"+options.PathsFormat.Extension()), pathsDocument.Bytes(), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write paths file: %w", err)
+	}
+
+	contentHash, err := generator.ContentHash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash generation inputs: %w", err)
+	}
+
+	motionStats := generator.GetMotionStats()
+	result := &runStats{
+		Image:            imagePath,
+		ContentHash:      contentHash,
+		Paths:            stats.TotalLines,
+		ThreadLength:     stats.ThreadLength,
+		GenerationTime:   time.Since(startTime).Round(time.Millisecond).String(),
+		Moves:            motionStats.Moves,
+		MergedMoves:      motionStats.MergedMoves,
+		RotaryTurns:      motionStats.RotaryTurns,
+		MaxTwistTurns:    motionStats.MaxTwistTurns,
+		EstimatedRunTime: motionStats.EstimatedRunTime.Round(time.Second).String(),
+		Segments:         segments,
+		Analytics:        generator.GetPathAnalytics(),
+		Config:           options.Config,
+	}
+	statsJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stats: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "stats.json"), statsJSON, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write stats file: %w", err)
+	}
+
+	log.Info().
+		Str("image", imagePath).
+		Str("output", outputDir).
+		Int("paths", result.Paths).
+		Int("threadLength", result.ThreadLength).
+		Str("estimatedRunTime", result.EstimatedRunTime).
+		Int("spools", len(segments)).
+		Str("generationTime", result.GenerationTime).
+		Msg("Thread art generated")
+	return result, nil
+}
+
+// generateFolder generates every image of a folder, each in its own output
+// sub directory. A failing image does not stop the batch, the number of
+// failures is returned.
+func generateFolder(inputDir, outputDir string, parallel int, options runOptions) (int, error) {
+	entries, err := os.ReadDir(inputDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read input folder: %w", err)
+	}
+
+	var images []string
+	for _, entry := range entries {
+		if !entry.IsDir() && slices.Contains(imageExtensions, strings.ToLower(filepath.Ext(entry.Name()))) {
+			images = append(images, entry.Name())
+		}
+	}
+	if len(images) == 0 {
+		return 0, fmt.Errorf("no images found in %s", inputDir)
+	}
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+		slots  = make(chan struct{}, parallel)
+	)
+	for _, name := range images {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			imageOutput := filepath.Join(outputDir, strings.TrimSuffix(name, filepath.Ext(name)))
+			if _, err := generateImage(filepath.Join(inputDir, name), imageOutput, options); err != nil {
+				log.Error().Err(err).Str("image", name).Msg("Generation failed")
+				mu.Lock()
+				failed++
+				mu.Unlock()
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	log.Info().Int("images", len(images)).Int("failed", failed).Msg("Batch completed")
+	return failed, nil
+}
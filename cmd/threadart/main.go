@@ -0,0 +1,183 @@
+// Command threadart generates thread art locally, without the database, the
+// queue or the storage the worker needs.
+//
+//	go run ./cmd/threadart -in portrait.jpg -out out/
+//	go run ./cmd/threadart -in photos/ -out out/ -nails 240 -parallel 4
+//	go run ./cmd/threadart -in portrait.jpg -rings 300:609.6,200:400:8
+//	go run ./cmd/threadart -in portrait.jpg -thread-profile 0.85:0.4
+//	go run ./cmd/threadart -in logo.svg -input logo
+//	go run ./cmd/threadart -in portrait.jpg -seed 42
+//	go run ./cmd/threadart -in mandala.svg -input line_drawing -symmetry 6 -mirror
+//
+// For each image it writes preview.png, heatmap.png, gcode.txt,
+// drill_gcode.txt, paths.json (paths.csv or paths.bin with -paths-format) and
+// stats.json, with the nail analytics and the content hash, to the output
+// directory, in a sub directory per image in batch mode.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+)
+
+func main() {
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "15:04:05"})
+
+	defaults := threadGenerator.DefaultConfig()
+	config := defaults
+	var dialect, rings, threadProfile, pathsFormat, inputType string
+
+	input := flag.String("in", "", "Image to process, or a folder of images for batch mode")
+	output := flag.String("out", "out", "Output directory")
+	parallel := flag.Int("parallel", 1, "Images processed at the same time in batch mode")
+	verify := flag.Bool("verify", true, "Replay the G-code on the machine model and fail on mismatches")
+	flag.StringVar(&pathsFormat, "paths-format", string(threadGenerator.PathListJSON), "Paths list format: json, csv or binary")
+
+	flag.IntVar(&config.NailsQuantity, "nails", defaults.NailsQuantity, "Number of nails on the ring")
+	flag.IntVar(&config.ImgSize, "img-size", defaults.ImgSize, "Size of the processed image in pixels")
+	flag.IntVar(&config.MaxPaths, "max-paths", defaults.MaxPaths, "Maximum number of paths to generate")
+	flag.IntVar(&config.StartingNail, "starting-nail", defaults.StartingNail, "Index of the first nail")
+	flag.IntVar(&config.MinimumDifference, "min-difference", defaults.MinimumDifference, "Minimum number of nails between both ends of a path")
+	flag.IntVar(&config.BrightnessFactor, "brightness", defaults.BrightnessFactor, "Brightness removed from the image by each path")
+	flag.Float64Var(&config.ImageContrast, "contrast", defaults.ImageContrast, "Contrast adjustment of the image")
+	flag.Float64Var(&config.PhysicalRadius, "radius", defaults.PhysicalRadius, "Physical radius of the ring in mm")
+	flag.StringVar(&config.RotationAxis, "rotation-axis", defaults.RotationAxis, "Rotation axis letter")
+	flag.StringVar(&config.NeedleAxis, "needle-axis", defaults.NeedleAxis, "Needle axis letter")
+	flag.StringVar(&config.SpindleAxis, "spindle-axis", defaults.SpindleAxis, "Spindle axis letter")
+	flag.StringVar(&dialect, "dialect", string(defaults.GcodeDialect), "G-code dialect: fluidnc, grbl, marlin or marlin-ijk for Marlin 2.0.x")
+	flag.Float64Var(&config.MaxTwistTurns, "max-twist", defaults.MaxTwistTurns, "Turns the ring may accumulate in one direction, 0 for no limit")
+	flag.Float64Var(&config.SpoolLength, "spool", defaults.SpoolLength, "Thread on one spool in meters, 0 for unlimited")
+	flag.StringVar(&rings, "rings", "", "Concentric rings as nails:radius[:min-difference], comma separated, replacing -nails")
+	flag.StringVar(&config.RingAxis, "ring-axis", defaults.RingAxis, "Ring axis letter of multi ring layouts")
+	flag.BoolVar(&config.LinearLight, "linear", defaults.LinearLight, "Model the thread darkening in linear light instead of sRGB gray levels")
+	flag.Int64Var(&config.Seed, "seed", defaults.Seed, "Seed drawing among lines of equal weight, 0 to pick the lowest nail")
+	flag.IntVar(&config.SymmetryFold, "symmetry", defaults.SymmetryFold, "Rotational symmetry order, the nails of every ring must split in as many sectors, 0 for none")
+	flag.BoolVar(&config.MirrorSymmetry, "mirror", defaults.MirrorSymmetry, "Mirror the piece across its vertical axis")
+	flag.StringVar(&inputType, "input", string(threadGenerator.InputPhoto), "Input type: photo, logo (outlines of filled shapes) or line_drawing (center lines of strokes)")
+	flag.StringVar(&threadProfile, "thread-profile", "", "Calibrated thread as opacity:width-mm, e.g. 0.85:0.4, replacing -brightness")
+	flag.Parse()
+
+	if *input == "" {
+		fmt.Fprintln(os.Stderr, "-in is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var err error
+	config.GcodeDialect, err = threadGenerator.ParseGcodeDialect(dialect)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if rings != "" {
+		config.Rings, err = parseRings(rings)
+		if err == nil {
+			err = config.ValidateRings()
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+
+	if err := config.ValidateSymmetry(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	config.InputType, err = threadGenerator.ParseInputType(inputType)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	format, err := threadGenerator.ParsePathListFormat(pathsFormat)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if threadProfile != "" {
+		config.ThreadProfile, err = parseThreadProfile(threadProfile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+
+	info, err := os.Stat(*input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	options := runOptions{Config: config, Verify: *verify, PathsFormat: format}
+	if !info.IsDir() {
+		if _, err := generateImage(*input, *output, options); err != nil {
+			log.Fatal().Err(err).Str("image", *input).Msg("Generation failed")
+		}
+		return
+	}
+
+	failed, err := generateFolder(*input, *output, *parallel, options)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Batch failed")
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// parseRings parses the -rings flag, e.g. 300:609.6,200:400:8
+func parseRings(spec string) ([]threadGenerator.Ring, error) {
+	var rings []threadGenerator.Ring
+	for _, part := range strings.Split(spec, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ":")
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("ring %q must be nails:radius or nails:radius:min-difference", part)
+		}
+		var (
+			ring threadGenerator.Ring
+			err  error
+		)
+		if ring.NailsQuantity, err = strconv.Atoi(fields[0]); err != nil {
+			return nil, fmt.Errorf("ring %q has invalid nails: %w", part, err)
+		}
+		if ring.Radius, err = strconv.ParseFloat(fields[1], 64); err != nil {
+			return nil, fmt.Errorf("ring %q has invalid radius: %w", part, err)
+		}
+		if len(fields) == 3 {
+			if ring.MinimumDifference, err = strconv.Atoi(fields[2]); err != nil {
+				return nil, fmt.Errorf("ring %q has invalid minimum difference: %w", part, err)
+			}
+		}
+		rings = append(rings, ring)
+	}
+	return rings, nil
+}
+
+// parseThreadProfile parses the -thread-profile flag, e.g. 0.85:0.4
+func parseThreadProfile(spec string) (*threadGenerator.ThreadProfile, error) {
+	fields := strings.Split(spec, ":")
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("thread profile %q must be opacity:width", spec)
+	}
+	opacity, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || opacity <= 0 || opacity > 1 {
+		return nil, fmt.Errorf("thread profile %q must have an opacity between 0 and 1", spec)
+	}
+	width, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || width <= 0 {
+		return nil, fmt.Errorf("thread profile %q must have a positive width", spec)
+	}
+	return &threadGenerator.ThreadProfile{Opacity: opacity, Width: width}, nil
+}
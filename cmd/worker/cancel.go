@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	"github.com/Damione1/thread-art-generator/core/storage"
+)
+
+// cancellationPollInterval is the time between two checks of the status of a
+// composition being processed, each check is also a heartbeat of its worker
+var cancellationPollInterval = time.Second
+
+// errCompositionCancelled stops the processing of a composition its author
+// cancelled, the message is acked without a retry
+var errCompositionCancelled = errors.New("composition cancelled")
+
+// cancellationWatcher polls the status of a composition while it is processed
+// and cancels the processing context once the composition is cancelled or
+// deleted. Every poll bumps the updated_at of the composition, a heartbeat
+// telling the claims of other workers the composition is still processed.
+type cancellationWatcher struct {
+	cancel    context.CancelFunc
+	cancelled atomic.Bool
+	done      chan struct{}
+}
+
+// watchCancellation starts watching a composition, the returned context is
+// done once it is cancelled. The caller stops the watcher when the processing
+// ends.
+func watchCancellation(ctx context.Context, db *sql.DB, compositionID string) (context.Context, *cancellationWatcher) {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &cancellationWatcher{cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(cancellationPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			status, err := heartbeat(ctx, db, compositionID)
+			switch {
+			case errors.Is(err, sql.ErrNoRows) || err == nil && status == models.CompositionStatusEnumCANCELLED:
+				log.Info().Str("compositionID", compositionID).Msg("Composition cancelled, stopping its processing")
+				w.cancelled.Store(true)
+				cancel()
+				return
+			case err != nil && ctx.Err() == nil:
+				// A missed check only delays the cancellation
+				log.Warn().Err(err).Str("compositionID", compositionID).Msg("Failed to check composition status")
+			}
+		}
+	}()
+	return ctx, w
+}
+
+// heartbeat bumps the updated_at of a composition and returns its status
+func heartbeat(ctx context.Context, db *sql.DB, compositionID string) (models.CompositionStatusEnum, error) {
+	var status models.CompositionStatusEnum
+	err := db.QueryRowContext(ctx,
+		"UPDATE compositions SET updated_at = now() WHERE id = $1 RETURNING status",
+		compositionID,
+	).Scan(&status)
+	return status, err
+}
+
+// stop ends the watch and releases its context
+func (w *cancellationWatcher) stop() {
+	w.cancel()
+	<-w.done
+}
+
+// isCancelled reports whether the composition was cancelled during the watch
+func (w *cancellationWatcher) isCancelled() bool {
+	return w.cancelled.Load()
+}
+
+// removeArtifacts deletes the files of a cancelled composition. Every key is
+// removed whether or not this attempt reached its upload, an earlier attempt
+// may have uploaded some of them.
+func removeArtifacts(ctx context.Context, dualStorage *storage.DualBucketStorage, keys []string) {
+	for _, key := range keys {
+		if err := dualStorage.GetPublicStorage().Delete(ctx, key); err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("Failed to delete file of cancelled composition")
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	"github.com/Damione1/thread-art-generator/core/queue"
+	"github.com/Damione1/thread-art-generator/core/storage"
+	"github.com/Damione1/thread-art-generator/core/util"
+)
+
+const heartbeatQuery = `UPDATE compositions SET updated_at = now\(\) WHERE id = \$1 RETURNING status`
+
+// fastPolls shortens the poll interval of the cancellation watcher for a test
+func fastPolls(t *testing.T) {
+	interval := cancellationPollInterval
+	cancellationPollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { cancellationPollInterval = interval })
+}
+
+func TestWatchCancellation(t *testing.T) {
+	fastPolls(t)
+	tests := []struct {
+		name   string
+		expect func(mock sqlmock.Sqlmock)
+	}{
+		{name: "cancelled", expect: func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(heartbeatQuery).WithArgs("composition").
+				WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.CompositionStatusEnumCANCELLED))
+		}},
+		{name: "deleted", expect: func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(heartbeatQuery).WithArgs("composition").WillReturnError(sql.ErrNoRows)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			// The composition is still processing on the first beats, a failed
+			// check only delays the cancellation
+			mock.ExpectQuery(heartbeatQuery).WithArgs("composition").
+				WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.CompositionStatusEnumPROCESSING))
+			mock.ExpectQuery(heartbeatQuery).WithArgs("composition").WillReturnError(errors.New("connection reset"))
+			tt.expect(mock)
+
+			ctx, watcher := watchCancellation(context.Background(), db, "composition")
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+				t.Fatal("the processing context was not cancelled")
+			}
+			require.True(t, watcher.isCancelled())
+			watcher.stop()
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWatchCancellationStopped(t *testing.T) {
+	fastPolls(t)
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(heartbeatQuery).WithArgs("composition").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.CompositionStatusEnumPROCESSING))
+
+	// The processing ended, stopping the watcher is not a cancellation
+	ctx, watcher := watchCancellation(context.Background(), db, "composition")
+	require.Eventually(t, func() bool { return mock.ExpectationsWereMet() == nil }, time.Second, time.Millisecond)
+	watcher.stop()
+	require.Error(t, ctx.Err())
+	require.False(t, watcher.isCancelled())
+}
+
+func TestHeartbeat(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(heartbeatQuery).WithArgs("composition").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.CompositionStatusEnumPROCESSING))
+
+	status, err := heartbeat(context.Background(), db, "composition")
+	require.NoError(t, err)
+	require.Equal(t, models.CompositionStatusEnumPROCESSING, status)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// uploadFiles stores placeholder files under the keys of the public bucket
+func uploadFiles(t *testing.T, dualStorage *storage.DualBucketStorage, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		require.NoError(t, dualStorage.UploadPublic(context.Background(), key, strings.NewReader(key), "application/octet-stream"))
+	}
+}
+
+func TestRemoveArtifacts(t *testing.T) {
+	dualStorage := storage.NewMemoryDualBucketStorage()
+	uploadFiles(t, dualStorage, "compositions/1/preview.png", "compositions/1/gcode.txt", "compositions/2/preview.png")
+
+	// The files an attempt never uploaded are skipped
+	removeArtifacts(context.Background(), dualStorage, []string{"compositions/1/preview.png", "compositions/1/gcode.txt", "compositions/1/paths.json"})
+
+	for key, kept := range map[string]bool{
+		"compositions/1/preview.png": false,
+		"compositions/1/gcode.txt":   false,
+		"compositions/2/preview.png": true,
+	} {
+		exists, err := dualStorage.GetPublicStorage().Exists(context.Background(), key)
+		require.NoError(t, err)
+		require.Equal(t, kept, exists, key)
+	}
+}
+
+func TestProcessMessageRemovesCancelledArtifacts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	// An earlier attempt uploaded the files before the author cancelled
+	dualStorage := storage.NewMemoryDualBucketStorage()
+	prefix := "users/author/arts/art/compositions/composition/"
+	artifacts := []string{"preview.png", "gcode.txt", "paths.json", "drill_gcode.txt", "heatmap.png"}
+	for _, artifact := range artifacts {
+		uploadFiles(t, dualStorage, prefix+artifact)
+	}
+	uploadFiles(t, dualStorage, "users/author/arts/image")
+
+	body, err := queue.NewCompositionProcessingMessage("art", "composition").ToJSON()
+	require.NoError(t, err)
+	mock.ExpectQuery(`SELECT "compositions"\.\* FROM "compositions"`).
+		WithArgs("composition", "art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "art_id", "status", "max_paths"}).
+			AddRow("composition", "art", models.CompositionStatusEnumPENDING, 1000))
+	mock.ExpectQuery(`SELECT "arts"\.\* FROM "arts"`).
+		WithArgs("art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "image_id"}).AddRow("art", "author", "image"))
+	expectClaim(mock, models.CompositionStatusEnumCANCELLED, time.Now(), -1)
+	mock.ExpectRollback()
+
+	claimed, err := processMessage(context.Background(), body, db, dualStorage, util.QueueConfig{MaxJobsPerUser: 2})
+	require.ErrorIs(t, err, errCompositionCancelled)
+	require.False(t, claimed)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// Every file of the composition is removed, the source image is kept
+	for _, artifact := range artifacts {
+		exists, err := dualStorage.GetPublicStorage().Exists(context.Background(), prefix+artifact)
+		require.NoError(t, err)
+		require.False(t, exists, artifact)
+	}
+	exists, err := dualStorage.GetPublicStorage().Exists(context.Background(), "users/author/arts/image")
+	require.NoError(t, err)
+	require.True(t, exists)
+}
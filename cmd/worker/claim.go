@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+)
+
+// heartbeatTimeout is the time after which a processing composition whose
+// worker stopped beating is no longer counted against its user, its worker
+// crashed and its message waits for a redelivery
+const heartbeatTimeout = time.Minute
+
+// errUserAtCapacity puts a job aside while its user already has the most
+// compositions processing at once, the message is deferred without counting
+// an attempt
+var errUserAtCapacity = errors.New("user reached the cap of concurrent jobs")
+
+// errCompositionFinished drops a message for a composition already complete or
+// failed. The outbox publishes a message at least once, so a duplicate is
+// acked without processing the composition again.
+var errCompositionFinished = errors.New("composition already finished")
+
+// errCompositionClaimed puts aside a message for a composition another worker
+// is processing, its heartbeat is fresh. The outbox publishes at least once
+// and the queue redelivers the message of a crashed worker right away, so the
+// message is deferred rather than dropped: it is dropped once the composition
+// finishes, or takes the composition over once the heartbeat is stale.
+var errCompositionClaimed = errors.New("composition claimed by another worker")
+
+// claimComposition marks a composition as processing, unless it was cancelled,
+// deleted or finished meanwhile, another worker is processing it or its
+// author already has maxJobsPerUser compositions processing. An advisory lock
+// on the author serializes the claims of the workers, so the cap holds across
+// them.
+func claimComposition(ctx context.Context, db *sql.DB, compositionID, authorID string, maxJobsPerUser int) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", authorID); err != nil {
+		return fmt.Errorf("failed to lock user jobs: %w", err)
+	}
+
+	composition, err := models.Compositions(
+		qm.Select(models.CompositionColumns.Status, models.CompositionColumns.UpdatedAt),
+		models.CompositionWhere.ID.EQ(compositionID),
+		qm.For("UPDATE"),
+	).One(ctx, tx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errCompositionCancelled
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock composition: %w", err)
+	}
+	switch composition.Status {
+	case models.CompositionStatusEnumCANCELLED:
+		return errCompositionCancelled
+	case models.CompositionStatusEnumCOMPLETE, models.CompositionStatusEnumFAILED:
+		return errCompositionFinished
+	case models.CompositionStatusEnumPROCESSING:
+		// Only taken over from a worker that stopped beating
+		if time.Since(composition.UpdatedAt) < heartbeatTimeout {
+			return errCompositionClaimed
+		}
+	}
+
+	// Other compositions of the user may still be marked processing by the
+	// workers that lost them until their redelivery
+	running, err := models.Compositions(
+		qm.InnerJoin("arts ON arts.id = compositions.art_id AND arts.author_id = ?", authorID),
+		models.CompositionWhere.Status.EQ(models.CompositionStatusEnumPROCESSING),
+		models.CompositionWhere.ID.NEQ(compositionID),
+		qm.Where("compositions.updated_at > now() - ? * interval '1 second'", int(heartbeatTimeout.Seconds())),
+	).Count(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to count user jobs: %w", err)
+	}
+	if running >= int64(maxJobsPerUser) {
+		return errUserAtCapacity
+	}
+
+	_, err = models.Compositions(
+		models.CompositionWhere.ID.EQ(compositionID),
+	).UpdateAll(ctx, tx, models.M{
+		models.CompositionColumns.Status:    models.CompositionStatusEnumPROCESSING,
+		models.CompositionColumns.UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update composition status: %w", err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	"github.com/Damione1/thread-art-generator/core/queue"
+	"github.com/Damione1/thread-art-generator/core/util"
+)
+
+const (
+	lockQuery  = `SELECT pg_advisory_xact_lock\(hashtext\(\$1\)\)`
+	claimQuery = `SELECT "status", "updated_at" FROM "compositions" WHERE \("compositions"\."id" = \$1\) LIMIT 1 FOR UPDATE`
+	countQuery = `SELECT COUNT\(\*\) FROM "compositions" INNER JOIN arts ON arts\.id = compositions\.art_id AND arts\.author_id = \$1 ` +
+		`WHERE \("compositions"\."status" = \$2\) AND \("compositions"\."id" != \$3\) AND \(compositions\.updated_at > now\(\) - \$4 \* interval '1 second'\)`
+	updateQuery = `UPDATE "compositions" SET "status" = \$1, "updated_at" = \$2 WHERE \("compositions"\."id" = \$3\)`
+)
+
+// expectClaim expects a claim up to the count of the running jobs of the
+// author, the composition was last updated or beat at updatedAt
+func expectClaim(mock sqlmock.Sqlmock, status models.CompositionStatusEnum, updatedAt time.Time, running int) {
+	mock.ExpectBegin()
+	mock.ExpectExec(lockQuery).WithArgs("author").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(claimQuery).WithArgs("composition").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "updated_at"}).AddRow(status, updatedAt))
+	if running < 0 {
+		return
+	}
+	// Only the jobs whose worker beat within the heartbeat timeout count
+	mock.ExpectQuery(countQuery).
+		WithArgs("author", models.CompositionStatusEnumPROCESSING, "composition", int(heartbeatTimeout.Seconds())).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(running))
+}
+
+func TestClaimComposition(t *testing.T) {
+	stale := time.Now().Add(-2 * heartbeatTimeout)
+	tests := []struct {
+		name      string
+		status    models.CompositionStatusEnum
+		updatedAt time.Time
+		running   int
+		err       error
+	}{
+		{name: "pending", status: models.CompositionStatusEnumPENDING, updatedAt: stale, running: 1},
+		// The worker processing the composition crashed, its heartbeat is stale
+		{name: "taken over", status: models.CompositionStatusEnumPROCESSING, updatedAt: stale, running: 0},
+		// A duplicate of a message another worker is processing
+		{name: "claimed elsewhere", status: models.CompositionStatusEnumPROCESSING, updatedAt: time.Now(), running: -1, err: errCompositionClaimed},
+		{name: "at capacity", status: models.CompositionStatusEnumPENDING, updatedAt: stale, running: 2, err: errUserAtCapacity},
+		{name: "cancelled", status: models.CompositionStatusEnumCANCELLED, updatedAt: stale, running: -1, err: errCompositionCancelled},
+		{name: "complete", status: models.CompositionStatusEnumCOMPLETE, updatedAt: stale, running: -1, err: errCompositionFinished},
+		{name: "failed", status: models.CompositionStatusEnumFAILED, updatedAt: stale, running: -1, err: errCompositionFinished},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			expectClaim(mock, tt.status, tt.updatedAt, tt.running)
+			if tt.err == nil {
+				mock.ExpectExec(updateQuery).
+					WithArgs(models.CompositionStatusEnumPROCESSING, sqlmock.AnyArg(), "composition").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			err = claimComposition(context.Background(), db, "composition", "author", 2)
+			require.ErrorIs(t, err, tt.err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// fakePublisher records the messages the worker settles
+type fakePublisher struct {
+	keys     []string
+	messages []amqp.Publishing
+}
+
+func (p *fakePublisher) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	p.keys = append(p.keys, exchange+"/"+key)
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+// fakeAcknowledger records how a delivery is settled
+type fakeAcknowledger struct {
+	acked, nacked bool
+}
+
+func (a *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	a.nacked = true
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	a.nacked = true
+	return nil
+}
+
+func TestHandleDeliveryDefersAtCapacity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	body, err := queue.NewCompositionProcessingMessage("art", "composition").ToJSON()
+	require.NoError(t, err)
+	acknowledger := &fakeAcknowledger{}
+	delivery := amqp.Delivery{
+		Acknowledger: acknowledger,
+		Headers:      amqp.Table{queue.AttemptHeader: int32(2)},
+		Body:         body,
+	}
+	publisher := &fakePublisher{}
+
+	mock.ExpectQuery(`SELECT "compositions"\.\* FROM "compositions"`).
+		WithArgs("composition", "art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "art_id", "status", "max_paths"}).
+			AddRow("composition", "art", models.CompositionStatusEnumPENDING, 1000))
+	mock.ExpectQuery(`SELECT "arts"\.\* FROM "arts"`).
+		WithArgs("art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "image_id"}).AddRow("art", "author", "image"))
+	expectClaim(mock, models.CompositionStatusEnumPENDING, time.Now(), 1)
+	mock.ExpectRollback()
+
+	// The author already has a job processing, the message waits in the defer
+	// queue without counting an attempt and the composition stays pending
+	policy := queue.RetryPolicy{MaxAttempts: 4}
+	handleDelivery(context.Background(), publisher, delivery, "composition-processing", policy, util.QueueConfig{DeferDelay: 15 * time.Second, MaxJobsPerUser: 1}, db, nil)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Equal(t, []string{"/composition-processing.deferred.15s"}, publisher.keys)
+	require.Equal(t, int32(2), publisher.messages[0].Headers[queue.AttemptHeader])
+	require.Equal(t, body, publisher.messages[0].Body)
+	require.True(t, acknowledger.acked)
+	require.False(t, acknowledger.nacked)
+}
+
+func TestHandleDeliveryDefersDuplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	body, err := queue.NewCompositionProcessingMessage("art", "composition").ToJSON()
+	require.NoError(t, err)
+	acknowledger := &fakeAcknowledger{}
+	delivery := amqp.Delivery{
+		Acknowledger: acknowledger,
+		Headers:      amqp.Table{queue.AttemptHeader: int32(2)},
+		Body:         body,
+	}
+	publisher := &fakePublisher{}
+
+	mock.ExpectQuery(`SELECT "compositions"\.\* FROM "compositions"`).
+		WithArgs("composition", "art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "art_id", "status", "max_paths"}).
+			AddRow("composition", "art", models.CompositionStatusEnumPROCESSING, 1000))
+	mock.ExpectQuery(`SELECT "arts"\.\* FROM "arts"`).
+		WithArgs("art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "image_id"}).AddRow("art", "author", "image"))
+	expectClaim(mock, models.CompositionStatusEnumPROCESSING, time.Now(), -1)
+	mock.ExpectRollback()
+
+	// Another worker beat moments ago, the duplicate waits in the defer queue
+	// instead of processing the composition a second time
+	policy := queue.RetryPolicy{MaxAttempts: 4}
+	handleDelivery(context.Background(), publisher, delivery, "composition-processing", policy, util.QueueConfig{DeferDelay: 15 * time.Second, MaxJobsPerUser: 1}, db, nil)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Equal(t, []string{"/composition-processing.deferred.15s"}, publisher.keys)
+	require.Equal(t, int32(2), publisher.messages[0].Headers[queue.AttemptHeader])
+	require.True(t, acknowledger.acked)
+	require.False(t, acknowledger.nacked)
+}
+
+// pendingQuery resets a composition waiting for a retry to pending
+const pendingQuery = `UPDATE "compositions" SET "status" = \$1 WHERE \("compositions"\."id" = \$2\) AND \("compositions"\."status" = \$3\)`
+
+func TestHandleDeliveryRetriesUnclaimed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	body, err := queue.NewCompositionProcessingMessage("art", "composition").ToJSON()
+	require.NoError(t, err)
+	acknowledger := &fakeAcknowledger{}
+	delivery := amqp.Delivery{
+		Acknowledger: acknowledger,
+		Headers:      amqp.Table{queue.AttemptHeader: int32(2)},
+		Body:         body,
+	}
+	publisher := &fakePublisher{}
+
+	mock.ExpectQuery(`SELECT "compositions"\.\* FROM "compositions"`).
+		WithArgs("composition", "art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "art_id", "status", "max_paths"}).
+			AddRow("composition", "art", models.CompositionStatusEnumPROCESSING, 1000))
+	mock.ExpectQuery(`SELECT "arts"\.\* FROM "arts"`).
+		WithArgs("art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "image_id"}).AddRow("art", "author", "image"))
+	mock.ExpectBegin()
+	mock.ExpectExec(lockQuery).WithArgs("author").WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+	// Expected last, the reset must not happen
+	mock.ExpectExec(pendingQuery).
+		WithArgs(models.CompositionStatusEnumPENDING, "composition", models.CompositionStatusEnumPROCESSING).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// The claim failed before this delivery took the composition, another worker
+	// is processing it: the message is retried and the composition stays
+	// processing
+	policy := queue.RetryPolicy{MaxAttempts: 4, BaseDelay: 5 * time.Second}
+	handleDelivery(context.Background(), publisher, delivery, "composition-processing", policy, util.QueueConfig{DeferDelay: 15 * time.Second, MaxJobsPerUser: 1}, db, nil)
+	require.ErrorContains(t, mock.ExpectationsWereMet(), `UPDATE "compositions"`, "the composition was reset to pending")
+
+	require.Equal(t, []string{"/" + queue.RetryQueueName("composition-processing", policy.Delay(2))}, publisher.keys)
+	require.Equal(t, int32(3), publisher.messages[0].Headers[queue.AttemptHeader])
+	require.True(t, acknowledger.acked)
+	require.False(t, acknowledger.nacked)
+}
+
+// outboxClient records the messages the outbox relay publishes
+type outboxClient struct {
+	messages [][]byte
+}
+
+func (c *outboxClient) PublishMessage(ctx context.Context, queueName string, message []byte) error {
+	c.messages = append(c.messages, message)
+	return nil
+}
+
+func (c *outboxClient) QueueDepth(queueName string) (queue.QueueDepth, error) {
+	return queue.QueueDepth{}, nil
+}
+
+func (c *outboxClient) Close() error {
+	return nil
+}
+
+func TestOutboxDuplicateIsNotProcessedTwice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	body, err := queue.NewCompositionProcessingMessage("art", "composition").ToJSON()
+	require.NoError(t, err)
+	client := &outboxClient{}
+	relay := queue.NewOutboxRelay(db, client, time.Minute)
+	now := time.Now()
+	outboxRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "queue", "payload", "attempts", "last_error", "next_attempt_at", "created_at"}).
+			AddRow("outbox", "composition-processing", body, 0, nil, now, now)
+	}
+
+	// The message is published but its deletion fails, the next relay publishes it again
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM "outbox_messages"`).WillReturnRows(outboxRows())
+	mock.ExpectExec(`DELETE FROM "outbox_messages"`).WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM "outbox_messages"`).WillReturnRows(outboxRows())
+	mock.ExpectExec(`DELETE FROM "outbox_messages"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		relay.Run(ctx)
+	}()
+	relay.Notify()
+	require.Eventually(t, func() bool { return mock.ExpectationsWereMet() == nil }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+	require.Len(t, client.messages, 2)
+
+	// The first delivery is processing, a worker got the duplicate meanwhile:
+	// it is settled without a second generation of the composition
+	mock.ExpectQuery(`SELECT "compositions"\.\* FROM "compositions"`).
+		WithArgs("composition", "art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "art_id", "status", "max_paths"}).
+			AddRow("composition", "art", models.CompositionStatusEnumPROCESSING, 1000))
+	mock.ExpectQuery(`SELECT "arts"\.\* FROM "arts"`).
+		WithArgs("art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "image_id"}).AddRow("art", "author", "image"))
+	expectClaim(mock, models.CompositionStatusEnumPROCESSING, time.Now(), -1)
+	mock.ExpectRollback()
+
+	acknowledger := &fakeAcknowledger{}
+	publisher := &fakePublisher{}
+	delivery := amqp.Delivery{Acknowledger: acknowledger, Body: client.messages[1]}
+	handleDelivery(context.Background(), publisher, delivery, "composition-processing", queue.RetryPolicy{MaxAttempts: 4}, util.QueueConfig{DeferDelay: 15 * time.Second, MaxJobsPerUser: 2}, db, nil)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.True(t, acknowledger.acked)
+	require.Equal(t, []string{"/composition-processing.deferred.15s"}, publisher.keys)
+
+	// Once the first delivery completed the composition, the duplicate is dropped
+	mock.ExpectQuery(`SELECT "compositions"\.\* FROM "compositions"`).
+		WithArgs("composition", "art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "art_id", "status", "max_paths"}).
+			AddRow("composition", "art", models.CompositionStatusEnumCOMPLETE, 1000))
+	mock.ExpectQuery(`SELECT "arts"\.\* FROM "arts"`).
+		WithArgs("art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "image_id"}).AddRow("art", "author", "image"))
+	expectClaim(mock, models.CompositionStatusEnumCOMPLETE, time.Now(), -1)
+	mock.ExpectRollback()
+
+	acknowledger = &fakeAcknowledger{}
+	publisher = &fakePublisher{}
+	delivery = amqp.Delivery{Acknowledger: acknowledger, Body: client.messages[1]}
+	handleDelivery(context.Background(), publisher, delivery, "composition-processing", queue.RetryPolicy{MaxAttempts: 4}, util.QueueConfig{DeferDelay: 15 * time.Second, MaxJobsPerUser: 2}, db, nil)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.True(t, acknowledger.acked)
+	require.Empty(t, publisher.keys)
+}
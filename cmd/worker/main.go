@@ -1,16 +1,21 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"image/png"
 	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -20,6 +25,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 
 	database "github.com/Damione1/thread-art-generator/core/db"
 	"github.com/Damione1/thread-art-generator/core/db/models"
@@ -28,6 +34,7 @@ import (
 	"github.com/Damione1/thread-art-generator/core/storage"
 	"github.com/Damione1/thread-art-generator/core/util"
 	"github.com/Damione1/thread-art-generator/threadGenerator"
+	"github.com/Damione1/thread-art-generator/threadGenerator/gcodesim"
 )
 
 func main() {
@@ -70,6 +77,33 @@ func initializeDualStorage(ctx context.Context, config util.Config) (*storage.Du
 	return storage.NewDualBucketStorage(ctx, config.Storage)
 }
 
+// consumerTag identifies the consumers of the worker channel, to cancel them on shutdown
+const consumerTag = "composition-worker"
+
+// priorityConsumerTag returns the tag of the consumer of a priority queue
+func priorityConsumerTag(priority queue.Priority) string {
+	return consumerTag + "." + priority.String()
+}
+
+// declareQueue declares a priority queue with its retry and defer queues
+func declareQueue(ch *amqp.Channel, queueName string, policy queue.RetryPolicy, deferDelay time.Duration) error {
+	_, err := ch.QueueDeclare(
+		queueName, // name
+		true,      // durable
+		false,     // delete when unused
+		false,     // exclusive
+		false,     // no-wait
+		nil,       // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare a queue: %w", err)
+	}
+	if err := queue.DeclareRetryTopology(ch, queueName, policy); err != nil {
+		return err
+	}
+	return queue.DeclareDeferQueue(ch, queueName, deferDelay)
+}
+
 func startQueueProcessing(ctx context.Context, config util.Config, dualStorage *storage.DualBucketStorage) error {
 	// Connect to RabbitMQ
 	queueURL := config.Queue.URL
@@ -89,104 +123,217 @@ func startQueueProcessing(ctx context.Context, config util.Config, dualStorage *
 	}
 	defer ch.Close()
 
-	// Get queue name from config
+	// Get queue name from config, each priority has its own queue
 	queueName := config.Queue.CompositionProcessing
 	if queueName == "" {
 		queueName = "composition-processing"
 	}
 
-	// Declare queue
-	q, err := ch.QueueDeclare(
-		queueName, // name
-		true,      // durable
-		false,     // delete when unused
-		false,     // exclusive
-		false,     // no-wait
-		nil,       // arguments
-	)
-	if err != nil {
-		return fmt.Errorf("failed to declare a queue: %w", err)
+	// Failed messages wait in delay queues between attempts, then end in the
+	// dead-letter queue. Messages of users at the cap wait in a defer queue.
+	policy := queue.RetryPolicy{MaxAttempts: config.Queue.MaxAttempts, BaseDelay: config.Queue.RetryBaseDelay}
+	for _, priority := range queue.Priorities {
+		if err := declareQueue(ch, queue.PriorityQueueName(queueName, priority), policy, config.Queue.DeferDelay); err != nil {
+			return err
+		}
 	}
 
-	// Set QoS
+	// Prefetch one message per job the worker runs at once, from each queue
+	concurrency := config.Queue.WorkerConcurrency
 	err = ch.Qos(
-		1,     // prefetch count
-		0,     // prefetch size
-		false, // global
+		concurrency, // prefetch count
+		0,           // prefetch size
+		false,       // global
 	)
 	if err != nil {
 		return fmt.Errorf("failed to set QoS: %w", err)
 	}
 
-	// Consume messages
-	msgs, err := ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		false,  // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
-	if err != nil {
-		return fmt.Errorf("failed to register a consumer: %w", err)
-	}
-
 	// Set up signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	// Process messages
-	go func() {
-		for d := range msgs {
-			log.Info().Int("size", len(d.Body)).Msg("Received a message")
-
-			// Process message
-			err := processMessage(ctx, d.Body, config.DB, dualStorage)
-			if err != nil {
-				log.Error().Err(err).Msg("Failed to process message")
-
-				// Nack the message with requeue
-				err = d.Nack(false, true)
-				if err != nil {
-					log.Error().Err(err).Msg("Failed to nack message")
+	// Jobs start once the memory they need is free, by priority then fairly across users
+	scheduler := queue.NewScheduler(int64(config.Queue.WorkerMemoryMB) << 20)
+	var workers sync.WaitGroup
+	for range concurrency {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				job, ok := scheduler.Next()
+				if !ok {
+					return
 				}
-
-				// Wait a bit before continuing to avoid rapid requeuing
-				time.Sleep(5 * time.Second)
-				continue
+				handleDelivery(ctx, ch, job.Delivery, job.Queue, policy, config.Queue, config.DB, dualStorage)
+				scheduler.Done(job)
 			}
+		}()
+	}
+
+	// Consume every priority queue
+	for _, priority := range queue.Priorities {
+		name := queue.PriorityQueueName(queueName, priority)
+		msgs, err := ch.Consume(
+			name,                          // queue
+			priorityConsumerTag(priority), // consumer
+			false,                         // auto-ack
+			false,                         // exclusive
+			false,                         // no-local
+			false,                         // no-wait
+			nil,                           // args
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register a consumer: %w", err)
+		}
 
-			// Ack the message
-			err = d.Ack(false)
-			if err != nil {
-				log.Error().Err(err).Msg("Failed to ack message")
+		// Process messages
+		go func() {
+			for d := range msgs {
+				job := newJob(ctx, config.DB, d)
+				job.Queue = name
+				job.Priority = priority
+				log.Info().
+					Int("size", len(d.Body)).
+					Int("attempt", queue.Attempt(d)).
+					Str("priority", priority.String()).
+					Str("user", job.User).
+					Int64("estimatedMemoryMB", job.Memory>>20).
+					Msg("Received a message")
+				if !scheduler.Submit(job) {
+					requeue(d)
+				}
 			}
-		}
-	}()
+		}()
+	}
 
-	log.Info().Str("queue", queueName).Msg("🧵 Worker is waiting for messages")
+	log.Info().
+		Str("queue", queueName).
+		Int("concurrency", concurrency).
+		Int("memoryBudgetMB", config.Queue.WorkerMemoryMB).
+		Int("maxJobsPerUser", config.Queue.MaxJobsPerUser).
+		Msg("🧵 Worker is waiting for messages")
 
 	// Wait for termination signal
 	<-sigChan
-	log.Info().Msg("Received termination signal, shutting down")
+	log.Info().Msg("Received termination signal, draining in-flight jobs")
+
+	// Stop receiving messages, give back the ones not started and let the
+	// running ones finish
+	for _, priority := range queue.Priorities {
+		if err := ch.Cancel(priorityConsumerTag(priority), false); err != nil {
+			log.Error().Err(err).Str("priority", priority.String()).Msg("Failed to cancel consumer")
+		}
+	}
+	for _, job := range scheduler.Close() {
+		requeue(job.Delivery)
+	}
+	workers.Wait()
+	log.Info().Msg("In-flight jobs drained, shutting down")
 	return nil
 }
 
-// processMessage processes a single message from the queue
-func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *storage.DualBucketStorage) error {
+// newJob identifies the user of a delivery and estimates the memory its
+// generation holds. A delivery whose composition can't be read is scheduled
+// as is, its processing reports the error.
+func newJob(ctx context.Context, db *sql.DB, d amqp.Delivery) *queue.Job {
+	job := &queue.Job{Delivery: d}
+
+	var message queue.CompositionProcessingMessage
+	if err := message.FromJSON(d.Body); err != nil {
+		return job
+	}
+	composition, err := models.Compositions(
+		models.CompositionWhere.ID.EQ(message.CompositionID),
+		qm.Load(models.CompositionRels.Art),
+	).One(ctx, db)
+	if err != nil {
+		log.Warn().Err(err).Str("compositionID", message.CompositionID).Msg("Failed to get composition to schedule its job")
+		return job
+	}
+
+	job.User = composition.R.Art.AuthorID
+	job.Memory = pbx.CompositionToGeneratorConfig(composition).EstimatedMemory()
+	return job
+}
+
+// requeue gives a delivery back to the queue for another worker
+func requeue(d amqp.Delivery) {
+	if err := d.Nack(false, true); err != nil {
+		log.Error().Err(err).Msg("Failed to nack message")
+	}
+}
+
+// handleDelivery processes a message and settles it. Transient failures are
+// retried after a growing delay, permanent ones and the last attempt fail the
+// composition and dead-letter the message. Messages of users at the cap of
+// concurrent jobs are deferred, the jobs of other users go ahead meanwhile, and
+// so are duplicates of a message another worker is processing.
+func handleDelivery(ctx context.Context, ch queue.Publisher, d amqp.Delivery, queueName string, policy queue.RetryPolicy, queueConfig util.QueueConfig, db *sql.DB, dualStorage *storage.DualBucketStorage) {
+	claimed, err := processMessage(ctx, d.Body, db, dualStorage, queueConfig)
+	switch {
+	case errors.Is(err, errCompositionCancelled):
+		// Nothing is left to do for a cancelled composition
+		log.Info().Msg("Composition cancelled, message dropped")
+		err = nil
+	case errors.Is(err, errCompositionFinished):
+		log.Info().Msg("Composition already finished, duplicate message dropped")
+		err = nil
+	}
+	if err == nil {
+		if err := d.Ack(false); err != nil {
+			log.Error().Err(err).Msg("Failed to ack message")
+		}
+		return
+	}
+
+	switch {
+	case errors.Is(err, errUserAtCapacity), errors.Is(err, errCompositionClaimed):
+		err = queue.Defer(ctx, ch, d, queueName, queueConfig.DeferDelay, err)
+	case !queue.IsPermanent(err) && queue.Attempt(d) < policy.MaxAttempts:
+		log.Error().Err(err).Int("attempt", queue.Attempt(d)).Msg("Failed to process message")
+		err = queue.Retry(ctx, ch, d, queueName, policy, err)
+		// Another worker may be processing a composition this message didn't claim
+		if err == nil && claimed {
+			setCompositionPending(ctx, db, d.Body)
+		}
+	default:
+		log.Error().Err(err).Int("attempt", queue.Attempt(d)).Msg("Failed to process message")
+		failComposition(ctx, db, dualStorage, d.Body, err)
+		err = queue.DeadLetter(ctx, ch, d, queueName, err)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to settle message")
+
+		// Requeue the message as is, waiting a bit to avoid rapid requeuing
+		if err := d.Nack(false, true); err != nil {
+			log.Error().Err(err).Msg("Failed to nack message")
+		}
+		time.Sleep(5 * time.Second)
+		return
+	}
+
+	if err := d.Ack(false); err != nil {
+		log.Error().Err(err).Msg("Failed to ack message")
+	}
+}
+
+// processMessage processes a single message from the queue. It reports
+// whether the message claimed the composition, the failures before the claim
+// leave the composition as the claim found it.
+func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *storage.DualBucketStorage, queueConfig util.QueueConfig) (claimed bool, err error) {
 	processingStartTime := time.Now()
 
 	// Parse the message
 	var message queue.CompositionProcessingMessage
-	err := message.FromJSON(body)
+	err = message.FromJSON(body)
 	if err != nil {
-		return fmt.Errorf("failed to parse message: %w", err)
+		return false, queue.Permanent(fmt.Errorf("failed to parse message: %w", err))
 	}
 
 	// Verify message type
 	if message.Type != queue.MessageTypeCompositionProcessing {
-		return fmt.Errorf("unexpected message type: %s", message.Type)
+		return false, queue.Permanent(fmt.Errorf("unexpected message type: %s", message.Type))
 	}
 
 	log.Info().
@@ -195,13 +342,25 @@ func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *s
 		Str("compositionID", message.CompositionID).
 		Msg("Processing composition")
 
-	// Get the composition
+	// Get the composition with its thread profile
 	composition, err := models.Compositions(
 		models.CompositionWhere.ID.EQ(message.CompositionID),
 		models.CompositionWhere.ArtID.EQ(message.ArtID),
+		qm.Load(models.CompositionRels.ThreadProfile),
 	).One(ctx, db)
 	if err != nil {
-		return fmt.Errorf("failed to get composition: %w", err)
+		return false, missingIsPermanent(fmt.Errorf("failed to get composition: %w", err))
+	}
+
+	// The last composition of a sweep to finish completes the sweep. A completed
+	// or cancelled composition is finished here, a failed one by failComposition
+	// once its retries are exhausted.
+	if composition.SweepID.Valid {
+		defer func() {
+			if err == nil || errors.Is(err, errCompositionCancelled) {
+				finishSweepComposition(ctx, db, dualStorage, composition.SweepID.String)
+			}
+		}()
 	}
 
 	// Get the art (needed for accessing the image)
@@ -209,20 +368,46 @@ func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *s
 		models.ArtWhere.ID.EQ(message.ArtID),
 	).One(ctx, db)
 	if err != nil {
-		return fmt.Errorf("failed to get art: %w", err)
+		return false, missingIsPermanent(fmt.Errorf("failed to get art: %w", err))
+	}
+	if !art.ImageID.Valid {
+		return false, queue.Permanent(errors.New("art has no image"))
 	}
 
-	// Update status to processing
-	composition.Status = models.CompositionStatusEnumPROCESSING
-	_, err = composition.Update(ctx, db, boil.Whitelist(models.CompositionColumns.Status))
-	if err != nil {
-		return fmt.Errorf("failed to update composition status: %w", err)
+	// Storage keys of the composition files
+	previewKey := fmt.Sprintf("users/%s/arts/%s/compositions/%s/preview.png", art.AuthorID, art.ID, composition.ID)
+	gcodeKey := fmt.Sprintf("users/%s/arts/%s/compositions/%s/gcode.txt", art.AuthorID, art.ID, composition.ID)
+	pathsKey := fmt.Sprintf("users/%s/arts/%s/compositions/%s/paths.json", art.AuthorID, art.ID, composition.ID)
+	drillGcodeKey := fmt.Sprintf("users/%s/arts/%s/compositions/%s/drill_gcode.txt", art.AuthorID, art.ID, composition.ID)
+	heatmapKey := fmt.Sprintf("users/%s/arts/%s/compositions/%s/heatmap.png", art.AuthorID, art.ID, composition.ID)
+
+	// Stop the processing once the author cancels the composition, and remove
+	// the files this attempt or an earlier one uploaded
+	processCtx, watcher := watchCancellation(ctx, db, composition.ID)
+	defer func() {
+		watcher.stop()
+		if errors.Is(err, errCompositionCancelled) || err != nil && watcher.isCancelled() {
+			removeArtifacts(ctx, dualStorage, []string{previewKey, gcodeKey, pathsKey, drillGcodeKey, heatmapKey})
+			err = errCompositionCancelled
+		}
+	}()
+
+	// Update status to processing, unless the composition was cancelled, deleted
+	// or finished while its message waited in the queue, another worker is
+	// processing it or its author is at the cap
+	if err := claimComposition(processCtx, db, composition.ID, art.AuthorID, queueConfig.MaxJobsPerUser); err != nil {
+		return false, err
 	}
+	composition.Status = models.CompositionStatusEnumPROCESSING
+
+	progress := newProgressReporter(processCtx, db, composition.ID, composition.MaxPaths)
+	defer progress.stop()
+	progress.stage(pbx.ProgressStageDownload)
 
 	// Create temporary directory for processing
 	tempDir, err := os.MkdirTemp("", "composition-*")
 	if err != nil {
-		return fmt.Errorf("failed to create temp directory: %w", err)
+		return true, fmt.Errorf("failed to create temp directory: %w", err)
 	}
 	defer os.RemoveAll(tempDir)
 
@@ -230,8 +415,7 @@ func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *s
 	sourceImagePath := filepath.Join(tempDir, "source.jpg")
 	sourceFile, err := os.Create(sourceImagePath)
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to create source file: %v", err))
-		return fmt.Errorf("failed to create source file: %w", err)
+		return true, fmt.Errorf("failed to create source file: %w", err)
 	}
 	defer sourceFile.Close()
 
@@ -246,17 +430,25 @@ func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *s
 		Str("imageID", art.ImageID.String).
 		Msg("Attempting to download source image")
 
-	reader, err := dualStorage.GetPublicStorage().Download(ctx, imageKey)
+	// SVG sources are kept private, the public image is only their rendering
+	source := dualStorage.GetPublicStorage()
+	svgSource, err := dualStorage.GetPrivateStorage().Exists(processCtx, imageKey)
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to download source image: %v", err))
-		return fmt.Errorf("failed to download source image: %w", err)
+		return true, fmt.Errorf("failed to look up svg source: %w", err)
+	}
+	if svgSource {
+		source = dualStorage.GetPrivateStorage()
+	}
+
+	reader, err := source.Download(processCtx, imageKey)
+	if err != nil {
+		return true, fmt.Errorf("failed to download source image: %w", err)
 	}
 	defer reader.Close()
 
 	written, err := io.Copy(sourceFile, reader)
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to write source image: %v", err))
-		return fmt.Errorf("failed to write source image: %w", err)
+		return true, fmt.Errorf("failed to write source image: %w", err)
 	}
 
 	log.Info().
@@ -266,8 +458,7 @@ func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *s
 
 	// Verify the file exists and has content
 	if fi, err := os.Stat(sourceImagePath); err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to verify source image: %v", err))
-		return fmt.Errorf("failed to verify source image: %w", err)
+		return true, fmt.Errorf("failed to verify source image: %w", err)
 	} else {
 		log.Info().
 			Int64("size", fi.Size()).
@@ -275,15 +466,9 @@ func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *s
 	}
 
 	// Initialize thread generator with composition settings
-	config := threadGenerator.DefaultConfig()
-	config.NailsQuantity = composition.NailsQuantity
-	config.ImgSize = composition.ImgSize
-	config.MaxPaths = composition.MaxPaths
-	config.StartingNail = composition.StartingNail
-	config.MinimumDifference = composition.MinimumDifference
-	config.BrightnessFactor = composition.BrightnessFactor
-	config.ImageContrast = composition.ImageContrast
-	config.PhysicalRadius = composition.PhysicalRadius
+	config := pbx.CompositionToGeneratorConfig(composition)
+	// The jobs running at once share the cores for scoring the nails
+	config.ScoringWorkers = max(1, runtime.GOMAXPROCS(0)/max(1, queueConfig.WorkerConcurrency))
 
 	// Log the configuration settings being used
 	log.Info().
@@ -295,19 +480,29 @@ func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *s
 		Int("brightnessFactor", composition.BrightnessFactor).
 		Float64("imageContrast", composition.ImageContrast).
 		Float64("physicalRadius", composition.PhysicalRadius).
+		Float64("spoolLength", composition.SpoolLength).
+		Int("rings", len(config.NailRings())).
+		Bool("threadProfile", config.ThreadProfile != nil).
+		Bool("linearLight", config.LinearLight).
+		Str("inputType", string(config.InputType)).
+		Int("symmetryFold", config.SymmetryFold).
+		Bool("mirrorSymmetry", config.MirrorSymmetry).
+		Str("gcodeDialect", string(config.GcodeDialect)).
 		Msg("Applying thread generator settings")
 
 	generator := threadGenerator.NewThreadGenerator(config)
 	generator.SetImage(sourceImagePath)
+	generator.SetProgressFunc(progress.lines)
+	progress.stage(pbx.ProgressStageGenerate)
 
 	// Generate thread art - now we can just pass the image name
 	startTime := time.Now()
-	stats, err := generator.Generate(threadGenerator.Args{
+	stats, err := generator.GenerateWithContext(processCtx, threadGenerator.Args{
 		ImageName: sourceImagePath,
 	})
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to generate thread art: %v", err))
-		return fmt.Errorf("failed to generate thread art: %w", err)
+		// Generation only depends on the image and settings, it fails the same way every time
+		return true, queue.Permanent(fmt.Errorf("failed to generate thread art: %w", err))
 	}
 
 	generationTime := time.Since(startTime)
@@ -315,13 +510,21 @@ func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *s
 		Int("threadLength", stats.ThreadLength).
 		Int("totalLines", stats.TotalLines).
 		Msg("Thread art generation completed")
+	progress.stage(pbx.ProgressStageRender)
+
+	// Identify the inputs so a rerun of the same inputs can be checked
+	contentHash, err := generator.ContentHash()
+	if err != nil {
+		return true, fmt.Errorf("failed to hash generation inputs: %w", err)
+	}
+	composition.ContentHash = null.StringFrom(contentHash)
+	verifyReproduction(processCtx, db, dualStorage, contentHash, generator.GetPathsList())
 
 	// Generate preview image
 	previewStartTime := time.Now()
 	previewImage, err := generator.GeneratePathsImage()
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to generate preview image: %v", err))
-		return fmt.Errorf("failed to generate preview image: %w", err)
+		return true, fmt.Errorf("failed to generate preview image: %w", err)
 	}
 
 	previewGenerationTime := time.Since(previewStartTime)
@@ -331,65 +534,129 @@ func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *s
 	previewPath := filepath.Join(tempDir, "preview.png")
 	previewFile, err := os.Create(previewPath)
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to create preview file: %v", err))
-		return fmt.Errorf("failed to create preview file: %w", err)
+		return true, fmt.Errorf("failed to create preview file: %w", err)
 	}
 	defer previewFile.Close()
 
 	err = png.Encode(previewFile, previewImage)
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to encode preview image: %v", err))
-		return fmt.Errorf("failed to encode preview image: %w", err)
+		return true, fmt.Errorf("failed to encode preview image: %w", err)
 	}
 
 	log.Info().Msg("Preview image saved to temp file")
 
+	// Split the thread into spools, the G-code pauses at every boundary
+	segments, err := generator.GetSpoolSegments()
+	if err != nil {
+		return true, queue.Permanent(fmt.Errorf("failed to plan spool segments: %w", err))
+	}
+	segmentsJSON, err := json.Marshal(segments)
+	if err != nil {
+		return true, fmt.Errorf("failed to marshal spool segments: %w", err)
+	}
+	composition.Segments = null.JSONFrom(segmentsJSON)
+	log.Info().Int("segments", len(segments)).Msg("Spool segments planned")
+
 	// Generate GCode
 	gcode := generator.GetGcode()
+
+	// Replay the G-code on the machine model before anything reaches a board
+	verification, err := gcodesim.Verify(gcode, config.GcodeDialect, gcodesim.MachineFromConfig(config), generator.GetPathsList())
+	if err == nil {
+		err = verification.Err()
+	}
+	if err != nil {
+		return true, queue.Permanent(fmt.Errorf("generated gcode failed verification: %w", err))
+	}
+
 	gcodePath := filepath.Join(tempDir, "gcode.txt")
 	err = os.WriteFile(gcodePath, []byte(strings.Join(gcode, "\n")), 0644)
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to write gcode file: %v", err))
-		return fmt.Errorf("failed to write gcode file: %w", err)
+		return true, fmt.Errorf("failed to write gcode file: %w", err)
 	}
 
-	log.Info().Msg("GCode file generated")
+	motionStats := generator.GetMotionStats()
+	log.Info().
+		Int("moves", motionStats.Moves).
+		Int("mergedMoves", motionStats.MergedMoves).
+		Float64("rotaryTurns", motionStats.RotaryTurns).
+		Float64("maxTwistTurns", motionStats.MaxTwistTurns).
+		Dur("estimatedRunTime", motionStats.EstimatedRunTime).
+		Msg("GCode file generated")
+
+	// Generate the nail hole drilling program for the same ring
+	drillGcodePath := filepath.Join(tempDir, "drill_gcode.txt")
+	err = os.WriteFile(drillGcodePath, []byte(strings.Join(generator.GenerateHolesGcode(), "\n")), 0644)
+	if err != nil {
+		return true, fmt.Errorf("failed to write drill gcode file: %w", err)
+	}
 
-	// Get paths list
-	paths := generator.GetPathsList()
-	pathsJSON, err := json.Marshal(paths)
+	log.Info().Msg("Drill GCode file generated")
+
+	// Get paths list with the configuration and layout it was generated for
+	var pathsDocument bytes.Buffer
+	err = threadGenerator.WritePathList(&pathsDocument, generator.GetPathListDocument(), threadGenerator.PathListJSON)
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to marshal paths list: %v", err))
-		return fmt.Errorf("failed to marshal paths list: %w", err)
+		return true, fmt.Errorf("failed to encode paths list: %w", err)
 	}
 
 	pathsPath := filepath.Join(tempDir, "paths.json")
-	err = os.WriteFile(pathsPath, pathsJSON, 0644)
+	err = os.WriteFile(pathsPath, pathsDocument.Bytes(), 0644)
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to write paths file: %v", err))
-		return fmt.Errorf("failed to write paths file: %w", err)
+		return true, fmt.Errorf("failed to write paths file: %w", err)
 	}
 
 	log.Info().Msg("Paths list file generated")
 
+	// Report how the paths load the nails and cover the board
+	analytics := generator.GetPathAnalytics()
+	analyticsJSON, err := json.Marshal(analytics)
+	if err != nil {
+		return true, fmt.Errorf("failed to marshal path analytics: %w", err)
+	}
+	composition.Analytics = null.JSONFrom(analyticsJSON)
+
+	heatmapImage, err := generator.GenerateDensityHeatmap()
+	if err != nil {
+		return true, fmt.Errorf("failed to generate heatmap image: %w", err)
+	}
+
+	var heatmapPNG bytes.Buffer
+	err = png.Encode(&heatmapPNG, heatmapImage)
+	if err != nil {
+		return true, fmt.Errorf("failed to encode heatmap image: %w", err)
+	}
+
+	log.Info().
+		Float64("meanHits", analytics.MeanHits).
+		Int("overloadedNails", analytics.OverloadedNails).
+		Float64("meanChordLength", analytics.MeanChordLength).
+		Msg("Path analytics computed")
+
+	// Score the compositions of a sweep so the sweep can rank them
+	if composition.SweepID.Valid {
+		score, err := generator.SimilarityScore()
+		if err != nil {
+			return true, fmt.Errorf("failed to score composition: %w", err)
+		}
+		composition.SimilarityScore = null.Float64From(score)
+		log.Info().Float64("similarityScore", score).Msg("Sweep composition scored")
+	}
+
 	// Upload files to storage
+	progress.stage(pbx.ProgressStageUpload)
 	uploadStartTime := time.Now()
-	previewKey := fmt.Sprintf("users/%s/arts/%s/compositions/%s/preview.png", art.AuthorID, art.ID, composition.ID)
-	gcodeKey := fmt.Sprintf("users/%s/arts/%s/compositions/%s/gcode.txt", art.AuthorID, art.ID, composition.ID)
-	pathsKey := fmt.Sprintf("users/%s/arts/%s/compositions/%s/paths.json", art.AuthorID, art.ID, composition.ID)
 
 	// Upload preview image
 	previewFile, err = os.Open(previewPath)
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to open preview file: %v", err))
-		return fmt.Errorf("failed to open preview file: %w", err)
+		return true, fmt.Errorf("failed to open preview file: %w", err)
 	}
 	defer previewFile.Close()
 
-	err = dualStorage.GetPublicStorage().Upload(ctx, previewKey, previewFile, "image/png")
+	err = dualStorage.GetPublicStorage().Upload(processCtx, previewKey, previewFile, "image/png")
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to upload preview image: %v", err))
-		return fmt.Errorf("failed to upload preview image: %w", err)
+		return true, fmt.Errorf("failed to upload preview image: %w", err)
 	}
 
 	log.Info().Str("key", previewKey).Msg("Preview image uploaded to bucket")
@@ -397,35 +664,53 @@ func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *s
 	// Upload GCode file
 	gcodeFile, err := os.Open(gcodePath)
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to open gcode file: %v", err))
-		return fmt.Errorf("failed to open gcode file: %w", err)
+		return true, fmt.Errorf("failed to open gcode file: %w", err)
 	}
 	defer gcodeFile.Close()
 
-	err = dualStorage.GetPublicStorage().Upload(ctx, gcodeKey, gcodeFile, "text/plain")
+	err = dualStorage.GetPublicStorage().Upload(processCtx, gcodeKey, gcodeFile, "text/plain")
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to upload gcode file: %v", err))
-		return fmt.Errorf("failed to upload gcode file: %w", err)
+		return true, fmt.Errorf("failed to upload gcode file: %w", err)
 	}
 
 	log.Info().Str("key", gcodeKey).Msg("GCode file uploaded to bucket")
 
+	// Upload drill GCode file
+	drillGcodeFile, err := os.Open(drillGcodePath)
+	if err != nil {
+		return true, fmt.Errorf("failed to open drill gcode file: %w", err)
+	}
+	defer drillGcodeFile.Close()
+
+	err = dualStorage.GetPublicStorage().Upload(processCtx, drillGcodeKey, drillGcodeFile, "text/plain")
+	if err != nil {
+		return true, fmt.Errorf("failed to upload drill gcode file: %w", err)
+	}
+
+	log.Info().Str("key", drillGcodeKey).Msg("Drill GCode file uploaded to bucket")
+
 	// Upload paths file
 	pathsFile, err := os.Open(pathsPath)
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to open paths file: %v", err))
-		return fmt.Errorf("failed to open paths file: %w", err)
+		return true, fmt.Errorf("failed to open paths file: %w", err)
 	}
 	defer pathsFile.Close()
 
-	err = dualStorage.GetPublicStorage().Upload(ctx, pathsKey, pathsFile, "application/json")
+	err = dualStorage.GetPublicStorage().Upload(processCtx, pathsKey, pathsFile, "application/json")
 	if err != nil {
-		setCompositionError(ctx, db, composition, fmt.Sprintf("failed to upload paths file: %v", err))
-		return fmt.Errorf("failed to upload paths file: %w", err)
+		return true, fmt.Errorf("failed to upload paths file: %w", err)
 	}
 
 	log.Info().Str("key", pathsKey).Msg("Paths file uploaded to bucket")
 
+	// Upload heatmap image
+	err = dualStorage.GetPublicStorage().Upload(processCtx, heatmapKey, &heatmapPNG, "image/png")
+	if err != nil {
+		return true, fmt.Errorf("failed to upload heatmap image: %w", err)
+	}
+
+	log.Info().Str("key", heatmapKey).Msg("Heatmap image uploaded to bucket")
+
 	uploadTime := time.Since(uploadStartTime)
 	log.Info().Msg("All files uploaded")
 
@@ -434,20 +719,52 @@ func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *s
 	composition.PreviewURL = null.StringFrom(previewKey)
 	composition.GcodeURL = null.StringFrom(gcodeKey)
 	composition.PathlistURL = null.StringFrom(pathsKey)
+	composition.DrillGcodeURL = null.StringFrom(drillGcodeKey)
+	composition.HeatmapURL = null.StringFrom(heatmapKey)
 	composition.ThreadLength = null.IntFrom(stats.ThreadLength)
 	composition.TotalLines = null.IntFrom(stats.TotalLines)
 
-	_, err = composition.Update(ctx, db, boil.Whitelist(
+	// Store the results unless the composition was cancelled meanwhile, the row
+	// is locked so a cancellation can't slip in before the update
+	tx, err := db.BeginTx(processCtx, nil)
+	if err != nil {
+		return true, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := models.Compositions(
+		qm.Select(models.CompositionColumns.Status),
+		models.CompositionWhere.ID.EQ(composition.ID),
+		qm.For("UPDATE"),
+	).One(processCtx, tx)
+	if err != nil {
+		return true, missingIsPermanent(fmt.Errorf("failed to lock composition: %w", err))
+	}
+	if current.Status == models.CompositionStatusEnumCANCELLED {
+		return true, errCompositionCancelled
+	}
+
+	_, err = composition.Update(processCtx, tx, boil.Whitelist(
 		models.CompositionColumns.Status,
 		models.CompositionColumns.PreviewURL,
 		models.CompositionColumns.GcodeURL,
 		models.CompositionColumns.PathlistURL,
+		models.CompositionColumns.DrillGcodeURL,
 		models.CompositionColumns.ThreadLength,
 		models.CompositionColumns.TotalLines,
+		models.CompositionColumns.SimilarityScore,
+		models.CompositionColumns.Segments,
+		models.CompositionColumns.Analytics,
+		models.CompositionColumns.HeatmapURL,
+		models.CompositionColumns.ContentHash,
 	))
 	if err != nil {
-		return fmt.Errorf("failed to update composition with results: %w", err)
+		return true, fmt.Errorf("failed to update composition with results: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return true, fmt.Errorf("failed to commit composition results: %w", err)
 	}
+	progress.complete()
 
 	log.Info().
 		Str("compositionID", composition.ID).
@@ -470,9 +787,109 @@ func processMessage(ctx context.Context, body []byte, db *sql.DB, dualStorage *s
 			previewGenerationTime,
 			uploadTime)
 
-	return nil
+	return true, nil
+}
+
+// verifyReproduction compares the paths with the earliest completed generation
+// of the same inputs. Generation is deterministic, a mismatch is a generator bug
+// and is logged without failing the composition. Devel builds share their
+// version with other generator code, so they are not checked.
+func verifyReproduction(ctx context.Context, db *sql.DB, dualStorage *storage.DualBucketStorage, contentHash string, paths []threadGenerator.Path) {
+	if !threadGenerator.ReproducibleVersion() {
+		log.Debug().Str("version", threadGenerator.Version).Msg("Generator version is not reproducible, skipping the reproduction check")
+		return
+	}
+
+	previous, err := models.Compositions(
+		models.CompositionWhere.ContentHash.EQ(null.StringFrom(contentHash)),
+		models.CompositionWhere.Status.EQ(models.CompositionStatusEnumCOMPLETE),
+		models.CompositionWhere.PathlistURL.IsNotNull(),
+		qm.OrderBy(models.CompositionColumns.CreatedAt),
+	).One(ctx, db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to look up an earlier generation of the same inputs")
+		return
+	}
+
+	reader, err := dualStorage.GetPublicStorage().Download(ctx, previous.PathlistURL.String)
+	if err != nil {
+		log.Warn().Err(err).Str("key", previous.PathlistURL.String).Msg("Failed to download the earlier paths list")
+		return
+	}
+	defer reader.Close()
+
+	document, err := threadGenerator.ReadPathList(reader)
+	if err != nil {
+		log.Warn().Err(err).Str("key", previous.PathlistURL.String).Msg("Failed to read the earlier paths list")
+		return
+	}
+
+	if !slices.Equal(document.Paths, paths) {
+		log.Error().
+			Str("contentHash", contentHash).
+			Str("previousCompositionID", previous.ID).
+			Msg("Rerun did not reproduce the paths of an earlier generation of the same inputs")
+		return
+	}
+	log.Info().
+		Str("contentHash", contentHash).
+		Str("previousCompositionID", previous.ID).
+		Msg("Rerun reproduced the paths of an earlier generation")
+}
+
+// missingIsPermanent marks the failure to find a deleted row as permanent,
+// retrying won't bring it back
+func missingIsPermanent(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return queue.Permanent(err)
+	}
+	return err
+}
+
+// failComposition records the error of the last attempt of a message on its
+// composition, and finishes its sweep
+func failComposition(ctx context.Context, db *sql.DB, dualStorage *storage.DualBucketStorage, body []byte, failure error) {
+	var message queue.CompositionProcessingMessage
+	if err := message.FromJSON(body); err != nil || message.CompositionID == "" {
+		return
+	}
+	composition, err := models.FindComposition(ctx, db, message.CompositionID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Error().Err(err).Str("compositionID", message.CompositionID).Msg("Failed to get composition to record its failure")
+		}
+		return
+	}
+
+	// A cancelled composition keeps its status
+	if composition.Status != models.CompositionStatusEnumCANCELLED {
+		setCompositionError(ctx, db, composition, failure.Error())
+	}
+	if composition.SweepID.Valid {
+		finishSweepComposition(ctx, db, dualStorage, composition.SweepID.String)
+	}
+}
+
+// setCompositionPending shows the composition of a message waiting for a retry
+// as pending again
+func setCompositionPending(ctx context.Context, db *sql.DB, body []byte) {
+	var message queue.CompositionProcessingMessage
+	if err := message.FromJSON(body); err != nil {
+		return
+	}
+	_, err := models.Compositions(
+		models.CompositionWhere.ID.EQ(message.CompositionID),
+		models.CompositionWhere.Status.EQ(models.CompositionStatusEnumPROCESSING),
+	).UpdateAll(ctx, db, models.M{models.CompositionColumns.Status: models.CompositionStatusEnumPENDING})
+	if err != nil {
+		log.Error().Err(err).Str("compositionID", message.CompositionID).Msg("Failed to reset composition status")
+	}
 }
 
+// setCompositionError marks a composition as failed with the error of its last attempt
 func setCompositionError(ctx context.Context, db *sql.DB, composition *models.Composition, errorMessage string) {
 	composition.Status = models.CompositionStatusEnumFAILED
 	composition.ErrorMessage = null.StringFrom(errorMessage)
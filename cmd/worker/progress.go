@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v8"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	"github.com/Damione1/thread-art-generator/core/pbx"
+)
+
+// progressInterval is the shortest time between two progress writes while
+// lines are placed, stage changes are always written
+const progressInterval = time.Second
+
+// stagePercents are the shares of the processing each stage ends at, the
+// generation takes most of the time
+var stagePercents = map[pbx.ProgressStage][2]float64{
+	pbx.ProgressStageDownload: {0, 5},
+	pbx.ProgressStageGenerate: {5, 85},
+	pbx.ProgressStageRender:   {85, 95},
+	pbx.ProgressStageUpload:   {95, 100},
+}
+
+// progressReporter stores the progress of a composition on its row, for the
+// clients polling its status. The generator reports from its hot loop, so the
+// writes go through a goroutine and only the latest progress waits for it,
+// a slow database never stalls the generation.
+type progressReporter struct {
+	ctx           context.Context
+	db            *sql.DB
+	compositionID string
+	start         time.Time
+	lastWrite     time.Time
+	progress      pbx.CompositionProgress
+	updates       chan []byte
+	done          chan struct{}
+}
+
+func newProgressReporter(ctx context.Context, db *sql.DB, compositionID string, maxLines int) *progressReporter {
+	r := &progressReporter{
+		ctx:           ctx,
+		db:            db,
+		compositionID: compositionID,
+		start:         time.Now(),
+		progress:      pbx.CompositionProgress{MaxLines: maxLines},
+		updates:       make(chan []byte, 1),
+		done:          make(chan struct{}),
+	}
+	go r.store()
+	return r
+}
+
+// stage records the start of a stage
+func (r *progressReporter) stage(stage pbx.ProgressStage) {
+	r.progress.Stage = stage
+	r.progress.Percent = stagePercents[stage][0]
+	r.write()
+}
+
+// lines records the lines placed by the generator, throttled to one write per
+// progress interval
+func (r *progressReporter) lines(placed, total int) {
+	r.progress.LinesPlaced = placed
+	r.progress.MaxLines = total
+	start, end := stagePercents[pbx.ProgressStageGenerate][0], stagePercents[pbx.ProgressStageGenerate][1]
+	r.progress.Percent = start + (end-start)*float64(min(placed, total))/float64(max(1, total))
+	if time.Since(r.lastWrite) >= progressInterval {
+		r.write()
+	}
+}
+
+// complete records the end of the processing
+func (r *progressReporter) complete() {
+	r.progress.Percent = 100
+	r.write()
+}
+
+// write stores the progress. The completion time is extrapolated from the
+// time spent so far once lines are placed, the download alone says nothing
+// of the generation time.
+func (r *progressReporter) write() {
+	now := time.Now()
+	r.lastWrite = now
+	r.progress.UpdatedAt = now
+	r.progress.EstimatedCompletion = nil
+	if r.progress.LinesPlaced > 0 && r.progress.Percent < 100 {
+		elapsed := now.Sub(r.start)
+		completion := now.Add(time.Duration(float64(elapsed) * (100 - r.progress.Percent) / r.progress.Percent))
+		r.progress.EstimatedCompletion = &completion
+	}
+
+	// Plain values, the encoding can't fail
+	encoded, _ := json.Marshal(r.progress)
+	// Replace the progress still waiting for the goroutine, if any. The
+	// reporter is the only sender so the buffer has room once drained.
+	select {
+	case r.updates <- encoded:
+	default:
+		select {
+		case <-r.updates:
+		default:
+		}
+		r.updates <- encoded
+	}
+}
+
+// store writes the progress updates to the composition row
+func (r *progressReporter) store() {
+	defer close(r.done)
+	for encoded := range r.updates {
+		_, err := models.Compositions(
+			models.CompositionWhere.ID.EQ(r.compositionID),
+		).UpdateAll(r.ctx, r.db, models.M{models.CompositionColumns.Progress: null.JSONFrom(encoded)})
+		if err != nil {
+			// Progress is informative, the processing goes on without it
+			log.Warn().Err(err).Str("compositionID", r.compositionID).Msg("Failed to update composition progress")
+		}
+	}
+}
+
+// stop waits for the last progress update to be written
+func (r *progressReporter) stop() {
+	close(r.updates)
+	<-r.done
+}
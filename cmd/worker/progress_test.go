@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Damione1/thread-art-generator/core/pbx"
+)
+
+const progressQuery = `UPDATE "compositions" SET "progress" = \$1 WHERE \("compositions"\."id" = \$2\)`
+
+// progressPercent matches a progress column holding the given percent
+type progressPercent float64
+
+func (p progressPercent) Match(value driver.Value) bool {
+	data, ok := value.([]byte)
+	if !ok {
+		return false
+	}
+	var progress pbx.CompositionProgress
+	return json.Unmarshal(data, &progress) == nil && progress.Percent == float64(p)
+}
+
+func TestProgressReporterDoesNotBlock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	delay := 200 * time.Millisecond
+	mock.ExpectExec(progressQuery).WithArgs(progressPercent(0), "composition").
+		WillDelayFor(delay).WillReturnResult(sqlmock.NewResult(0, 1))
+	// The updates reported during the slow write collapse into the latest one
+	mock.ExpectExec(progressQuery).WithArgs(progressPercent(100), "composition").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	progress := newProgressReporter(context.Background(), db, "composition", 1000)
+	progress.stage(pbx.ProgressStageDownload)
+	require.Eventually(t, func() bool { return len(progress.updates) == 0 }, time.Second, time.Millisecond)
+
+	start := time.Now()
+	progress.stage(pbx.ProgressStageGenerate)
+	for placed := 1; placed <= 1000; placed++ {
+		progress.lastWrite = time.Time{}
+		progress.lines(placed, 1000)
+	}
+	progress.complete()
+	require.Less(t, time.Since(start), delay/2, "reporting waited for the database")
+
+	progress.stop()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	"github.com/Damione1/thread-art-generator/core/pbx"
+	"github.com/Damione1/thread-art-generator/core/storage"
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+)
+
+const (
+	contactSheetColumns  = 6
+	contactSheetTileSize = 240
+)
+
+// finishSweepComposition moves a sweep forward once one of its compositions is
+// done. The sweep row is locked so only the last composition to finish claims
+// the contact sheet, it is built and uploaded once the lock is released.
+func finishSweepComposition(ctx context.Context, db *sql.DB, dualStorage *storage.DualBucketStorage, sweepID string) {
+	sweep, completed, err := updateSweep(ctx, db, sweepID)
+	if err == nil && sweep != nil {
+		err = completeSweep(ctx, db, dualStorage, sweep, completed)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("sweepID", sweepID).Msg("Failed to update parameter sweep")
+	}
+}
+
+// updateSweep counts the finished compositions of a sweep. Once they are all
+// finished, it returns the sweep with its completed compositions if this call
+// claimed the contact sheet. The claim stores the key of the sheet, its
+// URL is only shown once the sweep is complete.
+func updateSweep(ctx context.Context, db *sql.DB, sweepID string) (*models.ParameterSweep, []*models.Composition, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	sweep, err := models.ParameterSweeps(
+		models.ParameterSweepWhere.ID.EQ(sweepID),
+		qm.For("UPDATE"),
+	).One(ctx, tx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get parameter sweep: %w", err)
+	}
+	if sweep.Status == models.ParameterSweepStatusEnumCOMPLETE || sweep.Status == models.ParameterSweepStatusEnumFAILED {
+		return nil, nil, nil
+	}
+	if sweep.ContactSheetURL.Valid {
+		// Another worker is building the contact sheet
+		return nil, nil, nil
+	}
+
+	compositions, err := models.Compositions(
+		models.CompositionWhere.SweepID.EQ(null.StringFrom(sweepID)),
+	).All(ctx, tx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get sweep compositions: %w", err)
+	}
+
+	var completed []*models.Composition
+	finished := 0
+	for _, composition := range compositions {
+		switch composition.Status {
+		case models.CompositionStatusEnumCOMPLETE:
+			completed = append(completed, composition)
+			finished++
+		case models.CompositionStatusEnumFAILED, models.CompositionStatusEnumCANCELLED:
+			finished++
+		}
+	}
+
+	if finished < len(compositions) {
+		if sweep.Status == models.ParameterSweepStatusEnumPENDING {
+			sweep.Status = models.ParameterSweepStatusEnumPROCESSING
+			if _, err := sweep.Update(ctx, tx, boil.Whitelist(models.ParameterSweepColumns.Status)); err != nil {
+				return nil, nil, fmt.Errorf("failed to update sweep status: %w", err)
+			}
+		}
+		log.Info().Str("sweepID", sweepID).Int("finished", finished).Int("total", len(compositions)).Msg("Parameter sweep in progress")
+		return nil, nil, tx.Commit()
+	}
+
+	if len(completed) == 0 {
+		sweep.Status = models.ParameterSweepStatusEnumFAILED
+		sweep.ErrorMessage = null.StringFrom("every composition of the sweep failed")
+		if _, err := sweep.Update(ctx, tx, boil.Whitelist(models.ParameterSweepColumns.Status, models.ParameterSweepColumns.ErrorMessage)); err != nil {
+			return nil, nil, fmt.Errorf("failed to update sweep status: %w", err)
+		}
+		return nil, nil, tx.Commit()
+	}
+
+	art, err := models.Arts(models.ArtWhere.ID.EQ(sweep.ArtID)).One(ctx, tx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get art: %w", err)
+	}
+	sweep.Status = models.ParameterSweepStatusEnumPROCESSING
+	sweep.ContactSheetURL = null.StringFrom(fmt.Sprintf("users/%s/arts/%s/sweeps/%s/contact_sheet.png", art.AuthorID, art.ID, sweep.ID))
+	if _, err := sweep.Update(ctx, tx, boil.Whitelist(models.ParameterSweepColumns.Status, models.ParameterSweepColumns.ContactSheetURL)); err != nil {
+		return nil, nil, fmt.Errorf("failed to claim contact sheet: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit contact sheet claim: %w", err)
+	}
+	return sweep, completed, nil
+}
+
+// completeSweep builds and uploads the contact sheet a call of updateSweep
+// claimed, then completes the sweep, or fails it if the sheet can't be built
+func completeSweep(ctx context.Context, db *sql.DB, dualStorage *storage.DualBucketStorage, sweep *models.ParameterSweep, completed []*models.Composition) error {
+	if err := uploadContactSheet(ctx, dualStorage, sweep, completed); err != nil {
+		sweep.Status = models.ParameterSweepStatusEnumFAILED
+		sweep.ErrorMessage = null.StringFrom(fmt.Sprintf("failed to build contact sheet: %v", err))
+		sweep.ContactSheetURL = null.String{}
+		if _, updateErr := sweep.Update(ctx, db, boil.Whitelist(models.ParameterSweepColumns.Status, models.ParameterSweepColumns.ErrorMessage, models.ParameterSweepColumns.ContactSheetURL)); updateErr != nil {
+			return fmt.Errorf("failed to update sweep status: %w", updateErr)
+		}
+		return err
+	}
+
+	sweep.Status = models.ParameterSweepStatusEnumCOMPLETE
+	if _, err := sweep.Update(ctx, db, boil.Whitelist(models.ParameterSweepColumns.Status)); err != nil {
+		return fmt.Errorf("failed to update sweep with results: %w", err)
+	}
+
+	log.Info().
+		Str("sweepID", sweep.ID).
+		Int("completed", len(completed)).
+		Str("contactSheet", sweep.ContactSheetURL.String).
+		Msg("🎉 Parameter sweep completed")
+	return nil
+}
+
+// uploadContactSheet builds the contact sheet of the completed compositions,
+// ranked by score with the swept values in the captions, and uploads it under
+// the key the sweep claimed
+func uploadContactSheet(ctx context.Context, dualStorage *storage.DualBucketStorage, sweep *models.ParameterSweep, completed []*models.Composition) error {
+	ranges, err := pbx.ParseSweepRanges(sweep.Ranges)
+	if err != nil {
+		return err
+	}
+
+	pbx.RankSweepCompositions(completed)
+	tiles := make([]threadGenerator.ContactSheetTile, 0, len(completed))
+	for i, composition := range completed {
+		preview, err := downloadPreview(ctx, dualStorage, composition)
+		if err != nil {
+			return err
+		}
+
+		values := make([]string, 0, len(ranges))
+		for _, sweepRange := range ranges {
+			values = append(values, fmt.Sprintf("%s %g", sweepRange.Parameter, pbx.SweepParameterValue(composition, sweepRange.Parameter)))
+		}
+		tiles = append(tiles, threadGenerator.ContactSheetTile{
+			Image:   preview,
+			Caption: fmt.Sprintf("#%d  score %.3f\n%s", i+1, composition.SimilarityScore.Float64, strings.Join(values, "\n")),
+		})
+	}
+
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, threadGenerator.ContactSheet(tiles, contactSheetColumns, contactSheetTileSize)); err != nil {
+		return fmt.Errorf("failed to encode contact sheet: %w", err)
+	}
+
+	if err := dualStorage.GetPublicStorage().Upload(ctx, sweep.ContactSheetURL.String, &encoded, "image/png"); err != nil {
+		return fmt.Errorf("failed to upload contact sheet: %w", err)
+	}
+	return nil
+}
+
+func downloadPreview(ctx context.Context, dualStorage *storage.DualBucketStorage, composition *models.Composition) (image.Image, error) {
+	reader, err := dualStorage.GetPublicStorage().Download(ctx, composition.PreviewURL.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download preview of composition %s: %w", composition.ID, err)
+	}
+	defer reader.Close()
+
+	preview, err := png.Decode(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode preview of composition %s: %w", composition.ID, err)
+	}
+	return preview, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	"github.com/Damione1/thread-art-generator/core/storage"
+)
+
+const (
+	sweepQuery        = `SELECT "parameter_sweeps"\.\* FROM "parameter_sweeps" WHERE \("parameter_sweeps"\."id" = \$1\) LIMIT 1 FOR UPDATE`
+	sweepCompositions = `SELECT "compositions"\.\* FROM "compositions" WHERE \("compositions"\."sweep_id" = \$1\)`
+	contactSheetKey   = "users/author/arts/art/sweeps/sweep/contact_sheet.png"
+)
+
+var sweepColumns = []string{"id", "art_id", "status", "ranges", "contact_sheet_url"}
+
+// expectFinishedSweep expects the lookup of a sweep whose compositions are all complete
+func expectFinishedSweep(mock sqlmock.Sqlmock, contactSheetURL any) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(sweepQuery).WithArgs("sweep").
+		WillReturnRows(sqlmock.NewRows(sweepColumns).
+			AddRow("sweep", "art", models.ParameterSweepStatusEnumPROCESSING, `[{"parameter":"max_paths","min":1000,"max":2000,"step":1000}]`, contactSheetURL))
+	if contactSheetURL != nil {
+		return
+	}
+	mock.ExpectQuery(sweepCompositions).WithArgs("sweep").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "max_paths", "preview_url", "similarity_score"}).
+			AddRow("1", models.CompositionStatusEnumCOMPLETE, 1000, "previews/1.png", 0.5).
+			AddRow("2", models.CompositionStatusEnumCOMPLETE, 2000, "previews/2.png", 0.7))
+}
+
+func TestUpdateSweepClaimsContactSheet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	// The last composition to finish claims the contact sheet and releases the
+	// sweep before building it
+	expectFinishedSweep(mock, nil)
+	mock.ExpectQuery(`SELECT "arts"\.\* FROM "arts"`).WithArgs("art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id"}).AddRow("art", "author"))
+	mock.ExpectExec(`UPDATE "parameter_sweeps" SET "status"=\$1,"contact_sheet_url"=\$2`).
+		WithArgs(models.ParameterSweepStatusEnumPROCESSING, contactSheetKey, "sweep").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	sweep, completed, err := updateSweep(context.Background(), db, "sweep")
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, contactSheetKey, sweep.ContactSheetURL.String)
+	require.Len(t, completed, 2)
+
+	// A later call sees the claim and leaves the contact sheet to its builder
+	expectFinishedSweep(mock, contactSheetKey)
+	mock.ExpectRollback()
+
+	sweep, _, err = updateSweep(context.Background(), db, "sweep")
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Nil(t, sweep)
+}
+
+func TestCompleteSweep(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	dualStorage := storage.NewMemoryDualBucketStorage()
+	for _, key := range []string{"previews/1.png", "previews/2.png"} {
+		var encoded bytes.Buffer
+		require.NoError(t, png.Encode(&encoded, image.NewGray(image.Rect(0, 0, 10, 10))))
+		require.NoError(t, dualStorage.UploadPublic(context.Background(), key, &encoded, "image/png"))
+	}
+
+	expectFinishedSweep(mock, nil)
+	mock.ExpectQuery(`SELECT "arts"\.\* FROM "arts"`).WithArgs("art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id"}).AddRow("art", "author"))
+	mock.ExpectExec(`UPDATE "parameter_sweeps"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	sweep, completed, err := updateSweep(context.Background(), db, "sweep")
+	require.NoError(t, err)
+
+	// The sheet is built outside the transaction, a short update completes the sweep
+	mock.ExpectExec(`UPDATE "parameter_sweeps" SET "status"=\$1`).
+		WithArgs(models.ParameterSweepStatusEnumCOMPLETE, "sweep").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, completeSweep(context.Background(), db, dualStorage, sweep, completed))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	exists, err := dualStorage.GetPublicStorage().Exists(context.Background(), contactSheetKey)
+	require.NoError(t, err)
+	require.True(t, exists)
+}
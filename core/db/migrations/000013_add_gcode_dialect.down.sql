@@ -0,0 +1,8 @@
+-- Migration 000013: add_gcode_dialect (down)
+
+-- Remove dialect column
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS gcode_dialect;
+
+-- Drop enum type
+DROP TYPE IF EXISTS gcode_dialect_enum;
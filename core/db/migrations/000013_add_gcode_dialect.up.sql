@@ -0,0 +1,15 @@
+-- Migration 000013: add_gcode_dialect (up)
+
+-- Create enum type for the firmware flavour of generated G-code
+CREATE TYPE gcode_dialect_enum AS ENUM (
+    'FLUIDNC', -- FluidNC, the firmware running on the reference machine
+    'GRBL', -- GRBL based controllers
+    'MARLIN' -- Marlin based controllers
+);
+
+-- Add dialect column to compositions table
+ALTER TABLE compositions
+ADD COLUMN gcode_dialect gcode_dialect_enum NOT NULL DEFAULT 'FLUIDNC';
+
+-- Add comment
+COMMENT ON COLUMN compositions.gcode_dialect IS 'Firmware flavour the G-code is generated for';
@@ -0,0 +1,5 @@
+-- Migration 000014: add_drill_gcode_url (down)
+
+-- Remove drilling program column
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS drill_gcode_url;
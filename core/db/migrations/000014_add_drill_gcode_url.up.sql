@@ -0,0 +1,8 @@
+-- Migration 000014: add_drill_gcode_url (up)
+
+-- Add drilling program column to compositions table
+ALTER TABLE compositions
+ADD COLUMN drill_gcode_url TEXT;
+
+-- Add comment
+COMMENT ON COLUMN compositions.drill_gcode_url IS 'URL to download the nail hole drilling GCode file';
@@ -0,0 +1,18 @@
+-- Migration 000015: add_parameter_sweeps (down)
+
+-- Remove sweep columns from compositions
+DROP INDEX IF EXISTS idx_compositions_sweep_id;
+
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS similarity_score;
+
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS sweep_id;
+
+-- Drop parameter sweeps table
+DROP INDEX IF EXISTS idx_parameter_sweeps_art_id;
+
+DROP TABLE IF EXISTS parameter_sweeps;
+
+-- Drop enum type
+DROP TYPE IF EXISTS parameter_sweep_status_enum;
@@ -0,0 +1,47 @@
+-- Migration 000015: add_parameter_sweeps (up)
+
+-- Create enum type for parameter sweep status
+CREATE TYPE parameter_sweep_status_enum AS ENUM (
+    'PENDING', -- Sweep created, child compositions waiting to be processed
+    'PROCESSING', -- Child compositions are being processed
+    'COMPLETE', -- Every child composition is finished and scored
+    'FAILED' -- The sweep could not be completed
+);
+
+-- Create parameter sweeps table
+CREATE TABLE
+    parameter_sweeps (
+        id UUID DEFAULT uuid_generate_v1mc () PRIMARY KEY,
+        art_id UUID NOT NULL REFERENCES arts (id) ON DELETE CASCADE,
+        status parameter_sweep_status_enum NOT NULL DEFAULT 'PENDING',
+        ranges JSONB NOT NULL,
+        contact_sheet_url TEXT,
+        error_message TEXT,
+        created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW () NOT NULL,
+            updated_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW () NOT NULL
+    );
+
+-- Add indexes
+CREATE INDEX idx_parameter_sweeps_art_id ON parameter_sweeps (art_id);
+
+-- Link compositions to the sweep that created them
+ALTER TABLE compositions
+ADD COLUMN sweep_id UUID REFERENCES parameter_sweeps (id) ON DELETE CASCADE;
+
+ALTER TABLE compositions
+ADD COLUMN similarity_score DOUBLE PRECISION;
+
+CREATE INDEX idx_compositions_sweep_id ON compositions (sweep_id);
+
+-- Add comments
+COMMENT ON TABLE parameter_sweeps IS 'Parameter sweeps generating one composition per combination of settings';
+COMMENT ON COLUMN parameter_sweeps.status IS 'Current status of the sweep';
+COMMENT ON COLUMN parameter_sweeps.ranges IS 'Swept parameters with their min, max and step';
+COMMENT ON COLUMN parameter_sweeps.contact_sheet_url IS 'URL to the contact sheet of all previews, ranked by score';
+COMMENT ON COLUMN parameter_sweeps.error_message IS 'Error message if the sweep failed';
+COMMENT ON COLUMN compositions.sweep_id IS 'Parameter sweep the composition was created by';
+COMMENT ON COLUMN compositions.similarity_score IS 'Similarity between the preview and the source image, from 0 to 1';
@@ -0,0 +1,8 @@
+-- Migration 000016: add_spool_segments (down)
+
+-- Remove spool planning columns from compositions
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS segments;
+
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS spool_length;
@@ -0,0 +1,12 @@
+-- Migration 000016: add_spool_segments (up)
+
+-- Add spool planning columns to compositions
+ALTER TABLE compositions
+ADD COLUMN spool_length DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+ALTER TABLE compositions
+ADD COLUMN segments JSONB;
+
+-- Add comments
+COMMENT ON COLUMN compositions.spool_length IS 'Length of thread on one spool in meters, 0 for unlimited';
+COMMENT ON COLUMN compositions.segments IS 'Paths strung with each spool, with their starting nail and thread length';
@@ -0,0 +1,5 @@
+-- Migration 000017: add_nail_rings (down)
+
+-- Remove nail rings column
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS rings;
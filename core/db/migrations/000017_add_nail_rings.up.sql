@@ -0,0 +1,8 @@
+-- Migration 000017: add_nail_rings (up)
+
+-- Add concentric nail rings to compositions
+ALTER TABLE compositions
+ADD COLUMN rings JSONB;
+
+-- Add comment
+COMMENT ON COLUMN compositions.rings IS 'Concentric nail rings with their nail count, radius and minimum difference, NULL for a single ring';
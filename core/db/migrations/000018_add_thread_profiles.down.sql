@@ -0,0 +1,12 @@
+-- Migration 000018: add_thread_profiles (down)
+
+-- Remove thread profile column from compositions
+DROP INDEX IF EXISTS idx_compositions_thread_profile_id;
+
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS thread_profile_id;
+
+-- Drop thread profiles table
+DROP INDEX IF EXISTS idx_thread_profiles_user_id;
+
+DROP TABLE IF EXISTS thread_profiles;
@@ -0,0 +1,38 @@
+-- Migration 000018: add_thread_profiles (up)
+
+-- Create thread profiles table
+CREATE TABLE
+    thread_profiles (
+        id UUID DEFAULT uuid_generate_v1mc () PRIMARY KEY,
+        user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        name VARCHAR(255) NOT NULL,
+        opacity DOUBLE PRECISION NOT NULL,
+        thread_width DOUBLE PRECISION NOT NULL,
+        bands JSONB NOT NULL,
+        fit_error DOUBLE PRECISION NOT NULL,
+        created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW () NOT NULL,
+            updated_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW () NOT NULL,
+            UNIQUE (user_id, name)
+    );
+
+-- Add indexes
+CREATE INDEX idx_thread_profiles_user_id ON thread_profiles (user_id);
+
+-- Link compositions to the thread profile they are rendered with
+ALTER TABLE compositions
+ADD COLUMN thread_profile_id UUID REFERENCES thread_profiles (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_compositions_thread_profile_id ON compositions (thread_profile_id);
+
+-- Add comments
+COMMENT ON TABLE thread_profiles IS 'Thread darkness models fitted on the photo of a calibration test piece';
+COMMENT ON COLUMN thread_profiles.name IS 'Name of the profile, unique per user';
+COMMENT ON COLUMN thread_profiles.opacity IS 'Darkening of the background under a thread, from 0 to 1';
+COMMENT ON COLUMN thread_profiles.thread_width IS 'Apparent thread width in mm';
+COMMENT ON COLUMN thread_profiles.bands IS 'Measured bands of the calibration photo with their density and brightness';
+COMMENT ON COLUMN thread_profiles.fit_error IS 'Root mean square error of the fitted model on the measured bands';
+COMMENT ON COLUMN compositions.thread_profile_id IS 'Thread profile used to score lines and render the preview';
@@ -0,0 +1,5 @@
+-- Migration 000019: add_linear_light (down)
+
+-- Remove linear light column
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS linear_light;
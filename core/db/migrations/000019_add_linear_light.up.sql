@@ -0,0 +1,8 @@
+-- Migration 000019: add_linear_light (up)
+
+-- Add linear light processing option to compositions
+ALTER TABLE compositions
+ADD COLUMN linear_light BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Add comment
+COMMENT ON COLUMN compositions.linear_light IS 'Whether thread darkening is modelled in linear light instead of sRGB gray levels';
@@ -0,0 +1,6 @@
+-- Migration 000020: add_composition_analytics (down)
+
+-- Remove analytics columns
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS heatmap_url,
+DROP COLUMN IF EXISTS analytics;
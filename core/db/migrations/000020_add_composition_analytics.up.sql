@@ -0,0 +1,10 @@
+-- Migration 000020: add_composition_analytics (up)
+
+-- Add nail usage and line density analytics to compositions
+ALTER TABLE compositions
+ADD COLUMN analytics JSONB,
+ADD COLUMN heatmap_url TEXT;
+
+-- Add comments
+COMMENT ON COLUMN compositions.analytics IS 'Nail wraps histogram, most loaded nails and chord length distribution of the paths list';
+COMMENT ON COLUMN compositions.heatmap_url IS 'Storage key of the line density heatmap image';
@@ -0,0 +1,8 @@
+-- Migration 000021: add_input_type (down)
+
+-- Remove input type column
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS input_type;
+
+-- Drop enum type
+DROP TYPE IF EXISTS input_type_enum;
@@ -0,0 +1,15 @@
+-- Migration 000021: add_input_type (up)
+
+-- Create enum type for the kind of source the threads reproduce
+CREATE TYPE input_type_enum AS ENUM (
+    'PHOTO', -- Photographs, the threads match the tones
+    'LOGO', -- Logos, the threads follow the outlines of the filled shapes
+    'LINE_DRAWING' -- Line drawings, the threads follow the center lines of the strokes
+);
+
+-- Add input type column to compositions table
+ALTER TABLE compositions
+ADD COLUMN input_type input_type_enum NOT NULL DEFAULT 'PHOTO';
+
+-- Add comment
+COMMENT ON COLUMN compositions.input_type IS 'Kind of source the threads reproduce';
@@ -0,0 +1,9 @@
+-- Migration 000022: add_reproducibility (down)
+
+-- Drop index
+DROP INDEX IF EXISTS idx_compositions_content_hash;
+
+-- Remove reproducibility columns
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS content_hash,
+DROP COLUMN IF EXISTS seed;
@@ -0,0 +1,13 @@
+-- Migration 000022: add_reproducibility (up)
+
+-- Add the tie breaking seed and the content hash of the generation inputs
+ALTER TABLE compositions
+ADD COLUMN seed BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN content_hash VARCHAR(64);
+
+-- Find earlier generations of the same inputs to verify reruns
+CREATE INDEX idx_compositions_content_hash ON compositions (content_hash);
+
+-- Add comments
+COMMENT ON COLUMN compositions.seed IS 'Seed drawing among lines of equal weight, 0 to pick the lowest nail';
+COMMENT ON COLUMN compositions.content_hash IS 'SHA-256 of the source image, configuration and generator version';
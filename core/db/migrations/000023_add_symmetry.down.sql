@@ -0,0 +1,6 @@
+-- Migration 000023: add_symmetry (down)
+
+-- Remove symmetry columns
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS mirror_symmetry,
+DROP COLUMN IF EXISTS symmetry_fold;
@@ -0,0 +1,10 @@
+-- Migration 000023: add_symmetry (up)
+
+-- Add the symmetry constraints of the generated piece
+ALTER TABLE compositions
+ADD COLUMN symmetry_fold INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN mirror_symmetry BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Add comments
+COMMENT ON COLUMN compositions.symmetry_fold IS 'Rotational symmetry order of the piece, 0 or 1 for none';
+COMMENT ON COLUMN compositions.mirror_symmetry IS 'Whether the piece is mirrored across its vertical axis';
@@ -0,0 +1,5 @@
+-- Migration 000024: add_composition_progress (down)
+
+-- Remove progress column
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS progress;
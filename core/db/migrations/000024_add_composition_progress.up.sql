@@ -0,0 +1,8 @@
+-- Migration 000024: add_composition_progress (up)
+
+-- Add the progress the worker reports while processing a composition
+ALTER TABLE compositions
+ADD COLUMN progress JSONB;
+
+-- Add comment
+COMMENT ON COLUMN compositions.progress IS 'Stage, percent done, lines placed and estimated completion of the processing';
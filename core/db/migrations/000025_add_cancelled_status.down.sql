@@ -0,0 +1,20 @@
+-- Migration 000025: add_cancelled_status (down)
+
+-- Enum values can't be dropped, cancelled compositions become failed and the
+-- type is recreated without the value
+UPDATE compositions SET status = 'FAILED' WHERE status = 'CANCELLED';
+
+ALTER TYPE composition_status_enum RENAME TO composition_status_enum_old;
+
+CREATE TYPE composition_status_enum AS ENUM (
+    'PENDING', -- Composition created but waiting to be processed
+    'PROCESSING', -- Composition is currently being processed
+    'COMPLETE', -- Composition has been successfully processed
+    'FAILED' -- Composition processing failed
+);
+
+ALTER TABLE compositions ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE compositions ALTER COLUMN status TYPE composition_status_enum USING status::text::composition_status_enum;
+ALTER TABLE compositions ALTER COLUMN status SET DEFAULT 'PENDING';
+
+DROP TYPE composition_status_enum_old;
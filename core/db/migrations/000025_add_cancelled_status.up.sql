@@ -0,0 +1,4 @@
+-- Migration 000025: add_cancelled_status (up)
+
+-- Compositions stopped by their author before they finished
+ALTER TYPE composition_status_enum ADD VALUE IF NOT EXISTS 'CANCELLED';
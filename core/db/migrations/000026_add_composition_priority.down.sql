@@ -0,0 +1,8 @@
+-- Migration 000026: add_composition_priority (down)
+
+-- Remove priority column
+ALTER TABLE compositions
+DROP COLUMN IF EXISTS priority;
+
+-- Drop enum type
+DROP TYPE IF EXISTS composition_priority_enum;
@@ -0,0 +1,18 @@
+-- Migration 000026: add_composition_priority (up)
+
+-- Create enum type for the processing priority of compositions
+CREATE TYPE composition_priority_enum AS ENUM (
+    'INTERACTIVE', -- Quick previews a user waits for
+    'RENDER', -- Full renders
+    'BATCH' -- Parameter sweeps and other bulk work
+);
+
+-- Add priority column to compositions table
+ALTER TABLE compositions
+ADD COLUMN priority composition_priority_enum NOT NULL DEFAULT 'RENDER';
+
+-- Sweep compositions are batch work
+UPDATE compositions SET priority = 'BATCH' WHERE sweep_id IS NOT NULL;
+
+-- Add comment
+COMMENT ON COLUMN compositions.priority IS 'Processing priority, picks the queue the composition is sent to';
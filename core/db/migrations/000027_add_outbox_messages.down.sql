@@ -0,0 +1,6 @@
+-- Migration 000027: add_outbox_messages (down)
+
+-- Drop outbox table
+DROP INDEX IF EXISTS idx_outbox_messages_next_attempt_at;
+
+DROP TABLE IF EXISTS outbox_messages;
@@ -0,0 +1,28 @@
+-- Migration 000027: add_outbox_messages (up)
+
+-- Create outbox table for the queue messages published by the relay
+CREATE TABLE
+    outbox_messages (
+        id UUID DEFAULT uuid_generate_v1mc () PRIMARY KEY,
+        queue VARCHAR(255) NOT NULL,
+        payload BYTEA NOT NULL,
+        attempts INTEGER NOT NULL DEFAULT 0,
+        last_error TEXT,
+        next_attempt_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW () NOT NULL,
+            created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW () NOT NULL
+    );
+
+-- Add indexes
+CREATE INDEX idx_outbox_messages_next_attempt_at ON outbox_messages (next_attempt_at);
+
+-- Add comments
+COMMENT ON TABLE outbox_messages IS 'Queue messages written with the change they announce, deleted once published';
+COMMENT ON COLUMN outbox_messages.queue IS 'Queue the message is published to';
+COMMENT ON COLUMN outbox_messages.payload IS 'Body of the message';
+COMMENT ON COLUMN outbox_messages.attempts IS 'Failed attempts to publish the message';
+COMMENT ON COLUMN outbox_messages.last_error IS 'Error of the last failed attempt';
+COMMENT ON COLUMN outbox_messages.next_attempt_at IS 'Time the relay publishes the message at the earliest';
@@ -0,0 +1,19 @@
+-- Migration 000028: add_marlin_ijk_dialect (down)
+
+-- Enum values can't be dropped, Marlin 2.0.x compositions fall back to Marlin
+-- and the type is recreated without the value
+UPDATE compositions SET gcode_dialect = 'MARLIN' WHERE gcode_dialect = 'MARLIN_IJK';
+
+ALTER TYPE gcode_dialect_enum RENAME TO gcode_dialect_enum_old;
+
+CREATE TYPE gcode_dialect_enum AS ENUM (
+    'FLUIDNC', -- FluidNC, the firmware running on the reference machine
+    'GRBL', -- GRBL based controllers
+    'MARLIN' -- Marlin based controllers
+);
+
+ALTER TABLE compositions ALTER COLUMN gcode_dialect DROP DEFAULT;
+ALTER TABLE compositions ALTER COLUMN gcode_dialect TYPE gcode_dialect_enum USING gcode_dialect::text::gcode_dialect_enum;
+ALTER TABLE compositions ALTER COLUMN gcode_dialect SET DEFAULT 'FLUIDNC';
+
+DROP TYPE gcode_dialect_enum_old;
@@ -0,0 +1,4 @@
+-- Migration 000028: add_marlin_ijk_dialect (up)
+
+-- Marlin 2.0.x controllers, where the 4th to 6th axes are always named I, J and K
+ALTER TYPE gcode_dialect_enum ADD VALUE IF NOT EXISTS 'MARLIN_IJK';
@@ -137,20 +137,23 @@ var ArtWhere = struct {
 
 // ArtRels is where relationship names are stored.
 var ArtRels = struct {
-	Author        string
-	ArtVariations string
-	Compositions  string
+	Author          string
+	ArtVariations   string
+	Compositions    string
+	ParameterSweeps string
 }{
-	Author:        "Author",
-	ArtVariations: "ArtVariations",
-	Compositions:  "Compositions",
+	Author:          "Author",
+	ArtVariations:   "ArtVariations",
+	Compositions:    "Compositions",
+	ParameterSweeps: "ParameterSweeps",
 }
 
 // artR is where relationships are stored.
 type artR struct {
-	Author        *User             `boil:"Author" json:"Author" toml:"Author" yaml:"Author"`
-	ArtVariations ArtVariationSlice `boil:"ArtVariations" json:"ArtVariations" toml:"ArtVariations" yaml:"ArtVariations"`
-	Compositions  CompositionSlice  `boil:"Compositions" json:"Compositions" toml:"Compositions" yaml:"Compositions"`
+	Author          *User               `boil:"Author" json:"Author" toml:"Author" yaml:"Author"`
+	ArtVariations   ArtVariationSlice   `boil:"ArtVariations" json:"ArtVariations" toml:"ArtVariations" yaml:"ArtVariations"`
+	Compositions    CompositionSlice    `boil:"Compositions" json:"Compositions" toml:"Compositions" yaml:"Compositions"`
+	ParameterSweeps ParameterSweepSlice `boil:"ParameterSweeps" json:"ParameterSweeps" toml:"ParameterSweeps" yaml:"ParameterSweeps"`
 }
 
 // NewStruct creates a new relationship struct
@@ -179,6 +182,13 @@ func (r *artR) GetCompositions() CompositionSlice {
 	return r.Compositions
 }
 
+func (r *artR) GetParameterSweeps() ParameterSweepSlice {
+	if r == nil {
+		return nil
+	}
+	return r.ParameterSweeps
+}
+
 // artL is where Load methods for each relationship are stored.
 type artL struct{}
 
@@ -534,6 +544,20 @@ func (o *Art) Compositions(mods ...qm.QueryMod) compositionQuery {
 	return Compositions(queryMods...)
 }
 
+// ParameterSweeps retrieves all the parameter_sweep's ParameterSweeps with an executor.
+func (o *Art) ParameterSweeps(mods ...qm.QueryMod) parameterSweepQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"parameter_sweeps\".\"art_id\"=?", o.ID),
+	)
+
+	return ParameterSweeps(queryMods...)
+}
+
 // LoadAuthor allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for an N-1 relationship.
 func (artL) LoadAuthor(ctx context.Context, e boil.ContextExecutor, singular bool, maybeArt interface{}, mods queries.Applicator) error {
@@ -880,6 +904,119 @@ func (artL) LoadCompositions(ctx context.Context, e boil.ContextExecutor, singul
 	return nil
 }
 
+// LoadParameterSweeps allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (artL) LoadParameterSweeps(ctx context.Context, e boil.ContextExecutor, singular bool, maybeArt interface{}, mods queries.Applicator) error {
+	var slice []*Art
+	var object *Art
+
+	if singular {
+		var ok bool
+		object, ok = maybeArt.(*Art)
+		if !ok {
+			object = new(Art)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeArt)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeArt))
+			}
+		}
+	} else {
+		s, ok := maybeArt.(*[]*Art)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeArt)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeArt))
+			}
+		}
+	}
+
+	args := make(map[interface{}]struct{})
+	if singular {
+		if object.R == nil {
+			object.R = &artR{}
+		}
+		args[object.ID] = struct{}{}
+	} else {
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &artR{}
+			}
+			args[obj.ID] = struct{}{}
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	argsSlice := make([]interface{}, len(args))
+	i := 0
+	for arg := range args {
+		argsSlice[i] = arg
+		i++
+	}
+
+	query := NewQuery(
+		qm.From(`parameter_sweeps`),
+		qm.WhereIn(`parameter_sweeps.art_id in ?`, argsSlice...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load parameter_sweeps")
+	}
+
+	var resultSlice []*ParameterSweep
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice parameter_sweeps")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on parameter_sweeps")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for parameter_sweeps")
+	}
+
+	if len(parameterSweepAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.ParameterSweeps = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &parameterSweepR{}
+			}
+			foreign.R.Art = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.ArtID {
+				local.R.ParameterSweeps = append(local.R.ParameterSweeps, foreign)
+				if foreign.R == nil {
+					foreign.R = &parameterSweepR{}
+				}
+				foreign.R.Art = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // SetAuthor of the art to the related item.
 // Sets o.R.Author to related.
 // Adds o to related.R.AuthorArts.
@@ -1107,6 +1244,59 @@ func (o *Art) AddCompositions(ctx context.Context, exec boil.ContextExecutor, in
 	return nil
 }
 
+// AddParameterSweeps adds the given related objects to the existing relationships
+// of the art, optionally inserting them as new records.
+// Appends related to o.R.ParameterSweeps.
+// Sets related.R.Art appropriately.
+func (o *Art) AddParameterSweeps(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*ParameterSweep) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.ArtID = o.ID
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"parameter_sweeps\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"art_id"}),
+				strmangle.WhereClause("\"", "\"", 2, parameterSweepPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.ArtID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &artR{
+			ParameterSweeps: related,
+		}
+	} else {
+		o.R.ParameterSweeps = append(o.R.ParameterSweeps, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &parameterSweepR{
+				Art: o,
+			}
+		} else {
+			rel.R.Art = o
+		}
+	}
+	return nil
+}
+
 // Arts retrieves all the records using an executor.
 func Arts(mods ...qm.QueryMod) artQuery {
 	mods = append(mods, qm.From("\"arts\""))
@@ -8,15 +8,21 @@ var TableNames = struct {
 	ArtVariations      string
 	Arts               string
 	Compositions       string
+	OutboxMessages     string
+	ParameterSweeps    string
 	SchemaMigrations   string
 	Sessions           string
+	ThreadProfiles     string
 	Users              string
 }{
 	AccountActivations: "account_activations",
 	ArtVariations:      "art_variations",
 	Arts:               "arts",
 	Compositions:       "compositions",
+	OutboxMessages:     "outbox_messages",
+	ParameterSweeps:    "parameter_sweeps",
 	SchemaMigrations:   "schema_migrations",
 	Sessions:           "sessions",
+	ThreadProfiles:     "thread_profiles",
 	Users:              "users",
 }
@@ -111,6 +111,7 @@ const (
 	CompositionStatusEnumPROCESSING CompositionStatusEnum = "PROCESSING"
 	CompositionStatusEnumCOMPLETE   CompositionStatusEnum = "COMPLETE"
 	CompositionStatusEnumFAILED     CompositionStatusEnum = "FAILED"
+	CompositionStatusEnumCANCELLED  CompositionStatusEnum = "CANCELLED"
 )
 
 func AllCompositionStatusEnum() []CompositionStatusEnum {
@@ -119,12 +120,13 @@ func AllCompositionStatusEnum() []CompositionStatusEnum {
 		CompositionStatusEnumPROCESSING,
 		CompositionStatusEnumCOMPLETE,
 		CompositionStatusEnumFAILED,
+		CompositionStatusEnumCANCELLED,
 	}
 }
 
 func (e CompositionStatusEnum) IsValid() error {
 	switch e {
-	case CompositionStatusEnumPENDING, CompositionStatusEnumPROCESSING, CompositionStatusEnumCOMPLETE, CompositionStatusEnumFAILED:
+	case CompositionStatusEnumPENDING, CompositionStatusEnumPROCESSING, CompositionStatusEnumCOMPLETE, CompositionStatusEnumFAILED, CompositionStatusEnumCANCELLED:
 		return nil
 	default:
 		return errors.New("enum is not valid")
@@ -145,6 +147,192 @@ func (e CompositionStatusEnum) Ordinal() int {
 		return 2
 	case CompositionStatusEnumFAILED:
 		return 3
+	case CompositionStatusEnumCANCELLED:
+		return 4
+
+	default:
+		panic(errors.New("enum is not valid"))
+	}
+}
+
+type GcodeDialectEnum string
+
+// Enum values for GcodeDialectEnum
+const (
+	GcodeDialectEnumFLUIDNC    GcodeDialectEnum = "FLUIDNC"
+	GcodeDialectEnumGRBL       GcodeDialectEnum = "GRBL"
+	GcodeDialectEnumMARLIN     GcodeDialectEnum = "MARLIN"
+	GcodeDialectEnumMARLIN_IJK GcodeDialectEnum = "MARLIN_IJK"
+)
+
+func AllGcodeDialectEnum() []GcodeDialectEnum {
+	return []GcodeDialectEnum{
+		GcodeDialectEnumFLUIDNC,
+		GcodeDialectEnumGRBL,
+		GcodeDialectEnumMARLIN,
+		GcodeDialectEnumMARLIN_IJK,
+	}
+}
+
+func (e GcodeDialectEnum) IsValid() error {
+	switch e {
+	case GcodeDialectEnumFLUIDNC, GcodeDialectEnumGRBL, GcodeDialectEnumMARLIN, GcodeDialectEnumMARLIN_IJK:
+		return nil
+	default:
+		return errors.New("enum is not valid")
+	}
+}
+
+func (e GcodeDialectEnum) String() string {
+	return string(e)
+}
+
+func (e GcodeDialectEnum) Ordinal() int {
+	switch e {
+	case GcodeDialectEnumFLUIDNC:
+		return 0
+	case GcodeDialectEnumGRBL:
+		return 1
+	case GcodeDialectEnumMARLIN:
+		return 2
+	case GcodeDialectEnumMARLIN_IJK:
+		return 3
+
+	default:
+		panic(errors.New("enum is not valid"))
+	}
+}
+
+type InputTypeEnum string
+
+// Enum values for InputTypeEnum
+const (
+	InputTypeEnumPHOTO        InputTypeEnum = "PHOTO"
+	InputTypeEnumLOGO         InputTypeEnum = "LOGO"
+	InputTypeEnumLINE_DRAWING InputTypeEnum = "LINE_DRAWING"
+)
+
+func AllInputTypeEnum() []InputTypeEnum {
+	return []InputTypeEnum{
+		InputTypeEnumPHOTO,
+		InputTypeEnumLOGO,
+		InputTypeEnumLINE_DRAWING,
+	}
+}
+
+func (e InputTypeEnum) IsValid() error {
+	switch e {
+	case InputTypeEnumPHOTO, InputTypeEnumLOGO, InputTypeEnumLINE_DRAWING:
+		return nil
+	default:
+		return errors.New("enum is not valid")
+	}
+}
+
+func (e InputTypeEnum) String() string {
+	return string(e)
+}
+
+func (e InputTypeEnum) Ordinal() int {
+	switch e {
+	case InputTypeEnumPHOTO:
+		return 0
+	case InputTypeEnumLOGO:
+		return 1
+	case InputTypeEnumLINE_DRAWING:
+		return 2
+
+	default:
+		panic(errors.New("enum is not valid"))
+	}
+}
+
+type CompositionPriorityEnum string
+
+// Enum values for CompositionPriorityEnum
+const (
+	CompositionPriorityEnumINTERACTIVE CompositionPriorityEnum = "INTERACTIVE"
+	CompositionPriorityEnumRENDER      CompositionPriorityEnum = "RENDER"
+	CompositionPriorityEnumBATCH       CompositionPriorityEnum = "BATCH"
+)
+
+func AllCompositionPriorityEnum() []CompositionPriorityEnum {
+	return []CompositionPriorityEnum{
+		CompositionPriorityEnumINTERACTIVE,
+		CompositionPriorityEnumRENDER,
+		CompositionPriorityEnumBATCH,
+	}
+}
+
+func (e CompositionPriorityEnum) IsValid() error {
+	switch e {
+	case CompositionPriorityEnumINTERACTIVE, CompositionPriorityEnumRENDER, CompositionPriorityEnumBATCH:
+		return nil
+	default:
+		return errors.New("enum is not valid")
+	}
+}
+
+func (e CompositionPriorityEnum) String() string {
+	return string(e)
+}
+
+func (e CompositionPriorityEnum) Ordinal() int {
+	switch e {
+	case CompositionPriorityEnumINTERACTIVE:
+		return 0
+	case CompositionPriorityEnumRENDER:
+		return 1
+	case CompositionPriorityEnumBATCH:
+		return 2
+
+	default:
+		panic(errors.New("enum is not valid"))
+	}
+}
+
+type ParameterSweepStatusEnum string
+
+// Enum values for ParameterSweepStatusEnum
+const (
+	ParameterSweepStatusEnumPENDING    ParameterSweepStatusEnum = "PENDING"
+	ParameterSweepStatusEnumPROCESSING ParameterSweepStatusEnum = "PROCESSING"
+	ParameterSweepStatusEnumCOMPLETE   ParameterSweepStatusEnum = "COMPLETE"
+	ParameterSweepStatusEnumFAILED     ParameterSweepStatusEnum = "FAILED"
+)
+
+func AllParameterSweepStatusEnum() []ParameterSweepStatusEnum {
+	return []ParameterSweepStatusEnum{
+		ParameterSweepStatusEnumPENDING,
+		ParameterSweepStatusEnumPROCESSING,
+		ParameterSweepStatusEnumCOMPLETE,
+		ParameterSweepStatusEnumFAILED,
+	}
+}
+
+func (e ParameterSweepStatusEnum) IsValid() error {
+	switch e {
+	case ParameterSweepStatusEnumPENDING, ParameterSweepStatusEnumPROCESSING, ParameterSweepStatusEnumCOMPLETE, ParameterSweepStatusEnumFAILED:
+		return nil
+	default:
+		return errors.New("enum is not valid")
+	}
+}
+
+func (e ParameterSweepStatusEnum) String() string {
+	return string(e)
+}
+
+func (e ParameterSweepStatusEnum) Ordinal() int {
+	switch e {
+	case ParameterSweepStatusEnumPENDING:
+		return 0
+	case ParameterSweepStatusEnumPROCESSING:
+		return 1
+	case ParameterSweepStatusEnumCOMPLETE:
+		return 2
+	case ParameterSweepStatusEnumFAILED:
+		return 3
 
 	default:
 		panic(errors.New("enum is not valid"))
@@ -58,6 +58,42 @@ type Composition struct {
 	ErrorMessage null.String `boil:"error_message" json:"error_message,omitempty" toml:"error_message" yaml:"error_message,omitempty"`
 	CreatedAt    time.Time   `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
 	UpdatedAt    time.Time   `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	// Firmware flavour the G-code is generated for
+	GcodeDialect GcodeDialectEnum `boil:"gcode_dialect" json:"gcode_dialect" toml:"gcode_dialect" yaml:"gcode_dialect"`
+	// URL to download the nail hole drilling GCode file
+	DrillGcodeURL null.String `boil:"drill_gcode_url" json:"drill_gcode_url,omitempty" toml:"drill_gcode_url" yaml:"drill_gcode_url,omitempty"`
+	// Parameter sweep the composition was created by
+	SweepID null.String `boil:"sweep_id" json:"sweep_id,omitempty" toml:"sweep_id" yaml:"sweep_id,omitempty"`
+	// Similarity between the preview and the source image, from 0 to 1
+	SimilarityScore null.Float64 `boil:"similarity_score" json:"similarity_score,omitempty" toml:"similarity_score" yaml:"similarity_score,omitempty"`
+	// Length of thread on one spool in meters, 0 for unlimited
+	SpoolLength float64 `boil:"spool_length" json:"spool_length" toml:"spool_length" yaml:"spool_length"`
+	// Paths strung with each spool, with their starting nail and thread length
+	Segments null.JSON `boil:"segments" json:"segments,omitempty" toml:"segments" yaml:"segments,omitempty"`
+	// Concentric nail rings with their nail count, radius and minimum difference, NULL for a single ring
+	Rings null.JSON `boil:"rings" json:"rings,omitempty" toml:"rings" yaml:"rings,omitempty"`
+	// Thread profile used to score lines and render the preview
+	ThreadProfileID null.String `boil:"thread_profile_id" json:"thread_profile_id,omitempty" toml:"thread_profile_id" yaml:"thread_profile_id,omitempty"`
+	// Whether thread darkening is modelled in linear light instead of sRGB gray levels
+	LinearLight bool `boil:"linear_light" json:"linear_light" toml:"linear_light" yaml:"linear_light"`
+	// Nail wraps histogram, most loaded nails and chord length distribution of the paths list
+	Analytics null.JSON `boil:"analytics" json:"analytics,omitempty" toml:"analytics" yaml:"analytics,omitempty"`
+	// Storage key of the line density heatmap image
+	HeatmapURL null.String `boil:"heatmap_url" json:"heatmap_url,omitempty" toml:"heatmap_url" yaml:"heatmap_url,omitempty"`
+	// Kind of source the threads reproduce
+	InputType InputTypeEnum `boil:"input_type" json:"input_type" toml:"input_type" yaml:"input_type"`
+	// Seed drawing among lines of equal weight, 0 to pick the lowest nail
+	Seed int64 `boil:"seed" json:"seed" toml:"seed" yaml:"seed"`
+	// SHA-256 of the source image, configuration and generator version
+	ContentHash null.String `boil:"content_hash" json:"content_hash,omitempty" toml:"content_hash" yaml:"content_hash,omitempty"`
+	// Rotational symmetry order of the piece, 0 or 1 for none
+	SymmetryFold int `boil:"symmetry_fold" json:"symmetry_fold" toml:"symmetry_fold" yaml:"symmetry_fold"`
+	// Whether the piece is mirrored across its vertical axis
+	MirrorSymmetry bool `boil:"mirror_symmetry" json:"mirror_symmetry" toml:"mirror_symmetry" yaml:"mirror_symmetry"`
+	// Stage, percent done, lines placed and estimated completion of the processing
+	Progress null.JSON `boil:"progress" json:"progress,omitempty" toml:"progress" yaml:"progress,omitempty"`
+	// Processing priority, picks the queue the composition is sent to
+	Priority CompositionPriorityEnum `boil:"priority" json:"priority" toml:"priority" yaml:"priority"`
 
 	R *compositionR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L compositionL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -83,6 +119,24 @@ var CompositionColumns = struct {
 	ErrorMessage      string
 	CreatedAt         string
 	UpdatedAt         string
+	GcodeDialect      string
+	DrillGcodeURL     string
+	SweepID           string
+	SimilarityScore   string
+	SpoolLength       string
+	Segments          string
+	Rings             string
+	ThreadProfileID   string
+	LinearLight       string
+	Analytics         string
+	HeatmapURL        string
+	InputType         string
+	Seed              string
+	ContentHash       string
+	SymmetryFold      string
+	MirrorSymmetry    string
+	Progress          string
+	Priority          string
 }{
 	ID:                "id",
 	ArtID:             "art_id",
@@ -103,6 +157,24 @@ var CompositionColumns = struct {
 	ErrorMessage:      "error_message",
 	CreatedAt:         "created_at",
 	UpdatedAt:         "updated_at",
+	GcodeDialect:      "gcode_dialect",
+	DrillGcodeURL:     "drill_gcode_url",
+	SweepID:           "sweep_id",
+	SimilarityScore:   "similarity_score",
+	SpoolLength:       "spool_length",
+	Segments:          "segments",
+	Rings:             "rings",
+	ThreadProfileID:   "thread_profile_id",
+	LinearLight:       "linear_light",
+	Analytics:         "analytics",
+	HeatmapURL:        "heatmap_url",
+	InputType:         "input_type",
+	Seed:              "seed",
+	ContentHash:       "content_hash",
+	SymmetryFold:      "symmetry_fold",
+	MirrorSymmetry:    "mirror_symmetry",
+	Progress:          "progress",
+	Priority:          "priority",
 }
 
 var CompositionTableColumns = struct {
@@ -125,6 +197,24 @@ var CompositionTableColumns = struct {
 	ErrorMessage      string
 	CreatedAt         string
 	UpdatedAt         string
+	GcodeDialect      string
+	DrillGcodeURL     string
+	SweepID           string
+	SimilarityScore   string
+	SpoolLength       string
+	Segments          string
+	Rings             string
+	ThreadProfileID   string
+	LinearLight       string
+	Analytics         string
+	HeatmapURL        string
+	InputType         string
+	Seed              string
+	ContentHash       string
+	SymmetryFold      string
+	MirrorSymmetry    string
+	Progress          string
+	Priority          string
 }{
 	ID:                "compositions.id",
 	ArtID:             "compositions.art_id",
@@ -145,6 +235,24 @@ var CompositionTableColumns = struct {
 	ErrorMessage:      "compositions.error_message",
 	CreatedAt:         "compositions.created_at",
 	UpdatedAt:         "compositions.updated_at",
+	GcodeDialect:      "compositions.gcode_dialect",
+	DrillGcodeURL:     "compositions.drill_gcode_url",
+	SweepID:           "compositions.sweep_id",
+	SimilarityScore:   "compositions.similarity_score",
+	SpoolLength:       "compositions.spool_length",
+	Segments:          "compositions.segments",
+	Rings:             "compositions.rings",
+	ThreadProfileID:   "compositions.thread_profile_id",
+	LinearLight:       "compositions.linear_light",
+	Analytics:         "compositions.analytics",
+	HeatmapURL:        "compositions.heatmap_url",
+	InputType:         "compositions.input_type",
+	Seed:              "compositions.seed",
+	ContentHash:       "compositions.content_hash",
+	SymmetryFold:      "compositions.symmetry_fold",
+	MirrorSymmetry:    "compositions.mirror_symmetry",
+	Progress:          "compositions.progress",
+	Priority:          "compositions.priority",
 }
 
 // Generated where
@@ -251,6 +359,205 @@ func (w whereHelpernull_Int) NIN(slice []int) qm.QueryMod {
 func (w whereHelpernull_Int) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
 func (w whereHelpernull_Int) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
 
+type whereHelperGcodeDialectEnum struct{ field string }
+
+func (w whereHelperGcodeDialectEnum) EQ(x GcodeDialectEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.EQ, x)
+}
+func (w whereHelperGcodeDialectEnum) NEQ(x GcodeDialectEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.NEQ, x)
+}
+func (w whereHelperGcodeDialectEnum) LT(x GcodeDialectEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelperGcodeDialectEnum) LTE(x GcodeDialectEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelperGcodeDialectEnum) GT(x GcodeDialectEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelperGcodeDialectEnum) GTE(x GcodeDialectEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+func (w whereHelperGcodeDialectEnum) IN(slice []GcodeDialectEnum) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelperGcodeDialectEnum) NIN(slice []GcodeDialectEnum) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+type whereHelpernull_Float64 struct{ field string }
+
+func (w whereHelpernull_Float64) EQ(x null.Float64) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_Float64) NEQ(x null.Float64) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_Float64) LT(x null.Float64) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_Float64) LTE(x null.Float64) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_Float64) GT(x null.Float64) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_Float64) GTE(x null.Float64) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+func (w whereHelpernull_Float64) IN(slice []float64) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelpernull_Float64) NIN(slice []float64) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+func (w whereHelpernull_Float64) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_Float64) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+
+type whereHelpernull_JSON struct{ field string }
+
+func (w whereHelpernull_JSON) EQ(x null.JSON) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_JSON) NEQ(x null.JSON) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_JSON) LT(x null.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_JSON) LTE(x null.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_JSON) GT(x null.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_JSON) GTE(x null.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
+func (w whereHelpernull_JSON) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_JSON) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+
+type whereHelperbool struct{ field string }
+
+func (w whereHelperbool) EQ(x bool) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
+func (w whereHelperbool) NEQ(x bool) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
+func (w whereHelperbool) LT(x bool) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
+func (w whereHelperbool) LTE(x bool) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
+func (w whereHelperbool) GT(x bool) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
+func (w whereHelperbool) GTE(x bool) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
+
+type whereHelperInputTypeEnum struct{ field string }
+
+func (w whereHelperInputTypeEnum) EQ(x InputTypeEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.EQ, x)
+}
+func (w whereHelperInputTypeEnum) NEQ(x InputTypeEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.NEQ, x)
+}
+func (w whereHelperInputTypeEnum) LT(x InputTypeEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelperInputTypeEnum) LTE(x InputTypeEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelperInputTypeEnum) GT(x InputTypeEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelperInputTypeEnum) GTE(x InputTypeEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+func (w whereHelperInputTypeEnum) IN(slice []InputTypeEnum) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelperInputTypeEnum) NIN(slice []InputTypeEnum) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+type whereHelperint64 struct{ field string }
+
+func (w whereHelperint64) EQ(x int64) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
+func (w whereHelperint64) NEQ(x int64) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
+func (w whereHelperint64) LT(x int64) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
+func (w whereHelperint64) LTE(x int64) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
+func (w whereHelperint64) GT(x int64) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
+func (w whereHelperint64) GTE(x int64) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
+func (w whereHelperint64) IN(slice []int64) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelperint64) NIN(slice []int64) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+type whereHelperCompositionPriorityEnum struct{ field string }
+
+func (w whereHelperCompositionPriorityEnum) EQ(x CompositionPriorityEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.EQ, x)
+}
+func (w whereHelperCompositionPriorityEnum) NEQ(x CompositionPriorityEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.NEQ, x)
+}
+func (w whereHelperCompositionPriorityEnum) LT(x CompositionPriorityEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelperCompositionPriorityEnum) LTE(x CompositionPriorityEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelperCompositionPriorityEnum) GT(x CompositionPriorityEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelperCompositionPriorityEnum) GTE(x CompositionPriorityEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+func (w whereHelperCompositionPriorityEnum) IN(slice []CompositionPriorityEnum) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelperCompositionPriorityEnum) NIN(slice []CompositionPriorityEnum) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
 var CompositionWhere = struct {
 	ID                whereHelperstring
 	ArtID             whereHelperstring
@@ -271,6 +578,24 @@ var CompositionWhere = struct {
 	ErrorMessage      whereHelpernull_String
 	CreatedAt         whereHelpertime_Time
 	UpdatedAt         whereHelpertime_Time
+	GcodeDialect      whereHelperGcodeDialectEnum
+	DrillGcodeURL     whereHelpernull_String
+	SweepID           whereHelpernull_String
+	SimilarityScore   whereHelpernull_Float64
+	SpoolLength       whereHelperfloat64
+	Segments          whereHelpernull_JSON
+	Rings             whereHelpernull_JSON
+	ThreadProfileID   whereHelpernull_String
+	LinearLight       whereHelperbool
+	Analytics         whereHelpernull_JSON
+	HeatmapURL        whereHelpernull_String
+	InputType         whereHelperInputTypeEnum
+	Seed              whereHelperint64
+	ContentHash       whereHelpernull_String
+	SymmetryFold      whereHelperint
+	MirrorSymmetry    whereHelperbool
+	Progress          whereHelpernull_JSON
+	Priority          whereHelperCompositionPriorityEnum
 }{
 	ID:                whereHelperstring{field: "\"compositions\".\"id\""},
 	ArtID:             whereHelperstring{field: "\"compositions\".\"art_id\""},
@@ -291,18 +616,42 @@ var CompositionWhere = struct {
 	ErrorMessage:      whereHelpernull_String{field: "\"compositions\".\"error_message\""},
 	CreatedAt:         whereHelpertime_Time{field: "\"compositions\".\"created_at\""},
 	UpdatedAt:         whereHelpertime_Time{field: "\"compositions\".\"updated_at\""},
+	GcodeDialect:      whereHelperGcodeDialectEnum{field: "\"compositions\".\"gcode_dialect\""},
+	DrillGcodeURL:     whereHelpernull_String{field: "\"compositions\".\"drill_gcode_url\""},
+	SweepID:           whereHelpernull_String{field: "\"compositions\".\"sweep_id\""},
+	SimilarityScore:   whereHelpernull_Float64{field: "\"compositions\".\"similarity_score\""},
+	SpoolLength:       whereHelperfloat64{field: "\"compositions\".\"spool_length\""},
+	Segments:          whereHelpernull_JSON{field: "\"compositions\".\"segments\""},
+	Rings:             whereHelpernull_JSON{field: "\"compositions\".\"rings\""},
+	ThreadProfileID:   whereHelpernull_String{field: "\"compositions\".\"thread_profile_id\""},
+	LinearLight:       whereHelperbool{field: "\"compositions\".\"linear_light\""},
+	Analytics:         whereHelpernull_JSON{field: "\"compositions\".\"analytics\""},
+	HeatmapURL:        whereHelpernull_String{field: "\"compositions\".\"heatmap_url\""},
+	InputType:         whereHelperInputTypeEnum{field: "\"compositions\".\"input_type\""},
+	Seed:              whereHelperint64{field: "\"compositions\".\"seed\""},
+	ContentHash:       whereHelpernull_String{field: "\"compositions\".\"content_hash\""},
+	SymmetryFold:      whereHelperint{field: "\"compositions\".\"symmetry_fold\""},
+	MirrorSymmetry:    whereHelperbool{field: "\"compositions\".\"mirror_symmetry\""},
+	Progress:          whereHelpernull_JSON{field: "\"compositions\".\"progress\""},
+	Priority:          whereHelperCompositionPriorityEnum{field: "\"compositions\".\"priority\""},
 }
 
 // CompositionRels is where relationship names are stored.
 var CompositionRels = struct {
-	Art string
+	Art           string
+	Sweep         string
+	ThreadProfile string
 }{
-	Art: "Art",
+	Art:           "Art",
+	Sweep:         "Sweep",
+	ThreadProfile: "ThreadProfile",
 }
 
 // compositionR is where relationships are stored.
 type compositionR struct {
-	Art *Art `boil:"Art" json:"Art" toml:"Art" yaml:"Art"`
+	Art           *Art            `boil:"Art" json:"Art" toml:"Art" yaml:"Art"`
+	Sweep         *ParameterSweep `boil:"Sweep" json:"Sweep" toml:"Sweep" yaml:"Sweep"`
+	ThreadProfile *ThreadProfile  `boil:"ThreadProfile" json:"ThreadProfile" toml:"ThreadProfile" yaml:"ThreadProfile"`
 }
 
 // NewStruct creates a new relationship struct
@@ -317,13 +666,27 @@ func (r *compositionR) GetArt() *Art {
 	return r.Art
 }
 
+func (r *compositionR) GetSweep() *ParameterSweep {
+	if r == nil {
+		return nil
+	}
+	return r.Sweep
+}
+
+func (r *compositionR) GetThreadProfile() *ThreadProfile {
+	if r == nil {
+		return nil
+	}
+	return r.ThreadProfile
+}
+
 // compositionL is where Load methods for each relationship are stored.
 type compositionL struct{}
 
 var (
-	compositionAllColumns            = []string{"id", "art_id", "status", "nails_quantity", "img_size", "max_paths", "starting_nail", "minimum_difference", "brightness_factor", "image_contrast", "physical_radius", "preview_url", "gcode_url", "pathlist_url", "thread_length", "total_lines", "error_message", "created_at", "updated_at"}
+	compositionAllColumns            = []string{"id", "art_id", "status", "nails_quantity", "img_size", "max_paths", "starting_nail", "minimum_difference", "brightness_factor", "image_contrast", "physical_radius", "preview_url", "gcode_url", "pathlist_url", "thread_length", "total_lines", "error_message", "created_at", "updated_at", "gcode_dialect", "drill_gcode_url", "sweep_id", "similarity_score", "spool_length", "segments", "rings", "thread_profile_id", "linear_light", "analytics", "heatmap_url", "input_type", "seed", "content_hash", "symmetry_fold", "mirror_symmetry", "progress", "priority"}
 	compositionColumnsWithoutDefault = []string{"art_id"}
-	compositionColumnsWithDefault    = []string{"id", "status", "nails_quantity", "img_size", "max_paths", "starting_nail", "minimum_difference", "brightness_factor", "image_contrast", "physical_radius", "preview_url", "gcode_url", "pathlist_url", "thread_length", "total_lines", "error_message", "created_at", "updated_at"}
+	compositionColumnsWithDefault    = []string{"id", "status", "nails_quantity", "img_size", "max_paths", "starting_nail", "minimum_difference", "brightness_factor", "image_contrast", "physical_radius", "preview_url", "gcode_url", "pathlist_url", "thread_length", "total_lines", "error_message", "created_at", "updated_at", "gcode_dialect", "drill_gcode_url", "sweep_id", "similarity_score", "spool_length", "segments", "rings", "thread_profile_id", "linear_light", "analytics", "heatmap_url", "input_type", "seed", "content_hash", "symmetry_fold", "mirror_symmetry", "progress", "priority"}
 	compositionPrimaryKeyColumns     = []string{"id"}
 	compositionGeneratedColumns      = []string{}
 )
@@ -644,6 +1007,28 @@ func (o *Composition) Art(mods ...qm.QueryMod) artQuery {
 	return Arts(queryMods...)
 }
 
+// Sweep pointed to by the foreign key.
+func (o *Composition) Sweep(mods ...qm.QueryMod) parameterSweepQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.SweepID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	return ParameterSweeps(queryMods...)
+}
+
+// ThreadProfile pointed to by the foreign key.
+func (o *Composition) ThreadProfile(mods ...qm.QueryMod) threadProfileQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.ThreadProfileID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	return ThreadProfiles(queryMods...)
+}
+
 // LoadArt allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for an N-1 relationship.
 func (compositionL) LoadArt(ctx context.Context, e boil.ContextExecutor, singular bool, maybeComposition interface{}, mods queries.Applicator) error {
@@ -764,6 +1149,254 @@ func (compositionL) LoadArt(ctx context.Context, e boil.ContextExecutor, singula
 	return nil
 }
 
+// LoadSweep allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (compositionL) LoadSweep(ctx context.Context, e boil.ContextExecutor, singular bool, maybeComposition interface{}, mods queries.Applicator) error {
+	var slice []*Composition
+	var object *Composition
+
+	if singular {
+		var ok bool
+		object, ok = maybeComposition.(*Composition)
+		if !ok {
+			object = new(Composition)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeComposition)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeComposition))
+			}
+		}
+	} else {
+		s, ok := maybeComposition.(*[]*Composition)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeComposition)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeComposition))
+			}
+		}
+	}
+
+	args := make(map[interface{}]struct{})
+	if singular {
+		if object.R == nil {
+			object.R = &compositionR{}
+		}
+		if !queries.IsNil(object.SweepID) {
+			args[object.SweepID] = struct{}{}
+		}
+
+	} else {
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &compositionR{}
+			}
+
+			if !queries.IsNil(obj.SweepID) {
+				args[obj.SweepID] = struct{}{}
+			}
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	argsSlice := make([]interface{}, len(args))
+	i := 0
+	for arg := range args {
+		argsSlice[i] = arg
+		i++
+	}
+
+	query := NewQuery(
+		qm.From(`parameter_sweeps`),
+		qm.WhereIn(`parameter_sweeps.id in ?`, argsSlice...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load ParameterSweep")
+	}
+
+	var resultSlice []*ParameterSweep
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice ParameterSweep")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for parameter_sweeps")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for parameter_sweeps")
+	}
+
+	if len(parameterSweepAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Sweep = foreign
+		if foreign.R == nil {
+			foreign.R = &parameterSweepR{}
+		}
+		foreign.R.SweepCompositions = append(foreign.R.SweepCompositions, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if queries.Equal(local.SweepID, foreign.ID) {
+				local.R.Sweep = foreign
+				if foreign.R == nil {
+					foreign.R = &parameterSweepR{}
+				}
+				foreign.R.SweepCompositions = append(foreign.R.SweepCompositions, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadThreadProfile allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (compositionL) LoadThreadProfile(ctx context.Context, e boil.ContextExecutor, singular bool, maybeComposition interface{}, mods queries.Applicator) error {
+	var slice []*Composition
+	var object *Composition
+
+	if singular {
+		var ok bool
+		object, ok = maybeComposition.(*Composition)
+		if !ok {
+			object = new(Composition)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeComposition)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeComposition))
+			}
+		}
+	} else {
+		s, ok := maybeComposition.(*[]*Composition)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeComposition)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeComposition))
+			}
+		}
+	}
+
+	args := make(map[interface{}]struct{})
+	if singular {
+		if object.R == nil {
+			object.R = &compositionR{}
+		}
+		if !queries.IsNil(object.ThreadProfileID) {
+			args[object.ThreadProfileID] = struct{}{}
+		}
+
+	} else {
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &compositionR{}
+			}
+
+			if !queries.IsNil(obj.ThreadProfileID) {
+				args[obj.ThreadProfileID] = struct{}{}
+			}
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	argsSlice := make([]interface{}, len(args))
+	i := 0
+	for arg := range args {
+		argsSlice[i] = arg
+		i++
+	}
+
+	query := NewQuery(
+		qm.From(`thread_profiles`),
+		qm.WhereIn(`thread_profiles.id in ?`, argsSlice...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load ThreadProfile")
+	}
+
+	var resultSlice []*ThreadProfile
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice ThreadProfile")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for thread_profiles")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for thread_profiles")
+	}
+
+	if len(threadProfileAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.ThreadProfile = foreign
+		if foreign.R == nil {
+			foreign.R = &threadProfileR{}
+		}
+		foreign.R.Compositions = append(foreign.R.Compositions, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if queries.Equal(local.ThreadProfileID, foreign.ID) {
+				local.R.ThreadProfile = foreign
+				if foreign.R == nil {
+					foreign.R = &threadProfileR{}
+				}
+				foreign.R.Compositions = append(foreign.R.Compositions, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // SetArt of the composition to the related item.
 // Sets o.R.Art to related.
 // Adds o to related.R.Compositions.
@@ -811,6 +1444,166 @@ func (o *Composition) SetArt(ctx context.Context, exec boil.ContextExecutor, ins
 	return nil
 }
 
+// SetSweep of the composition to the related item.
+// Sets o.R.Sweep to related.
+// Adds o to related.R.SweepCompositions.
+func (o *Composition) SetSweep(ctx context.Context, exec boil.ContextExecutor, insert bool, related *ParameterSweep) error {
+	var err error
+	if insert {
+		if err = related.Insert(ctx, exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"compositions\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"sweep_id"}),
+		strmangle.WhereClause("\"", "\"", 2, compositionPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, updateQuery)
+		fmt.Fprintln(writer, values)
+	}
+	if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	queries.Assign(&o.SweepID, related.ID)
+	if o.R == nil {
+		o.R = &compositionR{
+			Sweep: related,
+		}
+	} else {
+		o.R.Sweep = related
+	}
+
+	if related.R == nil {
+		related.R = &parameterSweepR{
+			SweepCompositions: CompositionSlice{o},
+		}
+	} else {
+		related.R.SweepCompositions = append(related.R.SweepCompositions, o)
+	}
+
+	return nil
+}
+
+// RemoveSweep relationship.
+// Sets o.R.Sweep to nil.
+// Removes o from all passed in related items' relationships struct.
+func (o *Composition) RemoveSweep(ctx context.Context, exec boil.ContextExecutor, related *ParameterSweep) error {
+	var err error
+
+	queries.SetScanner(&o.SweepID, nil)
+	if _, err = o.Update(ctx, exec, boil.Whitelist("sweep_id")); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	if o.R != nil {
+		o.R.Sweep = nil
+	}
+	if related == nil || related.R == nil {
+		return nil
+	}
+
+	for i, ri := range related.R.SweepCompositions {
+		if queries.Equal(o.SweepID, ri.SweepID) {
+			continue
+		}
+
+		ln := len(related.R.SweepCompositions)
+		if ln > 1 && i < ln-1 {
+			related.R.SweepCompositions[i] = related.R.SweepCompositions[ln-1]
+		}
+		related.R.SweepCompositions = related.R.SweepCompositions[:ln-1]
+		break
+	}
+	return nil
+}
+
+// SetThreadProfile of the composition to the related item.
+// Sets o.R.ThreadProfile to related.
+// Adds o to related.R.Compositions.
+func (o *Composition) SetThreadProfile(ctx context.Context, exec boil.ContextExecutor, insert bool, related *ThreadProfile) error {
+	var err error
+	if insert {
+		if err = related.Insert(ctx, exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"compositions\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"thread_profile_id"}),
+		strmangle.WhereClause("\"", "\"", 2, compositionPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, updateQuery)
+		fmt.Fprintln(writer, values)
+	}
+	if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	queries.Assign(&o.ThreadProfileID, related.ID)
+	if o.R == nil {
+		o.R = &compositionR{
+			ThreadProfile: related,
+		}
+	} else {
+		o.R.ThreadProfile = related
+	}
+
+	if related.R == nil {
+		related.R = &threadProfileR{
+			Compositions: CompositionSlice{o},
+		}
+	} else {
+		related.R.Compositions = append(related.R.Compositions, o)
+	}
+
+	return nil
+}
+
+// RemoveThreadProfile relationship.
+// Sets o.R.ThreadProfile to nil.
+// Removes o from all passed in related items' relationships struct.
+func (o *Composition) RemoveThreadProfile(ctx context.Context, exec boil.ContextExecutor, related *ThreadProfile) error {
+	var err error
+
+	queries.SetScanner(&o.ThreadProfileID, nil)
+	if _, err = o.Update(ctx, exec, boil.Whitelist("thread_profile_id")); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	if o.R != nil {
+		o.R.ThreadProfile = nil
+	}
+	if related == nil || related.R == nil {
+		return nil
+	}
+
+	for i, ri := range related.R.Compositions {
+		if queries.Equal(o.ThreadProfileID, ri.ThreadProfileID) {
+			continue
+		}
+
+		ln := len(related.R.Compositions)
+		if ln > 1 && i < ln-1 {
+			related.R.Compositions[i] = related.R.Compositions[ln-1]
+		}
+		related.R.Compositions = related.R.Compositions[:ln-1]
+		break
+	}
+	return nil
+}
+
 // Compositions retrieves all the records using an executor.
 func Compositions(mods ...qm.QueryMod) compositionQuery {
 	mods = append(mods, qm.From("\"compositions\""))
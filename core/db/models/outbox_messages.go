@@ -0,0 +1,992 @@
+// Code generated by SQLBoiler 4.16.2 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// OutboxMessage is an object representing the database table.
+type OutboxMessage struct {
+	ID string `boil:"id" json:"id" toml:"id" yaml:"id"`
+	// Queue the message is published to
+	Queue string `boil:"queue" json:"queue" toml:"queue" yaml:"queue"`
+	// Body of the message
+	Payload []byte `boil:"payload" json:"payload" toml:"payload" yaml:"payload"`
+	// Failed attempts to publish the message
+	Attempts int `boil:"attempts" json:"attempts" toml:"attempts" yaml:"attempts"`
+	// Error of the last failed attempt
+	LastError null.String `boil:"last_error" json:"last_error,omitempty" toml:"last_error" yaml:"last_error,omitempty"`
+	// Time the relay publishes the message at the earliest
+	NextAttemptAt time.Time `boil:"next_attempt_at" json:"next_attempt_at" toml:"next_attempt_at" yaml:"next_attempt_at"`
+	CreatedAt     time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+
+	R *outboxMessageR `boil:"-" json:"-" toml:"-" yaml:"-"`
+	L outboxMessageL  `boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var OutboxMessageColumns = struct {
+	ID            string
+	Queue         string
+	Payload       string
+	Attempts      string
+	LastError     string
+	NextAttemptAt string
+	CreatedAt     string
+}{
+	ID:            "id",
+	Queue:         "queue",
+	Payload:       "payload",
+	Attempts:      "attempts",
+	LastError:     "last_error",
+	NextAttemptAt: "next_attempt_at",
+	CreatedAt:     "created_at",
+}
+
+var OutboxMessageTableColumns = struct {
+	ID            string
+	Queue         string
+	Payload       string
+	Attempts      string
+	LastError     string
+	NextAttemptAt string
+	CreatedAt     string
+}{
+	ID:            "outbox_messages.id",
+	Queue:         "outbox_messages.queue",
+	Payload:       "outbox_messages.payload",
+	Attempts:      "outbox_messages.attempts",
+	LastError:     "outbox_messages.last_error",
+	NextAttemptAt: "outbox_messages.next_attempt_at",
+	CreatedAt:     "outbox_messages.created_at",
+}
+
+// Generated where
+
+type whereHelper__byte struct{ field string }
+
+func (w whereHelper__byte) EQ(x []byte) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
+func (w whereHelper__byte) NEQ(x []byte) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
+func (w whereHelper__byte) LT(x []byte) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
+func (w whereHelper__byte) LTE(x []byte) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
+func (w whereHelper__byte) GT(x []byte) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
+func (w whereHelper__byte) GTE(x []byte) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
+
+var OutboxMessageWhere = struct {
+	ID            whereHelperstring
+	Queue         whereHelperstring
+	Payload       whereHelper__byte
+	Attempts      whereHelperint
+	LastError     whereHelpernull_String
+	NextAttemptAt whereHelpertime_Time
+	CreatedAt     whereHelpertime_Time
+}{
+	ID:            whereHelperstring{field: "\"outbox_messages\".\"id\""},
+	Queue:         whereHelperstring{field: "\"outbox_messages\".\"queue\""},
+	Payload:       whereHelper__byte{field: "\"outbox_messages\".\"payload\""},
+	Attempts:      whereHelperint{field: "\"outbox_messages\".\"attempts\""},
+	LastError:     whereHelpernull_String{field: "\"outbox_messages\".\"last_error\""},
+	NextAttemptAt: whereHelpertime_Time{field: "\"outbox_messages\".\"next_attempt_at\""},
+	CreatedAt:     whereHelpertime_Time{field: "\"outbox_messages\".\"created_at\""},
+}
+
+// OutboxMessageRels is where relationship names are stored.
+var OutboxMessageRels = struct {
+}{}
+
+// outboxMessageR is where relationships are stored.
+type outboxMessageR struct {
+}
+
+// NewStruct creates a new relationship struct
+func (*outboxMessageR) NewStruct() *outboxMessageR {
+	return &outboxMessageR{}
+}
+
+// outboxMessageL is where Load methods for each relationship are stored.
+type outboxMessageL struct{}
+
+var (
+	outboxMessageAllColumns            = []string{"id", "queue", "payload", "attempts", "last_error", "next_attempt_at", "created_at"}
+	outboxMessageColumnsWithoutDefault = []string{"queue", "payload"}
+	outboxMessageColumnsWithDefault    = []string{"id", "attempts", "last_error", "next_attempt_at", "created_at"}
+	outboxMessagePrimaryKeyColumns     = []string{"id"}
+	outboxMessageGeneratedColumns      = []string{}
+)
+
+type (
+	// OutboxMessageSlice is an alias for a slice of pointers to OutboxMessage.
+	// This should almost always be used instead of []OutboxMessage.
+	OutboxMessageSlice []*OutboxMessage
+	// OutboxMessageHook is the signature for custom OutboxMessage hook methods
+	OutboxMessageHook func(context.Context, boil.ContextExecutor, *OutboxMessage) error
+
+	outboxMessageQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	outboxMessageType                 = reflect.TypeOf(&OutboxMessage{})
+	outboxMessageMapping              = queries.MakeStructMapping(outboxMessageType)
+	outboxMessagePrimaryKeyMapping, _ = queries.BindMapping(outboxMessageType, outboxMessageMapping, outboxMessagePrimaryKeyColumns)
+	outboxMessageInsertCacheMut       sync.RWMutex
+	outboxMessageInsertCache          = make(map[string]insertCache)
+	outboxMessageUpdateCacheMut       sync.RWMutex
+	outboxMessageUpdateCache          = make(map[string]updateCache)
+	outboxMessageUpsertCacheMut       sync.RWMutex
+	outboxMessageUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var outboxMessageAfterSelectMu sync.Mutex
+var outboxMessageAfterSelectHooks []OutboxMessageHook
+
+var outboxMessageBeforeInsertMu sync.Mutex
+var outboxMessageBeforeInsertHooks []OutboxMessageHook
+var outboxMessageAfterInsertMu sync.Mutex
+var outboxMessageAfterInsertHooks []OutboxMessageHook
+
+var outboxMessageBeforeUpdateMu sync.Mutex
+var outboxMessageBeforeUpdateHooks []OutboxMessageHook
+var outboxMessageAfterUpdateMu sync.Mutex
+var outboxMessageAfterUpdateHooks []OutboxMessageHook
+
+var outboxMessageBeforeDeleteMu sync.Mutex
+var outboxMessageBeforeDeleteHooks []OutboxMessageHook
+var outboxMessageAfterDeleteMu sync.Mutex
+var outboxMessageAfterDeleteHooks []OutboxMessageHook
+
+var outboxMessageBeforeUpsertMu sync.Mutex
+var outboxMessageBeforeUpsertHooks []OutboxMessageHook
+var outboxMessageAfterUpsertMu sync.Mutex
+var outboxMessageAfterUpsertHooks []OutboxMessageHook
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *OutboxMessage) doAfterSelectHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range outboxMessageAfterSelectHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *OutboxMessage) doBeforeInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range outboxMessageBeforeInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *OutboxMessage) doAfterInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range outboxMessageAfterInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *OutboxMessage) doBeforeUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range outboxMessageBeforeUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *OutboxMessage) doAfterUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range outboxMessageAfterUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *OutboxMessage) doBeforeDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range outboxMessageBeforeDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *OutboxMessage) doAfterDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range outboxMessageAfterDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *OutboxMessage) doBeforeUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range outboxMessageBeforeUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *OutboxMessage) doAfterUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range outboxMessageAfterUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddOutboxMessageHook registers your hook function for all future operations.
+func AddOutboxMessageHook(hookPoint boil.HookPoint, outboxMessageHook OutboxMessageHook) {
+	switch hookPoint {
+	case boil.AfterSelectHook:
+		outboxMessageAfterSelectMu.Lock()
+		outboxMessageAfterSelectHooks = append(outboxMessageAfterSelectHooks, outboxMessageHook)
+		outboxMessageAfterSelectMu.Unlock()
+	case boil.BeforeInsertHook:
+		outboxMessageBeforeInsertMu.Lock()
+		outboxMessageBeforeInsertHooks = append(outboxMessageBeforeInsertHooks, outboxMessageHook)
+		outboxMessageBeforeInsertMu.Unlock()
+	case boil.AfterInsertHook:
+		outboxMessageAfterInsertMu.Lock()
+		outboxMessageAfterInsertHooks = append(outboxMessageAfterInsertHooks, outboxMessageHook)
+		outboxMessageAfterInsertMu.Unlock()
+	case boil.BeforeUpdateHook:
+		outboxMessageBeforeUpdateMu.Lock()
+		outboxMessageBeforeUpdateHooks = append(outboxMessageBeforeUpdateHooks, outboxMessageHook)
+		outboxMessageBeforeUpdateMu.Unlock()
+	case boil.AfterUpdateHook:
+		outboxMessageAfterUpdateMu.Lock()
+		outboxMessageAfterUpdateHooks = append(outboxMessageAfterUpdateHooks, outboxMessageHook)
+		outboxMessageAfterUpdateMu.Unlock()
+	case boil.BeforeDeleteHook:
+		outboxMessageBeforeDeleteMu.Lock()
+		outboxMessageBeforeDeleteHooks = append(outboxMessageBeforeDeleteHooks, outboxMessageHook)
+		outboxMessageBeforeDeleteMu.Unlock()
+	case boil.AfterDeleteHook:
+		outboxMessageAfterDeleteMu.Lock()
+		outboxMessageAfterDeleteHooks = append(outboxMessageAfterDeleteHooks, outboxMessageHook)
+		outboxMessageAfterDeleteMu.Unlock()
+	case boil.BeforeUpsertHook:
+		outboxMessageBeforeUpsertMu.Lock()
+		outboxMessageBeforeUpsertHooks = append(outboxMessageBeforeUpsertHooks, outboxMessageHook)
+		outboxMessageBeforeUpsertMu.Unlock()
+	case boil.AfterUpsertHook:
+		outboxMessageAfterUpsertMu.Lock()
+		outboxMessageAfterUpsertHooks = append(outboxMessageAfterUpsertHooks, outboxMessageHook)
+		outboxMessageAfterUpsertMu.Unlock()
+	}
+}
+
+// One returns a single outboxMessage record from the query.
+func (q outboxMessageQuery) One(ctx context.Context, exec boil.ContextExecutor) (*OutboxMessage, error) {
+	o := &OutboxMessage{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: failed to execute a one query for outbox_messages")
+	}
+
+	if err := o.doAfterSelectHooks(ctx, exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// All returns all OutboxMessage records from the query.
+func (q outboxMessageQuery) All(ctx context.Context, exec boil.ContextExecutor) (OutboxMessageSlice, error) {
+	var o []*OutboxMessage
+
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "models: failed to assign all query results to OutboxMessage slice")
+	}
+
+	if len(outboxMessageAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(ctx, exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Count returns the count of all OutboxMessage records in the query.
+func (q outboxMessageQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to count outbox_messages rows")
+	}
+
+	return count, nil
+}
+
+// Exists checks if the row exists in the table.
+func (q outboxMessageQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "models: failed to check if outbox_messages exists")
+	}
+
+	return count > 0, nil
+}
+
+// OutboxMessages retrieves all the records using an executor.
+func OutboxMessages(mods ...qm.QueryMod) outboxMessageQuery {
+	mods = append(mods, qm.From("\"outbox_messages\""))
+	q := NewQuery(mods...)
+	if len(queries.GetSelect(q)) == 0 {
+		queries.SetSelect(q, []string{"\"outbox_messages\".*"})
+	}
+
+	return outboxMessageQuery{q}
+}
+
+// FindOutboxMessage retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindOutboxMessage(ctx context.Context, exec boil.ContextExecutor, iD string, selectCols ...string) (*OutboxMessage, error) {
+	outboxMessageObj := &OutboxMessage{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"outbox_messages\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(ctx, exec, outboxMessageObj)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: unable to select from outbox_messages")
+	}
+
+	if err = outboxMessageObj.doAfterSelectHooks(ctx, exec); err != nil {
+		return outboxMessageObj, err
+	}
+
+	return outboxMessageObj, nil
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *OutboxMessage) Insert(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no outbox_messages provided for insertion")
+	}
+
+	var err error
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+	}
+
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(outboxMessageColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	outboxMessageInsertCacheMut.RLock()
+	cache, cached := outboxMessageInsertCache[key]
+	outboxMessageInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			outboxMessageAllColumns,
+			outboxMessageColumnsWithDefault,
+			outboxMessageColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(outboxMessageType, outboxMessageMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(outboxMessageType, outboxMessageMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"outbox_messages\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"outbox_messages\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "models: unable to insert into outbox_messages")
+	}
+
+	if !cached {
+		outboxMessageInsertCacheMut.Lock()
+		outboxMessageInsertCache[key] = cache
+		outboxMessageInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(ctx, exec)
+}
+
+// Update uses an executor to update the OutboxMessage.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *OutboxMessage) Update(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) (int64, error) {
+	var err error
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	outboxMessageUpdateCacheMut.RLock()
+	cache, cached := outboxMessageUpdateCache[key]
+	outboxMessageUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			outboxMessageAllColumns,
+			outboxMessagePrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("models: unable to update outbox_messages, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"outbox_messages\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, outboxMessagePrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(outboxMessageType, outboxMessageMapping, append(wl, outboxMessagePrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, values)
+	}
+	var result sql.Result
+	result, err = exec.ExecContext(ctx, cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update outbox_messages row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by update for outbox_messages")
+	}
+
+	if !cached {
+		outboxMessageUpdateCacheMut.Lock()
+		outboxMessageUpdateCache[key] = cache
+		outboxMessageUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(ctx, exec)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q outboxMessageQuery) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all for outbox_messages")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected for outbox_messages")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o OutboxMessageSlice) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("models: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), outboxMessagePrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"outbox_messages\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, outboxMessagePrimaryKeyColumns, len(o)))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all in outboxMessage slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected all in update all outboxMessage")
+	}
+	return rowsAff, nil
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *OutboxMessage) Upsert(ctx context.Context, exec boil.ContextExecutor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns, opts ...UpsertOptionFunc) error {
+	if o == nil {
+		return errors.New("models: no outbox_messages provided for upsert")
+	}
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+	}
+
+	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(outboxMessageColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	outboxMessageUpsertCacheMut.RLock()
+	cache, cached := outboxMessageUpsertCache[key]
+	outboxMessageUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, _ := insertColumns.InsertColumnSet(
+			outboxMessageAllColumns,
+			outboxMessageColumnsWithDefault,
+			outboxMessageColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		update := updateColumns.UpdateColumnSet(
+			outboxMessageAllColumns,
+			outboxMessagePrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("models: unable to upsert outbox_messages, could not build update column list")
+		}
+
+		ret := strmangle.SetComplement(outboxMessageAllColumns, strmangle.SetIntersect(insert, update))
+
+		conflict := conflictColumns
+		if len(conflict) == 0 && updateOnConflict && len(update) != 0 {
+			if len(outboxMessagePrimaryKeyColumns) == 0 {
+				return errors.New("models: unable to upsert outbox_messages, could not build conflict column list")
+			}
+
+			conflict = make([]string, len(outboxMessagePrimaryKeyColumns))
+			copy(conflict, outboxMessagePrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"outbox_messages\"", updateOnConflict, ret, update, conflict, insert, opts...)
+
+		cache.valueMapping, err = queries.BindMapping(outboxMessageType, outboxMessageMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(outboxMessageType, outboxMessageMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(returns...)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "models: unable to upsert outbox_messages")
+	}
+
+	if !cached {
+		outboxMessageUpsertCacheMut.Lock()
+		outboxMessageUpsertCache[key] = cache
+		outboxMessageUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(ctx, exec)
+}
+
+// Delete deletes a single OutboxMessage record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *OutboxMessage) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("models: no OutboxMessage provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), outboxMessagePrimaryKeyMapping)
+	sql := "DELETE FROM \"outbox_messages\" WHERE \"id\"=$1"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete from outbox_messages")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by delete for outbox_messages")
+	}
+
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all matching rows.
+func (q outboxMessageQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("models: no outboxMessageQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from outbox_messages")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for outbox_messages")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o OutboxMessageSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(outboxMessageBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), outboxMessagePrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"outbox_messages\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, outboxMessagePrimaryKeyColumns, len(o))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from outboxMessage slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for outbox_messages")
+	}
+
+	if len(outboxMessageAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *OutboxMessage) Reload(ctx context.Context, exec boil.ContextExecutor) error {
+	ret, err := FindOutboxMessage(ctx, exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *OutboxMessageSlice) ReloadAll(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := OutboxMessageSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), outboxMessagePrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"outbox_messages\".* FROM \"outbox_messages\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, outboxMessagePrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(ctx, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to reload all in OutboxMessageSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// OutboxMessageExists checks if the OutboxMessage row exists.
+func OutboxMessageExists(ctx context.Context, exec boil.ContextExecutor, iD string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"outbox_messages\" where \"id\"=$1 limit 1)"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, iD)
+	}
+	row := exec.QueryRowContext(ctx, sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "models: unable to check if outbox_messages exists")
+	}
+
+	return exists, nil
+}
+
+// Exists checks if the OutboxMessage row exists.
+func (o *OutboxMessage) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	return OutboxMessageExists(ctx, exec, o.ID)
+}
@@ -0,0 +1,1509 @@
+// Code generated by SQLBoiler 4.16.2 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"github.com/volatiletech/strmangle"
+)
+
+// ParameterSweep is an object representing the database table.
+type ParameterSweep struct {
+	ID    string `boil:"id" json:"id" toml:"id" yaml:"id"`
+	ArtID string `boil:"art_id" json:"art_id" toml:"art_id" yaml:"art_id"`
+	// Current status of the sweep
+	Status ParameterSweepStatusEnum `boil:"status" json:"status" toml:"status" yaml:"status"`
+	// Swept parameters with their min, max and step
+	Ranges types.JSON `boil:"ranges" json:"ranges" toml:"ranges" yaml:"ranges"`
+	// URL to the contact sheet of all previews, ranked by score
+	ContactSheetURL null.String `boil:"contact_sheet_url" json:"contact_sheet_url,omitempty" toml:"contact_sheet_url" yaml:"contact_sheet_url,omitempty"`
+	// Error message if the sweep failed
+	ErrorMessage null.String `boil:"error_message" json:"error_message,omitempty" toml:"error_message" yaml:"error_message,omitempty"`
+	CreatedAt    time.Time   `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt    time.Time   `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+
+	R *parameterSweepR `boil:"-" json:"-" toml:"-" yaml:"-"`
+	L parameterSweepL  `boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var ParameterSweepColumns = struct {
+	ID              string
+	ArtID           string
+	Status          string
+	Ranges          string
+	ContactSheetURL string
+	ErrorMessage    string
+	CreatedAt       string
+	UpdatedAt       string
+}{
+	ID:              "id",
+	ArtID:           "art_id",
+	Status:          "status",
+	Ranges:          "ranges",
+	ContactSheetURL: "contact_sheet_url",
+	ErrorMessage:    "error_message",
+	CreatedAt:       "created_at",
+	UpdatedAt:       "updated_at",
+}
+
+var ParameterSweepTableColumns = struct {
+	ID              string
+	ArtID           string
+	Status          string
+	Ranges          string
+	ContactSheetURL string
+	ErrorMessage    string
+	CreatedAt       string
+	UpdatedAt       string
+}{
+	ID:              "parameter_sweeps.id",
+	ArtID:           "parameter_sweeps.art_id",
+	Status:          "parameter_sweeps.status",
+	Ranges:          "parameter_sweeps.ranges",
+	ContactSheetURL: "parameter_sweeps.contact_sheet_url",
+	ErrorMessage:    "parameter_sweeps.error_message",
+	CreatedAt:       "parameter_sweeps.created_at",
+	UpdatedAt:       "parameter_sweeps.updated_at",
+}
+
+// Generated where
+
+type whereHelperParameterSweepStatusEnum struct{ field string }
+
+func (w whereHelperParameterSweepStatusEnum) EQ(x ParameterSweepStatusEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.EQ, x)
+}
+func (w whereHelperParameterSweepStatusEnum) NEQ(x ParameterSweepStatusEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.NEQ, x)
+}
+func (w whereHelperParameterSweepStatusEnum) LT(x ParameterSweepStatusEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelperParameterSweepStatusEnum) LTE(x ParameterSweepStatusEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelperParameterSweepStatusEnum) GT(x ParameterSweepStatusEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelperParameterSweepStatusEnum) GTE(x ParameterSweepStatusEnum) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+func (w whereHelperParameterSweepStatusEnum) IN(slice []ParameterSweepStatusEnum) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelperParameterSweepStatusEnum) NIN(slice []ParameterSweepStatusEnum) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+type whereHelpertypes_JSON struct{ field string }
+
+func (w whereHelpertypes_JSON) EQ(x types.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.EQ, x)
+}
+func (w whereHelpertypes_JSON) NEQ(x types.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.NEQ, x)
+}
+func (w whereHelpertypes_JSON) LT(x types.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpertypes_JSON) LTE(x types.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpertypes_JSON) GT(x types.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpertypes_JSON) GTE(x types.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
+var ParameterSweepWhere = struct {
+	ID              whereHelperstring
+	ArtID           whereHelperstring
+	Status          whereHelperParameterSweepStatusEnum
+	Ranges          whereHelpertypes_JSON
+	ContactSheetURL whereHelpernull_String
+	ErrorMessage    whereHelpernull_String
+	CreatedAt       whereHelpertime_Time
+	UpdatedAt       whereHelpertime_Time
+}{
+	ID:              whereHelperstring{field: "\"parameter_sweeps\".\"id\""},
+	ArtID:           whereHelperstring{field: "\"parameter_sweeps\".\"art_id\""},
+	Status:          whereHelperParameterSweepStatusEnum{field: "\"parameter_sweeps\".\"status\""},
+	Ranges:          whereHelpertypes_JSON{field: "\"parameter_sweeps\".\"ranges\""},
+	ContactSheetURL: whereHelpernull_String{field: "\"parameter_sweeps\".\"contact_sheet_url\""},
+	ErrorMessage:    whereHelpernull_String{field: "\"parameter_sweeps\".\"error_message\""},
+	CreatedAt:       whereHelpertime_Time{field: "\"parameter_sweeps\".\"created_at\""},
+	UpdatedAt:       whereHelpertime_Time{field: "\"parameter_sweeps\".\"updated_at\""},
+}
+
+// ParameterSweepRels is where relationship names are stored.
+var ParameterSweepRels = struct {
+	Art               string
+	SweepCompositions string
+}{
+	Art:               "Art",
+	SweepCompositions: "SweepCompositions",
+}
+
+// parameterSweepR is where relationships are stored.
+type parameterSweepR struct {
+	Art               *Art             `boil:"Art" json:"Art" toml:"Art" yaml:"Art"`
+	SweepCompositions CompositionSlice `boil:"SweepCompositions" json:"SweepCompositions" toml:"SweepCompositions" yaml:"SweepCompositions"`
+}
+
+// NewStruct creates a new relationship struct
+func (*parameterSweepR) NewStruct() *parameterSweepR {
+	return &parameterSweepR{}
+}
+
+func (r *parameterSweepR) GetArt() *Art {
+	if r == nil {
+		return nil
+	}
+	return r.Art
+}
+
+func (r *parameterSweepR) GetSweepCompositions() CompositionSlice {
+	if r == nil {
+		return nil
+	}
+	return r.SweepCompositions
+}
+
+// parameterSweepL is where Load methods for each relationship are stored.
+type parameterSweepL struct{}
+
+var (
+	parameterSweepAllColumns            = []string{"id", "art_id", "status", "ranges", "contact_sheet_url", "error_message", "created_at", "updated_at"}
+	parameterSweepColumnsWithoutDefault = []string{"art_id", "ranges"}
+	parameterSweepColumnsWithDefault    = []string{"id", "status", "contact_sheet_url", "error_message", "created_at", "updated_at"}
+	parameterSweepPrimaryKeyColumns     = []string{"id"}
+	parameterSweepGeneratedColumns      = []string{}
+)
+
+type (
+	// ParameterSweepSlice is an alias for a slice of pointers to ParameterSweep.
+	// This should almost always be used instead of []ParameterSweep.
+	ParameterSweepSlice []*ParameterSweep
+	// ParameterSweepHook is the signature for custom ParameterSweep hook methods
+	ParameterSweepHook func(context.Context, boil.ContextExecutor, *ParameterSweep) error
+
+	parameterSweepQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	parameterSweepType                 = reflect.TypeOf(&ParameterSweep{})
+	parameterSweepMapping              = queries.MakeStructMapping(parameterSweepType)
+	parameterSweepPrimaryKeyMapping, _ = queries.BindMapping(parameterSweepType, parameterSweepMapping, parameterSweepPrimaryKeyColumns)
+	parameterSweepInsertCacheMut       sync.RWMutex
+	parameterSweepInsertCache          = make(map[string]insertCache)
+	parameterSweepUpdateCacheMut       sync.RWMutex
+	parameterSweepUpdateCache          = make(map[string]updateCache)
+	parameterSweepUpsertCacheMut       sync.RWMutex
+	parameterSweepUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var parameterSweepAfterSelectMu sync.Mutex
+var parameterSweepAfterSelectHooks []ParameterSweepHook
+
+var parameterSweepBeforeInsertMu sync.Mutex
+var parameterSweepBeforeInsertHooks []ParameterSweepHook
+var parameterSweepAfterInsertMu sync.Mutex
+var parameterSweepAfterInsertHooks []ParameterSweepHook
+
+var parameterSweepBeforeUpdateMu sync.Mutex
+var parameterSweepBeforeUpdateHooks []ParameterSweepHook
+var parameterSweepAfterUpdateMu sync.Mutex
+var parameterSweepAfterUpdateHooks []ParameterSweepHook
+
+var parameterSweepBeforeDeleteMu sync.Mutex
+var parameterSweepBeforeDeleteHooks []ParameterSweepHook
+var parameterSweepAfterDeleteMu sync.Mutex
+var parameterSweepAfterDeleteHooks []ParameterSweepHook
+
+var parameterSweepBeforeUpsertMu sync.Mutex
+var parameterSweepBeforeUpsertHooks []ParameterSweepHook
+var parameterSweepAfterUpsertMu sync.Mutex
+var parameterSweepAfterUpsertHooks []ParameterSweepHook
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *ParameterSweep) doAfterSelectHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range parameterSweepAfterSelectHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *ParameterSweep) doBeforeInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range parameterSweepBeforeInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *ParameterSweep) doAfterInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range parameterSweepAfterInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *ParameterSweep) doBeforeUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range parameterSweepBeforeUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *ParameterSweep) doAfterUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range parameterSweepAfterUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *ParameterSweep) doBeforeDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range parameterSweepBeforeDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *ParameterSweep) doAfterDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range parameterSweepAfterDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *ParameterSweep) doBeforeUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range parameterSweepBeforeUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *ParameterSweep) doAfterUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range parameterSweepAfterUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddParameterSweepHook registers your hook function for all future operations.
+func AddParameterSweepHook(hookPoint boil.HookPoint, parameterSweepHook ParameterSweepHook) {
+	switch hookPoint {
+	case boil.AfterSelectHook:
+		parameterSweepAfterSelectMu.Lock()
+		parameterSweepAfterSelectHooks = append(parameterSweepAfterSelectHooks, parameterSweepHook)
+		parameterSweepAfterSelectMu.Unlock()
+	case boil.BeforeInsertHook:
+		parameterSweepBeforeInsertMu.Lock()
+		parameterSweepBeforeInsertHooks = append(parameterSweepBeforeInsertHooks, parameterSweepHook)
+		parameterSweepBeforeInsertMu.Unlock()
+	case boil.AfterInsertHook:
+		parameterSweepAfterInsertMu.Lock()
+		parameterSweepAfterInsertHooks = append(parameterSweepAfterInsertHooks, parameterSweepHook)
+		parameterSweepAfterInsertMu.Unlock()
+	case boil.BeforeUpdateHook:
+		parameterSweepBeforeUpdateMu.Lock()
+		parameterSweepBeforeUpdateHooks = append(parameterSweepBeforeUpdateHooks, parameterSweepHook)
+		parameterSweepBeforeUpdateMu.Unlock()
+	case boil.AfterUpdateHook:
+		parameterSweepAfterUpdateMu.Lock()
+		parameterSweepAfterUpdateHooks = append(parameterSweepAfterUpdateHooks, parameterSweepHook)
+		parameterSweepAfterUpdateMu.Unlock()
+	case boil.BeforeDeleteHook:
+		parameterSweepBeforeDeleteMu.Lock()
+		parameterSweepBeforeDeleteHooks = append(parameterSweepBeforeDeleteHooks, parameterSweepHook)
+		parameterSweepBeforeDeleteMu.Unlock()
+	case boil.AfterDeleteHook:
+		parameterSweepAfterDeleteMu.Lock()
+		parameterSweepAfterDeleteHooks = append(parameterSweepAfterDeleteHooks, parameterSweepHook)
+		parameterSweepAfterDeleteMu.Unlock()
+	case boil.BeforeUpsertHook:
+		parameterSweepBeforeUpsertMu.Lock()
+		parameterSweepBeforeUpsertHooks = append(parameterSweepBeforeUpsertHooks, parameterSweepHook)
+		parameterSweepBeforeUpsertMu.Unlock()
+	case boil.AfterUpsertHook:
+		parameterSweepAfterUpsertMu.Lock()
+		parameterSweepAfterUpsertHooks = append(parameterSweepAfterUpsertHooks, parameterSweepHook)
+		parameterSweepAfterUpsertMu.Unlock()
+	}
+}
+
+// One returns a single parameterSweep record from the query.
+func (q parameterSweepQuery) One(ctx context.Context, exec boil.ContextExecutor) (*ParameterSweep, error) {
+	o := &ParameterSweep{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: failed to execute a one query for parameter_sweeps")
+	}
+
+	if err := o.doAfterSelectHooks(ctx, exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// All returns all ParameterSweep records from the query.
+func (q parameterSweepQuery) All(ctx context.Context, exec boil.ContextExecutor) (ParameterSweepSlice, error) {
+	var o []*ParameterSweep
+
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "models: failed to assign all query results to ParameterSweep slice")
+	}
+
+	if len(parameterSweepAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(ctx, exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Count returns the count of all ParameterSweep records in the query.
+func (q parameterSweepQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to count parameter_sweeps rows")
+	}
+
+	return count, nil
+}
+
+// Exists checks if the row exists in the table.
+func (q parameterSweepQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "models: failed to check if parameter_sweeps exists")
+	}
+
+	return count > 0, nil
+}
+
+// Art pointed to by the foreign key.
+func (o *ParameterSweep) Art(mods ...qm.QueryMod) artQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.ArtID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	return Arts(queryMods...)
+}
+
+// SweepCompositions retrieves all the composition's Compositions with an executor via sweep_id column.
+func (o *ParameterSweep) SweepCompositions(mods ...qm.QueryMod) compositionQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"compositions\".\"sweep_id\"=?", o.ID),
+	)
+
+	return Compositions(queryMods...)
+}
+
+// LoadArt allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (parameterSweepL) LoadArt(ctx context.Context, e boil.ContextExecutor, singular bool, maybeParameterSweep interface{}, mods queries.Applicator) error {
+	var slice []*ParameterSweep
+	var object *ParameterSweep
+
+	if singular {
+		var ok bool
+		object, ok = maybeParameterSweep.(*ParameterSweep)
+		if !ok {
+			object = new(ParameterSweep)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeParameterSweep)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeParameterSweep))
+			}
+		}
+	} else {
+		s, ok := maybeParameterSweep.(*[]*ParameterSweep)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeParameterSweep)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeParameterSweep))
+			}
+		}
+	}
+
+	args := make(map[interface{}]struct{})
+	if singular {
+		if object.R == nil {
+			object.R = &parameterSweepR{}
+		}
+		args[object.ArtID] = struct{}{}
+
+	} else {
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &parameterSweepR{}
+			}
+
+			args[obj.ArtID] = struct{}{}
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	argsSlice := make([]interface{}, len(args))
+	i := 0
+	for arg := range args {
+		argsSlice[i] = arg
+		i++
+	}
+
+	query := NewQuery(
+		qm.From(`arts`),
+		qm.WhereIn(`arts.id in ?`, argsSlice...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Art")
+	}
+
+	var resultSlice []*Art
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Art")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for arts")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for arts")
+	}
+
+	if len(artAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Art = foreign
+		if foreign.R == nil {
+			foreign.R = &artR{}
+		}
+		foreign.R.ParameterSweeps = append(foreign.R.ParameterSweeps, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.ArtID == foreign.ID {
+				local.R.Art = foreign
+				if foreign.R == nil {
+					foreign.R = &artR{}
+				}
+				foreign.R.ParameterSweeps = append(foreign.R.ParameterSweeps, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadSweepCompositions allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (parameterSweepL) LoadSweepCompositions(ctx context.Context, e boil.ContextExecutor, singular bool, maybeParameterSweep interface{}, mods queries.Applicator) error {
+	var slice []*ParameterSweep
+	var object *ParameterSweep
+
+	if singular {
+		var ok bool
+		object, ok = maybeParameterSweep.(*ParameterSweep)
+		if !ok {
+			object = new(ParameterSweep)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeParameterSweep)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeParameterSweep))
+			}
+		}
+	} else {
+		s, ok := maybeParameterSweep.(*[]*ParameterSweep)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeParameterSweep)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeParameterSweep))
+			}
+		}
+	}
+
+	args := make(map[interface{}]struct{})
+	if singular {
+		if object.R == nil {
+			object.R = &parameterSweepR{}
+		}
+		args[object.ID] = struct{}{}
+	} else {
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &parameterSweepR{}
+			}
+			args[obj.ID] = struct{}{}
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	argsSlice := make([]interface{}, len(args))
+	i := 0
+	for arg := range args {
+		argsSlice[i] = arg
+		i++
+	}
+
+	query := NewQuery(
+		qm.From(`compositions`),
+		qm.WhereIn(`compositions.sweep_id in ?`, argsSlice...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load compositions")
+	}
+
+	var resultSlice []*Composition
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice compositions")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on compositions")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for compositions")
+	}
+
+	if len(compositionAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.SweepCompositions = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &compositionR{}
+			}
+			foreign.R.Sweep = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if queries.Equal(local.ID, foreign.SweepID) {
+				local.R.SweepCompositions = append(local.R.SweepCompositions, foreign)
+				if foreign.R == nil {
+					foreign.R = &compositionR{}
+				}
+				foreign.R.Sweep = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetArt of the parameterSweep to the related item.
+// Sets o.R.Art to related.
+// Adds o to related.R.ParameterSweeps.
+func (o *ParameterSweep) SetArt(ctx context.Context, exec boil.ContextExecutor, insert bool, related *Art) error {
+	var err error
+	if insert {
+		if err = related.Insert(ctx, exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"parameter_sweeps\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"art_id"}),
+		strmangle.WhereClause("\"", "\"", 2, parameterSweepPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, updateQuery)
+		fmt.Fprintln(writer, values)
+	}
+	if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	o.ArtID = related.ID
+	if o.R == nil {
+		o.R = &parameterSweepR{
+			Art: related,
+		}
+	} else {
+		o.R.Art = related
+	}
+
+	if related.R == nil {
+		related.R = &artR{
+			ParameterSweeps: ParameterSweepSlice{o},
+		}
+	} else {
+		related.R.ParameterSweeps = append(related.R.ParameterSweeps, o)
+	}
+
+	return nil
+}
+
+// AddSweepCompositions adds the given related objects to the existing relationships
+// of the parameter_sweep, optionally inserting them as new records.
+// Appends related to o.R.SweepCompositions.
+// Sets related.R.Sweep appropriately.
+func (o *ParameterSweep) AddSweepCompositions(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*Composition) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			queries.Assign(&rel.SweepID, o.ID)
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"compositions\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"sweep_id"}),
+				strmangle.WhereClause("\"", "\"", 2, compositionPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			queries.Assign(&rel.SweepID, o.ID)
+		}
+	}
+
+	if o.R == nil {
+		o.R = &parameterSweepR{
+			SweepCompositions: related,
+		}
+	} else {
+		o.R.SweepCompositions = append(o.R.SweepCompositions, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &compositionR{
+				Sweep: o,
+			}
+		} else {
+			rel.R.Sweep = o
+		}
+	}
+	return nil
+}
+
+// SetSweepCompositions removes all previously related items of the
+// parameter_sweep replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.Sweep's SweepCompositions accordingly.
+// Replaces o.R.SweepCompositions with related.
+// Sets related.R.Sweep's SweepCompositions accordingly.
+func (o *ParameterSweep) SetSweepCompositions(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*Composition) error {
+	query := "update \"compositions\" set \"sweep_id\" = null where \"sweep_id\" = $1"
+	values := []interface{}{o.ID}
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, query)
+		fmt.Fprintln(writer, values)
+	}
+	_, err := exec.ExecContext(ctx, query, values...)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove relationships before set")
+	}
+
+	if o.R != nil {
+		for _, rel := range o.R.SweepCompositions {
+			queries.SetScanner(&rel.SweepID, nil)
+			if rel.R == nil {
+				continue
+			}
+
+			rel.R.Sweep = nil
+		}
+		o.R.SweepCompositions = nil
+	}
+
+	return o.AddSweepCompositions(ctx, exec, insert, related...)
+}
+
+// RemoveSweepCompositions relationships from objects passed in.
+// Removes related items from R.SweepCompositions (uses pointer comparison, removal does not keep order)
+// Sets related.R.Sweep.
+func (o *ParameterSweep) RemoveSweepCompositions(ctx context.Context, exec boil.ContextExecutor, related ...*Composition) error {
+	if len(related) == 0 {
+		return nil
+	}
+
+	var err error
+	for _, rel := range related {
+		queries.SetScanner(&rel.SweepID, nil)
+		if rel.R != nil {
+			rel.R.Sweep = nil
+		}
+		if _, err = rel.Update(ctx, exec, boil.Whitelist("sweep_id")); err != nil {
+			return err
+		}
+	}
+	if o.R == nil {
+		return nil
+	}
+
+	for _, rel := range related {
+		for i, ri := range o.R.SweepCompositions {
+			if rel != ri {
+				continue
+			}
+
+			ln := len(o.R.SweepCompositions)
+			if ln > 1 && i < ln-1 {
+				o.R.SweepCompositions[i] = o.R.SweepCompositions[ln-1]
+			}
+			o.R.SweepCompositions = o.R.SweepCompositions[:ln-1]
+			break
+		}
+	}
+
+	return nil
+}
+
+// ParameterSweeps retrieves all the records using an executor.
+func ParameterSweeps(mods ...qm.QueryMod) parameterSweepQuery {
+	mods = append(mods, qm.From("\"parameter_sweeps\""))
+	q := NewQuery(mods...)
+	if len(queries.GetSelect(q)) == 0 {
+		queries.SetSelect(q, []string{"\"parameter_sweeps\".*"})
+	}
+
+	return parameterSweepQuery{q}
+}
+
+// FindParameterSweep retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindParameterSweep(ctx context.Context, exec boil.ContextExecutor, iD string, selectCols ...string) (*ParameterSweep, error) {
+	parameterSweepObj := &ParameterSweep{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"parameter_sweeps\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(ctx, exec, parameterSweepObj)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: unable to select from parameter_sweeps")
+	}
+
+	if err = parameterSweepObj.doAfterSelectHooks(ctx, exec); err != nil {
+		return parameterSweepObj, err
+	}
+
+	return parameterSweepObj, nil
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *ParameterSweep) Insert(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no parameter_sweeps provided for insertion")
+	}
+
+	var err error
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+		if o.UpdatedAt.IsZero() {
+			o.UpdatedAt = currTime
+		}
+	}
+
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(parameterSweepColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	parameterSweepInsertCacheMut.RLock()
+	cache, cached := parameterSweepInsertCache[key]
+	parameterSweepInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			parameterSweepAllColumns,
+			parameterSweepColumnsWithDefault,
+			parameterSweepColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(parameterSweepType, parameterSweepMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(parameterSweepType, parameterSweepMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"parameter_sweeps\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"parameter_sweeps\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "models: unable to insert into parameter_sweeps")
+	}
+
+	if !cached {
+		parameterSweepInsertCacheMut.Lock()
+		parameterSweepInsertCache[key] = cache
+		parameterSweepInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(ctx, exec)
+}
+
+// Update uses an executor to update the ParameterSweep.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *ParameterSweep) Update(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) (int64, error) {
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		o.UpdatedAt = currTime
+	}
+
+	var err error
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	parameterSweepUpdateCacheMut.RLock()
+	cache, cached := parameterSweepUpdateCache[key]
+	parameterSweepUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			parameterSweepAllColumns,
+			parameterSweepPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("models: unable to update parameter_sweeps, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"parameter_sweeps\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, parameterSweepPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(parameterSweepType, parameterSweepMapping, append(wl, parameterSweepPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, values)
+	}
+	var result sql.Result
+	result, err = exec.ExecContext(ctx, cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update parameter_sweeps row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by update for parameter_sweeps")
+	}
+
+	if !cached {
+		parameterSweepUpdateCacheMut.Lock()
+		parameterSweepUpdateCache[key] = cache
+		parameterSweepUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(ctx, exec)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q parameterSweepQuery) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all for parameter_sweeps")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected for parameter_sweeps")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o ParameterSweepSlice) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("models: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), parameterSweepPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"parameter_sweeps\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, parameterSweepPrimaryKeyColumns, len(o)))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all in parameterSweep slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected all in update all parameterSweep")
+	}
+	return rowsAff, nil
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *ParameterSweep) Upsert(ctx context.Context, exec boil.ContextExecutor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns, opts ...UpsertOptionFunc) error {
+	if o == nil {
+		return errors.New("models: no parameter_sweeps provided for upsert")
+	}
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+		o.UpdatedAt = currTime
+	}
+
+	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(parameterSweepColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	parameterSweepUpsertCacheMut.RLock()
+	cache, cached := parameterSweepUpsertCache[key]
+	parameterSweepUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, _ := insertColumns.InsertColumnSet(
+			parameterSweepAllColumns,
+			parameterSweepColumnsWithDefault,
+			parameterSweepColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		update := updateColumns.UpdateColumnSet(
+			parameterSweepAllColumns,
+			parameterSweepPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("models: unable to upsert parameter_sweeps, could not build update column list")
+		}
+
+		ret := strmangle.SetComplement(parameterSweepAllColumns, strmangle.SetIntersect(insert, update))
+
+		conflict := conflictColumns
+		if len(conflict) == 0 && updateOnConflict && len(update) != 0 {
+			if len(parameterSweepPrimaryKeyColumns) == 0 {
+				return errors.New("models: unable to upsert parameter_sweeps, could not build conflict column list")
+			}
+
+			conflict = make([]string, len(parameterSweepPrimaryKeyColumns))
+			copy(conflict, parameterSweepPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"parameter_sweeps\"", updateOnConflict, ret, update, conflict, insert, opts...)
+
+		cache.valueMapping, err = queries.BindMapping(parameterSweepType, parameterSweepMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(parameterSweepType, parameterSweepMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(returns...)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "models: unable to upsert parameter_sweeps")
+	}
+
+	if !cached {
+		parameterSweepUpsertCacheMut.Lock()
+		parameterSweepUpsertCache[key] = cache
+		parameterSweepUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(ctx, exec)
+}
+
+// Delete deletes a single ParameterSweep record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *ParameterSweep) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("models: no ParameterSweep provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), parameterSweepPrimaryKeyMapping)
+	sql := "DELETE FROM \"parameter_sweeps\" WHERE \"id\"=$1"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete from parameter_sweeps")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by delete for parameter_sweeps")
+	}
+
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all matching rows.
+func (q parameterSweepQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("models: no parameterSweepQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from parameter_sweeps")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for parameter_sweeps")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o ParameterSweepSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(parameterSweepBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), parameterSweepPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"parameter_sweeps\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, parameterSweepPrimaryKeyColumns, len(o))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from parameterSweep slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for parameter_sweeps")
+	}
+
+	if len(parameterSweepAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *ParameterSweep) Reload(ctx context.Context, exec boil.ContextExecutor) error {
+	ret, err := FindParameterSweep(ctx, exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *ParameterSweepSlice) ReloadAll(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := ParameterSweepSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), parameterSweepPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"parameter_sweeps\".* FROM \"parameter_sweeps\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, parameterSweepPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(ctx, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to reload all in ParameterSweepSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// ParameterSweepExists checks if the ParameterSweep row exists.
+func ParameterSweepExists(ctx context.Context, exec boil.ContextExecutor, iD string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"parameter_sweeps\" where \"id\"=$1 limit 1)"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, iD)
+	}
+	row := exec.QueryRowContext(ctx, sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "models: unable to check if parameter_sweeps exists")
+	}
+
+	return exists, nil
+}
+
+// Exists checks if the ParameterSweep row exists.
+func (o *ParameterSweep) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	return ParameterSweepExists(ctx, exec, o.ID)
+}
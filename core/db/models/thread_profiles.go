@@ -0,0 +1,1460 @@
+// Code generated by SQLBoiler 4.16.2 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"github.com/volatiletech/strmangle"
+)
+
+// ThreadProfile is an object representing the database table.
+type ThreadProfile struct {
+	ID     string `boil:"id" json:"id" toml:"id" yaml:"id"`
+	UserID string `boil:"user_id" json:"user_id" toml:"user_id" yaml:"user_id"`
+	// Name of the profile, unique per user
+	Name string `boil:"name" json:"name" toml:"name" yaml:"name"`
+	// Darkening of the background under a thread, from 0 to 1
+	Opacity float64 `boil:"opacity" json:"opacity" toml:"opacity" yaml:"opacity"`
+	// Apparent thread width in mm
+	ThreadWidth float64 `boil:"thread_width" json:"thread_width" toml:"thread_width" yaml:"thread_width"`
+	// Measured bands of the calibration photo with their density and brightness
+	Bands types.JSON `boil:"bands" json:"bands" toml:"bands" yaml:"bands"`
+	// Root mean square error of the fitted model on the measured bands
+	FitError  float64   `boil:"fit_error" json:"fit_error" toml:"fit_error" yaml:"fit_error"`
+	CreatedAt time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt time.Time `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+
+	R *threadProfileR `boil:"-" json:"-" toml:"-" yaml:"-"`
+	L threadProfileL  `boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var ThreadProfileColumns = struct {
+	ID          string
+	UserID      string
+	Name        string
+	Opacity     string
+	ThreadWidth string
+	Bands       string
+	FitError    string
+	CreatedAt   string
+	UpdatedAt   string
+}{
+	ID:          "id",
+	UserID:      "user_id",
+	Name:        "name",
+	Opacity:     "opacity",
+	ThreadWidth: "thread_width",
+	Bands:       "bands",
+	FitError:    "fit_error",
+	CreatedAt:   "created_at",
+	UpdatedAt:   "updated_at",
+}
+
+var ThreadProfileTableColumns = struct {
+	ID          string
+	UserID      string
+	Name        string
+	Opacity     string
+	ThreadWidth string
+	Bands       string
+	FitError    string
+	CreatedAt   string
+	UpdatedAt   string
+}{
+	ID:          "thread_profiles.id",
+	UserID:      "thread_profiles.user_id",
+	Name:        "thread_profiles.name",
+	Opacity:     "thread_profiles.opacity",
+	ThreadWidth: "thread_profiles.thread_width",
+	Bands:       "thread_profiles.bands",
+	FitError:    "thread_profiles.fit_error",
+	CreatedAt:   "thread_profiles.created_at",
+	UpdatedAt:   "thread_profiles.updated_at",
+}
+
+// Generated where
+
+var ThreadProfileWhere = struct {
+	ID          whereHelperstring
+	UserID      whereHelperstring
+	Name        whereHelperstring
+	Opacity     whereHelperfloat64
+	ThreadWidth whereHelperfloat64
+	Bands       whereHelpertypes_JSON
+	FitError    whereHelperfloat64
+	CreatedAt   whereHelpertime_Time
+	UpdatedAt   whereHelpertime_Time
+}{
+	ID:          whereHelperstring{field: "\"thread_profiles\".\"id\""},
+	UserID:      whereHelperstring{field: "\"thread_profiles\".\"user_id\""},
+	Name:        whereHelperstring{field: "\"thread_profiles\".\"name\""},
+	Opacity:     whereHelperfloat64{field: "\"thread_profiles\".\"opacity\""},
+	ThreadWidth: whereHelperfloat64{field: "\"thread_profiles\".\"thread_width\""},
+	Bands:       whereHelpertypes_JSON{field: "\"thread_profiles\".\"bands\""},
+	FitError:    whereHelperfloat64{field: "\"thread_profiles\".\"fit_error\""},
+	CreatedAt:   whereHelpertime_Time{field: "\"thread_profiles\".\"created_at\""},
+	UpdatedAt:   whereHelpertime_Time{field: "\"thread_profiles\".\"updated_at\""},
+}
+
+// ThreadProfileRels is where relationship names are stored.
+var ThreadProfileRels = struct {
+	User         string
+	Compositions string
+}{
+	User:         "User",
+	Compositions: "Compositions",
+}
+
+// threadProfileR is where relationships are stored.
+type threadProfileR struct {
+	User         *User            `boil:"User" json:"User" toml:"User" yaml:"User"`
+	Compositions CompositionSlice `boil:"Compositions" json:"Compositions" toml:"Compositions" yaml:"Compositions"`
+}
+
+// NewStruct creates a new relationship struct
+func (*threadProfileR) NewStruct() *threadProfileR {
+	return &threadProfileR{}
+}
+
+func (r *threadProfileR) GetUser() *User {
+	if r == nil {
+		return nil
+	}
+	return r.User
+}
+
+func (r *threadProfileR) GetCompositions() CompositionSlice {
+	if r == nil {
+		return nil
+	}
+	return r.Compositions
+}
+
+// threadProfileL is where Load methods for each relationship are stored.
+type threadProfileL struct{}
+
+var (
+	threadProfileAllColumns            = []string{"id", "user_id", "name", "opacity", "thread_width", "bands", "fit_error", "created_at", "updated_at"}
+	threadProfileColumnsWithoutDefault = []string{"user_id", "name", "opacity", "thread_width", "bands", "fit_error"}
+	threadProfileColumnsWithDefault    = []string{"id", "created_at", "updated_at"}
+	threadProfilePrimaryKeyColumns     = []string{"id"}
+	threadProfileGeneratedColumns      = []string{}
+)
+
+type (
+	// ThreadProfileSlice is an alias for a slice of pointers to ThreadProfile.
+	// This should almost always be used instead of []ThreadProfile.
+	ThreadProfileSlice []*ThreadProfile
+	// ThreadProfileHook is the signature for custom ThreadProfile hook methods
+	ThreadProfileHook func(context.Context, boil.ContextExecutor, *ThreadProfile) error
+
+	threadProfileQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	threadProfileType                 = reflect.TypeOf(&ThreadProfile{})
+	threadProfileMapping              = queries.MakeStructMapping(threadProfileType)
+	threadProfilePrimaryKeyMapping, _ = queries.BindMapping(threadProfileType, threadProfileMapping, threadProfilePrimaryKeyColumns)
+	threadProfileInsertCacheMut       sync.RWMutex
+	threadProfileInsertCache          = make(map[string]insertCache)
+	threadProfileUpdateCacheMut       sync.RWMutex
+	threadProfileUpdateCache          = make(map[string]updateCache)
+	threadProfileUpsertCacheMut       sync.RWMutex
+	threadProfileUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var threadProfileAfterSelectMu sync.Mutex
+var threadProfileAfterSelectHooks []ThreadProfileHook
+
+var threadProfileBeforeInsertMu sync.Mutex
+var threadProfileBeforeInsertHooks []ThreadProfileHook
+var threadProfileAfterInsertMu sync.Mutex
+var threadProfileAfterInsertHooks []ThreadProfileHook
+
+var threadProfileBeforeUpdateMu sync.Mutex
+var threadProfileBeforeUpdateHooks []ThreadProfileHook
+var threadProfileAfterUpdateMu sync.Mutex
+var threadProfileAfterUpdateHooks []ThreadProfileHook
+
+var threadProfileBeforeDeleteMu sync.Mutex
+var threadProfileBeforeDeleteHooks []ThreadProfileHook
+var threadProfileAfterDeleteMu sync.Mutex
+var threadProfileAfterDeleteHooks []ThreadProfileHook
+
+var threadProfileBeforeUpsertMu sync.Mutex
+var threadProfileBeforeUpsertHooks []ThreadProfileHook
+var threadProfileAfterUpsertMu sync.Mutex
+var threadProfileAfterUpsertHooks []ThreadProfileHook
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *ThreadProfile) doAfterSelectHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range threadProfileAfterSelectHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *ThreadProfile) doBeforeInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range threadProfileBeforeInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *ThreadProfile) doAfterInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range threadProfileAfterInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *ThreadProfile) doBeforeUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range threadProfileBeforeUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *ThreadProfile) doAfterUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range threadProfileAfterUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *ThreadProfile) doBeforeDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range threadProfileBeforeDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *ThreadProfile) doAfterDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range threadProfileAfterDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *ThreadProfile) doBeforeUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range threadProfileBeforeUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *ThreadProfile) doAfterUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range threadProfileAfterUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddThreadProfileHook registers your hook function for all future operations.
+func AddThreadProfileHook(hookPoint boil.HookPoint, threadProfileHook ThreadProfileHook) {
+	switch hookPoint {
+	case boil.AfterSelectHook:
+		threadProfileAfterSelectMu.Lock()
+		threadProfileAfterSelectHooks = append(threadProfileAfterSelectHooks, threadProfileHook)
+		threadProfileAfterSelectMu.Unlock()
+	case boil.BeforeInsertHook:
+		threadProfileBeforeInsertMu.Lock()
+		threadProfileBeforeInsertHooks = append(threadProfileBeforeInsertHooks, threadProfileHook)
+		threadProfileBeforeInsertMu.Unlock()
+	case boil.AfterInsertHook:
+		threadProfileAfterInsertMu.Lock()
+		threadProfileAfterInsertHooks = append(threadProfileAfterInsertHooks, threadProfileHook)
+		threadProfileAfterInsertMu.Unlock()
+	case boil.BeforeUpdateHook:
+		threadProfileBeforeUpdateMu.Lock()
+		threadProfileBeforeUpdateHooks = append(threadProfileBeforeUpdateHooks, threadProfileHook)
+		threadProfileBeforeUpdateMu.Unlock()
+	case boil.AfterUpdateHook:
+		threadProfileAfterUpdateMu.Lock()
+		threadProfileAfterUpdateHooks = append(threadProfileAfterUpdateHooks, threadProfileHook)
+		threadProfileAfterUpdateMu.Unlock()
+	case boil.BeforeDeleteHook:
+		threadProfileBeforeDeleteMu.Lock()
+		threadProfileBeforeDeleteHooks = append(threadProfileBeforeDeleteHooks, threadProfileHook)
+		threadProfileBeforeDeleteMu.Unlock()
+	case boil.AfterDeleteHook:
+		threadProfileAfterDeleteMu.Lock()
+		threadProfileAfterDeleteHooks = append(threadProfileAfterDeleteHooks, threadProfileHook)
+		threadProfileAfterDeleteMu.Unlock()
+	case boil.BeforeUpsertHook:
+		threadProfileBeforeUpsertMu.Lock()
+		threadProfileBeforeUpsertHooks = append(threadProfileBeforeUpsertHooks, threadProfileHook)
+		threadProfileBeforeUpsertMu.Unlock()
+	case boil.AfterUpsertHook:
+		threadProfileAfterUpsertMu.Lock()
+		threadProfileAfterUpsertHooks = append(threadProfileAfterUpsertHooks, threadProfileHook)
+		threadProfileAfterUpsertMu.Unlock()
+	}
+}
+
+// One returns a single threadProfile record from the query.
+func (q threadProfileQuery) One(ctx context.Context, exec boil.ContextExecutor) (*ThreadProfile, error) {
+	o := &ThreadProfile{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: failed to execute a one query for thread_profiles")
+	}
+
+	if err := o.doAfterSelectHooks(ctx, exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// All returns all ThreadProfile records from the query.
+func (q threadProfileQuery) All(ctx context.Context, exec boil.ContextExecutor) (ThreadProfileSlice, error) {
+	var o []*ThreadProfile
+
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "models: failed to assign all query results to ThreadProfile slice")
+	}
+
+	if len(threadProfileAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(ctx, exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Count returns the count of all ThreadProfile records in the query.
+func (q threadProfileQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to count thread_profiles rows")
+	}
+
+	return count, nil
+}
+
+// Exists checks if the row exists in the table.
+func (q threadProfileQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "models: failed to check if thread_profiles exists")
+	}
+
+	return count > 0, nil
+}
+
+// User pointed to by the foreign key.
+func (o *ThreadProfile) User(mods ...qm.QueryMod) userQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.UserID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	return Users(queryMods...)
+}
+
+// Compositions retrieves all the composition's Compositions with an executor.
+func (o *ThreadProfile) Compositions(mods ...qm.QueryMod) compositionQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"compositions\".\"thread_profile_id\"=?", o.ID),
+	)
+
+	return Compositions(queryMods...)
+}
+
+// LoadUser allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (threadProfileL) LoadUser(ctx context.Context, e boil.ContextExecutor, singular bool, maybeThreadProfile interface{}, mods queries.Applicator) error {
+	var slice []*ThreadProfile
+	var object *ThreadProfile
+
+	if singular {
+		var ok bool
+		object, ok = maybeThreadProfile.(*ThreadProfile)
+		if !ok {
+			object = new(ThreadProfile)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeThreadProfile)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeThreadProfile))
+			}
+		}
+	} else {
+		s, ok := maybeThreadProfile.(*[]*ThreadProfile)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeThreadProfile)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeThreadProfile))
+			}
+		}
+	}
+
+	args := make(map[interface{}]struct{})
+	if singular {
+		if object.R == nil {
+			object.R = &threadProfileR{}
+		}
+		args[object.UserID] = struct{}{}
+
+	} else {
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &threadProfileR{}
+			}
+
+			args[obj.UserID] = struct{}{}
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	argsSlice := make([]interface{}, len(args))
+	i := 0
+	for arg := range args {
+		argsSlice[i] = arg
+		i++
+	}
+
+	query := NewQuery(
+		qm.From(`users`),
+		qm.WhereIn(`users.id in ?`, argsSlice...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load User")
+	}
+
+	var resultSlice []*User
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice User")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for users")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for users")
+	}
+
+	if len(userAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.User = foreign
+		if foreign.R == nil {
+			foreign.R = &userR{}
+		}
+		foreign.R.ThreadProfiles = append(foreign.R.ThreadProfiles, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.UserID == foreign.ID {
+				local.R.User = foreign
+				if foreign.R == nil {
+					foreign.R = &userR{}
+				}
+				foreign.R.ThreadProfiles = append(foreign.R.ThreadProfiles, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadCompositions allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (threadProfileL) LoadCompositions(ctx context.Context, e boil.ContextExecutor, singular bool, maybeThreadProfile interface{}, mods queries.Applicator) error {
+	var slice []*ThreadProfile
+	var object *ThreadProfile
+
+	if singular {
+		var ok bool
+		object, ok = maybeThreadProfile.(*ThreadProfile)
+		if !ok {
+			object = new(ThreadProfile)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeThreadProfile)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeThreadProfile))
+			}
+		}
+	} else {
+		s, ok := maybeThreadProfile.(*[]*ThreadProfile)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeThreadProfile)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeThreadProfile))
+			}
+		}
+	}
+
+	args := make(map[interface{}]struct{})
+	if singular {
+		if object.R == nil {
+			object.R = &threadProfileR{}
+		}
+		args[object.ID] = struct{}{}
+	} else {
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &threadProfileR{}
+			}
+			args[obj.ID] = struct{}{}
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	argsSlice := make([]interface{}, len(args))
+	i := 0
+	for arg := range args {
+		argsSlice[i] = arg
+		i++
+	}
+
+	query := NewQuery(
+		qm.From(`compositions`),
+		qm.WhereIn(`compositions.thread_profile_id in ?`, argsSlice...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load compositions")
+	}
+
+	var resultSlice []*Composition
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice compositions")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on compositions")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for compositions")
+	}
+
+	if len(compositionAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.Compositions = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &compositionR{}
+			}
+			foreign.R.ThreadProfile = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if queries.Equal(local.ID, foreign.ThreadProfileID) {
+				local.R.Compositions = append(local.R.Compositions, foreign)
+				if foreign.R == nil {
+					foreign.R = &compositionR{}
+				}
+				foreign.R.ThreadProfile = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetUser of the threadProfile to the related item.
+// Sets o.R.User to related.
+// Adds o to related.R.ThreadProfiles.
+func (o *ThreadProfile) SetUser(ctx context.Context, exec boil.ContextExecutor, insert bool, related *User) error {
+	var err error
+	if insert {
+		if err = related.Insert(ctx, exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"thread_profiles\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"user_id"}),
+		strmangle.WhereClause("\"", "\"", 2, threadProfilePrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, updateQuery)
+		fmt.Fprintln(writer, values)
+	}
+	if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	o.UserID = related.ID
+	if o.R == nil {
+		o.R = &threadProfileR{
+			User: related,
+		}
+	} else {
+		o.R.User = related
+	}
+
+	if related.R == nil {
+		related.R = &userR{
+			ThreadProfiles: ThreadProfileSlice{o},
+		}
+	} else {
+		related.R.ThreadProfiles = append(related.R.ThreadProfiles, o)
+	}
+
+	return nil
+}
+
+// AddCompositions adds the given related objects to the existing relationships
+// of the thread_profile, optionally inserting them as new records.
+// Appends related to o.R.Compositions.
+// Sets related.R.ThreadProfile appropriately.
+func (o *ThreadProfile) AddCompositions(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*Composition) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			queries.Assign(&rel.ThreadProfileID, o.ID)
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"compositions\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"thread_profile_id"}),
+				strmangle.WhereClause("\"", "\"", 2, compositionPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			queries.Assign(&rel.ThreadProfileID, o.ID)
+		}
+	}
+
+	if o.R == nil {
+		o.R = &threadProfileR{
+			Compositions: related,
+		}
+	} else {
+		o.R.Compositions = append(o.R.Compositions, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &compositionR{
+				ThreadProfile: o,
+			}
+		} else {
+			rel.R.ThreadProfile = o
+		}
+	}
+	return nil
+}
+
+// SetCompositions removes all previously related items of the
+// thread_profile replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.ThreadProfile's Compositions accordingly.
+// Replaces o.R.Compositions with related.
+// Sets related.R.ThreadProfile's Compositions accordingly.
+func (o *ThreadProfile) SetCompositions(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*Composition) error {
+	query := "update \"compositions\" set \"thread_profile_id\" = null where \"thread_profile_id\" = $1"
+	values := []interface{}{o.ID}
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, query)
+		fmt.Fprintln(writer, values)
+	}
+	_, err := exec.ExecContext(ctx, query, values...)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove relationships before set")
+	}
+
+	if o.R != nil {
+		for _, rel := range o.R.Compositions {
+			queries.SetScanner(&rel.ThreadProfileID, nil)
+			if rel.R == nil {
+				continue
+			}
+
+			rel.R.ThreadProfile = nil
+		}
+		o.R.Compositions = nil
+	}
+
+	return o.AddCompositions(ctx, exec, insert, related...)
+}
+
+// RemoveCompositions relationships from objects passed in.
+// Removes related items from R.Compositions (uses pointer comparison, removal does not keep order)
+// Sets related.R.ThreadProfile.
+func (o *ThreadProfile) RemoveCompositions(ctx context.Context, exec boil.ContextExecutor, related ...*Composition) error {
+	if len(related) == 0 {
+		return nil
+	}
+
+	var err error
+	for _, rel := range related {
+		queries.SetScanner(&rel.ThreadProfileID, nil)
+		if rel.R != nil {
+			rel.R.ThreadProfile = nil
+		}
+		if _, err = rel.Update(ctx, exec, boil.Whitelist("thread_profile_id")); err != nil {
+			return err
+		}
+	}
+	if o.R == nil {
+		return nil
+	}
+
+	for _, rel := range related {
+		for i, ri := range o.R.Compositions {
+			if rel != ri {
+				continue
+			}
+
+			ln := len(o.R.Compositions)
+			if ln > 1 && i < ln-1 {
+				o.R.Compositions[i] = o.R.Compositions[ln-1]
+			}
+			o.R.Compositions = o.R.Compositions[:ln-1]
+			break
+		}
+	}
+
+	return nil
+}
+
+// ThreadProfiles retrieves all the records using an executor.
+func ThreadProfiles(mods ...qm.QueryMod) threadProfileQuery {
+	mods = append(mods, qm.From("\"thread_profiles\""))
+	q := NewQuery(mods...)
+	if len(queries.GetSelect(q)) == 0 {
+		queries.SetSelect(q, []string{"\"thread_profiles\".*"})
+	}
+
+	return threadProfileQuery{q}
+}
+
+// FindThreadProfile retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindThreadProfile(ctx context.Context, exec boil.ContextExecutor, iD string, selectCols ...string) (*ThreadProfile, error) {
+	threadProfileObj := &ThreadProfile{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"thread_profiles\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(ctx, exec, threadProfileObj)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: unable to select from thread_profiles")
+	}
+
+	if err = threadProfileObj.doAfterSelectHooks(ctx, exec); err != nil {
+		return threadProfileObj, err
+	}
+
+	return threadProfileObj, nil
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *ThreadProfile) Insert(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no thread_profiles provided for insertion")
+	}
+
+	var err error
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+		if o.UpdatedAt.IsZero() {
+			o.UpdatedAt = currTime
+		}
+	}
+
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(threadProfileColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	threadProfileInsertCacheMut.RLock()
+	cache, cached := threadProfileInsertCache[key]
+	threadProfileInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			threadProfileAllColumns,
+			threadProfileColumnsWithDefault,
+			threadProfileColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(threadProfileType, threadProfileMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(threadProfileType, threadProfileMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"thread_profiles\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"thread_profiles\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "models: unable to insert into thread_profiles")
+	}
+
+	if !cached {
+		threadProfileInsertCacheMut.Lock()
+		threadProfileInsertCache[key] = cache
+		threadProfileInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(ctx, exec)
+}
+
+// Update uses an executor to update the ThreadProfile.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *ThreadProfile) Update(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) (int64, error) {
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		o.UpdatedAt = currTime
+	}
+
+	var err error
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	threadProfileUpdateCacheMut.RLock()
+	cache, cached := threadProfileUpdateCache[key]
+	threadProfileUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			threadProfileAllColumns,
+			threadProfilePrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("models: unable to update thread_profiles, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"thread_profiles\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, threadProfilePrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(threadProfileType, threadProfileMapping, append(wl, threadProfilePrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, values)
+	}
+	var result sql.Result
+	result, err = exec.ExecContext(ctx, cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update thread_profiles row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by update for thread_profiles")
+	}
+
+	if !cached {
+		threadProfileUpdateCacheMut.Lock()
+		threadProfileUpdateCache[key] = cache
+		threadProfileUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(ctx, exec)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q threadProfileQuery) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all for thread_profiles")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected for thread_profiles")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o ThreadProfileSlice) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("models: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), threadProfilePrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"thread_profiles\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, threadProfilePrimaryKeyColumns, len(o)))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all in threadProfile slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected all in update all threadProfile")
+	}
+	return rowsAff, nil
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *ThreadProfile) Upsert(ctx context.Context, exec boil.ContextExecutor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns, opts ...UpsertOptionFunc) error {
+	if o == nil {
+		return errors.New("models: no thread_profiles provided for upsert")
+	}
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+		o.UpdatedAt = currTime
+	}
+
+	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(threadProfileColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	threadProfileUpsertCacheMut.RLock()
+	cache, cached := threadProfileUpsertCache[key]
+	threadProfileUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, _ := insertColumns.InsertColumnSet(
+			threadProfileAllColumns,
+			threadProfileColumnsWithDefault,
+			threadProfileColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		update := updateColumns.UpdateColumnSet(
+			threadProfileAllColumns,
+			threadProfilePrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("models: unable to upsert thread_profiles, could not build update column list")
+		}
+
+		ret := strmangle.SetComplement(threadProfileAllColumns, strmangle.SetIntersect(insert, update))
+
+		conflict := conflictColumns
+		if len(conflict) == 0 && updateOnConflict && len(update) != 0 {
+			if len(threadProfilePrimaryKeyColumns) == 0 {
+				return errors.New("models: unable to upsert thread_profiles, could not build conflict column list")
+			}
+
+			conflict = make([]string, len(threadProfilePrimaryKeyColumns))
+			copy(conflict, threadProfilePrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"thread_profiles\"", updateOnConflict, ret, update, conflict, insert, opts...)
+
+		cache.valueMapping, err = queries.BindMapping(threadProfileType, threadProfileMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(threadProfileType, threadProfileMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(returns...)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "models: unable to upsert thread_profiles")
+	}
+
+	if !cached {
+		threadProfileUpsertCacheMut.Lock()
+		threadProfileUpsertCache[key] = cache
+		threadProfileUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(ctx, exec)
+}
+
+// Delete deletes a single ThreadProfile record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *ThreadProfile) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("models: no ThreadProfile provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), threadProfilePrimaryKeyMapping)
+	sql := "DELETE FROM \"thread_profiles\" WHERE \"id\"=$1"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete from thread_profiles")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by delete for thread_profiles")
+	}
+
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all matching rows.
+func (q threadProfileQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("models: no threadProfileQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from thread_profiles")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for thread_profiles")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o ThreadProfileSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(threadProfileBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), threadProfilePrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"thread_profiles\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, threadProfilePrimaryKeyColumns, len(o))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from threadProfile slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for thread_profiles")
+	}
+
+	if len(threadProfileAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *ThreadProfile) Reload(ctx context.Context, exec boil.ContextExecutor) error {
+	ret, err := FindThreadProfile(ctx, exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *ThreadProfileSlice) ReloadAll(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := ThreadProfileSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), threadProfilePrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"thread_profiles\".* FROM \"thread_profiles\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, threadProfilePrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(ctx, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to reload all in ThreadProfileSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// ThreadProfileExists checks if the ThreadProfile row exists.
+func ThreadProfileExists(ctx context.Context, exec boil.ContextExecutor, iD string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"thread_profiles\" where \"id\"=$1 limit 1)"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, iD)
+	}
+	row := exec.QueryRowContext(ctx, sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "models: unable to check if thread_profiles exists")
+	}
+
+	return exists, nil
+}
+
+// Exists checks if the ThreadProfile row exists.
+func (o *ThreadProfile) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	return ThreadProfileExists(ctx, exec, o.ID)
+}
@@ -153,10 +153,12 @@ var UserRels = struct {
 	AccountActivations  string
 	AuthorArtVariations string
 	AuthorArts          string
+	ThreadProfiles      string
 }{
 	AccountActivations:  "AccountActivations",
 	AuthorArtVariations: "AuthorArtVariations",
 	AuthorArts:          "AuthorArts",
+	ThreadProfiles:      "ThreadProfiles",
 }
 
 // userR is where relationships are stored.
@@ -164,6 +166,7 @@ type userR struct {
 	AccountActivations  AccountActivationSlice `boil:"AccountActivations" json:"AccountActivations" toml:"AccountActivations" yaml:"AccountActivations"`
 	AuthorArtVariations ArtVariationSlice      `boil:"AuthorArtVariations" json:"AuthorArtVariations" toml:"AuthorArtVariations" yaml:"AuthorArtVariations"`
 	AuthorArts          ArtSlice               `boil:"AuthorArts" json:"AuthorArts" toml:"AuthorArts" yaml:"AuthorArts"`
+	ThreadProfiles      ThreadProfileSlice     `boil:"ThreadProfiles" json:"ThreadProfiles" toml:"ThreadProfiles" yaml:"ThreadProfiles"`
 }
 
 // NewStruct creates a new relationship struct
@@ -192,6 +195,13 @@ func (r *userR) GetAuthorArts() ArtSlice {
 	return r.AuthorArts
 }
 
+func (r *userR) GetThreadProfiles() ThreadProfileSlice {
+	if r == nil {
+		return nil
+	}
+	return r.ThreadProfiles
+}
+
 // userL is where Load methods for each relationship are stored.
 type userL struct{}
 
@@ -550,6 +560,20 @@ func (o *User) AuthorArts(mods ...qm.QueryMod) artQuery {
 	return Arts(queryMods...)
 }
 
+// ThreadProfiles retrieves all the thread_profile's ThreadProfiles with an executor.
+func (o *User) ThreadProfiles(mods ...qm.QueryMod) threadProfileQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"thread_profiles\".\"user_id\"=?", o.ID),
+	)
+
+	return ThreadProfiles(queryMods...)
+}
+
 // LoadAccountActivations allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for a 1-M or N-M relationship.
 func (userL) LoadAccountActivations(ctx context.Context, e boil.ContextExecutor, singular bool, maybeUser interface{}, mods queries.Applicator) error {
@@ -889,6 +913,119 @@ func (userL) LoadAuthorArts(ctx context.Context, e boil.ContextExecutor, singula
 	return nil
 }
 
+// LoadThreadProfiles allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (userL) LoadThreadProfiles(ctx context.Context, e boil.ContextExecutor, singular bool, maybeUser interface{}, mods queries.Applicator) error {
+	var slice []*User
+	var object *User
+
+	if singular {
+		var ok bool
+		object, ok = maybeUser.(*User)
+		if !ok {
+			object = new(User)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeUser)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeUser))
+			}
+		}
+	} else {
+		s, ok := maybeUser.(*[]*User)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeUser)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeUser))
+			}
+		}
+	}
+
+	args := make(map[interface{}]struct{})
+	if singular {
+		if object.R == nil {
+			object.R = &userR{}
+		}
+		args[object.ID] = struct{}{}
+	} else {
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &userR{}
+			}
+			args[obj.ID] = struct{}{}
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	argsSlice := make([]interface{}, len(args))
+	i := 0
+	for arg := range args {
+		argsSlice[i] = arg
+		i++
+	}
+
+	query := NewQuery(
+		qm.From(`thread_profiles`),
+		qm.WhereIn(`thread_profiles.user_id in ?`, argsSlice...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load thread_profiles")
+	}
+
+	var resultSlice []*ThreadProfile
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice thread_profiles")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on thread_profiles")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for thread_profiles")
+	}
+
+	if len(threadProfileAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.ThreadProfiles = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &threadProfileR{}
+			}
+			foreign.R.User = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.UserID {
+				local.R.ThreadProfiles = append(local.R.ThreadProfiles, foreign)
+				if foreign.R == nil {
+					foreign.R = &threadProfileR{}
+				}
+				foreign.R.User = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // AddAccountActivations adds the given related objects to the existing relationships
 // of the user, optionally inserting them as new records.
 // Appends related to o.R.AccountActivations.
@@ -1048,6 +1185,59 @@ func (o *User) AddAuthorArts(ctx context.Context, exec boil.ContextExecutor, ins
 	return nil
 }
 
+// AddThreadProfiles adds the given related objects to the existing relationships
+// of the user, optionally inserting them as new records.
+// Appends related to o.R.ThreadProfiles.
+// Sets related.R.User appropriately.
+func (o *User) AddThreadProfiles(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*ThreadProfile) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.UserID = o.ID
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"thread_profiles\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"user_id"}),
+				strmangle.WhereClause("\"", "\"", 2, threadProfilePrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.UserID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &userR{
+			ThreadProfiles: related,
+		}
+	} else {
+		o.R.ThreadProfiles = append(o.R.ThreadProfiles, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &threadProfileR{
+				User: o,
+			}
+		} else {
+			rel.R.User = o
+		}
+	}
+	return nil
+}
+
 // Users retrieves all the records using an executor.
 func Users(mods ...qm.QueryMod) userQuery {
 	mods = append(mods, qm.From("\"users\""))
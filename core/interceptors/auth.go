@@ -2,6 +2,7 @@ package interceptors
 
 import (
 	"context"
+	"crypto/subtle"
 	"net/http"
 	"slices"
 	"strings"
@@ -113,3 +114,21 @@ func AuthMiddleware(authService auth.AuthService) connect.UnaryInterceptorFunc {
 func isWhiteListedPath(path string) bool {
 	return slices.Contains(whiteListedPaths, path)
 }
+
+// APIKeyAuth creates a Connect middleware accepting only requests carrying the
+// given internal API key as a Bearer token
+func APIKeyAuth(apiKey string) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			fields := strings.Fields(req.Header().Get(authorizationHeader))
+			if len(fields) != 2 || !strings.EqualFold(fields[0], authorizationBearer) ||
+				subtle.ConstantTimeCompare([]byte(fields[1]), []byte(apiKey)) != 1 {
+				log.Warn().
+					Str("endpoint", req.Spec().Procedure).
+					Msg("API key authentication failed")
+				return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid api key"))
+			}
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package machine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	pbErrors "github.com/Damione1/thread-art-generator/core/errors"
+	"github.com/Damione1/thread-art-generator/core/pb"
+	"github.com/bufbuild/protovalidate-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxProgramSize bounds the G-code download, a 10k paths program is a few MB
+const maxProgramSize = 64 << 20
+
+// ConnectHandler exposes the controller through the MachineService Connect API
+type ConnectHandler struct {
+	controller *Controller
+	httpClient *http.Client
+}
+
+// NewConnectHandler creates the Connect handler for a controller
+func NewConnectHandler(controller *Controller) *ConnectHandler {
+	return &ConnectHandler{
+		controller: controller,
+		httpClient: &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// StartMachineJob implements the Connect handler interface
+func (h *ConnectHandler) StartMachineJob(ctx context.Context, req *connect.Request[pb.StartMachineJobRequest]) (*connect.Response[pb.MachineJob], error) {
+	if err := protovalidate.Validate(req.Msg); err != nil {
+		return nil, pbErrors.ConvertProtoValidateError(err)
+	}
+
+	lines, err := h.downloadProgram(ctx, req.Msg.GetGcodeUrl())
+	if err != nil {
+		return nil, pbErrors.InternalError("failed to download gcode", err)
+	}
+
+	if err := h.controller.Start(req.Msg.GetComposition(), req.Msg.GetGcodeUrl(), lines); err != nil {
+		return nil, controllerError(err)
+	}
+	return h.jobResponse(), nil
+}
+
+// GetMachineJob implements the Connect handler interface
+func (h *ConnectHandler) GetMachineJob(ctx context.Context, req *connect.Request[pb.GetMachineJobRequest]) (*connect.Response[pb.MachineJob], error) {
+	return h.jobResponse(), nil
+}
+
+// PauseMachineJob implements the Connect handler interface
+func (h *ConnectHandler) PauseMachineJob(ctx context.Context, req *connect.Request[pb.PauseMachineJobRequest]) (*connect.Response[pb.MachineJob], error) {
+	if err := h.controller.Pause(); err != nil {
+		return nil, controllerError(err)
+	}
+	return h.jobResponse(), nil
+}
+
+// ResumeMachineJob implements the Connect handler interface
+func (h *ConnectHandler) ResumeMachineJob(ctx context.Context, req *connect.Request[pb.ResumeMachineJobRequest]) (*connect.Response[pb.MachineJob], error) {
+	resume := h.controller.Resume
+	if gcode := req.Msg.GetGcode(); gcode != "" {
+		resume = func() error { return h.controller.ResumeFrom(splitProgram(gcode)) }
+	}
+	if err := resume(); err != nil {
+		return nil, controllerError(err)
+	}
+	return h.jobResponse(), nil
+}
+
+// AcknowledgeMachineHold implements the Connect handler interface
+func (h *ConnectHandler) AcknowledgeMachineHold(ctx context.Context, req *connect.Request[pb.AcknowledgeMachineHoldRequest]) (*connect.Response[pb.MachineJob], error) {
+	if err := h.controller.Acknowledge(); err != nil {
+		return nil, controllerError(err)
+	}
+	return h.jobResponse(), nil
+}
+
+// CancelMachineJob implements the Connect handler interface
+func (h *ConnectHandler) CancelMachineJob(ctx context.Context, req *connect.Request[pb.CancelMachineJobRequest]) (*connect.Response[pb.MachineJob], error) {
+	if err := h.controller.Cancel(); err != nil {
+		return nil, controllerError(err)
+	}
+	return h.jobResponse(), nil
+}
+
+func (h *ConnectHandler) jobResponse() *connect.Response[pb.MachineJob] {
+	return connect.NewResponse(JobToProto(h.controller.Job(), h.controller.TotalLines(), h.controller.CurrentStep()))
+}
+
+// downloadProgram fetches the G-code and splits it into lines
+func (h *ConnectHandler) downloadProgram(ctx context.Context, url string) ([]string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := h.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", response.Status)
+	}
+	if response.ContentLength > maxProgramSize {
+		return nil, fmt.Errorf("program of %d bytes exceeds the %d bytes limit", response.ContentLength, maxProgramSize)
+	}
+	// Read one byte past the limit so a truncated program is never streamed
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxProgramSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxProgramSize {
+		return nil, fmt.Errorf("program exceeds the %d bytes limit", maxProgramSize)
+	}
+	return splitProgram(string(data)), nil
+}
+
+// splitProgram splits G-code into lines
+func splitProgram(gcode string) []string {
+	return strings.Split(strings.ReplaceAll(gcode, "\r\n", "\n"), "\n")
+}
+
+// controllerError maps controller errors to API errors
+func controllerError(err error) error {
+	switch {
+	case errors.Is(err, ErrJobActive), errors.Is(err, ErrInvalidState), errors.Is(err, ErrResumeProgramRequired):
+		return pbErrors.FailedPreconditionError(err.Error())
+	case errors.Is(err, ErrResumeProgramMismatch):
+		return pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("gcode", err),
+		})
+	case errors.Is(err, ErrNoJob):
+		return pbErrors.NotFoundError(err.Error())
+	default:
+		return pbErrors.InternalError("machine controller error", err)
+	}
+}
+
+// JobToProto converts a job snapshot to its API representation
+func JobToProto(job *Job, totalLines, currentStep int) *pb.MachineJob {
+	if job == nil {
+		return &pb.MachineJob{State: pb.MachineJobState_MACHINE_JOB_STATE_IDLE}
+	}
+	return &pb.MachineJob{
+		Composition:  job.Composition,
+		State:        JobStateToProto(job.State),
+		CurrentLine:  int32(job.CurrentLine),
+		TotalLines:   int32(totalLines),
+		HoldMessage:  job.HoldMessage,
+		ErrorMessage: job.ErrorMessage,
+		StartTime:    timestamppb.New(job.StartTime),
+		UpdateTime:   timestamppb.New(job.UpdateTime),
+		Interrupted:  job.Interrupted,
+		CurrentStep:  int32(currentStep),
+	}
+}
+
+// JobStateToProto converts a job state to the proto enum
+func JobStateToProto(state JobState) pb.MachineJobState {
+	switch state {
+	case JobStateIdle:
+		return pb.MachineJobState_MACHINE_JOB_STATE_IDLE
+	case JobStateRunning:
+		return pb.MachineJobState_MACHINE_JOB_STATE_RUNNING
+	case JobStatePaused:
+		return pb.MachineJobState_MACHINE_JOB_STATE_PAUSED
+	case JobStateHold:
+		return pb.MachineJobState_MACHINE_JOB_STATE_HOLD
+	case JobStateComplete:
+		return pb.MachineJobState_MACHINE_JOB_STATE_COMPLETE
+	case JobStateFailed:
+		return pb.MachineJobState_MACHINE_JOB_STATE_FAILED
+	case JobStateCancelled:
+		return pb.MachineJobState_MACHINE_JOB_STATE_CANCELLED
+	default:
+		return pb.MachineJobState_MACHINE_JOB_STATE_UNSPECIFIED
+	}
+}
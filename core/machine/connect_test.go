@@ -0,0 +1,50 @@
+package machine
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadProgramLimit(t *testing.T) {
+	var size int64
+	var announce bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if announce {
+			w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+		}
+		io.Copy(w, io.LimitReader(neverEnding('G'), size))
+	}))
+	defer server.Close()
+	handler := NewConnectHandler(nil)
+
+	size = 64
+	lines, err := handler.downloadProgram(context.Background(), server.URL)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+
+	// A program past the limit is rejected rather than streamed truncated,
+	// whether or not its size is announced
+	size = maxProgramSize + 1
+	_, err = handler.downloadProgram(context.Background(), server.URL)
+	require.ErrorContains(t, err, "exceeds")
+
+	announce = true
+	_, err = handler.downloadProgram(context.Background(), server.URL)
+	require.ErrorContains(t, err, "exceeds")
+}
+
+// neverEnding is a reader repeating a byte
+type neverEnding byte
+
+func (b neverEnding) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(b)
+	}
+	return len(p), nil
+}
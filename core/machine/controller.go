@@ -0,0 +1,602 @@
+// Package machine streams G-code jobs to the FluidNC controller of the string
+// art machine and keeps track of their progress.
+package machine
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+)
+
+const (
+	// DefaultRxBufferSize is the receive buffer GRBL guarantees, FluidNC has at least as much
+	DefaultRxBufferSize = 127
+
+	realtimeFeedHold = "!"
+	realtimeReset    = "\x18"
+)
+
+var (
+	// ErrJobActive is returned when starting a job while another one is not finished
+	ErrJobActive = errors.New("a machine job is already active")
+	// ErrNoJob is returned when there is no job to act on
+	ErrNoJob = errors.New("no machine job")
+	// ErrInvalidState is returned when an action does not apply to the job state
+	ErrInvalidState = errors.New("action not allowed in the current job state")
+	// ErrResumeProgramRequired is returned when resuming an interrupted job past
+	// its first step without the program of its current step
+	ErrResumeProgramRequired = errors.New("an interrupted job resumes with the program of its current step")
+	// ErrResumeProgramMismatch is returned when the resume program starts at
+	// another step than the interrupted job
+	ErrResumeProgramMismatch = errors.New("the resume program does not start at the current step of the job")
+)
+
+type (
+	// Controller streams jobs to a GRBL style controller using character counting
+	// flow control: lines are sent as long as they fit in the controller receive
+	// buffer, and every ok or error frees the space of the oldest line.
+	Controller struct {
+		conn       io.ReadWriteCloser
+		store      JobStore
+		bufferSize int
+
+		mu        sync.Mutex
+		job       *Job
+		pause     bool // The operator asked to pause, applied once the buffer drained
+		cancel    bool
+		ack       bool // The operator acknowledged the current hold
+		streaming bool
+		closed    bool // Closed by the process, not by the controller
+		streams   sync.WaitGroup
+		wake      chan struct{}
+		responses chan string
+	}
+
+	// inflightLine is a line sent to the controller that is not acknowledged yet
+	inflightLine struct {
+		index int
+		size  int
+	}
+)
+
+// NewController wraps a connection to the controller. A job left active by a
+// previous run is loaded from the store as paused and interrupted, the machine
+// may have lost its position, so the operator decides when and how to resume
+// it.
+func NewController(conn io.ReadWriteCloser, store JobStore, bufferSize int) (*Controller, error) {
+	if bufferSize <= 0 {
+		bufferSize = DefaultRxBufferSize
+	}
+
+	c := &Controller{
+		conn:       conn,
+		store:      store,
+		bufferSize: bufferSize,
+		wake:       make(chan struct{}, 1),
+		responses:  make(chan string, 64),
+	}
+
+	job, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if job != nil && job.Active() {
+		job.State = JobStatePaused
+		job.HoldMessage = ""
+		job.Interrupted = true
+		job.UpdateTime = time.Now()
+		if err := store.Save(job); err != nil {
+			return nil, err
+		}
+		log.Info().Str("composition", job.Composition).Int("line", job.CurrentLine).Msg("Interrupted machine job loaded as paused")
+	}
+	c.job = job
+
+	go c.readResponses()
+	return c, nil
+}
+
+// Close closes the connection to the controller and waits for the stream loop
+// to stop. An active job is left as is so the next run loads it as interrupted.
+func (c *Controller) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	err := c.conn.Close()
+	c.streams.Wait()
+	return err
+}
+
+// Job returns a snapshot of the current job, or nil when there is none
+func (c *Controller) Job() *Job {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.job == nil {
+		return nil
+	}
+	job := *c.job
+	job.Lines = nil
+	return &job
+}
+
+// TotalLines returns the number of lines of the current job
+func (c *Controller) TotalLines() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.job == nil {
+		return 0
+	}
+	return len(c.job.Lines)
+}
+
+// CurrentStep returns the path the current line of the job belongs to, -1 when
+// there is no job, before its first path or for a program without step markers
+func (c *Controller) CurrentStep() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.job == nil {
+		return -1
+	}
+	step, _ := c.job.Step()
+	return step
+}
+
+// Start streams a new program to the controller
+func (c *Controller) Start(composition, gcodeURL string, lines []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.job != nil && c.job.Active() {
+		return ErrJobActive
+	}
+
+	now := time.Now()
+	job := &Job{
+		Composition: composition,
+		GcodeURL:    gcodeURL,
+		Lines:       lines,
+		State:       JobStateRunning,
+		StartTime:   now,
+		UpdateTime:  now,
+	}
+	if err := c.store.SaveProgram(lines); err != nil {
+		return err
+	}
+	if err := c.store.Save(job); err != nil {
+		return err
+	}
+
+	c.job = job
+	c.pause, c.cancel, c.ack = false, false, false
+	c.startStreaming()
+	return nil
+}
+
+// Pause stops streaming once the lines already buffered by the controller are executed
+func (c *Controller) Pause() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.job == nil {
+		return ErrNoJob
+	}
+	if c.job.State != JobStateRunning {
+		return ErrInvalidState
+	}
+	c.pause = true
+	c.signal()
+	return nil
+}
+
+// Resume continues a paused job from its last acknowledged line. An interrupted
+// job, restarted or failed on a controller error, starts over before its first
+// path, its program homes the machine first, past it the job needs the program
+// of its current step, see ResumeFrom.
+func (c *Controller) Resume() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.job == nil {
+		return ErrNoJob
+	}
+	switch c.job.State {
+	case JobStateRunning:
+		// Pause requested but not applied yet
+		c.pause = false
+	case JobStatePaused, JobStateFailed:
+		if c.job.State == JobStateFailed && !c.job.Interrupted {
+			return ErrInvalidState
+		}
+		if c.job.Interrupted {
+			step, ok := c.job.Step()
+			if !ok {
+				return fmt.Errorf("%w, the job program has no step markers so start a new job from GetCompositionGcodeFromStep", ErrResumeProgramRequired)
+			}
+			if step > 0 {
+				return fmt.Errorf("%w, get the program of step %d from GetCompositionGcodeFromStep", ErrResumeProgramRequired, step)
+			}
+			c.job.CurrentLine = 0
+			c.job.Interrupted = false
+		}
+		c.pause = false
+		c.job.State = JobStateRunning
+		c.job.ErrorMessage = ""
+		c.job.UpdateTime = time.Now()
+		c.saveLocked()
+		c.startStreaming()
+	default:
+		return ErrInvalidState
+	}
+	return nil
+}
+
+// ResumeFrom continues an interrupted job, paused or failed, with the program
+// of its current step, built by the resume-from-step generator. The program re-homes the
+// machine, moves to the nail the step starts from and holds so the thread is
+// tied again, the partly wound step is wound again from its start.
+func (c *Controller) ResumeFrom(lines []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.job == nil {
+		return ErrNoJob
+	}
+	if (c.job.State != JobStatePaused && c.job.State != JobStateFailed) || !c.job.Interrupted {
+		return ErrInvalidState
+	}
+
+	step, ok := c.job.Step()
+	if !ok {
+		return fmt.Errorf("%w, the job program has no step markers", ErrResumeProgramMismatch)
+	}
+	resumeStep, ok := resumeStep(lines)
+	if !ok || resumeStep != max(step, 0) {
+		return fmt.Errorf("%w, the job is at step %d", ErrResumeProgramMismatch, max(step, 0))
+	}
+
+	if err := c.store.SaveProgram(lines); err != nil {
+		return err
+	}
+	c.job.Lines = lines
+	c.job.CurrentLine = 0
+	c.job.Interrupted = false
+	c.job.State = JobStateRunning
+	c.job.ErrorMessage = ""
+	c.job.UpdateTime = time.Now()
+	c.saveLocked()
+
+	c.pause, c.cancel, c.ack = false, false, false
+	c.startStreaming()
+	return nil
+}
+
+// Acknowledge releases an M0 hold so the program continues
+func (c *Controller) Acknowledge() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.job == nil {
+		return ErrNoJob
+	}
+	if c.job.State != JobStateHold {
+		return ErrInvalidState
+	}
+	c.ack = true
+	c.signal()
+	return nil
+}
+
+// Cancel stops the job and resets the controller
+func (c *Controller) Cancel() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.job == nil {
+		return ErrNoJob
+	}
+	if !c.job.Active() {
+		return ErrInvalidState
+	}
+	if c.streaming {
+		c.cancel = true
+		c.signal()
+		return nil
+	}
+	c.job.State = JobStateCancelled
+	c.job.UpdateTime = time.Now()
+	c.saveLocked()
+	return nil
+}
+
+// startStreaming launches the stream loop, c.mu must be held
+func (c *Controller) startStreaming() {
+	if c.streaming {
+		return
+	}
+	c.streaming = true
+	// Drop responses to lines of a previous run, e.g. after a reset
+	for len(c.responses) > 0 {
+		<-c.responses
+	}
+	c.streams.Add(1)
+	go func() {
+		defer c.streams.Done()
+		c.stream()
+	}()
+}
+
+func (c *Controller) signal() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// stream sends the job lines and processes the controller responses until the
+// job pauses, completes, fails or is cancelled
+func (c *Controller) stream() {
+	job := c.job
+	next := job.CurrentLine
+	used := 0
+	var inflight []inflightLine
+
+	for {
+		c.mu.Lock()
+
+		if c.cancel {
+			c.cancel = false
+			c.writeLocked(realtimeReset)
+			c.finishLocked(JobStateCancelled, "")
+			c.mu.Unlock()
+			return
+		}
+
+		if job.State == JobStateHold && c.ack {
+			c.ack = false
+			job.State = JobStateRunning
+			job.HoldMessage = ""
+			next++
+			if len(inflight) == 0 {
+				job.CurrentLine = next
+			}
+			job.UpdateTime = time.Now()
+			c.saveLocked()
+		}
+
+		if job.State == JobStateRunning && !c.pause && next < len(job.Lines) {
+			line, comment := cleanLine(job.Lines[next])
+			switch {
+			case line == "":
+				next++
+				if len(inflight) == 0 {
+					job.CurrentLine = next
+				}
+				c.mu.Unlock()
+				continue
+			case isProgramPause(line):
+				// Holds are handled here instead of on the controller so the
+				// operator acknowledges them through the API
+				if len(inflight) == 0 {
+					job.State = JobStateHold
+					job.HoldMessage = comment
+					job.UpdateTime = time.Now()
+					c.saveLocked()
+				}
+			case len(inflight) == 0 || used+len(line)+1 <= c.bufferSize:
+				if err := c.writeLocked(line + "\n"); err != nil {
+					c.finishLocked(JobStateFailed, fmt.Sprintf("connection to the controller was lost while sending line %d: %v", next+1, err))
+					c.mu.Unlock()
+					return
+				}
+				inflight = append(inflight, inflightLine{index: next, size: len(line) + 1})
+				used += len(line) + 1
+				next++
+				c.mu.Unlock()
+				continue
+			}
+		}
+
+		if len(inflight) == 0 {
+			if job.State == JobStateRunning && next >= len(job.Lines) {
+				job.CurrentLine = len(job.Lines)
+				c.finishLocked(JobStateComplete, "")
+				c.mu.Unlock()
+				return
+			}
+			if job.State == JobStateRunning && c.pause {
+				c.pause = false
+				job.State = JobStatePaused
+				job.UpdateTime = time.Now()
+				c.saveLocked()
+				c.streaming = false
+				c.mu.Unlock()
+				return
+			}
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-c.wake:
+		case response, ok := <-c.responses:
+			c.mu.Lock()
+			if !ok {
+				c.finishLocked(JobStateFailed, "connection to the controller was lost")
+				c.mu.Unlock()
+				return
+			}
+			switch {
+			case response == "ok" && len(inflight) > 0:
+				// Every line before the oldest inflight one is done, skipped lines included
+				used -= inflight[0].size
+				inflight = inflight[1:]
+				job.CurrentLine = next
+				if len(inflight) > 0 {
+					job.CurrentLine = inflight[0].index
+				}
+				job.UpdateTime = time.Now()
+				// Saved on every line, a restart must not wind a nail twice
+				c.saveLocked()
+			case strings.HasPrefix(response, "error") || strings.HasPrefix(response, "ALARM"):
+				// Stop the motion then reset so neither the lines left in the
+				// receive buffer nor the planned motion run after a failure
+				c.writeLocked(realtimeFeedHold)
+				c.writeLocked(realtimeReset)
+				message := fmt.Sprintf("controller reported %s", response)
+				if len(inflight) > 0 {
+					index := inflight[0].index
+					message = fmt.Sprintf("controller reported %s on line %d: %s", response, index+1, strings.TrimSpace(job.Lines[index]))
+				}
+				inflight, used = nil, 0
+				for len(c.responses) > 0 {
+					<-c.responses
+				}
+				// The reset loses the machine position, the job resumes from
+				// the start of the step the last acknowledged line belongs to
+				job.CurrentLine = lastStepMarker(job.Lines, job.CurrentLine)
+				job.Interrupted = true
+				c.finishLocked(JobStateFailed, message)
+				c.mu.Unlock()
+				return
+			default:
+				log.Debug().Str("response", response).Msg("Controller message")
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// finishLocked ends the stream loop with a final state, c.mu must be held
+func (c *Controller) finishLocked(state JobState, errorMessage string) {
+	c.streaming = false
+	if c.closed {
+		return
+	}
+	c.job.State = state
+	c.job.ErrorMessage = errorMessage
+	c.job.HoldMessage = ""
+	c.job.UpdateTime = time.Now()
+	c.pause, c.ack = false, false
+	c.saveLocked()
+
+	event := log.Info()
+	if state == JobStateFailed {
+		event = log.Error().Str("error", errorMessage)
+	}
+	event.Str("composition", c.job.Composition).Str("state", string(state)).Int("line", c.job.CurrentLine).Msg("Machine job finished")
+}
+
+// saveLocked persists the job, c.mu must be held. Failing to persist must not
+// stop the machine so errors are only logged.
+func (c *Controller) saveLocked() {
+	if err := c.store.Save(c.job); err != nil {
+		log.Error().Err(err).Msg("Failed to save machine job")
+	}
+}
+
+func (c *Controller) writeLocked(data string) error {
+	_, err := io.WriteString(c.conn, data)
+	return err
+}
+
+// readResponses forwards every line received from the controller
+func (c *Controller) readResponses() {
+	scanner := bufio.NewScanner(c.conn)
+	for scanner.Scan() {
+		response := strings.TrimSpace(scanner.Text())
+		if response != "" {
+			c.responses <- response
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to read from the controller")
+	}
+	close(c.responses)
+}
+
+// lastStepMarker returns the index of the last step marker before the given
+// line, 0 when the line is before the first step, or the line itself for a
+// program without step markers
+func lastStepMarker(lines []string, line int) int {
+	marker, markers := 0, false
+	for i, text := range lines {
+		_, comment := cleanLine(text)
+		if _, ok := threadGenerator.ParseStepMarker(comment); !ok {
+			continue
+		}
+		markers = true
+		if i >= line {
+			break
+		}
+		marker = i
+	}
+	if !markers {
+		return line
+	}
+	return marker
+}
+
+// cleanLine strips comments and whitespace so only the code uses controller
+// buffer space. The comment is returned to label holds.
+func cleanLine(line string) (string, string) {
+	var comment string
+	if idx := strings.Index(line, ";"); idx >= 0 {
+		comment = strings.TrimSpace(line[idx+1:])
+		line = line[:idx]
+	}
+	for {
+		start := strings.Index(line, "(")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(line[start:], ")")
+		if end < 0 {
+			comment = strings.TrimSpace(line[start+1:])
+			line = line[:start]
+			break
+		}
+		comment = strings.TrimSpace(line[start+1 : start+end])
+		line = line[:start] + line[start+end+1:]
+	}
+	line = strings.TrimSpace(line)
+
+	// Marlin style M0 carries its message as plain text
+	if fields := strings.Fields(line); len(fields) > 1 && isProgramPause(fields[0]) {
+		if comment == "" {
+			comment = strings.Join(fields[1:], " ")
+		}
+		line = fields[0]
+	}
+	return line, comment
+}
+
+// resumeStep returns the step a resume program starts at, from the header
+// comment the generator writes before the first command
+func resumeStep(lines []string) (int, bool) {
+	for _, line := range lines {
+		code, comment := cleanLine(line)
+		if step, ok := threadGenerator.ParseResumeMarker(comment); ok {
+			return step, true
+		}
+		if code != "" {
+			break
+		}
+	}
+	return 0, false
+}
+
+// isProgramPause reports whether the code is an M0 or M1 program pause
+func isProgramPause(line string) bool {
+	fields := strings.Fields(strings.ToUpper(line))
+	if len(fields) == 0 {
+		return false
+	}
+	switch fields[0] {
+	case "M0", "M00", "M1", "M01":
+		return true
+	default:
+		return false
+	}
+}
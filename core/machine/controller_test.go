@@ -0,0 +1,306 @@
+package machine
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+)
+
+func testProgram(moves int) []string {
+	lines := []string{
+		"; Paths: 3",
+		"$H",
+		"G90",
+		"M0 (Pausing to allow for thread to be attached)",
+	}
+	for i := 0; i < moves; i++ {
+		lines = append(lines, fmt.Sprintf("G0 X%d Y%d.%03d", i%10, i, i), "")
+	}
+	return lines
+}
+
+// testStepProgram returns a program with the step markers of the generator
+// before the moves of each step
+func testStepProgram(steps, movesPerStep int) []string {
+	lines := []string{
+		"; Paths: 3",
+		"$H",
+		"G90",
+		"M0 (Pausing to allow for thread to be attached)",
+	}
+	for i := 0; i < steps*movesPerStep; i++ {
+		if i%movesPerStep == 0 {
+			lines = append(lines, "; "+threadGenerator.StepMarker(i/movesPerStep))
+		}
+		lines = append(lines, fmt.Sprintf("G0 X%d Y%d.%03d", i%10, i, i))
+	}
+	return lines
+}
+
+// testResumeProgram returns the program resuming a step program at the given
+// step, re-homing the machine like the resume-from-step generator
+func testResumeProgram(program []string, step int) []string {
+	lines := []string{
+		fmt.Sprintf("; Resuming at step %d", step),
+		"$H",
+		"G90",
+		fmt.Sprintf("M0 (Tie the thread to resume at step %d)", step),
+	}
+	for i, line := range program {
+		if line == "; "+threadGenerator.StepMarker(step) {
+			return append(lines, program[i:]...)
+		}
+	}
+	return lines
+}
+
+func newTestController(t *testing.T, store JobStore) (*Controller, *FakeController) {
+	t.Helper()
+	fake, conn := NewFakeController(DefaultRxBufferSize)
+	fake.LineDelay = time.Millisecond
+	controller, err := NewController(conn, store, DefaultRxBufferSize)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		fake.Close()
+		controller.Close()
+	})
+	return controller, fake
+}
+
+func newTestStore(t *testing.T) *FileJobStore {
+	t.Helper()
+	store, err := NewFileJobStore(t.TempDir())
+	require.NoError(t, err)
+	return store
+}
+
+func waitForState(t *testing.T, controller *Controller, state JobState) *Job {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		job := controller.Job()
+		return job != nil && job.State == state
+	}, 10*time.Second, 5*time.Millisecond, "job never reached %s", state)
+	return controller.Job()
+}
+
+func TestControllerStreamsProgram(t *testing.T) {
+	controller, fake := newTestController(t, newTestStore(t))
+	program := testProgram(200)
+
+	require.NoError(t, controller.Start("users/1/arts/2/compositions/3", "http://example.com/gcode.txt", program))
+	require.ErrorIs(t, controller.Start("users/1/arts/2/compositions/3", "", program), ErrJobActive)
+
+	job := waitForState(t, controller, JobStateHold)
+	require.Equal(t, "Pausing to allow for thread to be attached", job.HoldMessage)
+	require.Equal(t, 3, job.CurrentLine)
+	require.NoError(t, controller.Acknowledge())
+
+	job = waitForState(t, controller, JobStateComplete)
+	require.Equal(t, len(program), job.CurrentLine)
+	require.Equal(t, len(program), controller.TotalLines())
+	require.False(t, fake.Overflowed(), "streamer overflowed the controller buffer")
+
+	var expected []string
+	for _, line := range program {
+		if code, _ := cleanLine(line); code != "" && !isProgramPause(code) {
+			expected = append(expected, code)
+		}
+	}
+	require.Equal(t, expected, fake.Received())
+}
+
+func TestControllerFailsOnError(t *testing.T) {
+	controller, fake := newTestController(t, newTestStore(t))
+	fake.Reject = func(line string) int {
+		if strings.HasPrefix(line, "G0 X5 Y15.") {
+			return 20
+		}
+		return 0
+	}
+
+	program := testProgram(50)
+	require.NoError(t, controller.Start("users/1/arts/2/compositions/3", "", program))
+	waitForState(t, controller, JobStateHold)
+	require.NoError(t, controller.Acknowledge())
+
+	job := waitForState(t, controller, JobStateFailed)
+	require.Contains(t, job.ErrorMessage, "error:20 on line 35")
+	require.Equal(t, 34, job.CurrentLine)
+	require.Equal(t, 1, fake.Holds())
+	require.Equal(t, 1, fake.Resets())
+}
+
+func TestControllerErrorMidStream(t *testing.T) {
+	store := newTestStore(t)
+	controller, fake := newTestController(t, store)
+	fake.LineDelay = 2 * time.Millisecond
+
+	var mu sync.Mutex
+	var executed []string
+	rejected := false
+	fake.Reject = func(line string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		executed = append(executed, line)
+		if line == "G0 X5 Y55.055" && !rejected {
+			rejected = true
+			return 9
+		}
+		return 0
+	}
+
+	program := testStepProgram(10, 10)
+	require.NoError(t, controller.Start("users/1/arts/2/compositions/3", "", program))
+	waitForState(t, controller, JobStateHold)
+	require.NoError(t, controller.Acknowledge())
+
+	job := waitForState(t, controller, JobStateFailed)
+	require.Contains(t, job.ErrorMessage, "error:9")
+	require.Equal(t, 1, fake.Holds())
+	require.Eventually(t, func() bool { return fake.Resets() == 1 }, time.Second, time.Millisecond)
+
+	// The reset drops the lines buffered behind the failed one, at most the
+	// line already executing when the error was read still runs
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	failed := slices.Index(executed, "G0 X5 Y55.055")
+	require.LessOrEqual(t, len(executed)-failed-1, 1, "buffered lines ran after the error: %v", executed[failed+1:])
+	mu.Unlock()
+
+	// The machine lost its position, the job is rewound to the start of the
+	// step of the failed line and resumes from it like an interrupted job
+	require.True(t, job.Interrupted)
+	require.Equal(t, "; "+threadGenerator.StepMarker(5), program[job.CurrentLine])
+	require.Equal(t, 5, controller.CurrentStep())
+	saved, err := store.Load()
+	require.NoError(t, err)
+	require.Equal(t, job.CurrentLine, saved.CurrentLine)
+	require.True(t, saved.Interrupted)
+
+	require.ErrorIs(t, controller.Resume(), ErrResumeProgramRequired)
+	resume := testResumeProgram(program, 5)
+	require.NoError(t, controller.ResumeFrom(resume))
+	waitForState(t, controller, JobStateHold)
+	require.NoError(t, controller.Acknowledge())
+	job = waitForState(t, controller, JobStateComplete)
+	require.Empty(t, job.ErrorMessage)
+	require.False(t, job.Interrupted)
+}
+
+func TestControllerResumesAfterRestart(t *testing.T) {
+	store := newTestStore(t)
+	controller, fake := newTestController(t, store)
+	fake.LineDelay = 2 * time.Millisecond
+
+	program := testStepProgram(30, 10)
+	require.NoError(t, controller.Start("users/1/arts/2/compositions/3", "", program))
+	waitForState(t, controller, JobStateHold)
+	require.NoError(t, controller.Acknowledge())
+
+	require.Eventually(t, func() bool {
+		return controller.Job().CurrentLine > 100
+	}, 10*time.Second, time.Millisecond)
+	require.NoError(t, controller.Pause())
+	paused := waitForState(t, controller, JobStatePaused)
+	require.ErrorIs(t, controller.Acknowledge(), ErrInvalidState)
+	require.ErrorIs(t, controller.ResumeFrom(program), ErrInvalidState, "only an interrupted job takes a resume program")
+
+	// A new process picks the job up where the previous one stopped, the
+	// progress of every acknowledged line is saved
+	fake.Close()
+	controller.Close()
+	saved, err := store.Load()
+	require.NoError(t, err)
+	require.Equal(t, paused.CurrentLine, saved.CurrentLine)
+
+	restarted, restartedFake := newTestController(t, store)
+	job := restarted.Job()
+	require.Equal(t, JobStatePaused, job.State)
+	require.True(t, job.Interrupted)
+	require.Equal(t, paused.CurrentLine, job.CurrentLine)
+	require.Equal(t, len(program), restarted.TotalLines())
+
+	// The machine lost its position, the job resumes from its current step with
+	// the program re-homing the machine
+	step := restarted.CurrentStep()
+	require.Greater(t, step, 0)
+	require.ErrorIs(t, restarted.Resume(), ErrResumeProgramRequired)
+	require.ErrorIs(t, restarted.ResumeFrom(testResumeProgram(program, step+1)), ErrResumeProgramMismatch)
+
+	resume := testResumeProgram(program, step)
+	require.NoError(t, restarted.ResumeFrom(resume))
+	waitForState(t, restarted, JobStateHold)
+	require.Equal(t, []string{"$H", "G90"}, restartedFake.Received())
+	require.NoError(t, restarted.Acknowledge())
+	job = waitForState(t, restarted, JobStateComplete)
+	require.False(t, job.Interrupted)
+	require.Equal(t, len(resume), job.CurrentLine)
+
+	received := restartedFake.Received()
+	require.Equal(t, fmt.Sprintf("G0 X0 Y%d.%03d", step*10, step*10), received[2], "the step is wound again from its start")
+	require.Equal(t, "G0 X9 Y299.299", received[len(received)-1])
+}
+
+func TestControllerRestartsBeforeFirstStep(t *testing.T) {
+	store := newTestStore(t)
+	controller, _ := newTestController(t, store)
+
+	program := testStepProgram(3, 10)
+	require.NoError(t, controller.Start("users/1/arts/2/compositions/3", "", program))
+	waitForState(t, controller, JobStateHold)
+	controller.Close()
+
+	// Shutting down leaves the job active, it is not failed like a lost connection
+	saved, err := store.Load()
+	require.NoError(t, err)
+	require.Equal(t, JobStateHold, saved.State)
+
+	// Interrupted on the hold before the first path, the program starts over
+	restarted, restartedFake := newTestController(t, store)
+	require.True(t, restarted.Job().Interrupted)
+	require.Equal(t, -1, restarted.CurrentStep())
+	require.NoError(t, restarted.Resume())
+	waitForState(t, restarted, JobStateHold)
+	require.Equal(t, []string{"$H", "G90"}, restartedFake.Received())
+}
+
+func TestControllerCancel(t *testing.T) {
+	controller, fake := newTestController(t, newTestStore(t))
+	fake.LineDelay = 5 * time.Millisecond
+
+	require.NoError(t, controller.Start("users/1/arts/2/compositions/3", "", testProgram(500)))
+	waitForState(t, controller, JobStateHold)
+	require.NoError(t, controller.Acknowledge())
+	require.Eventually(t, func() bool {
+		return controller.Job().CurrentLine > 10
+	}, 10*time.Second, time.Millisecond)
+
+	require.NoError(t, controller.Cancel())
+	waitForState(t, controller, JobStateCancelled)
+	require.Eventually(t, func() bool { return fake.Resets() == 1 }, time.Second, time.Millisecond)
+	require.ErrorIs(t, controller.Cancel(), ErrInvalidState)
+
+	// The machine is free for the next job
+	require.NoError(t, controller.Start("users/1/arts/2/compositions/4", "", []string{"G0 X1"}))
+	waitForState(t, controller, JobStateComplete)
+}
+
+func TestControllerConnectionLost(t *testing.T) {
+	controller, fake := newTestController(t, newTestStore(t))
+	fake.LineDelay = 5 * time.Millisecond
+
+	require.NoError(t, controller.Start("users/1/arts/2/compositions/3", "", testProgram(500)))
+	waitForState(t, controller, JobStateHold)
+	require.NoError(t, controller.Acknowledge())
+	fake.Close()
+
+	job := waitForState(t, controller, JobStateFailed)
+	require.Contains(t, job.ErrorMessage, "connection to the controller was lost")
+}
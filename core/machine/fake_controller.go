@@ -0,0 +1,170 @@
+package machine
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeController simulates a FluidNC controller for tests and dry runs. It
+// counts the bytes waiting in its receive buffer the way the firmware does,
+// executes one line at a time and answers ok, or error:N for lines rejected
+// by Reject.
+type FakeController struct {
+	// LineDelay is how long executing one line takes
+	LineDelay time.Duration
+	// Reject returns a non zero error code for lines the controller must refuse
+	Reject func(line string) int
+
+	conn       net.Conn
+	bufferSize int
+
+	mu         sync.Mutex
+	cond       *sync.Cond
+	queue      []string
+	buffered   int
+	received   []string
+	overflowed bool
+	holds      int
+	resets     int
+	closed     bool
+}
+
+// NewFakeController returns the controller side simulation and the connection
+// to hand to NewController
+func NewFakeController(bufferSize int) (*FakeController, io.ReadWriteCloser) {
+	if bufferSize <= 0 {
+		bufferSize = DefaultRxBufferSize
+	}
+	client, server := net.Pipe()
+	fake := &FakeController{conn: server, bufferSize: bufferSize}
+	fake.cond = sync.NewCond(&fake.mu)
+	go fake.receive()
+	go fake.execute()
+	return fake, client
+}
+
+// Received returns the lines the controller accepted, in order
+func (f *FakeController) Received() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.received...)
+}
+
+// Overflowed reports whether the streamer ever sent more than the receive buffer holds
+func (f *FakeController) Overflowed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.overflowed
+}
+
+// Holds returns how many feed holds were received
+func (f *FakeController) Holds() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.holds
+}
+
+// Resets returns how many soft resets were received
+func (f *FakeController) Resets() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.resets
+}
+
+// Close disconnects the controller, as if the cable was pulled
+func (f *FakeController) Close() error {
+	f.mu.Lock()
+	f.closed = true
+	f.cond.Broadcast()
+	f.mu.Unlock()
+	return f.conn.Close()
+}
+
+// receive reads the stream byte by byte so realtime commands are handled
+// immediately, like the firmware does
+func (f *FakeController) receive() {
+	reader := bufio.NewReader(f.conn)
+	var line strings.Builder
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			f.Close()
+			return
+		}
+
+		f.mu.Lock()
+		switch b {
+		case '!':
+			f.holds++
+		case '~', '?':
+		case 0x18:
+			f.resets++
+			f.queue = nil
+			f.buffered = 0
+			line.Reset()
+		case '\r':
+		case '\n':
+			text := line.String()
+			line.Reset()
+			f.received = append(f.received, text)
+			f.queue = append(f.queue, text)
+			f.cond.Broadcast()
+		default:
+			f.buffered++
+			if f.buffered > f.bufferSize {
+				f.overflowed = true
+			}
+			line.WriteByte(b)
+		}
+		if b == '\n' {
+			f.buffered++
+			if f.buffered > f.bufferSize {
+				f.overflowed = true
+			}
+		}
+		f.mu.Unlock()
+	}
+}
+
+// execute runs the queued lines and answers each one
+func (f *FakeController) execute() {
+	for {
+		f.mu.Lock()
+		for len(f.queue) == 0 && !f.closed {
+			f.cond.Wait()
+		}
+		if f.closed {
+			f.mu.Unlock()
+			return
+		}
+		line := f.queue[0]
+		f.queue = f.queue[1:]
+		f.mu.Unlock()
+
+		time.Sleep(f.LineDelay)
+
+		response := "ok"
+		if f.Reject != nil {
+			if code := f.Reject(line); code != 0 {
+				response = fmt.Sprintf("error:%d", code)
+			}
+		}
+
+		f.mu.Lock()
+		f.buffered -= len(line) + 1
+		if f.buffered < 0 {
+			// A reset emptied the buffer while the line was executing
+			f.buffered = 0
+		}
+		f.mu.Unlock()
+
+		if _, err := io.WriteString(f.conn, response+"\n"); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package machine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+)
+
+// JobState is the lifecycle state of a machine job
+type JobState string
+
+const (
+	JobStateIdle      JobState = "IDLE"
+	JobStateRunning   JobState = "RUNNING"
+	JobStatePaused    JobState = "PAUSED"
+	JobStateHold      JobState = "HOLD"
+	JobStateComplete  JobState = "COMPLETE"
+	JobStateFailed    JobState = "FAILED"
+	JobStateCancelled JobState = "CANCELLED"
+)
+
+// Job is a G-code program being streamed to the controller
+type Job struct {
+	Composition  string    `json:"composition"`
+	GcodeURL     string    `json:"gcode_url"`
+	Lines        []string  `json:"-"`
+	CurrentLine  int       `json:"current_line"` // Lines acknowledged by the controller
+	State        JobState  `json:"state"`
+	HoldMessage  string    `json:"hold_message,omitempty"`
+	ErrorMessage string    `json:"error_message,omitempty"`
+	StartTime    time.Time `json:"start_time"`
+	UpdateTime   time.Time `json:"update_time"`
+	Interrupted  bool      `json:"interrupted,omitempty"` // The service restarted or the controller was reset during the job, the machine position is lost
+}
+
+// Active reports whether the job still has lines to stream
+func (j *Job) Active() bool {
+	switch j.State {
+	case JobStateRunning, JobStatePaused, JobStateHold:
+		return true
+	default:
+		return false
+	}
+}
+
+// Step returns the path the current line belongs to, from the step markers the
+// generator writes before the commands of each path. It is -1 before the first
+// path, false when the program has no markers.
+func (j *Job) Step() (int, bool) {
+	for i := min(j.CurrentLine, len(j.Lines)-1); i >= 0; i-- {
+		if _, comment := cleanLine(j.Lines[i]); comment != "" {
+			if step, ok := threadGenerator.ParseStepMarker(comment); ok {
+				return step, true
+			}
+		}
+	}
+	for i := j.CurrentLine + 1; i < len(j.Lines); i++ {
+		if _, comment := cleanLine(j.Lines[i]); comment != "" {
+			if _, ok := threadGenerator.ParseStepMarker(comment); ok {
+				return -1, true
+			}
+		}
+	}
+	return -1, false
+}
+
+// JobStore persists the current job so it can be resumed after a restart
+type JobStore interface {
+	// Load returns the saved job, or nil when there is none
+	Load() (*Job, error)
+	// Save stores the job progress and state
+	Save(job *Job) error
+	// SaveProgram stores the G-code lines of the job
+	SaveProgram(lines []string) error
+}
+
+// FileJobStore keeps the job in a state directory next to the controller
+type FileJobStore struct {
+	dir string
+}
+
+// NewFileJobStore creates a store in the given directory, creating it if needed
+func NewFileJobStore(dir string) (*FileJobStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create machine state directory: %w", err)
+	}
+	return &FileJobStore{dir: dir}, nil
+}
+
+func (s *FileJobStore) jobPath() string {
+	return filepath.Join(s.dir, "job.json")
+}
+
+func (s *FileJobStore) programPath() string {
+	return filepath.Join(s.dir, "job.gcode")
+}
+
+// Load implements JobStore
+func (s *FileJobStore) Load() (*Job, error) {
+	data, err := os.ReadFile(s.jobPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read machine job: %w", err)
+	}
+
+	job := &Job{}
+	if err := json.Unmarshal(data, job); err != nil {
+		return nil, fmt.Errorf("failed to decode machine job: %w", err)
+	}
+
+	program, err := os.ReadFile(s.programPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read machine job program: %w", err)
+	}
+	job.Lines = strings.Split(string(program), "\n")
+	return job, nil
+}
+
+// Save implements JobStore
+func (s *FileJobStore) Save(job *Job) error {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode machine job: %w", err)
+	}
+	return writeFileAtomic(s.jobPath(), data)
+}
+
+// SaveProgram implements JobStore
+func (s *FileJobStore) SaveProgram(lines []string) error {
+	return writeFileAtomic(s.programPath(), []byte(strings.Join(lines, "\n")))
+}
+
+// writeFileAtomic writes through a temporary file so a crash never leaves a truncated file
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package machine
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+const dialTimeout = 10 * time.Second
+
+// Dial opens a connection to the controller. Supported addresses are:
+//
+//	serial:///dev/ttyUSB0   serial port, the baud rate must be set beforehand (stty -F /dev/ttyUSB0 115200 raw)
+//	telnet://fluidnc.local:23
+//	ws://fluidnc.local:81   FluidNC WebSocket channel
+func Dial(address string) (io.ReadWriteCloser, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid controller address %q: %w", address, err)
+	}
+
+	switch u.Scheme {
+	case "serial":
+		port, err := os.OpenFile(u.Path, os.O_RDWR, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open serial port: %w", err)
+		}
+		return port, nil
+	case "telnet", "tcp":
+		conn, err := net.DialTimeout("tcp", u.Host, dialTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to controller: %w", err)
+		}
+		return conn, nil
+	case "ws", "wss":
+		origin := "http://" + u.Host + "/"
+		conn, err := websocket.Dial(address, "", origin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open controller websocket: %w", err)
+		}
+		return conn, nil
+	default:
+		return nil, fmt.Errorf("unsupported controller address scheme %q", u.Scheme)
+	}
+}
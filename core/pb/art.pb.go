@@ -104,6 +104,8 @@ const (
 	CompositionStatus_COMPOSITION_STATUS_COMPLETE CompositionStatus = 3
 	// Composition processing failed
 	CompositionStatus_COMPOSITION_STATUS_FAILED CompositionStatus = 4
+	// Composition processing was cancelled by its author
+	CompositionStatus_COMPOSITION_STATUS_CANCELLED CompositionStatus = 5
 )
 
 // Enum value maps for CompositionStatus.
@@ -114,6 +116,7 @@ var (
 		2: "COMPOSITION_STATUS_PROCESSING",
 		3: "COMPOSITION_STATUS_COMPLETE",
 		4: "COMPOSITION_STATUS_FAILED",
+		5: "COMPOSITION_STATUS_CANCELLED",
 	}
 	CompositionStatus_value = map[string]int32{
 		"COMPOSITION_STATUS_UNSPECIFIED": 0,
@@ -121,6 +124,7 @@ var (
 		"COMPOSITION_STATUS_PROCESSING":  2,
 		"COMPOSITION_STATUS_COMPLETE":    3,
 		"COMPOSITION_STATUS_FAILED":      4,
+		"COMPOSITION_STATUS_CANCELLED":   5,
 	}
 )
 
@@ -151,6 +155,425 @@ func (CompositionStatus) EnumDescriptor() ([]byte, []int) {
 	return file_art_proto_rawDescGZIP(), []int{1}
 }
 
+// Firmware flavour of the generated G-code
+type GcodeDialect int32
+
+const (
+	// Default unspecified dialect, treated as FluidNC
+	GcodeDialect_GCODE_DIALECT_UNSPECIFIED GcodeDialect = 0
+	// FluidNC, the firmware running on the reference machine
+	GcodeDialect_GCODE_DIALECT_FLUIDNC GcodeDialect = 1
+	// GRBL based controllers
+	GcodeDialect_GCODE_DIALECT_GRBL GcodeDialect = 2
+	// Marlin based controllers
+	GcodeDialect_GCODE_DIALECT_MARLIN GcodeDialect = 3
+	// Marlin 2.0.x, where the 4th to 6th axes are always named I, J and K
+	GcodeDialect_GCODE_DIALECT_MARLIN_IJK GcodeDialect = 4
+)
+
+// Enum value maps for GcodeDialect.
+var (
+	GcodeDialect_name = map[int32]string{
+		0: "GCODE_DIALECT_UNSPECIFIED",
+		1: "GCODE_DIALECT_FLUIDNC",
+		2: "GCODE_DIALECT_GRBL",
+		3: "GCODE_DIALECT_MARLIN",
+		4: "GCODE_DIALECT_MARLIN_IJK",
+	}
+	GcodeDialect_value = map[string]int32{
+		"GCODE_DIALECT_UNSPECIFIED": 0,
+		"GCODE_DIALECT_FLUIDNC":     1,
+		"GCODE_DIALECT_GRBL":        2,
+		"GCODE_DIALECT_MARLIN":      3,
+		"GCODE_DIALECT_MARLIN_IJK":  4,
+	}
+)
+
+func (x GcodeDialect) Enum() *GcodeDialect {
+	p := new(GcodeDialect)
+	*p = x
+	return p
+}
+
+func (x GcodeDialect) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GcodeDialect) Descriptor() protoreflect.EnumDescriptor {
+	return file_art_proto_enumTypes[2].Descriptor()
+}
+
+func (GcodeDialect) Type() protoreflect.EnumType {
+	return &file_art_proto_enumTypes[2]
+}
+
+func (x GcodeDialect) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GcodeDialect.Descriptor instead.
+func (GcodeDialect) EnumDescriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{2}
+}
+
+// Kind of source the threads reproduce
+type InputType int32
+
+const (
+	// Default unspecified input, treated as a photo
+	InputType_INPUT_TYPE_UNSPECIFIED InputType = 0
+	// Photographs, the threads match the tones
+	InputType_INPUT_TYPE_PHOTO InputType = 1
+	// Logos as SVG or high contrast bitmaps, the threads follow the outlines of the filled shapes
+	InputType_INPUT_TYPE_LOGO InputType = 2
+	// Line drawings as SVG or high contrast bitmaps, the threads follow the center lines of the strokes
+	InputType_INPUT_TYPE_LINE_DRAWING InputType = 3
+)
+
+// Enum value maps for InputType.
+var (
+	InputType_name = map[int32]string{
+		0: "INPUT_TYPE_UNSPECIFIED",
+		1: "INPUT_TYPE_PHOTO",
+		2: "INPUT_TYPE_LOGO",
+		3: "INPUT_TYPE_LINE_DRAWING",
+	}
+	InputType_value = map[string]int32{
+		"INPUT_TYPE_UNSPECIFIED":  0,
+		"INPUT_TYPE_PHOTO":        1,
+		"INPUT_TYPE_LOGO":         2,
+		"INPUT_TYPE_LINE_DRAWING": 3,
+	}
+)
+
+func (x InputType) Enum() *InputType {
+	p := new(InputType)
+	*p = x
+	return p
+}
+
+func (x InputType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (InputType) Descriptor() protoreflect.EnumDescriptor {
+	return file_art_proto_enumTypes[3].Descriptor()
+}
+
+func (InputType) Type() protoreflect.EnumType {
+	return &file_art_proto_enumTypes[3]
+}
+
+func (x InputType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use InputType.Descriptor instead.
+func (InputType) EnumDescriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{3}
+}
+
+// Processing priority of a composition, each priority has its own queue
+type CompositionPriority int32
+
+const (
+	// Default unspecified priority, treated as a render
+	CompositionPriority_COMPOSITION_PRIORITY_UNSPECIFIED CompositionPriority = 0
+	// Quick previews a user waits for, processed first
+	CompositionPriority_COMPOSITION_PRIORITY_INTERACTIVE CompositionPriority = 1
+	// Full renders
+	CompositionPriority_COMPOSITION_PRIORITY_RENDER CompositionPriority = 2
+	// Parameter sweeps and other bulk work, processed last
+	CompositionPriority_COMPOSITION_PRIORITY_BATCH CompositionPriority = 3
+)
+
+// Enum value maps for CompositionPriority.
+var (
+	CompositionPriority_name = map[int32]string{
+		0: "COMPOSITION_PRIORITY_UNSPECIFIED",
+		1: "COMPOSITION_PRIORITY_INTERACTIVE",
+		2: "COMPOSITION_PRIORITY_RENDER",
+		3: "COMPOSITION_PRIORITY_BATCH",
+	}
+	CompositionPriority_value = map[string]int32{
+		"COMPOSITION_PRIORITY_UNSPECIFIED": 0,
+		"COMPOSITION_PRIORITY_INTERACTIVE": 1,
+		"COMPOSITION_PRIORITY_RENDER":      2,
+		"COMPOSITION_PRIORITY_BATCH":       3,
+	}
+)
+
+func (x CompositionPriority) Enum() *CompositionPriority {
+	p := new(CompositionPriority)
+	*p = x
+	return p
+}
+
+func (x CompositionPriority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CompositionPriority) Descriptor() protoreflect.EnumDescriptor {
+	return file_art_proto_enumTypes[4].Descriptor()
+}
+
+func (CompositionPriority) Type() protoreflect.EnumType {
+	return &file_art_proto_enumTypes[4]
+}
+
+func (x CompositionPriority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CompositionPriority.Descriptor instead.
+func (CompositionPriority) EnumDescriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{4}
+}
+
+// Stage of the processing of a composition
+type ProgressStage int32
+
+const (
+	// Default unspecified stage
+	ProgressStage_PROGRESS_STAGE_UNSPECIFIED ProgressStage = 0
+	// Downloading the source image
+	ProgressStage_PROGRESS_STAGE_DOWNLOAD ProgressStage = 1
+	// Placing the lines
+	ProgressStage_PROGRESS_STAGE_GENERATE ProgressStage = 2
+	// Rendering the preview, G-code and analytics
+	ProgressStage_PROGRESS_STAGE_RENDER ProgressStage = 3
+	// Uploading the results
+	ProgressStage_PROGRESS_STAGE_UPLOAD ProgressStage = 4
+)
+
+// Enum value maps for ProgressStage.
+var (
+	ProgressStage_name = map[int32]string{
+		0: "PROGRESS_STAGE_UNSPECIFIED",
+		1: "PROGRESS_STAGE_DOWNLOAD",
+		2: "PROGRESS_STAGE_GENERATE",
+		3: "PROGRESS_STAGE_RENDER",
+		4: "PROGRESS_STAGE_UPLOAD",
+	}
+	ProgressStage_value = map[string]int32{
+		"PROGRESS_STAGE_UNSPECIFIED": 0,
+		"PROGRESS_STAGE_DOWNLOAD":    1,
+		"PROGRESS_STAGE_GENERATE":    2,
+		"PROGRESS_STAGE_RENDER":      3,
+		"PROGRESS_STAGE_UPLOAD":      4,
+	}
+)
+
+func (x ProgressStage) Enum() *ProgressStage {
+	p := new(ProgressStage)
+	*p = x
+	return p
+}
+
+func (x ProgressStage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProgressStage) Descriptor() protoreflect.EnumDescriptor {
+	return file_art_proto_enumTypes[5].Descriptor()
+}
+
+func (ProgressStage) Type() protoreflect.EnumType {
+	return &file_art_proto_enumTypes[5]
+}
+
+func (x ProgressStage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProgressStage.Descriptor instead.
+func (ProgressStage) EnumDescriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{5}
+}
+
+// Calibration program run on a new machine before the first piece
+type CalibrationRoutine int32
+
+const (
+	// Default unspecified routine
+	CalibrationRoutine_CALIBRATION_ROUTINE_UNSPECIFIED CalibrationRoutine = 0
+	// Visit nails 0, N/4, N/2 and 3N/4 to verify the rotary steps per nail
+	CalibrationRoutine_CALIBRATION_ROUTINE_ROTARY CalibrationRoutine = 1
+	// Step the needle through depths next to a nail
+	CalibrationRoutine_CALIBRATION_ROUTINE_NEEDLE_DEPTH CalibrationRoutine = 2
+	// Approach a nail from both sides to measure the rotary backlash
+	CalibrationRoutine_CALIBRATION_ROUTINE_BACKLASH CalibrationRoutine = 3
+	// Drill a few holes in a scrap ring
+	CalibrationRoutine_CALIBRATION_ROUTINE_SPINDLE_DRILL CalibrationRoutine = 4
+)
+
+// Enum value maps for CalibrationRoutine.
+var (
+	CalibrationRoutine_name = map[int32]string{
+		0: "CALIBRATION_ROUTINE_UNSPECIFIED",
+		1: "CALIBRATION_ROUTINE_ROTARY",
+		2: "CALIBRATION_ROUTINE_NEEDLE_DEPTH",
+		3: "CALIBRATION_ROUTINE_BACKLASH",
+		4: "CALIBRATION_ROUTINE_SPINDLE_DRILL",
+	}
+	CalibrationRoutine_value = map[string]int32{
+		"CALIBRATION_ROUTINE_UNSPECIFIED":   0,
+		"CALIBRATION_ROUTINE_ROTARY":        1,
+		"CALIBRATION_ROUTINE_NEEDLE_DEPTH":  2,
+		"CALIBRATION_ROUTINE_BACKLASH":      3,
+		"CALIBRATION_ROUTINE_SPINDLE_DRILL": 4,
+	}
+)
+
+func (x CalibrationRoutine) Enum() *CalibrationRoutine {
+	p := new(CalibrationRoutine)
+	*p = x
+	return p
+}
+
+func (x CalibrationRoutine) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CalibrationRoutine) Descriptor() protoreflect.EnumDescriptor {
+	return file_art_proto_enumTypes[6].Descriptor()
+}
+
+func (CalibrationRoutine) Type() protoreflect.EnumType {
+	return &file_art_proto_enumTypes[6]
+}
+
+func (x CalibrationRoutine) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CalibrationRoutine.Descriptor instead.
+func (CalibrationRoutine) EnumDescriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{6}
+}
+
+// Status of a parameter sweep
+type ParameterSweepStatus int32
+
+const (
+	// Default unspecified status
+	ParameterSweepStatus_PARAMETER_SWEEP_STATUS_UNSPECIFIED ParameterSweepStatus = 0
+	// Sweep created, child compositions waiting to be processed
+	ParameterSweepStatus_PARAMETER_SWEEP_STATUS_PENDING ParameterSweepStatus = 1
+	// Child compositions are being processed
+	ParameterSweepStatus_PARAMETER_SWEEP_STATUS_PROCESSING ParameterSweepStatus = 2
+	// Every child composition is finished and scored
+	ParameterSweepStatus_PARAMETER_SWEEP_STATUS_COMPLETE ParameterSweepStatus = 3
+	// The sweep could not be completed
+	ParameterSweepStatus_PARAMETER_SWEEP_STATUS_FAILED ParameterSweepStatus = 4
+)
+
+// Enum value maps for ParameterSweepStatus.
+var (
+	ParameterSweepStatus_name = map[int32]string{
+		0: "PARAMETER_SWEEP_STATUS_UNSPECIFIED",
+		1: "PARAMETER_SWEEP_STATUS_PENDING",
+		2: "PARAMETER_SWEEP_STATUS_PROCESSING",
+		3: "PARAMETER_SWEEP_STATUS_COMPLETE",
+		4: "PARAMETER_SWEEP_STATUS_FAILED",
+	}
+	ParameterSweepStatus_value = map[string]int32{
+		"PARAMETER_SWEEP_STATUS_UNSPECIFIED": 0,
+		"PARAMETER_SWEEP_STATUS_PENDING":     1,
+		"PARAMETER_SWEEP_STATUS_PROCESSING":  2,
+		"PARAMETER_SWEEP_STATUS_COMPLETE":    3,
+		"PARAMETER_SWEEP_STATUS_FAILED":      4,
+	}
+)
+
+func (x ParameterSweepStatus) Enum() *ParameterSweepStatus {
+	p := new(ParameterSweepStatus)
+	*p = x
+	return p
+}
+
+func (x ParameterSweepStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ParameterSweepStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_art_proto_enumTypes[7].Descriptor()
+}
+
+func (ParameterSweepStatus) Type() protoreflect.EnumType {
+	return &file_art_proto_enumTypes[7]
+}
+
+func (x ParameterSweepStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ParameterSweepStatus.Descriptor instead.
+func (ParameterSweepStatus) EnumDescriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{7}
+}
+
+// Composition setting varied by a parameter sweep
+type SweepParameter int32
+
+const (
+	// Default unspecified parameter
+	SweepParameter_SWEEP_PARAMETER_UNSPECIFIED SweepParameter = 0
+	// Brightness factor for thread lines
+	SweepParameter_SWEEP_PARAMETER_BRIGHTNESS_FACTOR SweepParameter = 1
+	// Image contrast adjustment
+	SweepParameter_SWEEP_PARAMETER_IMAGE_CONTRAST SweepParameter = 2
+	// Minimum difference between connected nails
+	SweepParameter_SWEEP_PARAMETER_MINIMUM_DIFFERENCE SweepParameter = 3
+	// Maximum number of paths to generate
+	SweepParameter_SWEEP_PARAMETER_MAX_PATHS SweepParameter = 4
+)
+
+// Enum value maps for SweepParameter.
+var (
+	SweepParameter_name = map[int32]string{
+		0: "SWEEP_PARAMETER_UNSPECIFIED",
+		1: "SWEEP_PARAMETER_BRIGHTNESS_FACTOR",
+		2: "SWEEP_PARAMETER_IMAGE_CONTRAST",
+		3: "SWEEP_PARAMETER_MINIMUM_DIFFERENCE",
+		4: "SWEEP_PARAMETER_MAX_PATHS",
+	}
+	SweepParameter_value = map[string]int32{
+		"SWEEP_PARAMETER_UNSPECIFIED":        0,
+		"SWEEP_PARAMETER_BRIGHTNESS_FACTOR":  1,
+		"SWEEP_PARAMETER_IMAGE_CONTRAST":     2,
+		"SWEEP_PARAMETER_MINIMUM_DIFFERENCE": 3,
+		"SWEEP_PARAMETER_MAX_PATHS":          4,
+	}
+)
+
+func (x SweepParameter) Enum() *SweepParameter {
+	p := new(SweepParameter)
+	*p = x
+	return p
+}
+
+func (x SweepParameter) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SweepParameter) Descriptor() protoreflect.EnumDescriptor {
+	return file_art_proto_enumTypes[8].Descriptor()
+}
+
+func (SweepParameter) Type() protoreflect.EnumType {
+	return &file_art_proto_enumTypes[8]
+}
+
+func (x SweepParameter) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SweepParameter.Descriptor instead.
+func (SweepParameter) EnumDescriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{8}
+}
+
 type Art struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The name of the Art resource.
@@ -291,7 +714,50 @@ type Composition struct {
 	// Creation time
 	CreateTime *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
 	// Last update time
-	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	UpdateTime *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	// Firmware flavour the G-code is generated for
+	GcodeDialect GcodeDialect `protobuf:"varint,20,opt,name=gcode_dialect,json=gcodeDialect,proto3,enum=pb.GcodeDialect" json:"gcode_dialect,omitempty"`
+	// URL to download the nail hole drilling GCode file
+	DrillGcodeUrl string `protobuf:"bytes,21,opt,name=drill_gcode_url,json=drillGcodeUrl,proto3" json:"drill_gcode_url,omitempty"`
+	// Length of thread on one spool in meters, 0 for unlimited
+	SpoolLength float32 `protobuf:"fixed32,22,opt,name=spool_length,json=spoolLength,proto3" json:"spool_length,omitempty"`
+	// Paths strung with each spool, in stringing order
+	Segments []*ThreadSegment `protobuf:"bytes,23,rep,name=segments,proto3" json:"segments,omitempty"`
+	// Concentric nail rings, replacing nails_quantity when set. Nails are
+	// numbered ring after ring in the paths list.
+	Rings []*NailRing `protobuf:"bytes,24,rep,name=rings,proto3" json:"rings,omitempty"`
+	// Thread profile used to score lines and render the preview, so the
+	// preview predicts the physical piece. Empty for the default darkening.
+	// For example: "users/123/threadProfiles/456"
+	ThreadProfile string `protobuf:"bytes,25,opt,name=thread_profile,json=threadProfile,proto3" json:"thread_profile,omitempty"`
+	// Model the thread darkening in linear light instead of sRGB gray levels,
+	// for a truer reproduction of the midtones
+	LinearLight bool `protobuf:"varint,26,opt,name=linear_light,json=linearLight,proto3" json:"linear_light,omitempty"`
+	// Nail usage and line density of the generated paths list
+	Analytics *CompositionAnalytics `protobuf:"bytes,27,opt,name=analytics,proto3" json:"analytics,omitempty"`
+	// URL to the heatmap image of the line density across the board
+	HeatmapUrl string `protobuf:"bytes,28,opt,name=heatmap_url,json=heatmapUrl,proto3" json:"heatmap_url,omitempty"`
+	// Kind of source the threads reproduce. Logos and line drawings are scored
+	// on covering their strokes instead of matching tones.
+	InputType InputType `protobuf:"varint,29,opt,name=input_type,json=inputType,proto3,enum=pb.InputType" json:"input_type,omitempty"`
+	// Seed drawing among lines of equal weight, 0 to pick the lowest nail.
+	// Generations are deterministic either way, other seeds give variations.
+	Seed int64 `protobuf:"varint,30,opt,name=seed,proto3" json:"seed,omitempty"`
+	// SHA-256 of the source image, configuration and generator version.
+	// Compositions with the same hash have the same paths list.
+	ContentHash string `protobuf:"bytes,31,opt,name=content_hash,json=contentHash,proto3" json:"content_hash,omitempty"`
+	// Rotational symmetry order, 0 or 1 for none. Lines are strung with their
+	// images on every sector, the nails of every ring must split evenly.
+	SymmetryFold int32 `protobuf:"varint,32,opt,name=symmetry_fold,json=symmetryFold,proto3" json:"symmetry_fold,omitempty"`
+	// Mirror the piece across its vertical axis, every ring needs an even
+	// number of nails
+	MirrorSymmetry bool `protobuf:"varint,33,opt,name=mirror_symmetry,json=mirrorSymmetry,proto3" json:"mirror_symmetry,omitempty"`
+	// Progress of the worker while the composition is processed
+	Progress *CompositionProgress `protobuf:"bytes,34,opt,name=progress,proto3" json:"progress,omitempty"`
+	// Processing priority, sweep compositions are always batch work. A user
+	// has at most two interactive compositions pending or processing, the next
+	// ones are renders.
+	Priority      CompositionPriority `protobuf:"varint,35,opt,name=priority,proto3,enum=pb.CompositionPriority" json:"priority,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -452,85 +918,1780 @@ func (x *Composition) GetUpdateTime() *timestamppb.Timestamp {
 	return nil
 }
 
-type CreateCompositionRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// The parent which owns the composition.
-	// For example: "users/123/arts/456"
-	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
-	// The composition to create.
-	Composition   *Composition `protobuf:"bytes,2,opt,name=composition,proto3" json:"composition,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Composition) GetGcodeDialect() GcodeDialect {
+	if x != nil {
+		return x.GcodeDialect
+	}
+	return GcodeDialect_GCODE_DIALECT_UNSPECIFIED
 }
 
-func (x *CreateCompositionRequest) Reset() {
-	*x = CreateCompositionRequest{}
-	mi := &file_art_proto_msgTypes[2]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Composition) GetDrillGcodeUrl() string {
+	if x != nil {
+		return x.DrillGcodeUrl
+	}
+	return ""
 }
 
-func (x *CreateCompositionRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Composition) GetSpoolLength() float32 {
+	if x != nil {
+		return x.SpoolLength
+	}
+	return 0
 }
 
-func (*CreateCompositionRequest) ProtoMessage() {}
+func (x *Composition) GetSegments() []*ThreadSegment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
 
-func (x *CreateCompositionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[2]
+func (x *Composition) GetRings() []*NailRing {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Rings
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use CreateCompositionRequest.ProtoReflect.Descriptor instead.
-func (*CreateCompositionRequest) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{2}
+func (x *Composition) GetThreadProfile() string {
+	if x != nil {
+		return x.ThreadProfile
+	}
+	return ""
+}
+
+func (x *Composition) GetLinearLight() bool {
+	if x != nil {
+		return x.LinearLight
+	}
+	return false
+}
+
+func (x *Composition) GetAnalytics() *CompositionAnalytics {
+	if x != nil {
+		return x.Analytics
+	}
+	return nil
+}
+
+func (x *Composition) GetHeatmapUrl() string {
+	if x != nil {
+		return x.HeatmapUrl
+	}
+	return ""
+}
+
+func (x *Composition) GetInputType() InputType {
+	if x != nil {
+		return x.InputType
+	}
+	return InputType_INPUT_TYPE_UNSPECIFIED
+}
+
+func (x *Composition) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *Composition) GetContentHash() string {
+	if x != nil {
+		return x.ContentHash
+	}
+	return ""
+}
+
+func (x *Composition) GetSymmetryFold() int32 {
+	if x != nil {
+		return x.SymmetryFold
+	}
+	return 0
+}
+
+func (x *Composition) GetMirrorSymmetry() bool {
+	if x != nil {
+		return x.MirrorSymmetry
+	}
+	return false
+}
+
+func (x *Composition) GetProgress() *CompositionProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *Composition) GetPriority() CompositionPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return CompositionPriority_COMPOSITION_PRIORITY_UNSPECIFIED
+}
+
+// NailRing is a circle of evenly spaced nails on a multi ring frame
+type NailRing struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of nails on the ring
+	NailsQuantity int32 `protobuf:"varint,1,opt,name=nails_quantity,json=nailsQuantity,proto3" json:"nails_quantity,omitempty"`
+	// Radius of the ring in mm
+	Radius float32 `protobuf:"fixed32,2,opt,name=radius,proto3" json:"radius,omitempty"`
+	// Minimum difference between nails of the ring, 0 for the composition one
+	MinimumDifference int32 `protobuf:"varint,3,opt,name=minimum_difference,json=minimumDifference,proto3" json:"minimum_difference,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *NailRing) Reset() {
+	*x = NailRing{}
+	mi := &file_art_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NailRing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NailRing) ProtoMessage() {}
+
+func (x *NailRing) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NailRing.ProtoReflect.Descriptor instead.
+func (*NailRing) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *NailRing) GetNailsQuantity() int32 {
+	if x != nil {
+		return x.NailsQuantity
+	}
+	return 0
+}
+
+func (x *NailRing) GetRadius() float32 {
+	if x != nil {
+		return x.Radius
+	}
+	return 0
+}
+
+func (x *NailRing) GetMinimumDifference() int32 {
+	if x != nil {
+		return x.MinimumDifference
+	}
+	return 0
+}
+
+// ThreadSegment is a run of consecutive paths strung with the thread of one spool
+type ThreadSegment struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Index of the first path of the segment
+	StartStep int32 `protobuf:"varint,1,opt,name=start_step,json=startStep,proto3" json:"start_step,omitempty"`
+	// Index after the last path of the segment
+	EndStep int32 `protobuf:"varint,2,opt,name=end_step,json=endStep,proto3" json:"end_step,omitempty"`
+	// Nail the thread is tied to before the first path
+	StartNail int32 `protobuf:"varint,3,opt,name=start_nail,json=startNail,proto3" json:"start_nail,omitempty"`
+	// Thread used in meters, knots included
+	Length        float32 `protobuf:"fixed32,4,opt,name=length,proto3" json:"length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThreadSegment) Reset() {
+	*x = ThreadSegment{}
+	mi := &file_art_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThreadSegment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThreadSegment) ProtoMessage() {}
+
+func (x *ThreadSegment) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThreadSegment.ProtoReflect.Descriptor instead.
+func (*ThreadSegment) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ThreadSegment) GetStartStep() int32 {
+	if x != nil {
+		return x.StartStep
+	}
+	return 0
+}
+
+func (x *ThreadSegment) GetEndStep() int32 {
+	if x != nil {
+		return x.EndStep
+	}
+	return 0
+}
+
+func (x *ThreadSegment) GetStartNail() int32 {
+	if x != nil {
+		return x.StartNail
+	}
+	return 0
+}
+
+func (x *ThreadSegment) GetLength() float32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+// CompositionProgress reports how far the worker is with a composition
+type CompositionProgress struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Stage the worker is at
+	Stage ProgressStage `protobuf:"varint,1,opt,name=stage,proto3,enum=pb.ProgressStage" json:"stage,omitempty"`
+	// Share of the processing done, from 0 to 100
+	Percent float32 `protobuf:"fixed32,2,opt,name=percent,proto3" json:"percent,omitempty"`
+	// Lines placed by the generator so far
+	LinesPlaced int32 `protobuf:"varint,3,opt,name=lines_placed,json=linesPlaced,proto3" json:"lines_placed,omitempty"`
+	// Most lines the generator places, it stops earlier once no line darkens the piece
+	MaxLines int32 `protobuf:"varint,4,opt,name=max_lines,json=maxLines,proto3" json:"max_lines,omitempty"`
+	// Estimated completion time, unset until the generator placed its first lines
+	EstimatedCompletionTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=estimated_completion_time,json=estimatedCompletionTime,proto3" json:"estimated_completion_time,omitempty"`
+	// Time of the last progress update
+	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompositionProgress) Reset() {
+	*x = CompositionProgress{}
+	mi := &file_art_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompositionProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompositionProgress) ProtoMessage() {}
+
+func (x *CompositionProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompositionProgress.ProtoReflect.Descriptor instead.
+func (*CompositionProgress) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CompositionProgress) GetStage() ProgressStage {
+	if x != nil {
+		return x.Stage
+	}
+	return ProgressStage_PROGRESS_STAGE_UNSPECIFIED
+}
+
+func (x *CompositionProgress) GetPercent() float32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *CompositionProgress) GetLinesPlaced() int32 {
+	if x != nil {
+		return x.LinesPlaced
+	}
+	return 0
+}
+
+func (x *CompositionProgress) GetMaxLines() int32 {
+	if x != nil {
+		return x.MaxLines
+	}
+	return 0
+}
+
+func (x *CompositionProgress) GetEstimatedCompletionTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EstimatedCompletionTime
+	}
+	return nil
+}
+
+func (x *CompositionProgress) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+// CompositionAnalytics reports how the paths list loads the nails and covers
+// the board, to pick stronger nails and spot over concentrated thread
+type CompositionAnalytics struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Wraps on each nail, by nail number
+	NailHits []int32 `protobuf:"varint,1,rep,packed,name=nail_hits,json=nailHits,proto3" json:"nail_hits,omitempty"`
+	// Nails by number of wraps
+	HitsHistogram []*HistogramBin `protobuf:"bytes,2,rep,name=hits_histogram,json=hitsHistogram,proto3" json:"hits_histogram,omitempty"`
+	// Mean wraps per nail
+	MeanHits float32 `protobuf:"fixed32,3,opt,name=mean_hits,json=meanHits,proto3" json:"mean_hits,omitempty"`
+	// Most wrapped nails, the most loaded first
+	HeaviestNails []*NailLoad `protobuf:"bytes,4,rep,name=heaviest_nails,json=heaviestNails,proto3" json:"heaviest_nails,omitempty"`
+	// Nails wrapped more than twice the mean, at risk of pulling out
+	OverloadedNails int32 `protobuf:"varint,5,opt,name=overloaded_nails,json=overloadedNails,proto3" json:"overloaded_nails,omitempty"`
+	// Paths by chord length in mm
+	ChordLengths []*HistogramBin `protobuf:"bytes,6,rep,name=chord_lengths,json=chordLengths,proto3" json:"chord_lengths,omitempty"`
+	// Mean chord length in mm
+	MeanChordLength float32 `protobuf:"fixed32,7,opt,name=mean_chord_length,json=meanChordLength,proto3" json:"mean_chord_length,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CompositionAnalytics) Reset() {
+	*x = CompositionAnalytics{}
+	mi := &file_art_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompositionAnalytics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompositionAnalytics) ProtoMessage() {}
+
+func (x *CompositionAnalytics) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompositionAnalytics.ProtoReflect.Descriptor instead.
+func (*CompositionAnalytics) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CompositionAnalytics) GetNailHits() []int32 {
+	if x != nil {
+		return x.NailHits
+	}
+	return nil
+}
+
+func (x *CompositionAnalytics) GetHitsHistogram() []*HistogramBin {
+	if x != nil {
+		return x.HitsHistogram
+	}
+	return nil
+}
+
+func (x *CompositionAnalytics) GetMeanHits() float32 {
+	if x != nil {
+		return x.MeanHits
+	}
+	return 0
+}
+
+func (x *CompositionAnalytics) GetHeaviestNails() []*NailLoad {
+	if x != nil {
+		return x.HeaviestNails
+	}
+	return nil
+}
+
+func (x *CompositionAnalytics) GetOverloadedNails() int32 {
+	if x != nil {
+		return x.OverloadedNails
+	}
+	return 0
+}
+
+func (x *CompositionAnalytics) GetChordLengths() []*HistogramBin {
+	if x != nil {
+		return x.ChordLengths
+	}
+	return nil
+}
+
+func (x *CompositionAnalytics) GetMeanChordLength() float32 {
+	if x != nil {
+		return x.MeanChordLength
+	}
+	return 0
+}
+
+// HistogramBin counts the values from min included to max excluded, the last
+// bin of a histogram also holds its max
+type HistogramBin struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Min           float32                `protobuf:"fixed32,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max           float32                `protobuf:"fixed32,2,opt,name=max,proto3" json:"max,omitempty"`
+	Count         int32                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistogramBin) Reset() {
+	*x = HistogramBin{}
+	mi := &file_art_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistogramBin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistogramBin) ProtoMessage() {}
+
+func (x *HistogramBin) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistogramBin.ProtoReflect.Descriptor instead.
+func (*HistogramBin) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HistogramBin) GetMin() float32 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *HistogramBin) GetMax() float32 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *HistogramBin) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// NailLoad is the number of wraps on a nail
+type NailLoad struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Nail number of the paths list
+	Nail int32 `protobuf:"varint,1,opt,name=nail,proto3" json:"nail,omitempty"`
+	// Ring of the nail, 0 for single ring layouts
+	Ring int32 `protobuf:"varint,2,opt,name=ring,proto3" json:"ring,omitempty"`
+	// Index of the nail on its ring
+	Index int32 `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	// Wraps on the nail
+	Hits int32 `protobuf:"varint,4,opt,name=hits,proto3" json:"hits,omitempty"`
+	// Wraps relative to the mean of all nails
+	Ratio         float32 `protobuf:"fixed32,5,opt,name=ratio,proto3" json:"ratio,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NailLoad) Reset() {
+	*x = NailLoad{}
+	mi := &file_art_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NailLoad) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NailLoad) ProtoMessage() {}
+
+func (x *NailLoad) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NailLoad.ProtoReflect.Descriptor instead.
+func (*NailLoad) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *NailLoad) GetNail() int32 {
+	if x != nil {
+		return x.Nail
+	}
+	return 0
+}
+
+func (x *NailLoad) GetRing() int32 {
+	if x != nil {
+		return x.Ring
+	}
+	return 0
+}
+
+func (x *NailLoad) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *NailLoad) GetHits() int32 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *NailLoad) GetRatio() float32 {
+	if x != nil {
+		return x.Ratio
+	}
+	return 0
+}
+
+type CreateCompositionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The parent which owns the composition.
+	// For example: "users/123/arts/456"
+	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	// The composition to create.
+	Composition   *Composition `protobuf:"bytes,2,opt,name=composition,proto3" json:"composition,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCompositionRequest) Reset() {
+	*x = CreateCompositionRequest{}
+	mi := &file_art_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCompositionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCompositionRequest) ProtoMessage() {}
+
+func (x *CreateCompositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCompositionRequest.ProtoReflect.Descriptor instead.
+func (*CreateCompositionRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateCompositionRequest) GetParent() string {
+	if x != nil {
+		return x.Parent
+	}
+	return ""
+}
+
+func (x *CreateCompositionRequest) GetComposition() *Composition {
+	if x != nil {
+		return x.Composition
+	}
+	return nil
+}
+
+type GetCompositionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the Composition resource.
+	// For example: "users/123/arts/456/compositions/789"
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCompositionRequest) Reset() {
+	*x = GetCompositionRequest{}
+	mi := &file_art_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCompositionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCompositionRequest) ProtoMessage() {}
+
+func (x *GetCompositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCompositionRequest.ProtoReflect.Descriptor instead.
+func (*GetCompositionRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetCompositionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type UpdateCompositionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The Composition resource to update.
+	Composition *Composition `protobuf:"bytes,1,opt,name=composition,proto3" json:"composition,omitempty"`
+	// The update mask applies to the resource.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCompositionRequest) Reset() {
+	*x = UpdateCompositionRequest{}
+	mi := &file_art_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCompositionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCompositionRequest) ProtoMessage() {}
+
+func (x *UpdateCompositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCompositionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCompositionRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateCompositionRequest) GetComposition() *Composition {
+	if x != nil {
+		return x.Composition
+	}
+	return nil
+}
+
+func (x *UpdateCompositionRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type ListCompositionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The parent which owns the compositions.
+	// For example: "users/123/arts/456"
+	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	// The maximum number of compositions to return.
+	// The service may return fewer than this value.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// A page token, received from a previous `ListCompositions` call.
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCompositionsRequest) Reset() {
+	*x = ListCompositionsRequest{}
+	mi := &file_art_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCompositionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCompositionsRequest) ProtoMessage() {}
+
+func (x *ListCompositionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCompositionsRequest.ProtoReflect.Descriptor instead.
+func (*ListCompositionsRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListCompositionsRequest) GetParent() string {
+	if x != nil {
+		return x.Parent
+	}
+	return ""
+}
+
+func (x *ListCompositionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListCompositionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListCompositionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The compositions returned.
+	Compositions []*Composition `protobuf:"bytes,1,rep,name=compositions,proto3" json:"compositions,omitempty"`
+	// A token to retrieve next page of results.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCompositionsResponse) Reset() {
+	*x = ListCompositionsResponse{}
+	mi := &file_art_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCompositionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCompositionsResponse) ProtoMessage() {}
+
+func (x *ListCompositionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCompositionsResponse.ProtoReflect.Descriptor instead.
+func (*ListCompositionsResponse) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListCompositionsResponse) GetCompositions() []*Composition {
+	if x != nil {
+		return x.Compositions
+	}
+	return nil
+}
+
+func (x *ListCompositionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetCompositionGcodeFromStepRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the Composition resource.
+	// For example: "users/123/arts/456/compositions/789"
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Zero based index in the paths list of the first path to string
+	Step          int32 `protobuf:"varint,2,opt,name=step,proto3" json:"step,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCompositionGcodeFromStepRequest) Reset() {
+	*x = GetCompositionGcodeFromStepRequest{}
+	mi := &file_art_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCompositionGcodeFromStepRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCompositionGcodeFromStepRequest) ProtoMessage() {}
+
+func (x *GetCompositionGcodeFromStepRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCompositionGcodeFromStepRequest.ProtoReflect.Descriptor instead.
+func (*GetCompositionGcodeFromStepRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetCompositionGcodeFromStepRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetCompositionGcodeFromStepRequest) GetStep() int32 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+type GetCompositionGcodeFromStepResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The G-code program, one command per line
+	Gcode string `protobuf:"bytes,1,opt,name=gcode,proto3" json:"gcode,omitempty"`
+	// The index of the first path in the program
+	Step int32 `protobuf:"varint,2,opt,name=step,proto3" json:"step,omitempty"`
+	// Number of paths left to string from this step
+	RemainingPaths int32 `protobuf:"varint,3,opt,name=remaining_paths,json=remainingPaths,proto3" json:"remaining_paths,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetCompositionGcodeFromStepResponse) Reset() {
+	*x = GetCompositionGcodeFromStepResponse{}
+	mi := &file_art_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCompositionGcodeFromStepResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCompositionGcodeFromStepResponse) ProtoMessage() {}
+
+func (x *GetCompositionGcodeFromStepResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCompositionGcodeFromStepResponse.ProtoReflect.Descriptor instead.
+func (*GetCompositionGcodeFromStepResponse) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetCompositionGcodeFromStepResponse) GetGcode() string {
+	if x != nil {
+		return x.Gcode
+	}
+	return ""
+}
+
+func (x *GetCompositionGcodeFromStepResponse) GetStep() int32 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *GetCompositionGcodeFromStepResponse) GetRemainingPaths() int32 {
+	if x != nil {
+		return x.RemainingPaths
+	}
+	return 0
+}
+
+type GetCompositionCalibrationGcodeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the Composition resource.
+	// For example: "users/123/arts/456/compositions/789"
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The calibration program to generate
+	Routine       CalibrationRoutine `protobuf:"varint,2,opt,name=routine,proto3,enum=pb.CalibrationRoutine" json:"routine,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCompositionCalibrationGcodeRequest) Reset() {
+	*x = GetCompositionCalibrationGcodeRequest{}
+	mi := &file_art_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCompositionCalibrationGcodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCompositionCalibrationGcodeRequest) ProtoMessage() {}
+
+func (x *GetCompositionCalibrationGcodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCompositionCalibrationGcodeRequest.ProtoReflect.Descriptor instead.
+func (*GetCompositionCalibrationGcodeRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetCompositionCalibrationGcodeRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetCompositionCalibrationGcodeRequest) GetRoutine() CalibrationRoutine {
+	if x != nil {
+		return x.Routine
+	}
+	return CalibrationRoutine_CALIBRATION_ROUTINE_UNSPECIFIED
+}
+
+type GetCompositionCalibrationGcodeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The G-code program, one command per line
+	Gcode string `protobuf:"bytes,1,opt,name=gcode,proto3" json:"gcode,omitempty"`
+	// The calibration program generated
+	Routine       CalibrationRoutine `protobuf:"varint,2,opt,name=routine,proto3,enum=pb.CalibrationRoutine" json:"routine,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCompositionCalibrationGcodeResponse) Reset() {
+	*x = GetCompositionCalibrationGcodeResponse{}
+	mi := &file_art_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCompositionCalibrationGcodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCompositionCalibrationGcodeResponse) ProtoMessage() {}
+
+func (x *GetCompositionCalibrationGcodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCompositionCalibrationGcodeResponse.ProtoReflect.Descriptor instead.
+func (*GetCompositionCalibrationGcodeResponse) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetCompositionCalibrationGcodeResponse) GetGcode() string {
+	if x != nil {
+		return x.Gcode
+	}
+	return ""
+}
+
+func (x *GetCompositionCalibrationGcodeResponse) GetRoutine() CalibrationRoutine {
+	if x != nil {
+		return x.Routine
+	}
+	return CalibrationRoutine_CALIBRATION_ROUTINE_UNSPECIFIED
+}
+
+type CancelCompositionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the Composition resource.
+	// For example: "users/123/arts/456/compositions/789"
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelCompositionRequest) Reset() {
+	*x = CancelCompositionRequest{}
+	mi := &file_art_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelCompositionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelCompositionRequest) ProtoMessage() {}
+
+func (x *CancelCompositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelCompositionRequest.ProtoReflect.Descriptor instead.
+func (*CancelCompositionRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CancelCompositionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteCompositionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the Composition resource.
+	// For example: "users/123/arts/456/compositions/789"
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCompositionRequest) Reset() {
+	*x = DeleteCompositionRequest{}
+	mi := &file_art_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCompositionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCompositionRequest) ProtoMessage() {}
+
+func (x *DeleteCompositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCompositionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCompositionRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeleteCompositionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// ParameterRange is the values a sweep tries for one parameter, from min to max included
+type ParameterRange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The parameter to vary
+	Parameter SweepParameter `protobuf:"varint,1,opt,name=parameter,proto3,enum=pb.SweepParameter" json:"parameter,omitempty"`
+	// First value tried
+	Min float64 `protobuf:"fixed64,2,opt,name=min,proto3" json:"min,omitempty"`
+	// Last value tried
+	Max float64 `protobuf:"fixed64,3,opt,name=max,proto3" json:"max,omitempty"`
+	// Increment between two values
+	Step          float64 `protobuf:"fixed64,4,opt,name=step,proto3" json:"step,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParameterRange) Reset() {
+	*x = ParameterRange{}
+	mi := &file_art_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParameterRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParameterRange) ProtoMessage() {}
+
+func (x *ParameterRange) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParameterRange.ProtoReflect.Descriptor instead.
+func (*ParameterRange) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ParameterRange) GetParameter() SweepParameter {
+	if x != nil {
+		return x.Parameter
+	}
+	return SweepParameter_SWEEP_PARAMETER_UNSPECIFIED
+}
+
+func (x *ParameterRange) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *ParameterRange) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *ParameterRange) GetStep() float64 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+// ParameterSweepResult is a composition of a sweep with its score
+type ParameterSweepResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The composition generated for one combination of the swept parameters
+	Composition *Composition `protobuf:"bytes,1,opt,name=composition,proto3" json:"composition,omitempty"`
+	// Similarity between the preview and the source image, from 0 to 1
+	Score float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// Position of the composition once sorted by score, 1 is the best and 0 means not scored yet
+	Rank          int32 `protobuf:"varint,3,opt,name=rank,proto3" json:"rank,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParameterSweepResult) Reset() {
+	*x = ParameterSweepResult{}
+	mi := &file_art_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParameterSweepResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParameterSweepResult) ProtoMessage() {}
+
+func (x *ParameterSweepResult) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParameterSweepResult.ProtoReflect.Descriptor instead.
+func (*ParameterSweepResult) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ParameterSweepResult) GetComposition() *Composition {
+	if x != nil {
+		return x.Composition
+	}
+	return nil
+}
+
+func (x *ParameterSweepResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ParameterSweepResult) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+// ParameterSweep generates a composition for every combination of the swept
+// parameters and ranks them by similarity with the source image
+type ParameterSweep struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the ParameterSweep resource.
+	// For example: "users/123/arts/456/sweeps/789"
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Status of the sweep
+	Status ParameterSweepStatus `protobuf:"varint,2,opt,name=status,proto3,enum=pb.ParameterSweepStatus" json:"status,omitempty"`
+	// Settings shared by every composition of the sweep, the swept parameters are replaced by the range values
+	BaseComposition *Composition `protobuf:"bytes,3,opt,name=base_composition,json=baseComposition,proto3" json:"base_composition,omitempty"`
+	// Parameters to vary, a composition is generated for each combination of their values
+	Ranges []*ParameterRange `protobuf:"bytes,4,rep,name=ranges,proto3" json:"ranges,omitempty"`
+	// The compositions of the sweep, best score first
+	Results []*ParameterSweepResult `protobuf:"bytes,5,rep,name=results,proto3" json:"results,omitempty"`
+	// URL to the contact sheet of all previews, ranked by score
+	ContactSheetUrl string `protobuf:"bytes,6,opt,name=contact_sheet_url,json=contactSheetUrl,proto3" json:"contact_sheet_url,omitempty"`
+	// Number of compositions in the sweep
+	TotalCompositions int32 `protobuf:"varint,7,opt,name=total_compositions,json=totalCompositions,proto3" json:"total_compositions,omitempty"`
+	// Number of compositions finished, successfully or not
+	FinishedCompositions int32 `protobuf:"varint,8,opt,name=finished_compositions,json=finishedCompositions,proto3" json:"finished_compositions,omitempty"`
+	// Error message if the sweep failed
+	ErrorMessage string `protobuf:"bytes,9,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// Creation time
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Last update time
+	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParameterSweep) Reset() {
+	*x = ParameterSweep{}
+	mi := &file_art_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParameterSweep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParameterSweep) ProtoMessage() {}
+
+func (x *ParameterSweep) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParameterSweep.ProtoReflect.Descriptor instead.
+func (*ParameterSweep) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ParameterSweep) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ParameterSweep) GetStatus() ParameterSweepStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ParameterSweepStatus_PARAMETER_SWEEP_STATUS_UNSPECIFIED
+}
+
+func (x *ParameterSweep) GetBaseComposition() *Composition {
+	if x != nil {
+		return x.BaseComposition
+	}
+	return nil
+}
+
+func (x *ParameterSweep) GetRanges() []*ParameterRange {
+	if x != nil {
+		return x.Ranges
+	}
+	return nil
+}
+
+func (x *ParameterSweep) GetResults() []*ParameterSweepResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ParameterSweep) GetContactSheetUrl() string {
+	if x != nil {
+		return x.ContactSheetUrl
+	}
+	return ""
+}
+
+func (x *ParameterSweep) GetTotalCompositions() int32 {
+	if x != nil {
+		return x.TotalCompositions
+	}
+	return 0
+}
+
+func (x *ParameterSweep) GetFinishedCompositions() int32 {
+	if x != nil {
+		return x.FinishedCompositions
+	}
+	return 0
+}
+
+func (x *ParameterSweep) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *ParameterSweep) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *ParameterSweep) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+type CreateParameterSweepRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The parent which owns the sweep.
+	// For example: "users/123/arts/456"
+	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	// The sweep to create.
+	ParameterSweep *ParameterSweep `protobuf:"bytes,2,opt,name=parameter_sweep,json=parameterSweep,proto3" json:"parameter_sweep,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateParameterSweepRequest) Reset() {
+	*x = CreateParameterSweepRequest{}
+	mi := &file_art_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateParameterSweepRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateParameterSweepRequest) ProtoMessage() {}
+
+func (x *CreateParameterSweepRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateParameterSweepRequest.ProtoReflect.Descriptor instead.
+func (*CreateParameterSweepRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CreateParameterSweepRequest) GetParent() string {
+	if x != nil {
+		return x.Parent
+	}
+	return ""
+}
+
+func (x *CreateParameterSweepRequest) GetParameterSweep() *ParameterSweep {
+	if x != nil {
+		return x.ParameterSweep
+	}
+	return nil
+}
+
+type GetParameterSweepRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the ParameterSweep resource.
+	// For example: "users/123/arts/456/sweeps/789"
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetParameterSweepRequest) Reset() {
+	*x = GetParameterSweepRequest{}
+	mi := &file_art_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetParameterSweepRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetParameterSweepRequest) ProtoMessage() {}
+
+func (x *GetParameterSweepRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetParameterSweepRequest.ProtoReflect.Descriptor instead.
+func (*GetParameterSweepRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetParameterSweepRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// CalibrationBand is a band of the calibration test piece strung with parallel threads
+type CalibrationBand struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Threads per mm, 0 for the bare background
+	Density float64 `protobuf:"fixed64,1,opt,name=density,proto3" json:"density,omitempty"`
+	// Measured brightness relative to the background, from 0 to 1
+	Brightness float64 `protobuf:"fixed64,2,opt,name=brightness,proto3" json:"brightness,omitempty"`
+	// Brightness predicted by the fitted profile
+	PredictedBrightness float64 `protobuf:"fixed64,3,opt,name=predicted_brightness,json=predictedBrightness,proto3" json:"predicted_brightness,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *CalibrationBand) Reset() {
+	*x = CalibrationBand{}
+	mi := &file_art_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalibrationBand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalibrationBand) ProtoMessage() {}
+
+func (x *CalibrationBand) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalibrationBand.ProtoReflect.Descriptor instead.
+func (*CalibrationBand) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CalibrationBand) GetDensity() float64 {
+	if x != nil {
+		return x.Density
+	}
+	return 0
+}
+
+func (x *CalibrationBand) GetBrightness() float64 {
+	if x != nil {
+		return x.Brightness
+	}
+	return 0
+}
+
+func (x *CalibrationBand) GetPredictedBrightness() float64 {
+	if x != nil {
+		return x.PredictedBrightness
+	}
+	return 0
+}
+
+// ThreadProfile models how dark the threads of a user look on the frame. It
+// is fitted on the photo of a test piece strung with known line densities.
+type ThreadProfile struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the ThreadProfile resource.
+	// For example: "users/123/threadProfiles/456"
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Name shown to the user, unique among their profiles
+	DisplayName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// Photo of the test piece cropped to its bands: vertical bands of equal
+	// width from left to right, one per density
+	CalibrationPhoto []byte `protobuf:"bytes,3,opt,name=calibration_photo,json=calibrationPhoto,proto3" json:"calibration_photo,omitempty"`
+	// Threads per mm of each band, 0 for a bare band. At least one band must be bare.
+	Densities []float64 `protobuf:"fixed64,4,rep,packed,name=densities,proto3" json:"densities,omitempty"`
+	// Darkening of the background under a thread, from 0 to 1
+	Opacity float64 `protobuf:"fixed64,5,opt,name=opacity,proto3" json:"opacity,omitempty"`
+	// Apparent thread width in mm
+	ThreadWidth float64 `protobuf:"fixed64,6,opt,name=thread_width,json=threadWidth,proto3" json:"thread_width,omitempty"`
+	// Measured bands with the brightness predicted by the profile
+	Bands []*CalibrationBand `protobuf:"bytes,7,rep,name=bands,proto3" json:"bands,omitempty"`
+	// Root mean square error of the profile on the measured bands
+	FitError float64 `protobuf:"fixed64,8,opt,name=fit_error,json=fitError,proto3" json:"fit_error,omitempty"`
+	// Creation time
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Last update time
+	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThreadProfile) Reset() {
+	*x = ThreadProfile{}
+	mi := &file_art_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThreadProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThreadProfile) ProtoMessage() {}
+
+func (x *ThreadProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThreadProfile.ProtoReflect.Descriptor instead.
+func (*ThreadProfile) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ThreadProfile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ThreadProfile) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *ThreadProfile) GetCalibrationPhoto() []byte {
+	if x != nil {
+		return x.CalibrationPhoto
+	}
+	return nil
+}
+
+func (x *ThreadProfile) GetDensities() []float64 {
+	if x != nil {
+		return x.Densities
+	}
+	return nil
+}
+
+func (x *ThreadProfile) GetOpacity() float64 {
+	if x != nil {
+		return x.Opacity
+	}
+	return 0
+}
+
+func (x *ThreadProfile) GetThreadWidth() float64 {
+	if x != nil {
+		return x.ThreadWidth
+	}
+	return 0
+}
+
+func (x *ThreadProfile) GetBands() []*CalibrationBand {
+	if x != nil {
+		return x.Bands
+	}
+	return nil
+}
+
+func (x *ThreadProfile) GetFitError() float64 {
+	if x != nil {
+		return x.FitError
+	}
+	return 0
 }
 
-func (x *CreateCompositionRequest) GetParent() string {
+func (x *ThreadProfile) GetCreateTime() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Parent
+		return x.CreateTime
 	}
-	return ""
+	return nil
 }
 
-func (x *CreateCompositionRequest) GetComposition() *Composition {
+func (x *ThreadProfile) GetUpdateTime() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Composition
+		return x.UpdateTime
 	}
 	return nil
 }
 
-type GetCompositionRequest struct {
+type CreateThreadProfileRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The name of the Composition resource.
-	// For example: "users/123/arts/456/compositions/789"
-	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The parent which owns the profile.
+	// For example: "users/123"
+	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	// The profile to create.
+	ThreadProfile *ThreadProfile `protobuf:"bytes,2,opt,name=thread_profile,json=threadProfile,proto3" json:"thread_profile,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCompositionRequest) Reset() {
-	*x = GetCompositionRequest{}
-	mi := &file_art_proto_msgTypes[3]
+func (x *CreateThreadProfileRequest) Reset() {
+	*x = CreateThreadProfileRequest{}
+	mi := &file_art_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCompositionRequest) String() string {
+func (x *CreateThreadProfileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCompositionRequest) ProtoMessage() {}
+func (*CreateThreadProfileRequest) ProtoMessage() {}
 
-func (x *GetCompositionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[3]
+func (x *CreateThreadProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -541,43 +2702,49 @@ func (x *GetCompositionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCompositionRequest.ProtoReflect.Descriptor instead.
-func (*GetCompositionRequest) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use CreateThreadProfileRequest.ProtoReflect.Descriptor instead.
+func (*CreateThreadProfileRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *GetCompositionRequest) GetName() string {
+func (x *CreateThreadProfileRequest) GetParent() string {
 	if x != nil {
-		return x.Name
+		return x.Parent
 	}
 	return ""
 }
 
-type UpdateCompositionRequest struct {
+func (x *CreateThreadProfileRequest) GetThreadProfile() *ThreadProfile {
+	if x != nil {
+		return x.ThreadProfile
+	}
+	return nil
+}
+
+type GetThreadProfileRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The Composition resource to update.
-	Composition *Composition `protobuf:"bytes,1,opt,name=composition,proto3" json:"composition,omitempty"`
-	// The update mask applies to the resource.
-	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// The name of the ThreadProfile resource.
+	// For example: "users/123/threadProfiles/456"
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateCompositionRequest) Reset() {
-	*x = UpdateCompositionRequest{}
-	mi := &file_art_proto_msgTypes[4]
+func (x *GetThreadProfileRequest) Reset() {
+	*x = GetThreadProfileRequest{}
+	mi := &file_art_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateCompositionRequest) String() string {
+func (x *GetThreadProfileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateCompositionRequest) ProtoMessage() {}
+func (*GetThreadProfileRequest) ProtoMessage() {}
 
-func (x *UpdateCompositionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[4]
+func (x *GetThreadProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -588,54 +2755,46 @@ func (x *UpdateCompositionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateCompositionRequest.ProtoReflect.Descriptor instead.
-func (*UpdateCompositionRequest) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{4}
-}
-
-func (x *UpdateCompositionRequest) GetComposition() *Composition {
-	if x != nil {
-		return x.Composition
-	}
-	return nil
+// Deprecated: Use GetThreadProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetThreadProfileRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *UpdateCompositionRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+func (x *GetThreadProfileRequest) GetName() string {
 	if x != nil {
-		return x.UpdateMask
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-type ListCompositionsRequest struct {
+type ListThreadProfilesRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The parent which owns the compositions.
-	// For example: "users/123/arts/456"
+	// The parent which owns the profiles.
+	// For example: "users/123"
 	Parent string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
-	// The maximum number of compositions to return.
-	// The service may return fewer than this value.
+	// The maximum number of profiles to return. The service may return fewer than this value.
 	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	// A page token, received from a previous `ListCompositions` call.
+	// A page token, received from a previous `ListThreadProfiles` call.
 	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListCompositionsRequest) Reset() {
-	*x = ListCompositionsRequest{}
-	mi := &file_art_proto_msgTypes[5]
+func (x *ListThreadProfilesRequest) Reset() {
+	*x = ListThreadProfilesRequest{}
+	mi := &file_art_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListCompositionsRequest) String() string {
+func (x *ListThreadProfilesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListCompositionsRequest) ProtoMessage() {}
+func (*ListThreadProfilesRequest) ProtoMessage() {}
 
-func (x *ListCompositionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[5]
+func (x *ListThreadProfilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -646,57 +2805,57 @@ func (x *ListCompositionsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListCompositionsRequest.ProtoReflect.Descriptor instead.
-func (*ListCompositionsRequest) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ListThreadProfilesRequest.ProtoReflect.Descriptor instead.
+func (*ListThreadProfilesRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *ListCompositionsRequest) GetParent() string {
+func (x *ListThreadProfilesRequest) GetParent() string {
 	if x != nil {
 		return x.Parent
 	}
 	return ""
 }
 
-func (x *ListCompositionsRequest) GetPageSize() int32 {
+func (x *ListThreadProfilesRequest) GetPageSize() int32 {
 	if x != nil {
 		return x.PageSize
 	}
 	return 0
 }
 
-func (x *ListCompositionsRequest) GetPageToken() string {
+func (x *ListThreadProfilesRequest) GetPageToken() string {
 	if x != nil {
 		return x.PageToken
 	}
 	return ""
 }
 
-type ListCompositionsResponse struct {
+type ListThreadProfilesResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The compositions returned.
-	Compositions []*Composition `protobuf:"bytes,1,rep,name=compositions,proto3" json:"compositions,omitempty"`
+	// The profiles returned, by name.
+	ThreadProfiles []*ThreadProfile `protobuf:"bytes,1,rep,name=thread_profiles,json=threadProfiles,proto3" json:"thread_profiles,omitempty"`
 	// A token to retrieve next page of results.
 	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListCompositionsResponse) Reset() {
-	*x = ListCompositionsResponse{}
-	mi := &file_art_proto_msgTypes[6]
+func (x *ListThreadProfilesResponse) Reset() {
+	*x = ListThreadProfilesResponse{}
+	mi := &file_art_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListCompositionsResponse) String() string {
+func (x *ListThreadProfilesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListCompositionsResponse) ProtoMessage() {}
+func (*ListThreadProfilesResponse) ProtoMessage() {}
 
-func (x *ListCompositionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[6]
+func (x *ListThreadProfilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -707,49 +2866,49 @@ func (x *ListCompositionsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListCompositionsResponse.ProtoReflect.Descriptor instead.
-func (*ListCompositionsResponse) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ListThreadProfilesResponse.ProtoReflect.Descriptor instead.
+func (*ListThreadProfilesResponse) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *ListCompositionsResponse) GetCompositions() []*Composition {
+func (x *ListThreadProfilesResponse) GetThreadProfiles() []*ThreadProfile {
 	if x != nil {
-		return x.Compositions
+		return x.ThreadProfiles
 	}
 	return nil
 }
 
-func (x *ListCompositionsResponse) GetNextPageToken() string {
+func (x *ListThreadProfilesResponse) GetNextPageToken() string {
 	if x != nil {
 		return x.NextPageToken
 	}
 	return ""
 }
 
-type DeleteCompositionRequest struct {
+type DeleteThreadProfileRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The name of the Composition resource.
-	// For example: "users/123/arts/456/compositions/789"
+	// The name of the ThreadProfile resource.
+	// For example: "users/123/threadProfiles/456"
 	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteCompositionRequest) Reset() {
-	*x = DeleteCompositionRequest{}
-	mi := &file_art_proto_msgTypes[7]
+func (x *DeleteThreadProfileRequest) Reset() {
+	*x = DeleteThreadProfileRequest{}
+	mi := &file_art_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteCompositionRequest) String() string {
+func (x *DeleteThreadProfileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteCompositionRequest) ProtoMessage() {}
+func (*DeleteThreadProfileRequest) ProtoMessage() {}
 
-func (x *DeleteCompositionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[7]
+func (x *DeleteThreadProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -760,12 +2919,12 @@ func (x *DeleteCompositionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteCompositionRequest.ProtoReflect.Descriptor instead.
-func (*DeleteCompositionRequest) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use DeleteThreadProfileRequest.ProtoReflect.Descriptor instead.
+func (*DeleteThreadProfileRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *DeleteCompositionRequest) GetName() string {
+func (x *DeleteThreadProfileRequest) GetName() string {
 	if x != nil {
 		return x.Name
 	}
@@ -785,7 +2944,7 @@ type CreateArtRequest struct {
 
 func (x *CreateArtRequest) Reset() {
 	*x = CreateArtRequest{}
-	mi := &file_art_proto_msgTypes[8]
+	mi := &file_art_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -797,7 +2956,7 @@ func (x *CreateArtRequest) String() string {
 func (*CreateArtRequest) ProtoMessage() {}
 
 func (x *CreateArtRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[8]
+	mi := &file_art_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -810,7 +2969,7 @@ func (x *CreateArtRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateArtRequest.ProtoReflect.Descriptor instead.
 func (*CreateArtRequest) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{8}
+	return file_art_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *CreateArtRequest) GetParent() string {
@@ -839,7 +2998,7 @@ type UpdateArtRequest struct {
 
 func (x *UpdateArtRequest) Reset() {
 	*x = UpdateArtRequest{}
-	mi := &file_art_proto_msgTypes[9]
+	mi := &file_art_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -851,7 +3010,7 @@ func (x *UpdateArtRequest) String() string {
 func (*UpdateArtRequest) ProtoMessage() {}
 
 func (x *UpdateArtRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[9]
+	mi := &file_art_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -864,7 +3023,7 @@ func (x *UpdateArtRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateArtRequest.ProtoReflect.Descriptor instead.
 func (*UpdateArtRequest) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{9}
+	return file_art_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *UpdateArtRequest) GetArt() *Art {
@@ -892,7 +3051,7 @@ type GetArtRequest struct {
 
 func (x *GetArtRequest) Reset() {
 	*x = GetArtRequest{}
-	mi := &file_art_proto_msgTypes[10]
+	mi := &file_art_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -904,7 +3063,7 @@ func (x *GetArtRequest) String() string {
 func (*GetArtRequest) ProtoMessage() {}
 
 func (x *GetArtRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[10]
+	mi := &file_art_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -917,7 +3076,7 @@ func (x *GetArtRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetArtRequest.ProtoReflect.Descriptor instead.
 func (*GetArtRequest) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{10}
+	return file_art_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *GetArtRequest) GetName() string {
@@ -946,7 +3105,7 @@ type ListArtsRequest struct {
 
 func (x *ListArtsRequest) Reset() {
 	*x = ListArtsRequest{}
-	mi := &file_art_proto_msgTypes[11]
+	mi := &file_art_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -958,7 +3117,7 @@ func (x *ListArtsRequest) String() string {
 func (*ListArtsRequest) ProtoMessage() {}
 
 func (x *ListArtsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[11]
+	mi := &file_art_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -971,7 +3130,7 @@ func (x *ListArtsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListArtsRequest.ProtoReflect.Descriptor instead.
 func (*ListArtsRequest) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{11}
+	return file_art_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *ListArtsRequest) GetParent() string {
@@ -1022,7 +3181,7 @@ type ListArtsResponse struct {
 
 func (x *ListArtsResponse) Reset() {
 	*x = ListArtsResponse{}
-	mi := &file_art_proto_msgTypes[12]
+	mi := &file_art_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1034,7 +3193,7 @@ func (x *ListArtsResponse) String() string {
 func (*ListArtsResponse) ProtoMessage() {}
 
 func (x *ListArtsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[12]
+	mi := &file_art_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1047,7 +3206,7 @@ func (x *ListArtsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListArtsResponse.ProtoReflect.Descriptor instead.
 func (*ListArtsResponse) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{12}
+	return file_art_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *ListArtsResponse) GetArts() []*Art {
@@ -1075,7 +3234,7 @@ type DeleteArtRequest struct {
 
 func (x *DeleteArtRequest) Reset() {
 	*x = DeleteArtRequest{}
-	mi := &file_art_proto_msgTypes[13]
+	mi := &file_art_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1087,7 +3246,7 @@ func (x *DeleteArtRequest) String() string {
 func (*DeleteArtRequest) ProtoMessage() {}
 
 func (x *DeleteArtRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[13]
+	mi := &file_art_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1100,7 +3259,7 @@ func (x *DeleteArtRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteArtRequest.ProtoReflect.Descriptor instead.
 func (*DeleteArtRequest) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{13}
+	return file_art_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *DeleteArtRequest) GetName() string {
@@ -1115,7 +3274,8 @@ type GetArtUploadUrlRequest struct {
 	// The name of the Art resource to upload an image for.
 	// For example: "users/123/arts/456"
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	// The content type of the image to upload
+	// The content type of the image to upload. SVG images are uploaded to the
+	// private bucket, the art shows their rendering.
 	ContentType string `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
 	// The size of the file to upload in bytes
 	FileSize      int64 `protobuf:"varint,3,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
@@ -1125,7 +3285,7 @@ type GetArtUploadUrlRequest struct {
 
 func (x *GetArtUploadUrlRequest) Reset() {
 	*x = GetArtUploadUrlRequest{}
-	mi := &file_art_proto_msgTypes[14]
+	mi := &file_art_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1137,7 +3297,7 @@ func (x *GetArtUploadUrlRequest) String() string {
 func (*GetArtUploadUrlRequest) ProtoMessage() {}
 
 func (x *GetArtUploadUrlRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[14]
+	mi := &file_art_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1150,7 +3310,7 @@ func (x *GetArtUploadUrlRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetArtUploadUrlRequest.ProtoReflect.Descriptor instead.
 func (*GetArtUploadUrlRequest) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{14}
+	return file_art_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *GetArtUploadUrlRequest) GetName() string {
@@ -1186,7 +3346,7 @@ type GetArtUploadUrlResponse struct {
 
 func (x *GetArtUploadUrlResponse) Reset() {
 	*x = GetArtUploadUrlResponse{}
-	mi := &file_art_proto_msgTypes[15]
+	mi := &file_art_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1198,7 +3358,7 @@ func (x *GetArtUploadUrlResponse) String() string {
 func (*GetArtUploadUrlResponse) ProtoMessage() {}
 
 func (x *GetArtUploadUrlResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[15]
+	mi := &file_art_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1211,7 +3371,7 @@ func (x *GetArtUploadUrlResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetArtUploadUrlResponse.ProtoReflect.Descriptor instead.
 func (*GetArtUploadUrlResponse) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{15}
+	return file_art_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *GetArtUploadUrlResponse) GetUploadUrl() string {
@@ -1239,7 +3399,7 @@ type ConfirmArtImageUploadRequest struct {
 
 func (x *ConfirmArtImageUploadRequest) Reset() {
 	*x = ConfirmArtImageUploadRequest{}
-	mi := &file_art_proto_msgTypes[16]
+	mi := &file_art_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1251,7 +3411,7 @@ func (x *ConfirmArtImageUploadRequest) String() string {
 func (*ConfirmArtImageUploadRequest) ProtoMessage() {}
 
 func (x *ConfirmArtImageUploadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_art_proto_msgTypes[16]
+	mi := &file_art_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1264,7 +3424,7 @@ func (x *ConfirmArtImageUploadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConfirmArtImageUploadRequest.ProtoReflect.Descriptor instead.
 func (*ConfirmArtImageUploadRequest) Descriptor() ([]byte, []int) {
-	return file_art_proto_rawDescGZIP(), []int{16}
+	return file_art_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *ConfirmArtImageUploadRequest) GetName() string {
@@ -1274,6 +3434,170 @@ func (x *ConfirmArtImageUploadRequest) GetName() string {
 	return ""
 }
 
+type GetQueueStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQueueStatsRequest) Reset() {
+	*x = GetQueueStatsRequest{}
+	mi := &file_art_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQueueStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQueueStatsRequest) ProtoMessage() {}
+
+func (x *GetQueueStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQueueStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetQueueStatsRequest) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{40}
+}
+
+// QueueStats reports the composition jobs waiting in each priority queue
+type QueueStats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Queues from the highest priority to the lowest
+	Queues        []*QueueDepth `protobuf:"bytes,1,rep,name=queues,proto3" json:"queues,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueStats) Reset() {
+	*x = QueueStats{}
+	mi := &file_art_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueStats) ProtoMessage() {}
+
+func (x *QueueStats) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueStats.ProtoReflect.Descriptor instead.
+func (*QueueStats) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *QueueStats) GetQueues() []*QueueDepth {
+	if x != nil {
+		return x.Queues
+	}
+	return nil
+}
+
+// QueueDepth reports the jobs waiting in the queue of a priority
+type QueueDepth struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Priority the queue holds
+	Priority CompositionPriority `protobuf:"varint,1,opt,name=priority,proto3,enum=pb.CompositionPriority" json:"priority,omitempty"`
+	// Name of the queue
+	Queue string `protobuf:"bytes,2,opt,name=queue,proto3" json:"queue,omitempty"`
+	// Messages ready for a worker
+	ReadyMessages int32 `protobuf:"varint,3,opt,name=ready_messages,json=readyMessages,proto3" json:"ready_messages,omitempty"`
+	// Messages put aside because their user reached the cap of concurrent jobs
+	DeferredMessages int32 `protobuf:"varint,4,opt,name=deferred_messages,json=deferredMessages,proto3" json:"deferred_messages,omitempty"`
+	// Workers consuming the queue
+	Consumers     int32 `protobuf:"varint,5,opt,name=consumers,proto3" json:"consumers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueDepth) Reset() {
+	*x = QueueDepth{}
+	mi := &file_art_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueDepth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueDepth) ProtoMessage() {}
+
+func (x *QueueDepth) ProtoReflect() protoreflect.Message {
+	mi := &file_art_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueDepth.ProtoReflect.Descriptor instead.
+func (*QueueDepth) Descriptor() ([]byte, []int) {
+	return file_art_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *QueueDepth) GetPriority() CompositionPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return CompositionPriority_COMPOSITION_PRIORITY_UNSPECIFIED
+}
+
+func (x *QueueDepth) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *QueueDepth) GetReadyMessages() int32 {
+	if x != nil {
+		return x.ReadyMessages
+	}
+	return 0
+}
+
+func (x *QueueDepth) GetDeferredMessages() int32 {
+	if x != nil {
+		return x.DeferredMessages
+	}
+	return 0
+}
+
+func (x *QueueDepth) GetConsumers() int32 {
+	if x != nil {
+		return x.Consumers
+	}
+	return 0
+}
+
 var File_art_proto protoreflect.FileDescriptor
 
 const file_art_proto_rawDesc = "" +
@@ -1293,8 +3617,7 @@ const file_art_proto_rawDesc = "" +
 	"createTime\x12@\n" +
 	"\vupdate_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
 	"updateTime:1\xeaA.\n" +
-	"\x13art.example.com/Art\x12\x17users/{user}/arts/{art}\"\xf9\n" +
-	"\n" +
+	"\x13art.example.com/Art\x12\x17users/{user}/arts/{art}\"\xfb\x15\n" +
 	"\vComposition\x127\n" +
 	"\x04name\x18\x01 \x01(\tB#\xe0A\x03\xfaA\x1d\n" +
 	"\x1bart.example.com/CompositionR\x04name\x122\n" +
@@ -1330,8 +3653,74 @@ const file_art_proto_rawDesc = "" +
 	"\vcreate_time\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
 	"createTime\x12@\n" +
 	"\vupdate_time\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
-	"updateTime:T\xeaAQ\n" +
-	"\x1bart.example.com/Composition\x122users/{user}/arts/{art}/compositions/{composition}\"\xc1\x02\n" +
+	"updateTime\x12?\n" +
+	"\rgcode_dialect\x18\x14 \x01(\x0e2\x10.pb.GcodeDialectB\b\xbaH\x05\x82\x01\x02\x10\x01R\fgcodeDialect\x12\xc0\x01\n" +
+	"\x0fdrill_gcode_url\x18\x15 \x01(\tB\x97\x01\xe0A\x03\xbaH\x90\x01\xba\x01\x8c\x01\n" +
+	",composition.drill_gcode_url.uri_when_present\x120Drill GCode URL must be a valid URI when present\x1a*this == '' || this.matches('^https?://.+')R\rdrillGcodeUrl\x12-\n" +
+	"\fspool_length\x18\x16 \x01(\x02B\n" +
+	"\xbaH\a\n" +
+	"\x05-\x00\x00\x00\x00R\vspoolLength\x122\n" +
+	"\bsegments\x18\x17 \x03(\v2\x11.pb.ThreadSegmentB\x03\xe0A\x03R\bsegments\x12,\n" +
+	"\x05rings\x18\x18 \x03(\v2\f.pb.NailRingB\b\xbaH\x05\x92\x01\x02\x10\x03R\x05rings\x12\xf6\x01\n" +
+	"\x0ethread_profile\x18\x19 \x01(\tB\xce\x01\xfaA\x1f\n" +
+	"\x1dart.example.com/ThreadProfile\xbaH\xa8\x01\xba\x01\xa4\x01\n" +
+	"!composition.thread_profile.format\x12=Thread profile must follow pattern 'users/*/threadProfiles/*'\x1a@this == '' || this.matches('^users/[^/]+/threadProfiles/[^/]+$')R\rthreadProfile\x12!\n" +
+	"\flinear_light\x18\x1a \x01(\bR\vlinearLight\x12;\n" +
+	"\tanalytics\x18\x1b \x01(\v2\x18.pb.CompositionAnalyticsB\x03\xe0A\x03R\tanalytics\x12\xb1\x01\n" +
+	"\vheatmap_url\x18\x1c \x01(\tB\x8f\x01\xe0A\x03\xbaH\x88\x01\xba\x01\x84\x01\n" +
+	"(composition.heatmap_url.uri_when_present\x12,Heatmap URL must be a valid URI when present\x1a*this == '' || this.matches('^https?://.+')R\n" +
+	"heatmapUrl\x126\n" +
+	"\n" +
+	"input_type\x18\x1d \x01(\x0e2\r.pb.InputTypeB\b\xbaH\x05\x82\x01\x02\x10\x01R\tinputType\x12\x12\n" +
+	"\x04seed\x18\x1e \x01(\x03R\x04seed\x12&\n" +
+	"\fcontent_hash\x18\x1f \x01(\tB\x03\xe0A\x03R\vcontentHash\x12.\n" +
+	"\rsymmetry_fold\x18  \x01(\x05B\t\xbaH\x06\x1a\x04\x18$(\x00R\fsymmetryFold\x12'\n" +
+	"\x0fmirror_symmetry\x18! \x01(\bR\x0emirrorSymmetry\x128\n" +
+	"\bprogress\x18\" \x01(\v2\x17.pb.CompositionProgressB\x03\xe0A\x03R\bprogress\x12@\n" +
+	"\bpriority\x18# \x01(\x0e2\x17.pb.CompositionPriorityB\v\xe0A\x05\xbaH\x05\x82\x01\x02\x10\x01R\bpriority:\xea\x01\xeaAQ\n" +
+	"\x1bart.example.com/Composition\x122users/{user}/arts/{art}/compositions/{composition}\xbaH\x92\x01\x1a\x8f\x01\n" +
+	"(composition.rings.within_physical_radius\x12.Every ring must fit within the physical radius\x1a3this.rings.all(r, r.radius <= this.physical_radius)\"\x9c\x01\n" +
+	"\bNailRing\x121\n" +
+	"\x0enails_quantity\x18\x01 \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\xe8\a \x00R\rnailsQuantity\x12\"\n" +
+	"\x06radius\x18\x02 \x01(\x02B\n" +
+	"\xbaH\a\n" +
+	"\x05%\x00\x00\x00\x00R\x06radius\x129\n" +
+	"\x12minimum_difference\x18\x03 \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\xc8\x01(\x00R\x11minimumDifference\"\x80\x01\n" +
+	"\rThreadSegment\x12\x1d\n" +
+	"\n" +
+	"start_step\x18\x01 \x01(\x05R\tstartStep\x12\x19\n" +
+	"\bend_step\x18\x02 \x01(\x05R\aendStep\x12\x1d\n" +
+	"\n" +
+	"start_nail\x18\x03 \x01(\x05R\tstartNail\x12\x16\n" +
+	"\x06length\x18\x04 \x01(\x02R\x06length\"\xad\x02\n" +
+	"\x13CompositionProgress\x12'\n" +
+	"\x05stage\x18\x01 \x01(\x0e2\x11.pb.ProgressStageR\x05stage\x12\x18\n" +
+	"\apercent\x18\x02 \x01(\x02R\apercent\x12!\n" +
+	"\flines_placed\x18\x03 \x01(\x05R\vlinesPlaced\x12\x1b\n" +
+	"\tmax_lines\x18\x04 \x01(\x05R\bmaxLines\x12V\n" +
+	"\x19estimated_completion_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x17estimatedCompletionTime\x12;\n" +
+	"\vupdate_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"updateTime\"\xcc\x02\n" +
+	"\x14CompositionAnalytics\x12\x1b\n" +
+	"\tnail_hits\x18\x01 \x03(\x05R\bnailHits\x127\n" +
+	"\x0ehits_histogram\x18\x02 \x03(\v2\x10.pb.HistogramBinR\rhitsHistogram\x12\x1b\n" +
+	"\tmean_hits\x18\x03 \x01(\x02R\bmeanHits\x123\n" +
+	"\x0eheaviest_nails\x18\x04 \x03(\v2\f.pb.NailLoadR\rheaviestNails\x12)\n" +
+	"\x10overloaded_nails\x18\x05 \x01(\x05R\x0foverloadedNails\x125\n" +
+	"\rchord_lengths\x18\x06 \x03(\v2\x10.pb.HistogramBinR\fchordLengths\x12*\n" +
+	"\x11mean_chord_length\x18\a \x01(\x02R\x0fmeanChordLength\"H\n" +
+	"\fHistogramBin\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\x02R\x03min\x12\x10\n" +
+	"\x03max\x18\x02 \x01(\x02R\x03max\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x05R\x05count\"r\n" +
+	"\bNailLoad\x12\x12\n" +
+	"\x04nail\x18\x01 \x01(\x05R\x04nail\x12\x12\n" +
+	"\x04ring\x18\x02 \x01(\x05R\x04ring\x12\x14\n" +
+	"\x05index\x18\x03 \x01(\x05R\x05index\x12\x12\n" +
+	"\x04hits\x18\x04 \x01(\x05R\x04hits\x12\x14\n" +
+	"\x05ratio\x18\x05 \x01(\x02R\x05ratio\"\xc1\x02\n" +
 	"\x18CreateCompositionRequest\x12\xe6\x01\n" +
 	"\x06parent\x18\x01 \x01(\tB\xcd\x01\xe0A\x02\xfaA\x15\n" +
 	"\x13art.example.com/Art\xbaH\xae\x01\xba\x01\xaa\x01\n" +
@@ -1354,11 +3743,114 @@ const file_art_proto_rawDesc = "" +
 	"page_token\x18\x03 \x01(\tR\tpageToken\"w\n" +
 	"\x18ListCompositionsResponse\x123\n" +
 	"\fcompositions\x18\x01 \x03(\v2\x0f.pb.CompositionR\fcompositions\x12&\n" +
-	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xac\x02\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xe0\x02\n" +
+	"\"GetCompositionGcodeFromStepRequest\x12\x9c\x02\n" +
+	"\x04name\x18\x01 \x01(\tB\x87\x02\xe0A\x02\xfaA\x1d\n" +
+	"\x1bart.example.com/Composition\xbaH\xe0\x01\xba\x01\xdc\x01\n" +
+	"+get_composition_gcode_from_step.name.format\x12]Composition resource name is required and must follow pattern 'users/*/arts/*/compositions/*'\x1aNthis.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+/compositions/[^/]+$')R\x04name\x12\x1b\n" +
+	"\x04step\x18\x02 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\x04step\"x\n" +
+	"#GetCompositionGcodeFromStepResponse\x12\x14\n" +
+	"\x05gcode\x18\x01 \x01(\tR\x05gcode\x12\x12\n" +
+	"\x04step\x18\x02 \x01(\x05R\x04step\x12'\n" +
+	"\x0fremaining_paths\x18\x03 \x01(\x05R\x0eremainingPaths\"\x89\x03\n" +
+	"%GetCompositionCalibrationGcodeRequest\x12\x9e\x02\n" +
+	"\x04name\x18\x01 \x01(\tB\x89\x02\xe0A\x02\xfaA\x1d\n" +
+	"\x1bart.example.com/Composition\xbaH\xe2\x01\xba\x01\xde\x01\n" +
+	"-get_composition_calibration_gcode.name.format\x12]Composition resource name is required and must follow pattern 'users/*/arts/*/compositions/*'\x1aNthis.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+/compositions/[^/]+$')R\x04name\x12?\n" +
+	"\aroutine\x18\x02 \x01(\x0e2\x16.pb.CalibrationRoutineB\r\xe0A\x02\xbaH\a\x82\x01\x04\x10\x01 \x00R\aroutine\"p\n" +
+	"&GetCompositionCalibrationGcodeResponse\x12\x14\n" +
+	"\x05gcode\x18\x01 \x01(\tR\x05gcode\x120\n" +
+	"\aroutine\x18\x02 \x01(\x0e2\x16.pb.CalibrationRoutineR\aroutine\"\xac\x02\n" +
+	"\x18CancelCompositionRequest\x12\x8f\x02\n" +
+	"\x04name\x18\x01 \x01(\tB\xfa\x01\xe0A\x02\xfaA\x1d\n" +
+	"\x1bart.example.com/Composition\xbaH\xd3\x01\xba\x01\xcf\x01\n" +
+	"\x1ecancel_composition.name.format\x12]Composition resource name is required and must follow pattern 'users/*/arts/*/compositions/*'\x1aNthis.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+/compositions/[^/]+$')R\x04name\"\xac\x02\n" +
 	"\x18DeleteCompositionRequest\x12\x8f\x02\n" +
 	"\x04name\x18\x01 \x01(\tB\xfa\x01\xe0A\x02\xfaA\x1d\n" +
 	"\x1bart.example.com/Composition\xbaH\xd3\x01\xba\x01\xcf\x01\n" +
-	"\x1edelete_composition.name.format\x12]Composition resource name is required and must follow pattern 'users/*/arts/*/compositions/*'\x1aNthis.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+/compositions/[^/]+$')R\x04name\"\xf9\x01\n" +
+	"\x1edelete_composition.name.format\x12]Composition resource name is required and must follow pattern 'users/*/arts/*/compositions/*'\x1aNthis.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+/compositions/[^/]+$')R\x04name\"\x9d\x02\n" +
+	"\x0eParameterRange\x12?\n" +
+	"\tparameter\x18\x01 \x01(\x0e2\x12.pb.SweepParameterB\r\xe0A\x02\xbaH\a\x82\x01\x04\x10\x01 \x00R\tparameter\x12 \n" +
+	"\x03min\x18\x02 \x01(\x01B\x0e\xbaH\v\x12\t!\x00\x00\x00\x00\x00\x00\x00\x00R\x03min\x12 \n" +
+	"\x03max\x18\x03 \x01(\x01B\x0e\xbaH\v\x12\t!\x00\x00\x00\x00\x00\x00\x00\x00R\x03max\x12\"\n" +
+	"\x04step\x18\x04 \x01(\x01B\x0e\xbaH\v\x12\t!\x00\x00\x00\x00\x00\x00\x00\x00R\x04step:b\xbaH_\x1a]\n" +
+	"\x1bparameter_range.max_gte_min\x12(Max must be greater than or equal to min\x1a\x14this.max >= this.min\"s\n" +
+	"\x14ParameterSweepResult\x121\n" +
+	"\vcomposition\x18\x01 \x01(\v2\x0f.pb.CompositionR\vcomposition\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12\x12\n" +
+	"\x04rank\x18\x03 \x01(\x05R\x04rank\"\xf3\x06\n" +
+	"\x0eParameterSweep\x12:\n" +
+	"\x04name\x18\x01 \x01(\tB&\xe0A\x03\xfaA \n" +
+	"\x1eart.example.com/ParameterSweepR\x04name\x125\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x18.pb.ParameterSweepStatusB\x03\xe0A\x03R\x06status\x12B\n" +
+	"\x10base_composition\x18\x03 \x01(\v2\x0f.pb.CompositionB\x06\xe0A\x04\xe0A\x02R\x0fbaseComposition\x129\n" +
+	"\x06ranges\x18\x04 \x03(\v2\x12.pb.ParameterRangeB\r\xe0A\x02\xbaH\a\x92\x01\x04\b\x01\x10\x04R\x06ranges\x127\n" +
+	"\aresults\x18\x05 \x03(\v2\x18.pb.ParameterSweepResultB\x03\xe0A\x03R\aresults\x12\xcc\x01\n" +
+	"\x11contact_sheet_url\x18\x06 \x01(\tB\x9f\x01\xe0A\x03\xbaH\x98\x01\xba\x01\x94\x01\n" +
+	"2parameter_sweep.contact_sheet_url.uri_when_present\x122Contact sheet URL must be a valid URI when present\x1a*this == '' || this.matches('^https?://.+')R\x0fcontactSheetUrl\x122\n" +
+	"\x12total_compositions\x18\a \x01(\x05B\x03\xe0A\x03R\x11totalCompositions\x128\n" +
+	"\x15finished_compositions\x18\b \x01(\x05B\x03\xe0A\x03R\x14finishedCompositions\x12(\n" +
+	"\rerror_message\x18\t \x01(\tB\x03\xe0A\x03R\ferrorMessage\x12@\n" +
+	"\vcreate_time\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
+	"createTime\x12@\n" +
+	"\vupdate_time\x18\v \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
+	"updateTime:K\xeaAH\n" +
+	"\x1eart.example.com/ParameterSweep\x12&users/{user}/arts/{art}/sweeps/{sweep}\"\xd2\x02\n" +
+	"\x1bCreateParameterSweepRequest\x12\xea\x01\n" +
+	"\x06parent\x18\x01 \x01(\tB\xd1\x01\xe0A\x02\xfaA\x15\n" +
+	"\x13art.example.com/Art\xbaH\xb2\x01\xba\x01\xae\x01\n" +
+	"$create_parameter_sweep.parent.format\x12IParent resource name is required and must follow pattern 'users/*/arts/*'\x1a;this.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+$')R\x06parent\x12F\n" +
+	"\x0fparameter_sweep\x18\x02 \x01(\v2\x12.pb.ParameterSweepB\t\xe0A\x02\xbaH\x03\xc8\x01\x01R\x0eparameterSweep\"\xa8\x02\n" +
+	"\x18GetParameterSweepRequest\x12\x8b\x02\n" +
+	"\x04name\x18\x01 \x01(\tB\xf6\x01\xe0A\x02\xfaA \n" +
+	"\x1eart.example.com/ParameterSweep\xbaH\xcc\x01\xba\x01\xc8\x01\n" +
+	"\x1fget_parameter_sweep.name.format\x12[Parameter sweep resource name is required and must follow pattern 'users/*/arts/*/sweeps/*'\x1aHthis.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+/sweeps/[^/]+$')R\x04name\"~\n" +
+	"\x0fCalibrationBand\x12\x18\n" +
+	"\adensity\x18\x01 \x01(\x01R\adensity\x12\x1e\n" +
+	"\n" +
+	"brightness\x18\x02 \x01(\x01R\n" +
+	"brightness\x121\n" +
+	"\x14predicted_brightness\x18\x03 \x01(\x01R\x13predictedBrightness\"\xf1\x04\n" +
+	"\rThreadProfile\x129\n" +
+	"\x04name\x18\x01 \x01(\tB%\xe0A\x03\xfaA\x1f\n" +
+	"\x1dart.example.com/ThreadProfileR\x04name\x12/\n" +
+	"\fdisplay_name\x18\x02 \x01(\tB\f\xe0A\x02\xbaH\x06r\x04\x10\x01\x18dR\vdisplayName\x12?\n" +
+	"\x11calibration_photo\x18\x03 \x01(\fB\x12\xe0A\x04\xe0A\x02\xbaH\tz\a\x10\x01\x18\x80\x80\x80\x05R\x10calibrationPhoto\x12D\n" +
+	"\tdensities\x18\x04 \x03(\x01B&\xe0A\x04\xe0A\x02\xbaH\x1d\x92\x01\x1a\b\x02\x10\x14\"\x14\x12\x12\x19\x00\x00\x00\x00\x00\x00Y@)\x00\x00\x00\x00\x00\x00\x00\x00R\tdensities\x12\x1d\n" +
+	"\aopacity\x18\x05 \x01(\x01B\x03\xe0A\x03R\aopacity\x12&\n" +
+	"\fthread_width\x18\x06 \x01(\x01B\x03\xe0A\x03R\vthreadWidth\x12.\n" +
+	"\x05bands\x18\a \x03(\v2\x13.pb.CalibrationBandB\x03\xe0A\x03R\x05bands\x12 \n" +
+	"\tfit_error\x18\b \x01(\x01B\x03\xe0A\x03R\bfitError\x12@\n" +
+	"\vcreate_time\x18\t \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
+	"createTime\x12@\n" +
+	"\vupdate_time\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
+	"updateTime:P\xeaAM\n" +
+	"\x1dart.example.com/ThreadProfile\x12,users/{user}/threadProfiles/{thread_profile}\"\xbc\x02\n" +
+	"\x1aCreateThreadProfileRequest\x12\xd8\x01\n" +
+	"\x06parent\x18\x01 \x01(\tB\xbf\x01\xe0A\x02\xfaA\x16\n" +
+	"\x14art.example.com/User\xbaH\x9f\x01\xba\x01\x9b\x01\n" +
+	"#create_thread_profile.parent.format\x12BParent resource name is required and must follow pattern 'users/*'\x1a0this.size() > 0 && this.matches('^users/[^/]+$')R\x06parent\x12C\n" +
+	"\x0ethread_profile\x18\x02 \x01(\v2\x11.pb.ThreadProfileB\t\xe0A\x02\xbaH\x03\xc8\x01\x01R\rthreadProfile\"\xa2\x02\n" +
+	"\x17GetThreadProfileRequest\x12\x86\x02\n" +
+	"\x04name\x18\x01 \x01(\tB\xf1\x01\xe0A\x02\xfaA\x1f\n" +
+	"\x1dart.example.com/ThreadProfile\xbaH\xc8\x01\xba\x01\xc4\x01\n" +
+	"\x1eget_thread_profile.name.format\x12[Thread profile resource name is required and must follow pattern 'users/*/threadProfiles/*'\x1aEthis.size() > 0 && this.matches('^users/[^/]+/threadProfiles/[^/]+$')R\x04name\"\xbc\x02\n" +
+	"\x19ListThreadProfilesRequest\x12\xd7\x01\n" +
+	"\x06parent\x18\x01 \x01(\tB\xbe\x01\xe0A\x02\xfaA\x16\n" +
+	"\x14art.example.com/User\xbaH\x9e\x01\xba\x01\x9a\x01\n" +
+	"\"list_thread_profiles.parent.format\x12BParent resource name is required and must follow pattern 'users/*'\x1a0this.size() > 0 && this.matches('^users/[^/]+$')R\x06parent\x12&\n" +
+	"\tpage_size\x18\x02 \x01(\x05B\t\xbaH\x06\x1a\x04\x18d \x00R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"\x80\x01\n" +
+	"\x1aListThreadProfilesResponse\x12:\n" +
+	"\x0fthread_profiles\x18\x01 \x03(\v2\x11.pb.ThreadProfileR\x0ethreadProfiles\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xa8\x02\n" +
+	"\x1aDeleteThreadProfileRequest\x12\x89\x02\n" +
+	"\x04name\x18\x01 \x01(\tB\xf4\x01\xe0A\x02\xfaA\x1f\n" +
+	"\x1dart.example.com/ThreadProfile\xbaH\xcb\x01\xba\x01\xc7\x01\n" +
+	"!delete_thread_profile.name.format\x12[Thread profile resource name is required and must follow pattern 'users/*/threadProfiles/*'\x1aEthis.size() > 0 && this.matches('^users/[^/]+/threadProfiles/[^/]+$')R\x04name\"\xf9\x01\n" +
 	"\x10CreateArtRequest\x12\xbe\x01\n" +
 	"\x06parent\x18\x01 \x01(\tB\xa5\x01\xe0A\x02\xfaA\x16\n" +
 	"\x14art.example.com/User\xbaH\x85\x01\xba\x01\x81\x01\n" +
@@ -1387,13 +3879,13 @@ const file_art_proto_rawDesc = "" +
 	"\x10DeleteArtRequest\x12\xd5\x01\n" +
 	"\x04name\x18\x01 \x01(\tB\xc0\x01\xe0A\x02\xfaA\x15\n" +
 	"\x13art.example.com/Art\xbaH\xa1\x01\xba\x01\x9d\x01\n" +
-	"\x16delete_art.name.format\x12FArt resource name is required and must follow pattern 'users/*/arts/*'\x1a;this.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+$')R\x04name\"\xf5\x03\n" +
+	"\x16delete_art.name.format\x12FArt resource name is required and must follow pattern 'users/*/arts/*'\x1a;this.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+$')R\x04name\"\x86\x04\n" +
 	"\x16GetArtUploadUrlRequest\x12\xdd\x01\n" +
 	"\x04name\x18\x01 \x01(\tB\xc8\x01\xe0A\x02\xfaA\x15\n" +
 	"\x13art.example.com/Art\xbaH\xa9\x01\xba\x01\xa5\x01\n" +
-	"\x1eget_art_upload_url.name.format\x12FArt resource name is required and must follow pattern 'users/*/arts/*'\x1a;this.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+$')R\x04name\x12\xcc\x01\n" +
-	"\fcontent_type\x18\x02 \x01(\tB\xa8\x01\xe0A\x02\xbaH\xa1\x01\xba\x01\x9d\x01\n" +
-	"%get_art_upload_url.content_type.valid\x12'Content type must be a valid image type\x1aKthis in ['image/jpeg', 'image/jpg', 'image/png', 'image/gif', 'image/webp']R\vcontentType\x12,\n" +
+	"\x1eget_art_upload_url.name.format\x12FArt resource name is required and must follow pattern 'users/*/arts/*'\x1a;this.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+$')R\x04name\x12\xdd\x01\n" +
+	"\fcontent_type\x18\x02 \x01(\tB\xb9\x01\xe0A\x02\xbaH\xb2\x01\xba\x01\xae\x01\n" +
+	"%get_art_upload_url.content_type.valid\x12'Content type must be a valid image type\x1a\\this in ['image/jpeg', 'image/jpg', 'image/png', 'image/gif', 'image/webp', 'image/svg+xml']R\vcontentType\x12,\n" +
 	"\tfile_size\x18\x03 \x01(\x03B\x0f\xe0A\x02\xbaH\t\"\a\x18\x80\x80\xc0\x02(\x01R\bfileSize\"}\n" +
 	"\x17GetArtUploadUrlResponse\x12\x1d\n" +
 	"\n" +
@@ -1402,20 +3894,72 @@ const file_art_proto_rawDesc = "" +
 	"\x1cConfirmArtImageUploadRequest\x12\xe3\x01\n" +
 	"\x04name\x18\x01 \x01(\tB\xce\x01\xe0A\x02\xfaA\x15\n" +
 	"\x13art.example.com/Art\xbaH\xaf\x01\xba\x01\xab\x01\n" +
-	"$confirm_art_image_upload.name.format\x12FArt resource name is required and must follow pattern 'users/*/arts/*'\x1a;this.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+$')R\x04name*\xa9\x01\n" +
+	"$confirm_art_image_upload.name.format\x12FArt resource name is required and must follow pattern 'users/*/arts/*'\x1a;this.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+$')R\x04name\"\x16\n" +
+	"\x14GetQueueStatsRequest\"4\n" +
+	"\n" +
+	"QueueStats\x12&\n" +
+	"\x06queues\x18\x01 \x03(\v2\x0e.pb.QueueDepthR\x06queues\"\xc9\x01\n" +
+	"\n" +
+	"QueueDepth\x123\n" +
+	"\bpriority\x18\x01 \x01(\x0e2\x17.pb.CompositionPriorityR\bpriority\x12\x14\n" +
+	"\x05queue\x18\x02 \x01(\tR\x05queue\x12%\n" +
+	"\x0eready_messages\x18\x03 \x01(\x05R\rreadyMessages\x12+\n" +
+	"\x11deferred_messages\x18\x04 \x01(\x05R\x10deferredMessages\x12\x1c\n" +
+	"\tconsumers\x18\x05 \x01(\x05R\tconsumers*\xa9\x01\n" +
 	"\tArtStatus\x12\x1a\n" +
 	"\x16ART_STATUS_UNSPECIFIED\x10\x00\x12\x1c\n" +
 	"\x18ART_STATUS_PENDING_IMAGE\x10\x01\x12\x19\n" +
 	"\x15ART_STATUS_PROCESSING\x10\x02\x12\x17\n" +
 	"\x13ART_STATUS_COMPLETE\x10\x03\x12\x15\n" +
 	"\x11ART_STATUS_FAILED\x10\x04\x12\x17\n" +
-	"\x13ART_STATUS_ARCHIVED\x10\x05*\xba\x01\n" +
+	"\x13ART_STATUS_ARCHIVED\x10\x05*\xdc\x01\n" +
 	"\x11CompositionStatus\x12\"\n" +
 	"\x1eCOMPOSITION_STATUS_UNSPECIFIED\x10\x00\x12\x1e\n" +
 	"\x1aCOMPOSITION_STATUS_PENDING\x10\x01\x12!\n" +
 	"\x1dCOMPOSITION_STATUS_PROCESSING\x10\x02\x12\x1f\n" +
 	"\x1bCOMPOSITION_STATUS_COMPLETE\x10\x03\x12\x1d\n" +
-	"\x19COMPOSITION_STATUS_FAILED\x10\x04B2Z0github.com/Damione1/thread-art-generator/core/pbb\x06proto3"
+	"\x19COMPOSITION_STATUS_FAILED\x10\x04\x12 \n" +
+	"\x1cCOMPOSITION_STATUS_CANCELLED\x10\x05*\x98\x01\n" +
+	"\fGcodeDialect\x12\x1d\n" +
+	"\x19GCODE_DIALECT_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15GCODE_DIALECT_FLUIDNC\x10\x01\x12\x16\n" +
+	"\x12GCODE_DIALECT_GRBL\x10\x02\x12\x18\n" +
+	"\x14GCODE_DIALECT_MARLIN\x10\x03\x12\x1c\n" +
+	"\x18GCODE_DIALECT_MARLIN_IJK\x10\x04*o\n" +
+	"\tInputType\x12\x1a\n" +
+	"\x16INPUT_TYPE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10INPUT_TYPE_PHOTO\x10\x01\x12\x13\n" +
+	"\x0fINPUT_TYPE_LOGO\x10\x02\x12\x1b\n" +
+	"\x17INPUT_TYPE_LINE_DRAWING\x10\x03*\xa2\x01\n" +
+	"\x13CompositionPriority\x12$\n" +
+	" COMPOSITION_PRIORITY_UNSPECIFIED\x10\x00\x12$\n" +
+	" COMPOSITION_PRIORITY_INTERACTIVE\x10\x01\x12\x1f\n" +
+	"\x1bCOMPOSITION_PRIORITY_RENDER\x10\x02\x12\x1e\n" +
+	"\x1aCOMPOSITION_PRIORITY_BATCH\x10\x03*\x9f\x01\n" +
+	"\rProgressStage\x12\x1e\n" +
+	"\x1aPROGRESS_STAGE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17PROGRESS_STAGE_DOWNLOAD\x10\x01\x12\x1b\n" +
+	"\x17PROGRESS_STAGE_GENERATE\x10\x02\x12\x19\n" +
+	"\x15PROGRESS_STAGE_RENDER\x10\x03\x12\x19\n" +
+	"\x15PROGRESS_STAGE_UPLOAD\x10\x04*\xc8\x01\n" +
+	"\x12CalibrationRoutine\x12#\n" +
+	"\x1fCALIBRATION_ROUTINE_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aCALIBRATION_ROUTINE_ROTARY\x10\x01\x12$\n" +
+	" CALIBRATION_ROUTINE_NEEDLE_DEPTH\x10\x02\x12 \n" +
+	"\x1cCALIBRATION_ROUTINE_BACKLASH\x10\x03\x12%\n" +
+	"!CALIBRATION_ROUTINE_SPINDLE_DRILL\x10\x04*\xd1\x01\n" +
+	"\x14ParameterSweepStatus\x12&\n" +
+	"\"PARAMETER_SWEEP_STATUS_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1ePARAMETER_SWEEP_STATUS_PENDING\x10\x01\x12%\n" +
+	"!PARAMETER_SWEEP_STATUS_PROCESSING\x10\x02\x12#\n" +
+	"\x1fPARAMETER_SWEEP_STATUS_COMPLETE\x10\x03\x12!\n" +
+	"\x1dPARAMETER_SWEEP_STATUS_FAILED\x10\x04*\xc3\x01\n" +
+	"\x0eSweepParameter\x12\x1f\n" +
+	"\x1bSWEEP_PARAMETER_UNSPECIFIED\x10\x00\x12%\n" +
+	"!SWEEP_PARAMETER_BRIGHTNESS_FACTOR\x10\x01\x12\"\n" +
+	"\x1eSWEEP_PARAMETER_IMAGE_CONTRAST\x10\x02\x12&\n" +
+	"\"SWEEP_PARAMETER_MINIMUM_DIFFERENCE\x10\x03\x12\x1d\n" +
+	"\x19SWEEP_PARAMETER_MAX_PATHS\x10\x04B2Z0github.com/Damione1/thread-art-generator/core/pbb\x06proto3"
 
 var (
 	file_art_proto_rawDescOnce sync.Once
@@ -1429,52 +3973,116 @@ func file_art_proto_rawDescGZIP() []byte {
 	return file_art_proto_rawDescData
 }
 
-var file_art_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_art_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_art_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
+var file_art_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
 var file_art_proto_goTypes = []any{
-	(ArtStatus)(0),                       // 0: pb.ArtStatus
-	(CompositionStatus)(0),               // 1: pb.CompositionStatus
-	(*Art)(nil),                          // 2: pb.Art
-	(*Composition)(nil),                  // 3: pb.Composition
-	(*CreateCompositionRequest)(nil),     // 4: pb.CreateCompositionRequest
-	(*GetCompositionRequest)(nil),        // 5: pb.GetCompositionRequest
-	(*UpdateCompositionRequest)(nil),     // 6: pb.UpdateCompositionRequest
-	(*ListCompositionsRequest)(nil),      // 7: pb.ListCompositionsRequest
-	(*ListCompositionsResponse)(nil),     // 8: pb.ListCompositionsResponse
-	(*DeleteCompositionRequest)(nil),     // 9: pb.DeleteCompositionRequest
-	(*CreateArtRequest)(nil),             // 10: pb.CreateArtRequest
-	(*UpdateArtRequest)(nil),             // 11: pb.UpdateArtRequest
-	(*GetArtRequest)(nil),                // 12: pb.GetArtRequest
-	(*ListArtsRequest)(nil),              // 13: pb.ListArtsRequest
-	(*ListArtsResponse)(nil),             // 14: pb.ListArtsResponse
-	(*DeleteArtRequest)(nil),             // 15: pb.DeleteArtRequest
-	(*GetArtUploadUrlRequest)(nil),       // 16: pb.GetArtUploadUrlRequest
-	(*GetArtUploadUrlResponse)(nil),      // 17: pb.GetArtUploadUrlResponse
-	(*ConfirmArtImageUploadRequest)(nil), // 18: pb.ConfirmArtImageUploadRequest
-	(*timestamppb.Timestamp)(nil),        // 19: google.protobuf.Timestamp
-	(*fieldmaskpb.FieldMask)(nil),        // 20: google.protobuf.FieldMask
+	(ArtStatus)(0),                                 // 0: pb.ArtStatus
+	(CompositionStatus)(0),                         // 1: pb.CompositionStatus
+	(GcodeDialect)(0),                              // 2: pb.GcodeDialect
+	(InputType)(0),                                 // 3: pb.InputType
+	(CompositionPriority)(0),                       // 4: pb.CompositionPriority
+	(ProgressStage)(0),                             // 5: pb.ProgressStage
+	(CalibrationRoutine)(0),                        // 6: pb.CalibrationRoutine
+	(ParameterSweepStatus)(0),                      // 7: pb.ParameterSweepStatus
+	(SweepParameter)(0),                            // 8: pb.SweepParameter
+	(*Art)(nil),                                    // 9: pb.Art
+	(*Composition)(nil),                            // 10: pb.Composition
+	(*NailRing)(nil),                               // 11: pb.NailRing
+	(*ThreadSegment)(nil),                          // 12: pb.ThreadSegment
+	(*CompositionProgress)(nil),                    // 13: pb.CompositionProgress
+	(*CompositionAnalytics)(nil),                   // 14: pb.CompositionAnalytics
+	(*HistogramBin)(nil),                           // 15: pb.HistogramBin
+	(*NailLoad)(nil),                               // 16: pb.NailLoad
+	(*CreateCompositionRequest)(nil),               // 17: pb.CreateCompositionRequest
+	(*GetCompositionRequest)(nil),                  // 18: pb.GetCompositionRequest
+	(*UpdateCompositionRequest)(nil),               // 19: pb.UpdateCompositionRequest
+	(*ListCompositionsRequest)(nil),                // 20: pb.ListCompositionsRequest
+	(*ListCompositionsResponse)(nil),               // 21: pb.ListCompositionsResponse
+	(*GetCompositionGcodeFromStepRequest)(nil),     // 22: pb.GetCompositionGcodeFromStepRequest
+	(*GetCompositionGcodeFromStepResponse)(nil),    // 23: pb.GetCompositionGcodeFromStepResponse
+	(*GetCompositionCalibrationGcodeRequest)(nil),  // 24: pb.GetCompositionCalibrationGcodeRequest
+	(*GetCompositionCalibrationGcodeResponse)(nil), // 25: pb.GetCompositionCalibrationGcodeResponse
+	(*CancelCompositionRequest)(nil),               // 26: pb.CancelCompositionRequest
+	(*DeleteCompositionRequest)(nil),               // 27: pb.DeleteCompositionRequest
+	(*ParameterRange)(nil),                         // 28: pb.ParameterRange
+	(*ParameterSweepResult)(nil),                   // 29: pb.ParameterSweepResult
+	(*ParameterSweep)(nil),                         // 30: pb.ParameterSweep
+	(*CreateParameterSweepRequest)(nil),            // 31: pb.CreateParameterSweepRequest
+	(*GetParameterSweepRequest)(nil),               // 32: pb.GetParameterSweepRequest
+	(*CalibrationBand)(nil),                        // 33: pb.CalibrationBand
+	(*ThreadProfile)(nil),                          // 34: pb.ThreadProfile
+	(*CreateThreadProfileRequest)(nil),             // 35: pb.CreateThreadProfileRequest
+	(*GetThreadProfileRequest)(nil),                // 36: pb.GetThreadProfileRequest
+	(*ListThreadProfilesRequest)(nil),              // 37: pb.ListThreadProfilesRequest
+	(*ListThreadProfilesResponse)(nil),             // 38: pb.ListThreadProfilesResponse
+	(*DeleteThreadProfileRequest)(nil),             // 39: pb.DeleteThreadProfileRequest
+	(*CreateArtRequest)(nil),                       // 40: pb.CreateArtRequest
+	(*UpdateArtRequest)(nil),                       // 41: pb.UpdateArtRequest
+	(*GetArtRequest)(nil),                          // 42: pb.GetArtRequest
+	(*ListArtsRequest)(nil),                        // 43: pb.ListArtsRequest
+	(*ListArtsResponse)(nil),                       // 44: pb.ListArtsResponse
+	(*DeleteArtRequest)(nil),                       // 45: pb.DeleteArtRequest
+	(*GetArtUploadUrlRequest)(nil),                 // 46: pb.GetArtUploadUrlRequest
+	(*GetArtUploadUrlResponse)(nil),                // 47: pb.GetArtUploadUrlResponse
+	(*ConfirmArtImageUploadRequest)(nil),           // 48: pb.ConfirmArtImageUploadRequest
+	(*GetQueueStatsRequest)(nil),                   // 49: pb.GetQueueStatsRequest
+	(*QueueStats)(nil),                             // 50: pb.QueueStats
+	(*QueueDepth)(nil),                             // 51: pb.QueueDepth
+	(*timestamppb.Timestamp)(nil),                  // 52: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),                  // 53: google.protobuf.FieldMask
 }
 var file_art_proto_depIdxs = []int32{
 	0,  // 0: pb.Art.status:type_name -> pb.ArtStatus
-	19, // 1: pb.Art.create_time:type_name -> google.protobuf.Timestamp
-	19, // 2: pb.Art.update_time:type_name -> google.protobuf.Timestamp
+	52, // 1: pb.Art.create_time:type_name -> google.protobuf.Timestamp
+	52, // 2: pb.Art.update_time:type_name -> google.protobuf.Timestamp
 	1,  // 3: pb.Composition.status:type_name -> pb.CompositionStatus
-	19, // 4: pb.Composition.create_time:type_name -> google.protobuf.Timestamp
-	19, // 5: pb.Composition.update_time:type_name -> google.protobuf.Timestamp
-	3,  // 6: pb.CreateCompositionRequest.composition:type_name -> pb.Composition
-	3,  // 7: pb.UpdateCompositionRequest.composition:type_name -> pb.Composition
-	20, // 8: pb.UpdateCompositionRequest.update_mask:type_name -> google.protobuf.FieldMask
-	3,  // 9: pb.ListCompositionsResponse.compositions:type_name -> pb.Composition
-	2,  // 10: pb.CreateArtRequest.art:type_name -> pb.Art
-	2,  // 11: pb.UpdateArtRequest.art:type_name -> pb.Art
-	20, // 12: pb.UpdateArtRequest.update_mask:type_name -> google.protobuf.FieldMask
-	2,  // 13: pb.ListArtsResponse.arts:type_name -> pb.Art
-	19, // 14: pb.GetArtUploadUrlResponse.expiration_time:type_name -> google.protobuf.Timestamp
-	15, // [15:15] is the sub-list for method output_type
-	15, // [15:15] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	52, // 4: pb.Composition.create_time:type_name -> google.protobuf.Timestamp
+	52, // 5: pb.Composition.update_time:type_name -> google.protobuf.Timestamp
+	2,  // 6: pb.Composition.gcode_dialect:type_name -> pb.GcodeDialect
+	12, // 7: pb.Composition.segments:type_name -> pb.ThreadSegment
+	11, // 8: pb.Composition.rings:type_name -> pb.NailRing
+	14, // 9: pb.Composition.analytics:type_name -> pb.CompositionAnalytics
+	3,  // 10: pb.Composition.input_type:type_name -> pb.InputType
+	13, // 11: pb.Composition.progress:type_name -> pb.CompositionProgress
+	4,  // 12: pb.Composition.priority:type_name -> pb.CompositionPriority
+	5,  // 13: pb.CompositionProgress.stage:type_name -> pb.ProgressStage
+	52, // 14: pb.CompositionProgress.estimated_completion_time:type_name -> google.protobuf.Timestamp
+	52, // 15: pb.CompositionProgress.update_time:type_name -> google.protobuf.Timestamp
+	15, // 16: pb.CompositionAnalytics.hits_histogram:type_name -> pb.HistogramBin
+	16, // 17: pb.CompositionAnalytics.heaviest_nails:type_name -> pb.NailLoad
+	15, // 18: pb.CompositionAnalytics.chord_lengths:type_name -> pb.HistogramBin
+	10, // 19: pb.CreateCompositionRequest.composition:type_name -> pb.Composition
+	10, // 20: pb.UpdateCompositionRequest.composition:type_name -> pb.Composition
+	53, // 21: pb.UpdateCompositionRequest.update_mask:type_name -> google.protobuf.FieldMask
+	10, // 22: pb.ListCompositionsResponse.compositions:type_name -> pb.Composition
+	6,  // 23: pb.GetCompositionCalibrationGcodeRequest.routine:type_name -> pb.CalibrationRoutine
+	6,  // 24: pb.GetCompositionCalibrationGcodeResponse.routine:type_name -> pb.CalibrationRoutine
+	8,  // 25: pb.ParameterRange.parameter:type_name -> pb.SweepParameter
+	10, // 26: pb.ParameterSweepResult.composition:type_name -> pb.Composition
+	7,  // 27: pb.ParameterSweep.status:type_name -> pb.ParameterSweepStatus
+	10, // 28: pb.ParameterSweep.base_composition:type_name -> pb.Composition
+	28, // 29: pb.ParameterSweep.ranges:type_name -> pb.ParameterRange
+	29, // 30: pb.ParameterSweep.results:type_name -> pb.ParameterSweepResult
+	52, // 31: pb.ParameterSweep.create_time:type_name -> google.protobuf.Timestamp
+	52, // 32: pb.ParameterSweep.update_time:type_name -> google.protobuf.Timestamp
+	30, // 33: pb.CreateParameterSweepRequest.parameter_sweep:type_name -> pb.ParameterSweep
+	33, // 34: pb.ThreadProfile.bands:type_name -> pb.CalibrationBand
+	52, // 35: pb.ThreadProfile.create_time:type_name -> google.protobuf.Timestamp
+	52, // 36: pb.ThreadProfile.update_time:type_name -> google.protobuf.Timestamp
+	34, // 37: pb.CreateThreadProfileRequest.thread_profile:type_name -> pb.ThreadProfile
+	34, // 38: pb.ListThreadProfilesResponse.thread_profiles:type_name -> pb.ThreadProfile
+	9,  // 39: pb.CreateArtRequest.art:type_name -> pb.Art
+	9,  // 40: pb.UpdateArtRequest.art:type_name -> pb.Art
+	53, // 41: pb.UpdateArtRequest.update_mask:type_name -> google.protobuf.FieldMask
+	9,  // 42: pb.ListArtsResponse.arts:type_name -> pb.Art
+	52, // 43: pb.GetArtUploadUrlResponse.expiration_time:type_name -> google.protobuf.Timestamp
+	51, // 44: pb.QueueStats.queues:type_name -> pb.QueueDepth
+	4,  // 45: pb.QueueDepth.priority:type_name -> pb.CompositionPriority
+	46, // [46:46] is the sub-list for method output_type
+	46, // [46:46] is the sub-list for method input_type
+	46, // [46:46] is the sub-list for extension type_name
+	46, // [46:46] is the sub-list for extension extendee
+	0,  // [0:46] is the sub-list for field type_name
 }
 
 func init() { file_art_proto_init() }
@@ -1487,8 +4095,8 @@ func file_art_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_art_proto_rawDesc), len(file_art_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   17,
+			NumEnums:      9,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,612 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: machine.proto
+
+package pb
+
+import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// State of a machine job
+type MachineJobState int32
+
+const (
+	// Default unspecified state
+	MachineJobState_MACHINE_JOB_STATE_UNSPECIFIED MachineJobState = 0
+	// No job is loaded
+	MachineJobState_MACHINE_JOB_STATE_IDLE MachineJobState = 1
+	// G-code is being streamed to the controller
+	MachineJobState_MACHINE_JOB_STATE_RUNNING MachineJobState = 2
+	// Streaming is paused by the operator or was interrupted by a restart
+	MachineJobState_MACHINE_JOB_STATE_PAUSED MachineJobState = 3
+	// The program reached an M0 pause and waits for the operator
+	MachineJobState_MACHINE_JOB_STATE_HOLD MachineJobState = 4
+	// Every line was acknowledged by the controller
+	MachineJobState_MACHINE_JOB_STATE_COMPLETE MachineJobState = 5
+	// The controller reported an error or the connection was lost
+	MachineJobState_MACHINE_JOB_STATE_FAILED MachineJobState = 6
+	// The operator cancelled the job
+	MachineJobState_MACHINE_JOB_STATE_CANCELLED MachineJobState = 7
+)
+
+// Enum value maps for MachineJobState.
+var (
+	MachineJobState_name = map[int32]string{
+		0: "MACHINE_JOB_STATE_UNSPECIFIED",
+		1: "MACHINE_JOB_STATE_IDLE",
+		2: "MACHINE_JOB_STATE_RUNNING",
+		3: "MACHINE_JOB_STATE_PAUSED",
+		4: "MACHINE_JOB_STATE_HOLD",
+		5: "MACHINE_JOB_STATE_COMPLETE",
+		6: "MACHINE_JOB_STATE_FAILED",
+		7: "MACHINE_JOB_STATE_CANCELLED",
+	}
+	MachineJobState_value = map[string]int32{
+		"MACHINE_JOB_STATE_UNSPECIFIED": 0,
+		"MACHINE_JOB_STATE_IDLE":        1,
+		"MACHINE_JOB_STATE_RUNNING":     2,
+		"MACHINE_JOB_STATE_PAUSED":      3,
+		"MACHINE_JOB_STATE_HOLD":        4,
+		"MACHINE_JOB_STATE_COMPLETE":    5,
+		"MACHINE_JOB_STATE_FAILED":      6,
+		"MACHINE_JOB_STATE_CANCELLED":   7,
+	}
+)
+
+func (x MachineJobState) Enum() *MachineJobState {
+	p := new(MachineJobState)
+	*p = x
+	return p
+}
+
+func (x MachineJobState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MachineJobState) Descriptor() protoreflect.EnumDescriptor {
+	return file_machine_proto_enumTypes[0].Descriptor()
+}
+
+func (MachineJobState) Type() protoreflect.EnumType {
+	return &file_machine_proto_enumTypes[0]
+}
+
+func (x MachineJobState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MachineJobState.Descriptor instead.
+func (MachineJobState) EnumDescriptor() ([]byte, []int) {
+	return file_machine_proto_rawDescGZIP(), []int{0}
+}
+
+type MachineJob struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The composition the G-code belongs to.
+	// For example: "users/123/arts/456/compositions/789"
+	Composition string `protobuf:"bytes,1,opt,name=composition,proto3" json:"composition,omitempty"`
+	// Current state of the job
+	State MachineJobState `protobuf:"varint,2,opt,name=state,proto3,enum=pb.MachineJobState" json:"state,omitempty"`
+	// Number of G-code lines acknowledged by the controller
+	CurrentLine int32 `protobuf:"varint,3,opt,name=current_line,json=currentLine,proto3" json:"current_line,omitempty"`
+	// Total number of G-code lines in the program
+	TotalLines int32 `protobuf:"varint,4,opt,name=total_lines,json=totalLines,proto3" json:"total_lines,omitempty"`
+	// Message of the M0 pause the job is holding on
+	HoldMessage string `protobuf:"bytes,5,opt,name=hold_message,json=holdMessage,proto3" json:"hold_message,omitempty"`
+	// Error message if the job failed
+	ErrorMessage string `protobuf:"bytes,6,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// Time the job was started
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// Time of the last progress or state change
+	UpdateTime *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	// The machine service restarted or the controller was reset on an error
+	// during the job, the machine position is lost and the job resumes from the
+	// start of its current step
+	Interrupted bool `protobuf:"varint,9,opt,name=interrupted,proto3" json:"interrupted,omitempty"`
+	// Path the current line belongs to, -1 before the first path
+	CurrentStep   int32 `protobuf:"varint,10,opt,name=current_step,json=currentStep,proto3" json:"current_step,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MachineJob) Reset() {
+	*x = MachineJob{}
+	mi := &file_machine_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MachineJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MachineJob) ProtoMessage() {}
+
+func (x *MachineJob) ProtoReflect() protoreflect.Message {
+	mi := &file_machine_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MachineJob.ProtoReflect.Descriptor instead.
+func (*MachineJob) Descriptor() ([]byte, []int) {
+	return file_machine_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MachineJob) GetComposition() string {
+	if x != nil {
+		return x.Composition
+	}
+	return ""
+}
+
+func (x *MachineJob) GetState() MachineJobState {
+	if x != nil {
+		return x.State
+	}
+	return MachineJobState_MACHINE_JOB_STATE_UNSPECIFIED
+}
+
+func (x *MachineJob) GetCurrentLine() int32 {
+	if x != nil {
+		return x.CurrentLine
+	}
+	return 0
+}
+
+func (x *MachineJob) GetTotalLines() int32 {
+	if x != nil {
+		return x.TotalLines
+	}
+	return 0
+}
+
+func (x *MachineJob) GetHoldMessage() string {
+	if x != nil {
+		return x.HoldMessage
+	}
+	return ""
+}
+
+func (x *MachineJob) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *MachineJob) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *MachineJob) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+func (x *MachineJob) GetInterrupted() bool {
+	if x != nil {
+		return x.Interrupted
+	}
+	return false
+}
+
+func (x *MachineJob) GetCurrentStep() int32 {
+	if x != nil {
+		return x.CurrentStep
+	}
+	return 0
+}
+
+type StartMachineJobRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The composition the G-code belongs to.
+	// For example: "users/123/arts/456/compositions/789"
+	Composition string `protobuf:"bytes,1,opt,name=composition,proto3" json:"composition,omitempty"`
+	// URL of the G-code to stream, e.g. the composition gcode_url
+	GcodeUrl      string `protobuf:"bytes,2,opt,name=gcode_url,json=gcodeUrl,proto3" json:"gcode_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartMachineJobRequest) Reset() {
+	*x = StartMachineJobRequest{}
+	mi := &file_machine_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartMachineJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartMachineJobRequest) ProtoMessage() {}
+
+func (x *StartMachineJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_machine_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartMachineJobRequest.ProtoReflect.Descriptor instead.
+func (*StartMachineJobRequest) Descriptor() ([]byte, []int) {
+	return file_machine_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StartMachineJobRequest) GetComposition() string {
+	if x != nil {
+		return x.Composition
+	}
+	return ""
+}
+
+func (x *StartMachineJobRequest) GetGcodeUrl() string {
+	if x != nil {
+		return x.GcodeUrl
+	}
+	return ""
+}
+
+type GetMachineJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMachineJobRequest) Reset() {
+	*x = GetMachineJobRequest{}
+	mi := &file_machine_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMachineJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMachineJobRequest) ProtoMessage() {}
+
+func (x *GetMachineJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_machine_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMachineJobRequest.ProtoReflect.Descriptor instead.
+func (*GetMachineJobRequest) Descriptor() ([]byte, []int) {
+	return file_machine_proto_rawDescGZIP(), []int{2}
+}
+
+type PauseMachineJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseMachineJobRequest) Reset() {
+	*x = PauseMachineJobRequest{}
+	mi := &file_machine_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseMachineJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseMachineJobRequest) ProtoMessage() {}
+
+func (x *PauseMachineJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_machine_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseMachineJobRequest.ProtoReflect.Descriptor instead.
+func (*PauseMachineJobRequest) Descriptor() ([]byte, []int) {
+	return file_machine_proto_rawDescGZIP(), []int{3}
+}
+
+type ResumeMachineJobRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Program resuming an interrupted job at its current step, as returned by
+	// GetCompositionGcodeFromStep. It re-homes the machine before the step.
+	// Required to resume an interrupted job past its first step.
+	Gcode         string `protobuf:"bytes,1,opt,name=gcode,proto3" json:"gcode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeMachineJobRequest) Reset() {
+	*x = ResumeMachineJobRequest{}
+	mi := &file_machine_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeMachineJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeMachineJobRequest) ProtoMessage() {}
+
+func (x *ResumeMachineJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_machine_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeMachineJobRequest.ProtoReflect.Descriptor instead.
+func (*ResumeMachineJobRequest) Descriptor() ([]byte, []int) {
+	return file_machine_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ResumeMachineJobRequest) GetGcode() string {
+	if x != nil {
+		return x.Gcode
+	}
+	return ""
+}
+
+type AcknowledgeMachineHoldRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcknowledgeMachineHoldRequest) Reset() {
+	*x = AcknowledgeMachineHoldRequest{}
+	mi := &file_machine_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcknowledgeMachineHoldRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcknowledgeMachineHoldRequest) ProtoMessage() {}
+
+func (x *AcknowledgeMachineHoldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_machine_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcknowledgeMachineHoldRequest.ProtoReflect.Descriptor instead.
+func (*AcknowledgeMachineHoldRequest) Descriptor() ([]byte, []int) {
+	return file_machine_proto_rawDescGZIP(), []int{5}
+}
+
+type CancelMachineJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelMachineJobRequest) Reset() {
+	*x = CancelMachineJobRequest{}
+	mi := &file_machine_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelMachineJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelMachineJobRequest) ProtoMessage() {}
+
+func (x *CancelMachineJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_machine_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelMachineJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelMachineJobRequest) Descriptor() ([]byte, []int) {
+	return file_machine_proto_rawDescGZIP(), []int{6}
+}
+
+var File_machine_proto protoreflect.FileDescriptor
+
+const file_machine_proto_rawDesc = "" +
+	"\n" +
+	"\rmachine.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x19google/api/resource.proto\x1a\x1bbuf/validate/validate.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\xf4\x03\n" +
+	"\n" +
+	"MachineJob\x12E\n" +
+	"\vcomposition\x18\x01 \x01(\tB#\xe0A\x03\xfaA\x1d\n" +
+	"\x1bart.example.com/CompositionR\vcomposition\x12.\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x13.pb.MachineJobStateB\x03\xe0A\x03R\x05state\x12&\n" +
+	"\fcurrent_line\x18\x03 \x01(\x05B\x03\xe0A\x03R\vcurrentLine\x12$\n" +
+	"\vtotal_lines\x18\x04 \x01(\x05B\x03\xe0A\x03R\n" +
+	"totalLines\x12&\n" +
+	"\fhold_message\x18\x05 \x01(\tB\x03\xe0A\x03R\vholdMessage\x12(\n" +
+	"\rerror_message\x18\x06 \x01(\tB\x03\xe0A\x03R\ferrorMessage\x12>\n" +
+	"\n" +
+	"start_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\tstartTime\x12@\n" +
+	"\vupdate_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
+	"updateTime\x12%\n" +
+	"\vinterrupted\x18\t \x01(\bB\x03\xe0A\x03R\vinterrupted\x12&\n" +
+	"\fcurrent_step\x18\n" +
+	" \x01(\x05B\x03\xe0A\x03R\vcurrentStep\"\xc5\x03\n" +
+	"\x16StartMachineJobRequest\x12\xa3\x02\n" +
+	"\vcomposition\x18\x01 \x01(\tB\x80\x02\xe0A\x02\xfaA\x1d\n" +
+	"\x1bart.example.com/Composition\xbaH\xd9\x01\xba\x01\xd5\x01\n" +
+	"$start_machine_job.composition.format\x12]Composition resource name is required and must follow pattern 'users/*/arts/*/compositions/*'\x1aNthis.size() > 0 && this.matches('^users/[^/]+/arts/[^/]+/compositions/[^/]+$')R\vcomposition\x12\x84\x01\n" +
+	"\tgcode_url\x18\x02 \x01(\tBg\xe0A\x02\xbaHa\xba\x01^\n" +
+	"\x1fstart_machine_job.gcode_url.uri\x12\x1dGCode URL must be a valid URI\x1a\x1cthis.matches('^https?://.+')R\bgcodeUrl\"\x16\n" +
+	"\x14GetMachineJobRequest\"\x18\n" +
+	"\x16PauseMachineJobRequest\"4\n" +
+	"\x17ResumeMachineJobRequest\x12\x19\n" +
+	"\x05gcode\x18\x01 \x01(\tB\x03\xe0A\x01R\x05gcode\"\x1f\n" +
+	"\x1dAcknowledgeMachineHoldRequest\"\x19\n" +
+	"\x17CancelMachineJobRequest*\x88\x02\n" +
+	"\x0fMachineJobState\x12!\n" +
+	"\x1dMACHINE_JOB_STATE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16MACHINE_JOB_STATE_IDLE\x10\x01\x12\x1d\n" +
+	"\x19MACHINE_JOB_STATE_RUNNING\x10\x02\x12\x1c\n" +
+	"\x18MACHINE_JOB_STATE_PAUSED\x10\x03\x12\x1a\n" +
+	"\x16MACHINE_JOB_STATE_HOLD\x10\x04\x12\x1e\n" +
+	"\x1aMACHINE_JOB_STATE_COMPLETE\x10\x05\x12\x1c\n" +
+	"\x18MACHINE_JOB_STATE_FAILED\x10\x06\x12\x1f\n" +
+	"\x1bMACHINE_JOB_STATE_CANCELLED\x10\a2\xd5\t\n" +
+	"\x0eMachineService\x12\xd1\x01\n" +
+	"\x0fStartMachineJob\x12\x1a.pb.StartMachineJobRequest\x1a\x0e.pb.MachineJob\"\x91\x01\x92An\n" +
+	"\aMachine\x12\x13Start a machine job\x1aNDownload the G-code of a composition and start streaming it to the controller.\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/machine/job:start\x12\xb0\x01\n" +
+	"\rGetMachineJob\x12\x18.pb.GetMachineJobRequest\x1a\x0e.pb.MachineJob\"u\x92A[\n" +
+	"\aMachine\x12\x13Get the machine job\x1a;Retrieve the state and progress of the current machine job.\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/machine/job\x12\xd1\x01\n" +
+	"\x0fPauseMachineJob\x12\x1a.pb.PauseMachineJobRequest\x1a\x0e.pb.MachineJob\"\x91\x01\x92An\n" +
+	"\aMachine\x12\x15Pause the machine job\x1aLStop streaming new lines once the controller has executed the buffered ones.\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/machine/job:pause\x12\xc9\x01\n" +
+	"\x10ResumeMachineJob\x12\x1b.pb.ResumeMachineJobRequest\x1a\x0e.pb.MachineJob\"\x87\x01\x92Ac\n" +
+	"\aMachine\x12\x16Resume the machine job\x1a@Continue streaming a paused job from its last acknowledged line.\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/machine/job:resume\x12\xea\x01\n" +
+	"\x16AcknowledgeMachineHold\x12!.pb.AcknowledgeMachineHoldRequest\x1a\x0e.pb.MachineJob\"\x9c\x01\x92As\n" +
+	"\aMachine\x12\x1aAcknowledge a machine hold\x1aLConfirm an M0 pause, e.g. once the thread is attached, and continue the job.\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/machine/job:acknowledge\x12\xae\x01\n" +
+	"\x10CancelMachineJob\x12\x1b.pb.CancelMachineJobRequest\x1a\x0e.pb.MachineJob\"m\x92AI\n" +
+	"\aMachine\x12\x16Cancel the machine job\x1a&Stop the job and reset the controller.\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/machine/job:cancelB\xff\x02\x92A\xc9\x02\x12\x82\x01\n" +
+	"\x16Thread art Machine API\"a\n" +
+	"\x0eDamien Goehrig\x12(github.com/Damione1/thread-art-generator\x1a%thread-art-generator@damiengoehrig.ca2\x050.0.1Zn\n" +
+	"l\n" +
+	"\x06Bearer\x12b\b\x02\x12MInternal API key of the machine service, with the format: 'Bearer {api_key}'.\x1a\rAuthorization \x02b\f\n" +
+	"\n" +
+	"\n" +
+	"\x06Bearer\x12\x00jD\n" +
+	"\aMachine\x129Endpoints to stream G-code jobs to the string art machineZ0github.com/Damione1/thread-art-generator/core/pbb\x06proto3"
+
+var (
+	file_machine_proto_rawDescOnce sync.Once
+	file_machine_proto_rawDescData []byte
+)
+
+func file_machine_proto_rawDescGZIP() []byte {
+	file_machine_proto_rawDescOnce.Do(func() {
+		file_machine_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_machine_proto_rawDesc), len(file_machine_proto_rawDesc)))
+	})
+	return file_machine_proto_rawDescData
+}
+
+var file_machine_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_machine_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_machine_proto_goTypes = []any{
+	(MachineJobState)(0),                  // 0: pb.MachineJobState
+	(*MachineJob)(nil),                    // 1: pb.MachineJob
+	(*StartMachineJobRequest)(nil),        // 2: pb.StartMachineJobRequest
+	(*GetMachineJobRequest)(nil),          // 3: pb.GetMachineJobRequest
+	(*PauseMachineJobRequest)(nil),        // 4: pb.PauseMachineJobRequest
+	(*ResumeMachineJobRequest)(nil),       // 5: pb.ResumeMachineJobRequest
+	(*AcknowledgeMachineHoldRequest)(nil), // 6: pb.AcknowledgeMachineHoldRequest
+	(*CancelMachineJobRequest)(nil),       // 7: pb.CancelMachineJobRequest
+	(*timestamppb.Timestamp)(nil),         // 8: google.protobuf.Timestamp
+}
+var file_machine_proto_depIdxs = []int32{
+	0, // 0: pb.MachineJob.state:type_name -> pb.MachineJobState
+	8, // 1: pb.MachineJob.start_time:type_name -> google.protobuf.Timestamp
+	8, // 2: pb.MachineJob.update_time:type_name -> google.protobuf.Timestamp
+	2, // 3: pb.MachineService.StartMachineJob:input_type -> pb.StartMachineJobRequest
+	3, // 4: pb.MachineService.GetMachineJob:input_type -> pb.GetMachineJobRequest
+	4, // 5: pb.MachineService.PauseMachineJob:input_type -> pb.PauseMachineJobRequest
+	5, // 6: pb.MachineService.ResumeMachineJob:input_type -> pb.ResumeMachineJobRequest
+	6, // 7: pb.MachineService.AcknowledgeMachineHold:input_type -> pb.AcknowledgeMachineHoldRequest
+	7, // 8: pb.MachineService.CancelMachineJob:input_type -> pb.CancelMachineJobRequest
+	1, // 9: pb.MachineService.StartMachineJob:output_type -> pb.MachineJob
+	1, // 10: pb.MachineService.GetMachineJob:output_type -> pb.MachineJob
+	1, // 11: pb.MachineService.PauseMachineJob:output_type -> pb.MachineJob
+	1, // 12: pb.MachineService.ResumeMachineJob:output_type -> pb.MachineJob
+	1, // 13: pb.MachineService.AcknowledgeMachineHold:output_type -> pb.MachineJob
+	1, // 14: pb.MachineService.CancelMachineJob:output_type -> pb.MachineJob
+	9, // [9:15] is the sub-list for method output_type
+	3, // [3:9] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_machine_proto_init() }
+func file_machine_proto_init() {
+	if File_machine_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_machine_proto_rawDesc), len(file_machine_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_machine_proto_goTypes,
+		DependencyIndexes: file_machine_proto_depIdxs,
+		EnumInfos:         file_machine_proto_enumTypes,
+		MessageInfos:      file_machine_proto_msgTypes,
+	}.Build()
+	File_machine_proto = out.File
+	file_machine_proto_goTypes = nil
+	file_machine_proto_depIdxs = nil
+}
@@ -85,9 +85,39 @@ const (
 	// ArtGeneratorServiceListCompositionsProcedure is the fully-qualified name of the
 	// ArtGeneratorService's ListCompositions RPC.
 	ArtGeneratorServiceListCompositionsProcedure = "/pb.ArtGeneratorService/ListCompositions"
+	// ArtGeneratorServiceGetCompositionGcodeFromStepProcedure is the fully-qualified name of the
+	// ArtGeneratorService's GetCompositionGcodeFromStep RPC.
+	ArtGeneratorServiceGetCompositionGcodeFromStepProcedure = "/pb.ArtGeneratorService/GetCompositionGcodeFromStep"
+	// ArtGeneratorServiceGetCompositionCalibrationGcodeProcedure is the fully-qualified name of the
+	// ArtGeneratorService's GetCompositionCalibrationGcode RPC.
+	ArtGeneratorServiceGetCompositionCalibrationGcodeProcedure = "/pb.ArtGeneratorService/GetCompositionCalibrationGcode"
+	// ArtGeneratorServiceCancelCompositionProcedure is the fully-qualified name of the
+	// ArtGeneratorService's CancelComposition RPC.
+	ArtGeneratorServiceCancelCompositionProcedure = "/pb.ArtGeneratorService/CancelComposition"
 	// ArtGeneratorServiceDeleteCompositionProcedure is the fully-qualified name of the
 	// ArtGeneratorService's DeleteComposition RPC.
 	ArtGeneratorServiceDeleteCompositionProcedure = "/pb.ArtGeneratorService/DeleteComposition"
+	// ArtGeneratorServiceCreateParameterSweepProcedure is the fully-qualified name of the
+	// ArtGeneratorService's CreateParameterSweep RPC.
+	ArtGeneratorServiceCreateParameterSweepProcedure = "/pb.ArtGeneratorService/CreateParameterSweep"
+	// ArtGeneratorServiceGetParameterSweepProcedure is the fully-qualified name of the
+	// ArtGeneratorService's GetParameterSweep RPC.
+	ArtGeneratorServiceGetParameterSweepProcedure = "/pb.ArtGeneratorService/GetParameterSweep"
+	// ArtGeneratorServiceCreateThreadProfileProcedure is the fully-qualified name of the
+	// ArtGeneratorService's CreateThreadProfile RPC.
+	ArtGeneratorServiceCreateThreadProfileProcedure = "/pb.ArtGeneratorService/CreateThreadProfile"
+	// ArtGeneratorServiceGetThreadProfileProcedure is the fully-qualified name of the
+	// ArtGeneratorService's GetThreadProfile RPC.
+	ArtGeneratorServiceGetThreadProfileProcedure = "/pb.ArtGeneratorService/GetThreadProfile"
+	// ArtGeneratorServiceListThreadProfilesProcedure is the fully-qualified name of the
+	// ArtGeneratorService's ListThreadProfiles RPC.
+	ArtGeneratorServiceListThreadProfilesProcedure = "/pb.ArtGeneratorService/ListThreadProfiles"
+	// ArtGeneratorServiceDeleteThreadProfileProcedure is the fully-qualified name of the
+	// ArtGeneratorService's DeleteThreadProfile RPC.
+	ArtGeneratorServiceDeleteThreadProfileProcedure = "/pb.ArtGeneratorService/DeleteThreadProfile"
+	// ArtGeneratorServiceGetQueueStatsProcedure is the fully-qualified name of the
+	// ArtGeneratorService's GetQueueStats RPC.
+	ArtGeneratorServiceGetQueueStatsProcedure = "/pb.ArtGeneratorService/GetQueueStats"
 )
 
 // ArtGeneratorServiceClient is a client for the pb.ArtGeneratorService service.
@@ -110,7 +140,19 @@ type ArtGeneratorServiceClient interface {
 	GetComposition(context.Context, *connect.Request[pb.GetCompositionRequest]) (*connect.Response[pb.Composition], error)
 	UpdateComposition(context.Context, *connect.Request[pb.UpdateCompositionRequest]) (*connect.Response[pb.Composition], error)
 	ListCompositions(context.Context, *connect.Request[pb.ListCompositionsRequest]) (*connect.Response[pb.ListCompositionsResponse], error)
+	GetCompositionGcodeFromStep(context.Context, *connect.Request[pb.GetCompositionGcodeFromStepRequest]) (*connect.Response[pb.GetCompositionGcodeFromStepResponse], error)
+	GetCompositionCalibrationGcode(context.Context, *connect.Request[pb.GetCompositionCalibrationGcodeRequest]) (*connect.Response[pb.GetCompositionCalibrationGcodeResponse], error)
+	CancelComposition(context.Context, *connect.Request[pb.CancelCompositionRequest]) (*connect.Response[pb.Composition], error)
 	DeleteComposition(context.Context, *connect.Request[pb.DeleteCompositionRequest]) (*connect.Response[emptypb.Empty], error)
+	// Parameter sweep RPCs
+	CreateParameterSweep(context.Context, *connect.Request[pb.CreateParameterSweepRequest]) (*connect.Response[pb.ParameterSweep], error)
+	GetParameterSweep(context.Context, *connect.Request[pb.GetParameterSweepRequest]) (*connect.Response[pb.ParameterSweep], error)
+	// Thread profile RPCs
+	CreateThreadProfile(context.Context, *connect.Request[pb.CreateThreadProfileRequest]) (*connect.Response[pb.ThreadProfile], error)
+	GetThreadProfile(context.Context, *connect.Request[pb.GetThreadProfileRequest]) (*connect.Response[pb.ThreadProfile], error)
+	ListThreadProfiles(context.Context, *connect.Request[pb.ListThreadProfilesRequest]) (*connect.Response[pb.ListThreadProfilesResponse], error)
+	DeleteThreadProfile(context.Context, *connect.Request[pb.DeleteThreadProfileRequest]) (*connect.Response[emptypb.Empty], error)
+	GetQueueStats(context.Context, *connect.Request[pb.GetQueueStatsRequest]) (*connect.Response[pb.QueueStats], error)
 }
 
 // NewArtGeneratorServiceClient constructs a client for the pb.ArtGeneratorService service. By
@@ -226,35 +268,105 @@ func NewArtGeneratorServiceClient(httpClient connect.HTTPClient, baseURL string,
 			connect.WithSchema(artGeneratorServiceMethods.ByName("ListCompositions")),
 			connect.WithClientOptions(opts...),
 		),
+		getCompositionGcodeFromStep: connect.NewClient[pb.GetCompositionGcodeFromStepRequest, pb.GetCompositionGcodeFromStepResponse](
+			httpClient,
+			baseURL+ArtGeneratorServiceGetCompositionGcodeFromStepProcedure,
+			connect.WithSchema(artGeneratorServiceMethods.ByName("GetCompositionGcodeFromStep")),
+			connect.WithClientOptions(opts...),
+		),
+		getCompositionCalibrationGcode: connect.NewClient[pb.GetCompositionCalibrationGcodeRequest, pb.GetCompositionCalibrationGcodeResponse](
+			httpClient,
+			baseURL+ArtGeneratorServiceGetCompositionCalibrationGcodeProcedure,
+			connect.WithSchema(artGeneratorServiceMethods.ByName("GetCompositionCalibrationGcode")),
+			connect.WithClientOptions(opts...),
+		),
+		cancelComposition: connect.NewClient[pb.CancelCompositionRequest, pb.Composition](
+			httpClient,
+			baseURL+ArtGeneratorServiceCancelCompositionProcedure,
+			connect.WithSchema(artGeneratorServiceMethods.ByName("CancelComposition")),
+			connect.WithClientOptions(opts...),
+		),
 		deleteComposition: connect.NewClient[pb.DeleteCompositionRequest, emptypb.Empty](
 			httpClient,
 			baseURL+ArtGeneratorServiceDeleteCompositionProcedure,
 			connect.WithSchema(artGeneratorServiceMethods.ByName("DeleteComposition")),
 			connect.WithClientOptions(opts...),
 		),
+		createParameterSweep: connect.NewClient[pb.CreateParameterSweepRequest, pb.ParameterSweep](
+			httpClient,
+			baseURL+ArtGeneratorServiceCreateParameterSweepProcedure,
+			connect.WithSchema(artGeneratorServiceMethods.ByName("CreateParameterSweep")),
+			connect.WithClientOptions(opts...),
+		),
+		getParameterSweep: connect.NewClient[pb.GetParameterSweepRequest, pb.ParameterSweep](
+			httpClient,
+			baseURL+ArtGeneratorServiceGetParameterSweepProcedure,
+			connect.WithSchema(artGeneratorServiceMethods.ByName("GetParameterSweep")),
+			connect.WithClientOptions(opts...),
+		),
+		createThreadProfile: connect.NewClient[pb.CreateThreadProfileRequest, pb.ThreadProfile](
+			httpClient,
+			baseURL+ArtGeneratorServiceCreateThreadProfileProcedure,
+			connect.WithSchema(artGeneratorServiceMethods.ByName("CreateThreadProfile")),
+			connect.WithClientOptions(opts...),
+		),
+		getThreadProfile: connect.NewClient[pb.GetThreadProfileRequest, pb.ThreadProfile](
+			httpClient,
+			baseURL+ArtGeneratorServiceGetThreadProfileProcedure,
+			connect.WithSchema(artGeneratorServiceMethods.ByName("GetThreadProfile")),
+			connect.WithClientOptions(opts...),
+		),
+		listThreadProfiles: connect.NewClient[pb.ListThreadProfilesRequest, pb.ListThreadProfilesResponse](
+			httpClient,
+			baseURL+ArtGeneratorServiceListThreadProfilesProcedure,
+			connect.WithSchema(artGeneratorServiceMethods.ByName("ListThreadProfiles")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteThreadProfile: connect.NewClient[pb.DeleteThreadProfileRequest, emptypb.Empty](
+			httpClient,
+			baseURL+ArtGeneratorServiceDeleteThreadProfileProcedure,
+			connect.WithSchema(artGeneratorServiceMethods.ByName("DeleteThreadProfile")),
+			connect.WithClientOptions(opts...),
+		),
+		getQueueStats: connect.NewClient[pb.GetQueueStatsRequest, pb.QueueStats](
+			httpClient,
+			baseURL+ArtGeneratorServiceGetQueueStatsProcedure,
+			connect.WithSchema(artGeneratorServiceMethods.ByName("GetQueueStats")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // artGeneratorServiceClient implements ArtGeneratorServiceClient.
 type artGeneratorServiceClient struct {
-	updateUser            *connect.Client[pb.UpdateUserRequest, pb.User]
-	getUser               *connect.Client[pb.GetUserRequest, pb.User]
-	listUsers             *connect.Client[pb.ListUsersRequest, pb.ListUsersResponse]
-	deleteUser            *connect.Client[pb.DeleteUserRequest, emptypb.Empty]
-	getCurrentUser        *connect.Client[pb.GetCurrentUserRequest, pb.User]
-	syncUserFromFirebase  *connect.Client[pb.SyncUserFromFirebaseRequest, pb.User]
-	createArt             *connect.Client[pb.CreateArtRequest, pb.Art]
-	getArt                *connect.Client[pb.GetArtRequest, pb.Art]
-	updateArt             *connect.Client[pb.UpdateArtRequest, pb.Art]
-	listArts              *connect.Client[pb.ListArtsRequest, pb.ListArtsResponse]
-	deleteArt             *connect.Client[pb.DeleteArtRequest, emptypb.Empty]
-	getArtUploadUrl       *connect.Client[pb.GetArtUploadUrlRequest, pb.GetArtUploadUrlResponse]
-	confirmArtImageUpload *connect.Client[pb.ConfirmArtImageUploadRequest, pb.Art]
-	createComposition     *connect.Client[pb.CreateCompositionRequest, pb.Composition]
-	getComposition        *connect.Client[pb.GetCompositionRequest, pb.Composition]
-	updateComposition     *connect.Client[pb.UpdateCompositionRequest, pb.Composition]
-	listCompositions      *connect.Client[pb.ListCompositionsRequest, pb.ListCompositionsResponse]
-	deleteComposition     *connect.Client[pb.DeleteCompositionRequest, emptypb.Empty]
+	updateUser                     *connect.Client[pb.UpdateUserRequest, pb.User]
+	getUser                        *connect.Client[pb.GetUserRequest, pb.User]
+	listUsers                      *connect.Client[pb.ListUsersRequest, pb.ListUsersResponse]
+	deleteUser                     *connect.Client[pb.DeleteUserRequest, emptypb.Empty]
+	getCurrentUser                 *connect.Client[pb.GetCurrentUserRequest, pb.User]
+	syncUserFromFirebase           *connect.Client[pb.SyncUserFromFirebaseRequest, pb.User]
+	createArt                      *connect.Client[pb.CreateArtRequest, pb.Art]
+	getArt                         *connect.Client[pb.GetArtRequest, pb.Art]
+	updateArt                      *connect.Client[pb.UpdateArtRequest, pb.Art]
+	listArts                       *connect.Client[pb.ListArtsRequest, pb.ListArtsResponse]
+	deleteArt                      *connect.Client[pb.DeleteArtRequest, emptypb.Empty]
+	getArtUploadUrl                *connect.Client[pb.GetArtUploadUrlRequest, pb.GetArtUploadUrlResponse]
+	confirmArtImageUpload          *connect.Client[pb.ConfirmArtImageUploadRequest, pb.Art]
+	createComposition              *connect.Client[pb.CreateCompositionRequest, pb.Composition]
+	getComposition                 *connect.Client[pb.GetCompositionRequest, pb.Composition]
+	updateComposition              *connect.Client[pb.UpdateCompositionRequest, pb.Composition]
+	listCompositions               *connect.Client[pb.ListCompositionsRequest, pb.ListCompositionsResponse]
+	getCompositionGcodeFromStep    *connect.Client[pb.GetCompositionGcodeFromStepRequest, pb.GetCompositionGcodeFromStepResponse]
+	getCompositionCalibrationGcode *connect.Client[pb.GetCompositionCalibrationGcodeRequest, pb.GetCompositionCalibrationGcodeResponse]
+	cancelComposition              *connect.Client[pb.CancelCompositionRequest, pb.Composition]
+	deleteComposition              *connect.Client[pb.DeleteCompositionRequest, emptypb.Empty]
+	createParameterSweep           *connect.Client[pb.CreateParameterSweepRequest, pb.ParameterSweep]
+	getParameterSweep              *connect.Client[pb.GetParameterSweepRequest, pb.ParameterSweep]
+	createThreadProfile            *connect.Client[pb.CreateThreadProfileRequest, pb.ThreadProfile]
+	getThreadProfile               *connect.Client[pb.GetThreadProfileRequest, pb.ThreadProfile]
+	listThreadProfiles             *connect.Client[pb.ListThreadProfilesRequest, pb.ListThreadProfilesResponse]
+	deleteThreadProfile            *connect.Client[pb.DeleteThreadProfileRequest, emptypb.Empty]
+	getQueueStats                  *connect.Client[pb.GetQueueStatsRequest, pb.QueueStats]
 }
 
 // UpdateUser calls pb.ArtGeneratorService.UpdateUser.
@@ -342,11 +454,61 @@ func (c *artGeneratorServiceClient) ListCompositions(ctx context.Context, req *c
 	return c.listCompositions.CallUnary(ctx, req)
 }
 
+// GetCompositionGcodeFromStep calls pb.ArtGeneratorService.GetCompositionGcodeFromStep.
+func (c *artGeneratorServiceClient) GetCompositionGcodeFromStep(ctx context.Context, req *connect.Request[pb.GetCompositionGcodeFromStepRequest]) (*connect.Response[pb.GetCompositionGcodeFromStepResponse], error) {
+	return c.getCompositionGcodeFromStep.CallUnary(ctx, req)
+}
+
+// GetCompositionCalibrationGcode calls pb.ArtGeneratorService.GetCompositionCalibrationGcode.
+func (c *artGeneratorServiceClient) GetCompositionCalibrationGcode(ctx context.Context, req *connect.Request[pb.GetCompositionCalibrationGcodeRequest]) (*connect.Response[pb.GetCompositionCalibrationGcodeResponse], error) {
+	return c.getCompositionCalibrationGcode.CallUnary(ctx, req)
+}
+
+// CancelComposition calls pb.ArtGeneratorService.CancelComposition.
+func (c *artGeneratorServiceClient) CancelComposition(ctx context.Context, req *connect.Request[pb.CancelCompositionRequest]) (*connect.Response[pb.Composition], error) {
+	return c.cancelComposition.CallUnary(ctx, req)
+}
+
 // DeleteComposition calls pb.ArtGeneratorService.DeleteComposition.
 func (c *artGeneratorServiceClient) DeleteComposition(ctx context.Context, req *connect.Request[pb.DeleteCompositionRequest]) (*connect.Response[emptypb.Empty], error) {
 	return c.deleteComposition.CallUnary(ctx, req)
 }
 
+// CreateParameterSweep calls pb.ArtGeneratorService.CreateParameterSweep.
+func (c *artGeneratorServiceClient) CreateParameterSweep(ctx context.Context, req *connect.Request[pb.CreateParameterSweepRequest]) (*connect.Response[pb.ParameterSweep], error) {
+	return c.createParameterSweep.CallUnary(ctx, req)
+}
+
+// GetParameterSweep calls pb.ArtGeneratorService.GetParameterSweep.
+func (c *artGeneratorServiceClient) GetParameterSweep(ctx context.Context, req *connect.Request[pb.GetParameterSweepRequest]) (*connect.Response[pb.ParameterSweep], error) {
+	return c.getParameterSweep.CallUnary(ctx, req)
+}
+
+// CreateThreadProfile calls pb.ArtGeneratorService.CreateThreadProfile.
+func (c *artGeneratorServiceClient) CreateThreadProfile(ctx context.Context, req *connect.Request[pb.CreateThreadProfileRequest]) (*connect.Response[pb.ThreadProfile], error) {
+	return c.createThreadProfile.CallUnary(ctx, req)
+}
+
+// GetThreadProfile calls pb.ArtGeneratorService.GetThreadProfile.
+func (c *artGeneratorServiceClient) GetThreadProfile(ctx context.Context, req *connect.Request[pb.GetThreadProfileRequest]) (*connect.Response[pb.ThreadProfile], error) {
+	return c.getThreadProfile.CallUnary(ctx, req)
+}
+
+// ListThreadProfiles calls pb.ArtGeneratorService.ListThreadProfiles.
+func (c *artGeneratorServiceClient) ListThreadProfiles(ctx context.Context, req *connect.Request[pb.ListThreadProfilesRequest]) (*connect.Response[pb.ListThreadProfilesResponse], error) {
+	return c.listThreadProfiles.CallUnary(ctx, req)
+}
+
+// DeleteThreadProfile calls pb.ArtGeneratorService.DeleteThreadProfile.
+func (c *artGeneratorServiceClient) DeleteThreadProfile(ctx context.Context, req *connect.Request[pb.DeleteThreadProfileRequest]) (*connect.Response[emptypb.Empty], error) {
+	return c.deleteThreadProfile.CallUnary(ctx, req)
+}
+
+// GetQueueStats calls pb.ArtGeneratorService.GetQueueStats.
+func (c *artGeneratorServiceClient) GetQueueStats(ctx context.Context, req *connect.Request[pb.GetQueueStatsRequest]) (*connect.Response[pb.QueueStats], error) {
+	return c.getQueueStats.CallUnary(ctx, req)
+}
+
 // ArtGeneratorServiceHandler is an implementation of the pb.ArtGeneratorService service.
 type ArtGeneratorServiceHandler interface {
 	UpdateUser(context.Context, *connect.Request[pb.UpdateUserRequest]) (*connect.Response[pb.User], error)
@@ -367,7 +529,19 @@ type ArtGeneratorServiceHandler interface {
 	GetComposition(context.Context, *connect.Request[pb.GetCompositionRequest]) (*connect.Response[pb.Composition], error)
 	UpdateComposition(context.Context, *connect.Request[pb.UpdateCompositionRequest]) (*connect.Response[pb.Composition], error)
 	ListCompositions(context.Context, *connect.Request[pb.ListCompositionsRequest]) (*connect.Response[pb.ListCompositionsResponse], error)
+	GetCompositionGcodeFromStep(context.Context, *connect.Request[pb.GetCompositionGcodeFromStepRequest]) (*connect.Response[pb.GetCompositionGcodeFromStepResponse], error)
+	GetCompositionCalibrationGcode(context.Context, *connect.Request[pb.GetCompositionCalibrationGcodeRequest]) (*connect.Response[pb.GetCompositionCalibrationGcodeResponse], error)
+	CancelComposition(context.Context, *connect.Request[pb.CancelCompositionRequest]) (*connect.Response[pb.Composition], error)
 	DeleteComposition(context.Context, *connect.Request[pb.DeleteCompositionRequest]) (*connect.Response[emptypb.Empty], error)
+	// Parameter sweep RPCs
+	CreateParameterSweep(context.Context, *connect.Request[pb.CreateParameterSweepRequest]) (*connect.Response[pb.ParameterSweep], error)
+	GetParameterSweep(context.Context, *connect.Request[pb.GetParameterSweepRequest]) (*connect.Response[pb.ParameterSweep], error)
+	// Thread profile RPCs
+	CreateThreadProfile(context.Context, *connect.Request[pb.CreateThreadProfileRequest]) (*connect.Response[pb.ThreadProfile], error)
+	GetThreadProfile(context.Context, *connect.Request[pb.GetThreadProfileRequest]) (*connect.Response[pb.ThreadProfile], error)
+	ListThreadProfiles(context.Context, *connect.Request[pb.ListThreadProfilesRequest]) (*connect.Response[pb.ListThreadProfilesResponse], error)
+	DeleteThreadProfile(context.Context, *connect.Request[pb.DeleteThreadProfileRequest]) (*connect.Response[emptypb.Empty], error)
+	GetQueueStats(context.Context, *connect.Request[pb.GetQueueStatsRequest]) (*connect.Response[pb.QueueStats], error)
 }
 
 // NewArtGeneratorServiceHandler builds an HTTP handler from the service implementation. It returns
@@ -479,12 +653,72 @@ func NewArtGeneratorServiceHandler(svc ArtGeneratorServiceHandler, opts ...conne
 		connect.WithSchema(artGeneratorServiceMethods.ByName("ListCompositions")),
 		connect.WithHandlerOptions(opts...),
 	)
+	artGeneratorServiceGetCompositionGcodeFromStepHandler := connect.NewUnaryHandler(
+		ArtGeneratorServiceGetCompositionGcodeFromStepProcedure,
+		svc.GetCompositionGcodeFromStep,
+		connect.WithSchema(artGeneratorServiceMethods.ByName("GetCompositionGcodeFromStep")),
+		connect.WithHandlerOptions(opts...),
+	)
+	artGeneratorServiceGetCompositionCalibrationGcodeHandler := connect.NewUnaryHandler(
+		ArtGeneratorServiceGetCompositionCalibrationGcodeProcedure,
+		svc.GetCompositionCalibrationGcode,
+		connect.WithSchema(artGeneratorServiceMethods.ByName("GetCompositionCalibrationGcode")),
+		connect.WithHandlerOptions(opts...),
+	)
+	artGeneratorServiceCancelCompositionHandler := connect.NewUnaryHandler(
+		ArtGeneratorServiceCancelCompositionProcedure,
+		svc.CancelComposition,
+		connect.WithSchema(artGeneratorServiceMethods.ByName("CancelComposition")),
+		connect.WithHandlerOptions(opts...),
+	)
 	artGeneratorServiceDeleteCompositionHandler := connect.NewUnaryHandler(
 		ArtGeneratorServiceDeleteCompositionProcedure,
 		svc.DeleteComposition,
 		connect.WithSchema(artGeneratorServiceMethods.ByName("DeleteComposition")),
 		connect.WithHandlerOptions(opts...),
 	)
+	artGeneratorServiceCreateParameterSweepHandler := connect.NewUnaryHandler(
+		ArtGeneratorServiceCreateParameterSweepProcedure,
+		svc.CreateParameterSweep,
+		connect.WithSchema(artGeneratorServiceMethods.ByName("CreateParameterSweep")),
+		connect.WithHandlerOptions(opts...),
+	)
+	artGeneratorServiceGetParameterSweepHandler := connect.NewUnaryHandler(
+		ArtGeneratorServiceGetParameterSweepProcedure,
+		svc.GetParameterSweep,
+		connect.WithSchema(artGeneratorServiceMethods.ByName("GetParameterSweep")),
+		connect.WithHandlerOptions(opts...),
+	)
+	artGeneratorServiceCreateThreadProfileHandler := connect.NewUnaryHandler(
+		ArtGeneratorServiceCreateThreadProfileProcedure,
+		svc.CreateThreadProfile,
+		connect.WithSchema(artGeneratorServiceMethods.ByName("CreateThreadProfile")),
+		connect.WithHandlerOptions(opts...),
+	)
+	artGeneratorServiceGetThreadProfileHandler := connect.NewUnaryHandler(
+		ArtGeneratorServiceGetThreadProfileProcedure,
+		svc.GetThreadProfile,
+		connect.WithSchema(artGeneratorServiceMethods.ByName("GetThreadProfile")),
+		connect.WithHandlerOptions(opts...),
+	)
+	artGeneratorServiceListThreadProfilesHandler := connect.NewUnaryHandler(
+		ArtGeneratorServiceListThreadProfilesProcedure,
+		svc.ListThreadProfiles,
+		connect.WithSchema(artGeneratorServiceMethods.ByName("ListThreadProfiles")),
+		connect.WithHandlerOptions(opts...),
+	)
+	artGeneratorServiceDeleteThreadProfileHandler := connect.NewUnaryHandler(
+		ArtGeneratorServiceDeleteThreadProfileProcedure,
+		svc.DeleteThreadProfile,
+		connect.WithSchema(artGeneratorServiceMethods.ByName("DeleteThreadProfile")),
+		connect.WithHandlerOptions(opts...),
+	)
+	artGeneratorServiceGetQueueStatsHandler := connect.NewUnaryHandler(
+		ArtGeneratorServiceGetQueueStatsProcedure,
+		svc.GetQueueStats,
+		connect.WithSchema(artGeneratorServiceMethods.ByName("GetQueueStats")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/pb.ArtGeneratorService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case ArtGeneratorServiceUpdateUserProcedure:
@@ -521,8 +755,28 @@ func NewArtGeneratorServiceHandler(svc ArtGeneratorServiceHandler, opts ...conne
 			artGeneratorServiceUpdateCompositionHandler.ServeHTTP(w, r)
 		case ArtGeneratorServiceListCompositionsProcedure:
 			artGeneratorServiceListCompositionsHandler.ServeHTTP(w, r)
+		case ArtGeneratorServiceGetCompositionGcodeFromStepProcedure:
+			artGeneratorServiceGetCompositionGcodeFromStepHandler.ServeHTTP(w, r)
+		case ArtGeneratorServiceGetCompositionCalibrationGcodeProcedure:
+			artGeneratorServiceGetCompositionCalibrationGcodeHandler.ServeHTTP(w, r)
+		case ArtGeneratorServiceCancelCompositionProcedure:
+			artGeneratorServiceCancelCompositionHandler.ServeHTTP(w, r)
 		case ArtGeneratorServiceDeleteCompositionProcedure:
 			artGeneratorServiceDeleteCompositionHandler.ServeHTTP(w, r)
+		case ArtGeneratorServiceCreateParameterSweepProcedure:
+			artGeneratorServiceCreateParameterSweepHandler.ServeHTTP(w, r)
+		case ArtGeneratorServiceGetParameterSweepProcedure:
+			artGeneratorServiceGetParameterSweepHandler.ServeHTTP(w, r)
+		case ArtGeneratorServiceCreateThreadProfileProcedure:
+			artGeneratorServiceCreateThreadProfileHandler.ServeHTTP(w, r)
+		case ArtGeneratorServiceGetThreadProfileProcedure:
+			artGeneratorServiceGetThreadProfileHandler.ServeHTTP(w, r)
+		case ArtGeneratorServiceListThreadProfilesProcedure:
+			artGeneratorServiceListThreadProfilesHandler.ServeHTTP(w, r)
+		case ArtGeneratorServiceDeleteThreadProfileProcedure:
+			artGeneratorServiceDeleteThreadProfileHandler.ServeHTTP(w, r)
+		case ArtGeneratorServiceGetQueueStatsProcedure:
+			artGeneratorServiceGetQueueStatsHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -600,6 +854,46 @@ func (UnimplementedArtGeneratorServiceHandler) ListCompositions(context.Context,
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pb.ArtGeneratorService.ListCompositions is not implemented"))
 }
 
+func (UnimplementedArtGeneratorServiceHandler) GetCompositionGcodeFromStep(context.Context, *connect.Request[pb.GetCompositionGcodeFromStepRequest]) (*connect.Response[pb.GetCompositionGcodeFromStepResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pb.ArtGeneratorService.GetCompositionGcodeFromStep is not implemented"))
+}
+
+func (UnimplementedArtGeneratorServiceHandler) GetCompositionCalibrationGcode(context.Context, *connect.Request[pb.GetCompositionCalibrationGcodeRequest]) (*connect.Response[pb.GetCompositionCalibrationGcodeResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pb.ArtGeneratorService.GetCompositionCalibrationGcode is not implemented"))
+}
+
+func (UnimplementedArtGeneratorServiceHandler) CancelComposition(context.Context, *connect.Request[pb.CancelCompositionRequest]) (*connect.Response[pb.Composition], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pb.ArtGeneratorService.CancelComposition is not implemented"))
+}
+
 func (UnimplementedArtGeneratorServiceHandler) DeleteComposition(context.Context, *connect.Request[pb.DeleteCompositionRequest]) (*connect.Response[emptypb.Empty], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pb.ArtGeneratorService.DeleteComposition is not implemented"))
 }
+
+func (UnimplementedArtGeneratorServiceHandler) CreateParameterSweep(context.Context, *connect.Request[pb.CreateParameterSweepRequest]) (*connect.Response[pb.ParameterSweep], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pb.ArtGeneratorService.CreateParameterSweep is not implemented"))
+}
+
+func (UnimplementedArtGeneratorServiceHandler) GetParameterSweep(context.Context, *connect.Request[pb.GetParameterSweepRequest]) (*connect.Response[pb.ParameterSweep], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pb.ArtGeneratorService.GetParameterSweep is not implemented"))
+}
+
+func (UnimplementedArtGeneratorServiceHandler) CreateThreadProfile(context.Context, *connect.Request[pb.CreateThreadProfileRequest]) (*connect.Response[pb.ThreadProfile], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pb.ArtGeneratorService.CreateThreadProfile is not implemented"))
+}
+
+func (UnimplementedArtGeneratorServiceHandler) GetThreadProfile(context.Context, *connect.Request[pb.GetThreadProfileRequest]) (*connect.Response[pb.ThreadProfile], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pb.ArtGeneratorService.GetThreadProfile is not implemented"))
+}
+
+func (UnimplementedArtGeneratorServiceHandler) ListThreadProfiles(context.Context, *connect.Request[pb.ListThreadProfilesRequest]) (*connect.Response[pb.ListThreadProfilesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pb.ArtGeneratorService.ListThreadProfiles is not implemented"))
+}
+
+func (UnimplementedArtGeneratorServiceHandler) DeleteThreadProfile(context.Context, *connect.Request[pb.DeleteThreadProfileRequest]) (*connect.Response[emptypb.Empty], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pb.ArtGeneratorService.DeleteThreadProfile is not implemented"))
+}
+
+func (UnimplementedArtGeneratorServiceHandler) GetQueueStats(context.Context, *connect.Request[pb.GetQueueStatsRequest]) (*connect.Response[pb.QueueStats], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("pb.ArtGeneratorService.GetQueueStats is not implemented"))
+}
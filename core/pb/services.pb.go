@@ -29,7 +29,7 @@ var File_services_proto protoreflect.FileDescriptor
 const file_services_proto_rawDesc = "" +
 	"\n" +
 	"\x0eservices.proto\x12\x02pb\x1a\n" +
-	"user.proto\x1a\tart.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x17google/api/client.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/descriptor.proto\x1a.protoc-gen-openapiv2/options/annotations.proto2\x83\x1c\n" +
+	"user.proto\x1a\tart.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x17google/api/client.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/descriptor.proto\x1a.protoc-gen-openapiv2/options/annotations.proto2\xa92\n" +
 	"\x13ArtGeneratorService\x12\xa5\x01\n" +
 	"\n" +
 	"UpdateUser\x12\x15.pb.UpdateUserRequest\x1a\b.pb.User\"v\x92AP\n" +
@@ -67,9 +67,29 @@ const file_services_proto_rawDesc = "" +
 	"\x11UpdateComposition\x12\x1c.pb.UpdateCompositionRequest\x1a\x0f.pb.Composition\"\xba\x01\x92AT\n" +
 	"\fCompositions\x12\x14Update a composition\x1a.Modify the settings of a specific composition.\xdaA\x17composition,update_mask\x82\xd3\xe4\x93\x02C:\vcomposition24/v1/{composition.name=users/*/arts/*/compositions/*}\x12\xea\x01\n" +
 	"\x10ListCompositions\x12\x1b.pb.ListCompositionsRequest\x1a\x1c.pb.ListCompositionsResponse\"\x9a\x01\x92A^\n" +
-	"\fCompositions\x12\x15List all compositions\x1a7Retrieve a list of all compositions for a specific art.\xdaA\x06parent\x82\xd3\xe4\x93\x02*\x12(/v1/{parent=users/*/arts/*}/compositions\x12\xda\x01\n" +
+	"\fCompositions\x12\x15List all compositions\x1a7Retrieve a list of all compositions for a specific art.\xdaA\x06parent\x82\xd3\xe4\x93\x02*\x12(/v1/{parent=users/*/arts/*}/compositions\x12\xe8\x02\n" +
+	"\x1bGetCompositionGcodeFromStep\x12&.pb.GetCompositionGcodeFromStepRequest\x1a'.pb.GetCompositionGcodeFromStepResponse\"\xf7\x01\x92A\xa9\x01\n" +
+	"\fCompositions\x12\"Get composition G-code from a step\x1auGenerate the stringing G-code of a completed composition resuming at a given path index, e.g. after the thread broke.\xdaA\tname,step\x82\xd3\xe4\x93\x028\x126/v1/{name=users/*/arts/*/compositions/*}:gcodeFromStep\x12\x89\x03\n" +
+	"\x1eGetCompositionCalibrationGcode\x12).pb.GetCompositionCalibrationGcodeRequest\x1a*.pb.GetCompositionCalibrationGcodeResponse\"\x8f\x02\x92A\xbb\x01\n" +
+	"\fCompositions\x12\"Get composition calibration G-code\x1a\x86\x01Generate a calibration program for the nail count and G-code dialect of a composition, to run on a new machine before the first piece.\xdaA\fname,routine\x82\xd3\xe4\x93\x02;\x129/v1/{name=users/*/arts/*/compositions/*}:calibrationGcode\x12\x9e\x02\n" +
+	"\x11CancelComposition\x12\x1c.pb.CancelCompositionRequest\x1a\x0f.pb.Composition\"\xd9\x01\x92A\x9b\x01\n" +
+	"\fCompositions\x12\x14Cancel a composition\x1auStop a pending or processing composition. The worker aborts the generation and removes the files it already uploaded.\x82\xd3\xe4\x93\x024:\x01*\"//v1/{name=users/*/arts/*/compositions/*}:cancel\x12\xda\x01\n" +
 	"\x11DeleteComposition\x12\x1c.pb.DeleteCompositionRequest\x1a\x16.google.protobuf.Empty\"\x8e\x01\x92AT\n" +
-	"\fCompositions\x12\x14Delete a composition\x1a.Remove a specific composition from the system.\xdaA\x04name\x82\xd3\xe4\x93\x02**(/v1/{name=users/*/arts/*/compositions/*}B\xcc\x04\x92A\x96\x04\x12\x84\x01\n" +
+	"\fCompositions\x12\x14Delete a composition\x1a.Remove a specific composition from the system.\xdaA\x04name\x82\xd3\xe4\x93\x02**(/v1/{name=users/*/arts/*/compositions/*}\x12\xc8\x02\n" +
+	"\x14CreateParameterSweep\x12\x1f.pb.CreateParameterSweepRequest\x1a\x12.pb.ParameterSweep\"\xfa\x01\x92A\xa2\x01\n" +
+	"\x10Parameter Sweeps\x12\x18Create a parameter sweep\x1atGenerate a composition for every combination of the swept parameters and rank them by similarity with the art image.\xdaA\x16parent,parameter_sweep\x82\xd3\xe4\x93\x025:\x0fparameter_sweep\"\"/v1/{parent=users/*/arts/*}/sweeps\x12\xfe\x01\n" +
+	"\x11GetParameterSweep\x12\x1c.pb.GetParameterSweepRequest\x1a\x12.pb.ParameterSweep\"\xb6\x01\x92A\x81\x01\n" +
+	"\x10Parameter Sweeps\x12\x1bGet parameter sweep results\x1aPRetrieve the progress of a parameter sweep and its compositions ranked by score.\xdaA\x04name\x82\xd3\xe4\x93\x02$\x12\"/v1/{name=users/*/arts/*/sweeps/*}\x12\xaa\x02\n" +
+	"\x13CreateThreadProfile\x12\x1e.pb.CreateThreadProfileRequest\x1a\x11.pb.ThreadProfile\"\xdf\x01\x92A\x88\x01\n" +
+	"\x0fThread Profiles\x12\x17Create a thread profile\x1a\\Fit the thread darkness model on the photo of a test piece strung with known line densities.\xdaA\x15parent,thread_profile\x82\xd3\xe4\x93\x025:\x0ethread_profile\"#/v1/{parent=users/*}/threadProfiles\x12\xe3\x01\n" +
+	"\x10GetThreadProfile\x12\x1b.pb.GetThreadProfileRequest\x1a\x11.pb.ThreadProfile\"\x9e\x01\x92Ai\n" +
+	"\x0fThread Profiles\x12\x14Get a thread profile\x1a@Retrieve a thread profile with its measured and predicted bands.\xdaA\x04name\x82\xd3\xe4\x93\x02%\x12#/v1/{name=users/*/threadProfiles/*}\x12\xdd\x01\n" +
+	"\x12ListThreadProfiles\x12\x1d.pb.ListThreadProfilesRequest\x1a\x1e.pb.ListThreadProfilesResponse\"\x87\x01\x92AP\n" +
+	"\x0fThread Profiles\x12\x14List thread profiles\x1a'Retrieve the thread profiles of a user.\xdaA\x06parent\x82\xd3\xe4\x93\x02%\x12#/v1/{parent=users/*}/threadProfiles\x12\x88\x02\n" +
+	"\x13DeleteThreadProfile\x12\x1e.pb.DeleteThreadProfileRequest\x1a\x16.google.protobuf.Empty\"\xb8\x01\x92A\x82\x01\n" +
+	"\x0fThread Profiles\x12\x17Delete a thread profile\x1aVRemove a thread profile, the compositions using it fall back to the default darkening.\xdaA\x04name\x82\xd3\xe4\x93\x02%*#/v1/{name=users/*/threadProfiles/*}\x12\xc1\x01\n" +
+	"\rGetQueueStats\x12\x18.pb.GetQueueStatsRequest\x1a\x0e.pb.QueueStats\"\x85\x01\x92Aj\n" +
+	"\x05Admin\x12\x0fGet queue stats\x1aPReport the composition jobs waiting in each priority queue. Administrators only.\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/admin/queuesB\xb2\x06\x92A\xfc\x05\x12\x84\x01\n" +
 	"\x18Thread art Generator API\"a\n" +
 	"\x0eDamien Goehrig\x12(github.com/Damione1/thread-art-generator\x1a%thread-art-generator@damiengoehrig.ca2\x050.0.1Z\xa0\x01\n" +
 	"\x9d\x01\n" +
@@ -80,36 +100,55 @@ const file_services_proto_rawDesc = "" +
 	"\x0eAuthentication\x12\x1cEndpoints for authenticationj&\n" +
 	"\x05Users\x12\x1dEndpoints for user managementj$\n" +
 	"\x04Arts\x12\x1cEndpoints for art managementj5\n" +
-	"\fCompositions\x12%Endpoints for thread art compositionsj'\n" +
-	"\x05Media\x12\x1eEndpoints for media managementZ0github.com/Damione1/thread-art-generator/core/pbb\x06proto3"
+	"\fCompositions\x12%Endpoints for thread art compositionsjO\n" +
+	"\x10Parameter Sweeps\x12;Endpoints for parameter sweeps ranking composition settingsjV\n" +
+	"\x0fThread Profiles\x12CEndpoints for thread darkness profiles fitted on calibration photosj'\n" +
+	"\x05Media\x12\x1eEndpoints for media managementj;\n" +
+	"\x05Admin\x122Endpoints for administrators operating the serviceZ0github.com/Damione1/thread-art-generator/core/pbb\x06proto3"
 
 var file_services_proto_goTypes = []any{
-	(*UpdateUserRequest)(nil),            // 0: pb.UpdateUserRequest
-	(*GetUserRequest)(nil),               // 1: pb.GetUserRequest
-	(*ListUsersRequest)(nil),             // 2: pb.ListUsersRequest
-	(*DeleteUserRequest)(nil),            // 3: pb.DeleteUserRequest
-	(*GetCurrentUserRequest)(nil),        // 4: pb.GetCurrentUserRequest
-	(*SyncUserFromFirebaseRequest)(nil),  // 5: pb.SyncUserFromFirebaseRequest
-	(*CreateArtRequest)(nil),             // 6: pb.CreateArtRequest
-	(*GetArtRequest)(nil),                // 7: pb.GetArtRequest
-	(*UpdateArtRequest)(nil),             // 8: pb.UpdateArtRequest
-	(*ListArtsRequest)(nil),              // 9: pb.ListArtsRequest
-	(*DeleteArtRequest)(nil),             // 10: pb.DeleteArtRequest
-	(*GetArtUploadUrlRequest)(nil),       // 11: pb.GetArtUploadUrlRequest
-	(*ConfirmArtImageUploadRequest)(nil), // 12: pb.ConfirmArtImageUploadRequest
-	(*CreateCompositionRequest)(nil),     // 13: pb.CreateCompositionRequest
-	(*GetCompositionRequest)(nil),        // 14: pb.GetCompositionRequest
-	(*UpdateCompositionRequest)(nil),     // 15: pb.UpdateCompositionRequest
-	(*ListCompositionsRequest)(nil),      // 16: pb.ListCompositionsRequest
-	(*DeleteCompositionRequest)(nil),     // 17: pb.DeleteCompositionRequest
-	(*User)(nil),                         // 18: pb.User
-	(*ListUsersResponse)(nil),            // 19: pb.ListUsersResponse
-	(*emptypb.Empty)(nil),                // 20: google.protobuf.Empty
-	(*Art)(nil),                          // 21: pb.Art
-	(*ListArtsResponse)(nil),             // 22: pb.ListArtsResponse
-	(*GetArtUploadUrlResponse)(nil),      // 23: pb.GetArtUploadUrlResponse
-	(*Composition)(nil),                  // 24: pb.Composition
-	(*ListCompositionsResponse)(nil),     // 25: pb.ListCompositionsResponse
+	(*UpdateUserRequest)(nil),                      // 0: pb.UpdateUserRequest
+	(*GetUserRequest)(nil),                         // 1: pb.GetUserRequest
+	(*ListUsersRequest)(nil),                       // 2: pb.ListUsersRequest
+	(*DeleteUserRequest)(nil),                      // 3: pb.DeleteUserRequest
+	(*GetCurrentUserRequest)(nil),                  // 4: pb.GetCurrentUserRequest
+	(*SyncUserFromFirebaseRequest)(nil),            // 5: pb.SyncUserFromFirebaseRequest
+	(*CreateArtRequest)(nil),                       // 6: pb.CreateArtRequest
+	(*GetArtRequest)(nil),                          // 7: pb.GetArtRequest
+	(*UpdateArtRequest)(nil),                       // 8: pb.UpdateArtRequest
+	(*ListArtsRequest)(nil),                        // 9: pb.ListArtsRequest
+	(*DeleteArtRequest)(nil),                       // 10: pb.DeleteArtRequest
+	(*GetArtUploadUrlRequest)(nil),                 // 11: pb.GetArtUploadUrlRequest
+	(*ConfirmArtImageUploadRequest)(nil),           // 12: pb.ConfirmArtImageUploadRequest
+	(*CreateCompositionRequest)(nil),               // 13: pb.CreateCompositionRequest
+	(*GetCompositionRequest)(nil),                  // 14: pb.GetCompositionRequest
+	(*UpdateCompositionRequest)(nil),               // 15: pb.UpdateCompositionRequest
+	(*ListCompositionsRequest)(nil),                // 16: pb.ListCompositionsRequest
+	(*GetCompositionGcodeFromStepRequest)(nil),     // 17: pb.GetCompositionGcodeFromStepRequest
+	(*GetCompositionCalibrationGcodeRequest)(nil),  // 18: pb.GetCompositionCalibrationGcodeRequest
+	(*CancelCompositionRequest)(nil),               // 19: pb.CancelCompositionRequest
+	(*DeleteCompositionRequest)(nil),               // 20: pb.DeleteCompositionRequest
+	(*CreateParameterSweepRequest)(nil),            // 21: pb.CreateParameterSweepRequest
+	(*GetParameterSweepRequest)(nil),               // 22: pb.GetParameterSweepRequest
+	(*CreateThreadProfileRequest)(nil),             // 23: pb.CreateThreadProfileRequest
+	(*GetThreadProfileRequest)(nil),                // 24: pb.GetThreadProfileRequest
+	(*ListThreadProfilesRequest)(nil),              // 25: pb.ListThreadProfilesRequest
+	(*DeleteThreadProfileRequest)(nil),             // 26: pb.DeleteThreadProfileRequest
+	(*GetQueueStatsRequest)(nil),                   // 27: pb.GetQueueStatsRequest
+	(*User)(nil),                                   // 28: pb.User
+	(*ListUsersResponse)(nil),                      // 29: pb.ListUsersResponse
+	(*emptypb.Empty)(nil),                          // 30: google.protobuf.Empty
+	(*Art)(nil),                                    // 31: pb.Art
+	(*ListArtsResponse)(nil),                       // 32: pb.ListArtsResponse
+	(*GetArtUploadUrlResponse)(nil),                // 33: pb.GetArtUploadUrlResponse
+	(*Composition)(nil),                            // 34: pb.Composition
+	(*ListCompositionsResponse)(nil),               // 35: pb.ListCompositionsResponse
+	(*GetCompositionGcodeFromStepResponse)(nil),    // 36: pb.GetCompositionGcodeFromStepResponse
+	(*GetCompositionCalibrationGcodeResponse)(nil), // 37: pb.GetCompositionCalibrationGcodeResponse
+	(*ParameterSweep)(nil),                         // 38: pb.ParameterSweep
+	(*ThreadProfile)(nil),                          // 39: pb.ThreadProfile
+	(*ListThreadProfilesResponse)(nil),             // 40: pb.ListThreadProfilesResponse
+	(*QueueStats)(nil),                             // 41: pb.QueueStats
 }
 var file_services_proto_depIdxs = []int32{
 	0,  // 0: pb.ArtGeneratorService.UpdateUser:input_type -> pb.UpdateUserRequest
@@ -129,27 +168,47 @@ var file_services_proto_depIdxs = []int32{
 	14, // 14: pb.ArtGeneratorService.GetComposition:input_type -> pb.GetCompositionRequest
 	15, // 15: pb.ArtGeneratorService.UpdateComposition:input_type -> pb.UpdateCompositionRequest
 	16, // 16: pb.ArtGeneratorService.ListCompositions:input_type -> pb.ListCompositionsRequest
-	17, // 17: pb.ArtGeneratorService.DeleteComposition:input_type -> pb.DeleteCompositionRequest
-	18, // 18: pb.ArtGeneratorService.UpdateUser:output_type -> pb.User
-	18, // 19: pb.ArtGeneratorService.GetUser:output_type -> pb.User
-	19, // 20: pb.ArtGeneratorService.ListUsers:output_type -> pb.ListUsersResponse
-	20, // 21: pb.ArtGeneratorService.DeleteUser:output_type -> google.protobuf.Empty
-	18, // 22: pb.ArtGeneratorService.GetCurrentUser:output_type -> pb.User
-	18, // 23: pb.ArtGeneratorService.SyncUserFromFirebase:output_type -> pb.User
-	21, // 24: pb.ArtGeneratorService.CreateArt:output_type -> pb.Art
-	21, // 25: pb.ArtGeneratorService.GetArt:output_type -> pb.Art
-	21, // 26: pb.ArtGeneratorService.UpdateArt:output_type -> pb.Art
-	22, // 27: pb.ArtGeneratorService.ListArts:output_type -> pb.ListArtsResponse
-	20, // 28: pb.ArtGeneratorService.DeleteArt:output_type -> google.protobuf.Empty
-	23, // 29: pb.ArtGeneratorService.GetArtUploadUrl:output_type -> pb.GetArtUploadUrlResponse
-	21, // 30: pb.ArtGeneratorService.ConfirmArtImageUpload:output_type -> pb.Art
-	24, // 31: pb.ArtGeneratorService.CreateComposition:output_type -> pb.Composition
-	24, // 32: pb.ArtGeneratorService.GetComposition:output_type -> pb.Composition
-	24, // 33: pb.ArtGeneratorService.UpdateComposition:output_type -> pb.Composition
-	25, // 34: pb.ArtGeneratorService.ListCompositions:output_type -> pb.ListCompositionsResponse
-	20, // 35: pb.ArtGeneratorService.DeleteComposition:output_type -> google.protobuf.Empty
-	18, // [18:36] is the sub-list for method output_type
-	0,  // [0:18] is the sub-list for method input_type
+	17, // 17: pb.ArtGeneratorService.GetCompositionGcodeFromStep:input_type -> pb.GetCompositionGcodeFromStepRequest
+	18, // 18: pb.ArtGeneratorService.GetCompositionCalibrationGcode:input_type -> pb.GetCompositionCalibrationGcodeRequest
+	19, // 19: pb.ArtGeneratorService.CancelComposition:input_type -> pb.CancelCompositionRequest
+	20, // 20: pb.ArtGeneratorService.DeleteComposition:input_type -> pb.DeleteCompositionRequest
+	21, // 21: pb.ArtGeneratorService.CreateParameterSweep:input_type -> pb.CreateParameterSweepRequest
+	22, // 22: pb.ArtGeneratorService.GetParameterSweep:input_type -> pb.GetParameterSweepRequest
+	23, // 23: pb.ArtGeneratorService.CreateThreadProfile:input_type -> pb.CreateThreadProfileRequest
+	24, // 24: pb.ArtGeneratorService.GetThreadProfile:input_type -> pb.GetThreadProfileRequest
+	25, // 25: pb.ArtGeneratorService.ListThreadProfiles:input_type -> pb.ListThreadProfilesRequest
+	26, // 26: pb.ArtGeneratorService.DeleteThreadProfile:input_type -> pb.DeleteThreadProfileRequest
+	27, // 27: pb.ArtGeneratorService.GetQueueStats:input_type -> pb.GetQueueStatsRequest
+	28, // 28: pb.ArtGeneratorService.UpdateUser:output_type -> pb.User
+	28, // 29: pb.ArtGeneratorService.GetUser:output_type -> pb.User
+	29, // 30: pb.ArtGeneratorService.ListUsers:output_type -> pb.ListUsersResponse
+	30, // 31: pb.ArtGeneratorService.DeleteUser:output_type -> google.protobuf.Empty
+	28, // 32: pb.ArtGeneratorService.GetCurrentUser:output_type -> pb.User
+	28, // 33: pb.ArtGeneratorService.SyncUserFromFirebase:output_type -> pb.User
+	31, // 34: pb.ArtGeneratorService.CreateArt:output_type -> pb.Art
+	31, // 35: pb.ArtGeneratorService.GetArt:output_type -> pb.Art
+	31, // 36: pb.ArtGeneratorService.UpdateArt:output_type -> pb.Art
+	32, // 37: pb.ArtGeneratorService.ListArts:output_type -> pb.ListArtsResponse
+	30, // 38: pb.ArtGeneratorService.DeleteArt:output_type -> google.protobuf.Empty
+	33, // 39: pb.ArtGeneratorService.GetArtUploadUrl:output_type -> pb.GetArtUploadUrlResponse
+	31, // 40: pb.ArtGeneratorService.ConfirmArtImageUpload:output_type -> pb.Art
+	34, // 41: pb.ArtGeneratorService.CreateComposition:output_type -> pb.Composition
+	34, // 42: pb.ArtGeneratorService.GetComposition:output_type -> pb.Composition
+	34, // 43: pb.ArtGeneratorService.UpdateComposition:output_type -> pb.Composition
+	35, // 44: pb.ArtGeneratorService.ListCompositions:output_type -> pb.ListCompositionsResponse
+	36, // 45: pb.ArtGeneratorService.GetCompositionGcodeFromStep:output_type -> pb.GetCompositionGcodeFromStepResponse
+	37, // 46: pb.ArtGeneratorService.GetCompositionCalibrationGcode:output_type -> pb.GetCompositionCalibrationGcodeResponse
+	34, // 47: pb.ArtGeneratorService.CancelComposition:output_type -> pb.Composition
+	30, // 48: pb.ArtGeneratorService.DeleteComposition:output_type -> google.protobuf.Empty
+	38, // 49: pb.ArtGeneratorService.CreateParameterSweep:output_type -> pb.ParameterSweep
+	38, // 50: pb.ArtGeneratorService.GetParameterSweep:output_type -> pb.ParameterSweep
+	39, // 51: pb.ArtGeneratorService.CreateThreadProfile:output_type -> pb.ThreadProfile
+	39, // 52: pb.ArtGeneratorService.GetThreadProfile:output_type -> pb.ThreadProfile
+	40, // 53: pb.ArtGeneratorService.ListThreadProfiles:output_type -> pb.ListThreadProfilesResponse
+	30, // 54: pb.ArtGeneratorService.DeleteThreadProfile:output_type -> google.protobuf.Empty
+	41, // 55: pb.ArtGeneratorService.GetQueueStats:output_type -> pb.QueueStats
+	28, // [28:56] is the sub-list for method output_type
+	0,  // [0:28] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
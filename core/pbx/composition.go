@@ -2,12 +2,15 @@ package pbx
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/Damione1/thread-art-generator/core/db/models"
 	"github.com/Damione1/thread-art-generator/core/pb"
 	"github.com/Damione1/thread-art-generator/core/resource"
 	"github.com/Damione1/thread-art-generator/core/storage"
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+	"github.com/volatiletech/null/v8"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -24,6 +27,8 @@ func CompositionDbToProto(ctx context.Context, dualStorage *storage.DualBucketSt
 		status = pb.CompositionStatus_COMPOSITION_STATUS_COMPLETE
 	case models.CompositionStatusEnumFAILED:
 		status = pb.CompositionStatus_COMPOSITION_STATUS_FAILED
+	case models.CompositionStatusEnumCANCELLED:
+		status = pb.CompositionStatus_COMPOSITION_STATUS_CANCELLED
 	default:
 		status = pb.CompositionStatus_COMPOSITION_STATUS_UNSPECIFIED
 	}
@@ -39,6 +44,14 @@ func CompositionDbToProto(ctx context.Context, dualStorage *storage.DualBucketSt
 		ImageContrast:     float32(composition.ImageContrast),
 		PhysicalRadius:    float32(composition.PhysicalRadius),
 		Status:            status,
+		GcodeDialect:      GcodeDialectDbToProto(composition.GcodeDialect),
+		SpoolLength:       float32(composition.SpoolLength),
+		LinearLight:       composition.LinearLight,
+		InputType:         InputTypeDbToProto(composition.InputType),
+		Seed:              composition.Seed,
+		SymmetryFold:      int32(composition.SymmetryFold),
+		MirrorSymmetry:    composition.MirrorSymmetry,
+		Priority:          CompositionPriorityDbToProto(composition.Priority),
 		CreateTime:        timestamppb.New(composition.CreatedAt),
 		UpdateTime:        timestamppb.New(composition.UpdatedAt),
 	}
@@ -62,6 +75,14 @@ func CompositionDbToProto(ctx context.Context, dualStorage *storage.DualBucketSt
 		if composition.PathlistURL.Valid {
 			compositionPb.PathlistUrl = storage.GenerateImageURL(ctx, publicURLGenerator, composition.PathlistURL.String, urlOptions)
 		}
+
+		if composition.DrillGcodeURL.Valid {
+			compositionPb.DrillGcodeUrl = storage.GenerateImageURL(ctx, publicURLGenerator, composition.DrillGcodeURL.String, urlOptions)
+		}
+
+		if composition.HeatmapURL.Valid {
+			compositionPb.HeatmapUrl = storage.GenerateImageURL(ctx, publicURLGenerator, composition.HeatmapURL.String, urlOptions)
+		}
 	}
 
 	if composition.ThreadLength.Valid {
@@ -76,9 +97,140 @@ func CompositionDbToProto(ctx context.Context, dualStorage *storage.DualBucketSt
 		compositionPb.ErrorMessage = composition.ErrorMessage.String
 	}
 
+	if composition.ContentHash.Valid {
+		compositionPb.ContentHash = composition.ContentHash.String
+	}
+
+	// Profiles belong to the author of the art
+	if composition.ThreadProfileID.Valid {
+		compositionPb.ThreadProfile = resource.BuildThreadProfileResourceName(artDb.AuthorID, composition.ThreadProfileID.String)
+	}
+
+	if rings, err := ParseNailRings(composition.Rings); err == nil {
+		for _, ring := range rings {
+			compositionPb.Rings = append(compositionPb.Rings, &pb.NailRing{
+				NailsQuantity:     int32(ring.NailsQuantity),
+				Radius:            float32(ring.Radius),
+				MinimumDifference: int32(ring.MinimumDifference),
+			})
+		}
+	}
+
+	if segments, err := ParseSpoolSegments(composition.Segments); err == nil {
+		for _, segment := range segments {
+			compositionPb.Segments = append(compositionPb.Segments, &pb.ThreadSegment{
+				StartStep: int32(segment.StartStep),
+				EndStep:   int32(segment.EndStep),
+				StartNail: int32(segment.StartNail),
+				Length:    float32(segment.Length),
+			})
+		}
+	}
+
+	if analytics, err := ParsePathAnalytics(composition.Analytics); err == nil && analytics != nil {
+		compositionPb.Analytics = PathAnalyticsToProto(analytics)
+	}
+
+	if progress, err := ParseCompositionProgress(composition.Progress); err == nil && progress != nil {
+		compositionPb.Progress = CompositionProgressToProto(progress)
+	}
+
 	return compositionPb
 }
 
+// SetNailRings stores the rings of a composition, the nails quantity becomes
+// the total of the rings so the nail numbers of the paths list stay in range
+func SetNailRings(composition *models.Composition, ringsPb []*pb.NailRing) {
+	if len(ringsPb) == 0 {
+		composition.Rings = null.JSON{}
+		return
+	}
+	rings := make([]threadGenerator.Ring, len(ringsPb))
+	composition.NailsQuantity = 0
+	for i, ringPb := range ringsPb {
+		rings[i] = threadGenerator.Ring{
+			NailsQuantity:     int(ringPb.GetNailsQuantity()),
+			Radius:            float64(ringPb.GetRadius()),
+			MinimumDifference: int(ringPb.GetMinimumDifference()),
+		}
+		composition.NailsQuantity += rings[i].NailsQuantity
+	}
+	// Plain numbers, the encoding can't fail
+	encoded, _ := json.Marshal(rings)
+	composition.Rings = null.JSONFrom(encoded)
+}
+
+// ParseNailRings decodes the rings column of a composition, nil for a single ring
+func ParseNailRings(rings null.JSON) ([]threadGenerator.Ring, error) {
+	if !rings.Valid {
+		return nil, nil
+	}
+	var parsed []threadGenerator.Ring
+	if err := json.Unmarshal(rings.JSON, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode nail rings: %w", err)
+	}
+	return parsed, nil
+}
+
+// ParseSpoolSegments decodes the segments column of a composition, nil when
+// the composition was not planned yet
+func ParseSpoolSegments(segments null.JSON) ([]threadGenerator.SpoolSegment, error) {
+	if !segments.Valid {
+		return nil, nil
+	}
+	var parsed []threadGenerator.SpoolSegment
+	if err := json.Unmarshal(segments.JSON, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode spool segments: %w", err)
+	}
+	return parsed, nil
+}
+
+// ParsePathAnalytics decodes the analytics column of a composition, nil when
+// the composition was not generated yet
+func ParsePathAnalytics(analytics null.JSON) (*threadGenerator.PathAnalytics, error) {
+	if !analytics.Valid {
+		return nil, nil
+	}
+	var parsed threadGenerator.PathAnalytics
+	if err := json.Unmarshal(analytics.JSON, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode path analytics: %w", err)
+	}
+	return &parsed, nil
+}
+
+// PathAnalyticsToProto converts the generator analytics to the proto message
+func PathAnalyticsToProto(analytics *threadGenerator.PathAnalytics) *pb.CompositionAnalytics {
+	analyticsPb := &pb.CompositionAnalytics{
+		NailHits:        make([]int32, len(analytics.NailHits)),
+		HitsHistogram:   histogramToProto(analytics.HitsHistogram),
+		MeanHits:        float32(analytics.MeanHits),
+		OverloadedNails: int32(analytics.OverloadedNails),
+		ChordLengths:    histogramToProto(analytics.ChordLengths),
+		MeanChordLength: float32(analytics.MeanChordLength),
+	}
+	for i, hits := range analytics.NailHits {
+		analyticsPb.NailHits[i] = int32(hits)
+	}
+	for _, load := range analytics.HeaviestNails {
+		analyticsPb.HeaviestNails = append(analyticsPb.HeaviestNails, &pb.NailLoad{
+			Nail:  int32(load.Nail),
+			Ring:  int32(load.Ring),
+			Index: int32(load.Index),
+			Hits:  int32(load.Hits),
+			Ratio: float32(load.Ratio),
+		})
+	}
+	return analyticsPb
+}
+
+func histogramToProto(bins []threadGenerator.HistogramBin) []*pb.HistogramBin {
+	binsPb := make([]*pb.HistogramBin, len(bins))
+	for i, bin := range bins {
+		binsPb[i] = &pb.HistogramBin{Min: float32(bin.Min), Max: float32(bin.Max), Count: int32(bin.Count)}
+	}
+	return binsPb
+}
+
 // ProtoCompositionToDb converts a proto composition to a database composition model
 func ProtoCompositionToDb(comp *pb.Composition) *models.Composition {
 	compositionDb := &models.Composition{
@@ -90,7 +242,16 @@ func ProtoCompositionToDb(comp *pb.Composition) *models.Composition {
 		BrightnessFactor:  int(comp.GetBrightnessFactor()),
 		ImageContrast:     float64(comp.GetImageContrast()),
 		PhysicalRadius:    float64(comp.GetPhysicalRadius()),
+		GcodeDialect:      GcodeDialectProtoToDb(comp.GetGcodeDialect()),
+		SpoolLength:       float64(comp.GetSpoolLength()),
+		LinearLight:       comp.GetLinearLight(),
+		InputType:         InputTypeProtoToDb(comp.GetInputType()),
+		Seed:              comp.GetSeed(),
+		SymmetryFold:      int(comp.GetSymmetryFold()),
+		MirrorSymmetry:    comp.GetMirrorSymmetry(),
+		Priority:          CompositionPriorityProtoToDb(comp.GetPriority()),
 	}
+	SetNailRings(compositionDb, comp.GetRings())
 
 	// Extract resource IDs from the name if it exists
 	if comp.GetName() != "" {
@@ -105,6 +266,37 @@ func ProtoCompositionToDb(comp *pb.Composition) *models.Composition {
 	return compositionDb
 }
 
+// GcodeDialectDbToProto converts a database G-code dialect to its proto enum
+func GcodeDialectDbToProto(dialect models.GcodeDialectEnum) pb.GcodeDialect {
+	switch dialect {
+	case models.GcodeDialectEnumFLUIDNC:
+		return pb.GcodeDialect_GCODE_DIALECT_FLUIDNC
+	case models.GcodeDialectEnumGRBL:
+		return pb.GcodeDialect_GCODE_DIALECT_GRBL
+	case models.GcodeDialectEnumMARLIN:
+		return pb.GcodeDialect_GCODE_DIALECT_MARLIN
+	case models.GcodeDialectEnumMARLIN_IJK:
+		return pb.GcodeDialect_GCODE_DIALECT_MARLIN_IJK
+	default:
+		return pb.GcodeDialect_GCODE_DIALECT_UNSPECIFIED
+	}
+}
+
+// GcodeDialectProtoToDb converts a proto G-code dialect to the database enum.
+// Unspecified dialects default to FluidNC, the firmware of the reference machine.
+func GcodeDialectProtoToDb(dialect pb.GcodeDialect) models.GcodeDialectEnum {
+	switch dialect {
+	case pb.GcodeDialect_GCODE_DIALECT_GRBL:
+		return models.GcodeDialectEnumGRBL
+	case pb.GcodeDialect_GCODE_DIALECT_MARLIN:
+		return models.GcodeDialectEnumMARLIN
+	case pb.GcodeDialect_GCODE_DIALECT_MARLIN_IJK:
+		return models.GcodeDialectEnumMARLIN_IJK
+	default:
+		return models.GcodeDialectEnumFLUIDNC
+	}
+}
+
 // ParseCompositionResourceName parses a composition resource name into user ID, art ID, and composition ID
 // Deprecated: Use resource.ParseResourceName instead
 func ParseCompositionResourceName(resourceName string) (string, string, string, error) {
@@ -120,3 +312,99 @@ func ParseCompositionResourceName(resourceName string) (string, string, string,
 
 	return composition.UserID, composition.ArtID, composition.CompositionID, nil
 }
+
+// CompositionToGeneratorConfig builds the thread generator configuration for a composition
+func CompositionToGeneratorConfig(composition *models.Composition) threadGenerator.Config {
+	config := threadGenerator.DefaultConfig()
+	config.NailsQuantity = composition.NailsQuantity
+	config.ImgSize = composition.ImgSize
+	config.MaxPaths = composition.MaxPaths
+	config.StartingNail = composition.StartingNail
+	config.MinimumDifference = composition.MinimumDifference
+	config.BrightnessFactor = composition.BrightnessFactor
+	config.ImageContrast = composition.ImageContrast
+	config.PhysicalRadius = composition.PhysicalRadius
+	config.GcodeDialect = GcodeDialectDbToGenerator(composition.GcodeDialect)
+	config.SpoolLength = composition.SpoolLength
+	config.LinearLight = composition.LinearLight
+	config.InputType = InputTypeDbToGenerator(composition.InputType)
+	config.Seed = composition.Seed
+	config.SymmetryFold = composition.SymmetryFold
+	config.MirrorSymmetry = composition.MirrorSymmetry
+	// The rings are validated when the composition is created
+	config.Rings, _ = ParseNailRings(composition.Rings)
+	// The profile is only applied when loaded with the composition
+	if composition.R != nil && composition.R.ThreadProfile != nil {
+		config.ThreadProfile = ThreadProfileDbToGenerator(composition.R.ThreadProfile)
+	}
+	return config
+}
+
+// GcodeDialectDbToGenerator converts a database dialect enum to the thread generator dialect
+func GcodeDialectDbToGenerator(dialect models.GcodeDialectEnum) threadGenerator.GcodeDialect {
+	switch dialect {
+	case models.GcodeDialectEnumGRBL:
+		return threadGenerator.DialectGRBL
+	case models.GcodeDialectEnumMARLIN:
+		return threadGenerator.DialectMarlin
+	case models.GcodeDialectEnumMARLIN_IJK:
+		return threadGenerator.DialectMarlinIJK
+	default:
+		return threadGenerator.DialectFluidNC
+	}
+}
+
+// InputTypeDbToProto converts a database input type to its proto enum
+func InputTypeDbToProto(inputType models.InputTypeEnum) pb.InputType {
+	switch inputType {
+	case models.InputTypeEnumPHOTO:
+		return pb.InputType_INPUT_TYPE_PHOTO
+	case models.InputTypeEnumLOGO:
+		return pb.InputType_INPUT_TYPE_LOGO
+	case models.InputTypeEnumLINE_DRAWING:
+		return pb.InputType_INPUT_TYPE_LINE_DRAWING
+	default:
+		return pb.InputType_INPUT_TYPE_UNSPECIFIED
+	}
+}
+
+// InputTypeProtoToDb converts a proto input type to the database enum.
+// Unspecified input types default to photos.
+func InputTypeProtoToDb(inputType pb.InputType) models.InputTypeEnum {
+	switch inputType {
+	case pb.InputType_INPUT_TYPE_LOGO:
+		return models.InputTypeEnumLOGO
+	case pb.InputType_INPUT_TYPE_LINE_DRAWING:
+		return models.InputTypeEnumLINE_DRAWING
+	default:
+		return models.InputTypeEnumPHOTO
+	}
+}
+
+// InputTypeDbToGenerator converts a database input type to the thread generator input type
+func InputTypeDbToGenerator(inputType models.InputTypeEnum) threadGenerator.InputType {
+	switch inputType {
+	case models.InputTypeEnumLOGO:
+		return threadGenerator.InputLogo
+	case models.InputTypeEnumLINE_DRAWING:
+		return threadGenerator.InputLineDrawing
+	default:
+		return threadGenerator.InputPhoto
+	}
+}
+
+// CalibrationRoutineProtoToGenerator converts a proto calibration routine to the thread generator routine
+func CalibrationRoutineProtoToGenerator(routine pb.CalibrationRoutine) (threadGenerator.CalibrationRoutine, bool) {
+	switch routine {
+	case pb.CalibrationRoutine_CALIBRATION_ROUTINE_ROTARY:
+		return threadGenerator.CalibrationRotary, true
+	case pb.CalibrationRoutine_CALIBRATION_ROUTINE_NEEDLE_DEPTH:
+		return threadGenerator.CalibrationNeedleDepth, true
+	case pb.CalibrationRoutine_CALIBRATION_ROUTINE_BACKLASH:
+		return threadGenerator.CalibrationBacklash, true
+	case pb.CalibrationRoutine_CALIBRATION_ROUTINE_SPINDLE_DRILL:
+		return threadGenerator.CalibrationSpindleDrill, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,49 @@
+package pbx
+
+import (
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	"github.com/Damione1/thread-art-generator/core/pb"
+	"github.com/Damione1/thread-art-generator/core/queue"
+)
+
+// CompositionPriorityDbToProto converts a database priority to its proto enum
+func CompositionPriorityDbToProto(priority models.CompositionPriorityEnum) pb.CompositionPriority {
+	return QueuePriorityToProto(CompositionPriorityDbToQueue(priority))
+}
+
+// CompositionPriorityProtoToDb converts a proto priority to the database enum.
+// Unspecified priorities default to renders.
+func CompositionPriorityProtoToDb(priority pb.CompositionPriority) models.CompositionPriorityEnum {
+	switch priority {
+	case pb.CompositionPriority_COMPOSITION_PRIORITY_INTERACTIVE:
+		return models.CompositionPriorityEnumINTERACTIVE
+	case pb.CompositionPriority_COMPOSITION_PRIORITY_BATCH:
+		return models.CompositionPriorityEnumBATCH
+	default:
+		return models.CompositionPriorityEnumRENDER
+	}
+}
+
+// CompositionPriorityDbToQueue converts a database priority to the priority of its queue
+func CompositionPriorityDbToQueue(priority models.CompositionPriorityEnum) queue.Priority {
+	switch priority {
+	case models.CompositionPriorityEnumINTERACTIVE:
+		return queue.PriorityInteractive
+	case models.CompositionPriorityEnumBATCH:
+		return queue.PriorityBatch
+	default:
+		return queue.PriorityRender
+	}
+}
+
+// QueuePriorityToProto converts a queue priority to its proto enum
+func QueuePriorityToProto(priority queue.Priority) pb.CompositionPriority {
+	switch priority {
+	case queue.PriorityInteractive:
+		return pb.CompositionPriority_COMPOSITION_PRIORITY_INTERACTIVE
+	case queue.PriorityBatch:
+		return pb.CompositionPriority_COMPOSITION_PRIORITY_BATCH
+	default:
+		return pb.CompositionPriority_COMPOSITION_PRIORITY_RENDER
+	}
+}
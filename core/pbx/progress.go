@@ -0,0 +1,75 @@
+package pbx
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Damione1/thread-art-generator/core/pb"
+	"github.com/volatiletech/null/v8"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ProgressStage is a stage of the processing of a composition
+type ProgressStage string
+
+const (
+	ProgressStageDownload ProgressStage = "download"
+	ProgressStageGenerate ProgressStage = "generate"
+	ProgressStageRender   ProgressStage = "render"
+	ProgressStageUpload   ProgressStage = "upload"
+)
+
+// CompositionProgress is the progress the worker stores on a composition
+type CompositionProgress struct {
+	Stage               ProgressStage `json:"stage"`
+	Percent             float64       `json:"percent"`
+	LinesPlaced         int           `json:"lines_placed"`
+	MaxLines            int           `json:"max_lines"`
+	EstimatedCompletion *time.Time    `json:"estimated_completion,omitempty"`
+	UpdatedAt           time.Time     `json:"updated_at"`
+}
+
+// ParseCompositionProgress decodes the progress column of a composition, nil
+// when the worker did not pick the composition up yet
+func ParseCompositionProgress(progress null.JSON) (*CompositionProgress, error) {
+	if !progress.Valid {
+		return nil, nil
+	}
+	var parsed CompositionProgress
+	if err := json.Unmarshal(progress.JSON, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode composition progress: %w", err)
+	}
+	return &parsed, nil
+}
+
+// CompositionProgressToProto converts the stored progress to the proto message
+func CompositionProgressToProto(progress *CompositionProgress) *pb.CompositionProgress {
+	progressPb := &pb.CompositionProgress{
+		Stage:       ProgressStageToProto(progress.Stage),
+		Percent:     float32(progress.Percent),
+		LinesPlaced: int32(progress.LinesPlaced),
+		MaxLines:    int32(progress.MaxLines),
+		UpdateTime:  timestamppb.New(progress.UpdatedAt),
+	}
+	if progress.EstimatedCompletion != nil {
+		progressPb.EstimatedCompletionTime = timestamppb.New(*progress.EstimatedCompletion)
+	}
+	return progressPb
+}
+
+// ProgressStageToProto converts a stored progress stage to its proto enum
+func ProgressStageToProto(stage ProgressStage) pb.ProgressStage {
+	switch stage {
+	case ProgressStageDownload:
+		return pb.ProgressStage_PROGRESS_STAGE_DOWNLOAD
+	case ProgressStageGenerate:
+		return pb.ProgressStage_PROGRESS_STAGE_GENERATE
+	case ProgressStageRender:
+		return pb.ProgressStage_PROGRESS_STAGE_RENDER
+	case ProgressStageUpload:
+		return pb.ProgressStage_PROGRESS_STAGE_UPLOAD
+	default:
+		return pb.ProgressStage_PROGRESS_STAGE_UNSPECIFIED
+	}
+}
@@ -0,0 +1,210 @@
+package pbx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	"github.com/Damione1/thread-art-generator/core/pb"
+	"github.com/Damione1/thread-art-generator/core/resource"
+	"github.com/Damione1/thread-art-generator/core/storage"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Names of the swept parameters in the ranges column of a sweep
+const (
+	SweepParameterBrightnessFactor  = "brightness_factor"
+	SweepParameterImageContrast     = "image_contrast"
+	SweepParameterMinimumDifference = "minimum_difference"
+	SweepParameterMaxPaths          = "max_paths"
+)
+
+// SweepRange is a swept parameter as stored in the ranges column of a sweep
+type SweepRange struct {
+	Parameter string  `json:"parameter"`
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"`
+	Step      float64 `json:"step"`
+}
+
+// Count returns the number of values of the range without building them,
+// capped at math.MaxInt32 so tiny steps can't overflow
+func (r SweepRange) Count() int {
+	// Tolerance so float steps like 0.1 still reach max
+	steps := math.Floor((r.Max-r.Min)/r.Step + 1e-9)
+	switch {
+	case math.IsNaN(steps) || steps < 0:
+		return 0
+	case steps >= math.MaxInt32:
+		return math.MaxInt32
+	}
+	return int(steps) + 1
+}
+
+// Values returns every value of the range, from min to max included. Callers
+// bound Count first, a tiny step makes billions of values.
+func (r SweepRange) Values() []float64 {
+	values := make([]float64, r.Count())
+	for i := range values {
+		values[i] = r.Min + float64(i)*r.Step
+	}
+	return values
+}
+
+// SweepParameterProtoToDb converts a proto sweep parameter to its stored name
+func SweepParameterProtoToDb(parameter pb.SweepParameter) (string, bool) {
+	switch parameter {
+	case pb.SweepParameter_SWEEP_PARAMETER_BRIGHTNESS_FACTOR:
+		return SweepParameterBrightnessFactor, true
+	case pb.SweepParameter_SWEEP_PARAMETER_IMAGE_CONTRAST:
+		return SweepParameterImageContrast, true
+	case pb.SweepParameter_SWEEP_PARAMETER_MINIMUM_DIFFERENCE:
+		return SweepParameterMinimumDifference, true
+	case pb.SweepParameter_SWEEP_PARAMETER_MAX_PATHS:
+		return SweepParameterMaxPaths, true
+	default:
+		return "", false
+	}
+}
+
+// SweepParameterDbToProto converts a stored sweep parameter name to its proto enum
+func SweepParameterDbToProto(parameter string) pb.SweepParameter {
+	switch parameter {
+	case SweepParameterBrightnessFactor:
+		return pb.SweepParameter_SWEEP_PARAMETER_BRIGHTNESS_FACTOR
+	case SweepParameterImageContrast:
+		return pb.SweepParameter_SWEEP_PARAMETER_IMAGE_CONTRAST
+	case SweepParameterMinimumDifference:
+		return pb.SweepParameter_SWEEP_PARAMETER_MINIMUM_DIFFERENCE
+	case SweepParameterMaxPaths:
+		return pb.SweepParameter_SWEEP_PARAMETER_MAX_PATHS
+	default:
+		return pb.SweepParameter_SWEEP_PARAMETER_UNSPECIFIED
+	}
+}
+
+// SweepParameterValue returns the value of a swept parameter in a composition
+func SweepParameterValue(composition *models.Composition, parameter string) float64 {
+	switch parameter {
+	case SweepParameterBrightnessFactor:
+		return float64(composition.BrightnessFactor)
+	case SweepParameterImageContrast:
+		return composition.ImageContrast
+	case SweepParameterMinimumDifference:
+		return float64(composition.MinimumDifference)
+	case SweepParameterMaxPaths:
+		return float64(composition.MaxPaths)
+	default:
+		return 0
+	}
+}
+
+// SetSweepParameterValue sets a swept parameter of a composition, integer
+// parameters are rounded
+func SetSweepParameterValue(composition *models.Composition, parameter string, value float64) error {
+	switch parameter {
+	case SweepParameterBrightnessFactor:
+		composition.BrightnessFactor = int(value + 0.5)
+	case SweepParameterImageContrast:
+		composition.ImageContrast = value
+	case SweepParameterMinimumDifference:
+		composition.MinimumDifference = int(value + 0.5)
+	case SweepParameterMaxPaths:
+		composition.MaxPaths = int(value + 0.5)
+	default:
+		return fmt.Errorf("unknown sweep parameter %q", parameter)
+	}
+	return nil
+}
+
+// ParseSweepRanges decodes the ranges column of a sweep
+func ParseSweepRanges(ranges types.JSON) ([]SweepRange, error) {
+	var parsed []SweepRange
+	if err := json.Unmarshal(ranges, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode sweep ranges: %w", err)
+	}
+	return parsed, nil
+}
+
+// ParameterSweepStatusDbToProto converts a database sweep status to its proto enum
+func ParameterSweepStatusDbToProto(status models.ParameterSweepStatusEnum) pb.ParameterSweepStatus {
+	switch status {
+	case models.ParameterSweepStatusEnumPENDING:
+		return pb.ParameterSweepStatus_PARAMETER_SWEEP_STATUS_PENDING
+	case models.ParameterSweepStatusEnumPROCESSING:
+		return pb.ParameterSweepStatus_PARAMETER_SWEEP_STATUS_PROCESSING
+	case models.ParameterSweepStatusEnumCOMPLETE:
+		return pb.ParameterSweepStatus_PARAMETER_SWEEP_STATUS_COMPLETE
+	case models.ParameterSweepStatusEnumFAILED:
+		return pb.ParameterSweepStatus_PARAMETER_SWEEP_STATUS_FAILED
+	default:
+		return pb.ParameterSweepStatus_PARAMETER_SWEEP_STATUS_UNSPECIFIED
+	}
+}
+
+// RankSweepCompositions sorts the compositions of a sweep by score, best
+// first, the compositions not scored yet come last
+func RankSweepCompositions(compositions []*models.Composition) {
+	sort.SliceStable(compositions, func(i, j int) bool {
+		a, b := compositions[i].SimilarityScore, compositions[j].SimilarityScore
+		if a.Valid != b.Valid {
+			return a.Valid
+		}
+		return a.Float64 > b.Float64
+	})
+}
+
+// ParameterSweepDbToProto converts a database sweep and its compositions to a proto sweep
+func ParameterSweepDbToProto(ctx context.Context, dualStorage *storage.DualBucketStorage, artDb *models.Art, sweep *models.ParameterSweep, compositions []*models.Composition) *pb.ParameterSweep {
+	sweepPb := &pb.ParameterSweep{
+		Name:              resource.BuildParameterSweepResourceName(artDb.AuthorID, artDb.ID, sweep.ID),
+		Status:            ParameterSweepStatusDbToProto(sweep.Status),
+		TotalCompositions: int32(len(compositions)),
+		CreateTime:        timestamppb.New(sweep.CreatedAt),
+		UpdateTime:        timestamppb.New(sweep.UpdatedAt),
+	}
+
+	if ranges, err := ParseSweepRanges(sweep.Ranges); err == nil {
+		for _, r := range ranges {
+			sweepPb.Ranges = append(sweepPb.Ranges, &pb.ParameterRange{
+				Parameter: SweepParameterDbToProto(r.Parameter),
+				Min:       r.Min,
+				Max:       r.Max,
+				Step:      r.Step,
+			})
+		}
+	}
+
+	ranked := append([]*models.Composition(nil), compositions...)
+	RankSweepCompositions(ranked)
+	for i, composition := range ranked {
+		result := &pb.ParameterSweepResult{
+			Composition: CompositionDbToProto(ctx, dualStorage, artDb, composition),
+		}
+		if composition.SimilarityScore.Valid {
+			result.Score = composition.SimilarityScore.Float64
+			result.Rank = int32(i + 1)
+		}
+		if composition.Status == models.CompositionStatusEnumCOMPLETE || composition.Status == models.CompositionStatusEnumFAILED || composition.Status == models.CompositionStatusEnumCANCELLED {
+			sweepPb.FinishedCompositions++
+		}
+		sweepPb.Results = append(sweepPb.Results, result)
+	}
+
+	// The key of the contact sheet is stored before it is built, it is only
+	// shown once the sweep is complete
+	if dualStorage != nil && sweep.Status == models.ParameterSweepStatusEnumCOMPLETE && sweep.ContactSheetURL.Valid {
+		publicURLGenerator := storage.NewPublicURLGenerator(dualStorage.GetPublicStorage())
+		sweepPb.ContactSheetUrl = storage.GenerateImageURL(ctx, publicURLGenerator, sweep.ContactSheetURL.String, storage.DefaultURLOptions())
+	}
+
+	if sweep.ErrorMessage.Valid {
+		sweepPb.ErrorMessage = sweep.ErrorMessage.String
+	}
+
+	return sweepPb
+}
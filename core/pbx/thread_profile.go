@@ -0,0 +1,57 @@
+package pbx
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	"github.com/Damione1/thread-art-generator/core/pb"
+	"github.com/Damione1/thread-art-generator/core/resource"
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ParseCalibrationBands decodes the bands column of a thread profile
+func ParseCalibrationBands(bands types.JSON) ([]threadGenerator.CalibrationBand, error) {
+	var parsed []threadGenerator.CalibrationBand
+	if err := json.Unmarshal(bands, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode calibration bands: %w", err)
+	}
+	return parsed, nil
+}
+
+// ThreadProfileDbToGenerator converts a database thread profile to the thread generator model
+func ThreadProfileDbToGenerator(profile *models.ThreadProfile) *threadGenerator.ThreadProfile {
+	return &threadGenerator.ThreadProfile{
+		Opacity: profile.Opacity,
+		Width:   profile.ThreadWidth,
+	}
+}
+
+// ThreadProfileDbToProto converts a database thread profile to a proto thread
+// profile, with the brightness the profile predicts for each measured band
+func ThreadProfileDbToProto(profile *models.ThreadProfile) *pb.ThreadProfile {
+	profilePb := &pb.ThreadProfile{
+		Name:        resource.BuildThreadProfileResourceName(profile.UserID, profile.ID),
+		DisplayName: profile.Name,
+		Opacity:     profile.Opacity,
+		ThreadWidth: profile.ThreadWidth,
+		FitError:    profile.FitError,
+		CreateTime:  timestamppb.New(profile.CreatedAt),
+		UpdateTime:  timestamppb.New(profile.UpdatedAt),
+	}
+
+	if bands, err := ParseCalibrationBands(profile.Bands); err == nil {
+		model := ThreadProfileDbToGenerator(profile)
+		for _, band := range bands {
+			profilePb.Bands = append(profilePb.Bands, &pb.CalibrationBand{
+				Density:             band.Density,
+				Brightness:          band.Brightness,
+				PredictedBrightness: model.Brightness(band.Density),
+			})
+		}
+	}
+
+	return profilePb
+}
@@ -0,0 +1,158 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+)
+
+const (
+	// outboxBatchSize bounds the messages a relay locks at once
+	outboxBatchSize = 100
+	// outboxBaseDelay is the wait after the first failed publish of a message,
+	// doubled after each next one
+	outboxBaseDelay = 5 * time.Second
+	// outboxMaxDelay bounds the wait between two publishes of a message, the
+	// relay never gives up on one
+	outboxMaxDelay = 5 * time.Minute
+)
+
+// EnqueueOutbox stores a message for the relay to publish. Written in the
+// transaction of the change it announces, the message is published if and
+// only if the change is committed.
+func EnqueueOutbox(ctx context.Context, exec boil.ContextExecutor, queueName string, message []byte) error {
+	outboxMessage := &models.OutboxMessage{
+		ID:      uuid.New().String(),
+		Queue:   queueName,
+		Payload: message,
+	}
+	if err := outboxMessage.Insert(ctx, exec, boil.Infer()); err != nil {
+		return fmt.Errorf("failed to insert outbox message: %w", err)
+	}
+	return nil
+}
+
+// OutboxDelay returns the wait after the given failed publish of a message
+func OutboxDelay(attempts int) time.Duration {
+	return min(outboxBaseDelay<<min(max(0, attempts-1), 16), outboxMaxDelay)
+}
+
+// OutboxRelay publishes the messages of the outbox table to their queues and
+// deletes them once published. A message is published at least once: when the
+// deletion fails after the publish, the next relay publishes it again, so the
+// consumers must handle duplicates. The worker claims a composition only once,
+// a duplicate of a composition processing or finished is never run again. Rows
+// are locked with SKIP LOCKED so several relays share the table.
+type OutboxRelay struct {
+	db           *sql.DB
+	client       QueueClient
+	pollInterval time.Duration
+	wake         chan struct{}
+}
+
+// NewOutboxRelay creates a relay checking the outbox every poll interval
+func NewOutboxRelay(db *sql.DB, client QueueClient, pollInterval time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		db:           db,
+		client:       client,
+		pollInterval: pollInterval,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Notify wakes the relay up for messages just committed, without waiting for
+// the next poll
+func (r *OutboxRelay) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run publishes the outbox until the context is done
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain the due messages, a full batch may have more behind it
+		for {
+			relayed, err := r.relayBatch(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("Failed to relay outbox messages")
+			}
+			if err != nil || relayed < outboxBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// relayBatch publishes a batch of due messages and returns how many it locked.
+// A failed message is put back with a longer wait, the others carry on.
+func (r *OutboxRelay) relayBatch(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	messages, err := models.OutboxMessages(
+		models.OutboxMessageWhere.NextAttemptAt.LTE(time.Now()),
+		qm.OrderBy(models.OutboxMessageColumns.CreatedAt),
+		qm.Limit(outboxBatchSize),
+		qm.For("UPDATE SKIP LOCKED"),
+	).All(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get outbox messages: %w", err)
+	}
+
+	for _, message := range messages {
+		if err := r.client.PublishMessage(ctx, message.Queue, message.Payload); err != nil {
+			message.Attempts++
+			message.LastError = null.StringFrom(err.Error())
+			message.NextAttemptAt = time.Now().Add(OutboxDelay(message.Attempts))
+			log.Warn().
+				Err(err).
+				Str("queue", message.Queue).
+				Int("attempts", message.Attempts).
+				Time("nextAttemptAt", message.NextAttemptAt).
+				Msg("Failed to publish outbox message")
+
+			_, err = message.Update(ctx, tx, boil.Whitelist(
+				models.OutboxMessageColumns.Attempts,
+				models.OutboxMessageColumns.LastError,
+				models.OutboxMessageColumns.NextAttemptAt,
+			))
+			if err != nil {
+				return 0, fmt.Errorf("failed to update outbox message: %w", err)
+			}
+			continue
+		}
+
+		if _, err := message.Delete(ctx, tx); err != nil {
+			return 0, fmt.Errorf("failed to delete outbox message: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit outbox messages: %w", err)
+	}
+	return len(messages), nil
+}
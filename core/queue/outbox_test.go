@@ -0,0 +1,185 @@
+package queue
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQueueClient records the published messages and fails the publishes to
+// its broken queues
+type fakeQueueClient struct {
+	mu        sync.Mutex
+	published map[string][][]byte
+	broken    map[string]error
+}
+
+func (c *fakeQueueClient) PublishMessage(ctx context.Context, queueName string, message []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.broken[queueName]; err != nil {
+		return err
+	}
+	if c.published == nil {
+		c.published = map[string][][]byte{}
+	}
+	c.published[queueName] = append(c.published[queueName], message)
+	return nil
+}
+
+// count returns the messages published to a queue
+func (c *fakeQueueClient) count(queueName string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.published[queueName])
+}
+
+func (c *fakeQueueClient) QueueDepth(queueName string) (QueueDepth, error) {
+	return QueueDepth{}, nil
+}
+
+func (c *fakeQueueClient) Close() error {
+	return nil
+}
+
+// notBefore matches a time no earlier than its own
+type notBefore time.Time
+
+func (n notBefore) Match(value driver.Value) bool {
+	at, ok := value.(time.Time)
+	return ok && !at.Before(time.Time(n))
+}
+
+func outboxRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "queue", "payload", "attempts", "last_error", "next_attempt_at", "created_at"})
+}
+
+func TestOutboxDelay(t *testing.T) {
+	require.Equal(t, 5*time.Second, OutboxDelay(1))
+	require.Equal(t, 10*time.Second, OutboxDelay(2))
+	require.Equal(t, 40*time.Second, OutboxDelay(4))
+
+	// The wait is bounded, the relay keeps trying a message forever
+	require.Equal(t, 5*time.Minute, OutboxDelay(10))
+	require.Equal(t, 5*time.Minute, OutboxDelay(1000))
+}
+
+func TestOutboxRelayBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	client := &fakeQueueClient{broken: map[string]error{"broken": errors.New("connection refused")}}
+	relay := NewOutboxRelay(db, client, time.Minute)
+	now := time.Now()
+
+	// The due messages are locked in a batch other relays skip
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "outbox_messages"\.\* FROM "outbox_messages" WHERE \("outbox_messages"\."next_attempt_at" <= \$1\) ORDER BY created_at LIMIT 100 FOR UPDATE SKIP LOCKED`).
+		WillReturnRows(outboxRows().
+			AddRow("published", "composition-processing", []byte(`{"compositionId":"1"}`), 0, nil, now, now).
+			AddRow("failing", "broken", []byte(`{"compositionId":"2"}`), 2, nil, now, now).
+			AddRow("next", "composition-processing.batch", []byte(`{"compositionId":"3"}`), 1, "connection refused", now, now))
+
+	// A published message is deleted, a failed one waits longer and the batch carries on
+	mock.ExpectExec(`DELETE FROM "outbox_messages" WHERE "id"=\$1`).
+		WithArgs("published").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "outbox_messages" SET "attempts"=\$1,"last_error"=\$2,"next_attempt_at"=\$3 WHERE "id"=\$4`).
+		WithArgs(3, "connection refused", notBefore(now.Add(OutboxDelay(3))), "failing").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM "outbox_messages" WHERE "id"=\$1`).
+		WithArgs("next").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	relayed, err := relay.relayBatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, relayed)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Equal(t, map[string][][]byte{
+		"composition-processing":       {[]byte(`{"compositionId":"1"}`)},
+		"composition-processing.batch": {[]byte(`{"compositionId":"3"}`)},
+	}, client.published)
+}
+
+func TestOutboxRelayBatchRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	client := &fakeQueueClient{}
+	relay := NewOutboxRelay(db, client, time.Minute)
+	now := time.Now()
+
+	// A message that can't be deleted stays in the outbox, published again later
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM "outbox_messages"`).
+		WillReturnRows(outboxRows().AddRow("published", "composition-processing", []byte(`{}`), 0, nil, now, now))
+	mock.ExpectExec(`DELETE FROM "outbox_messages"`).WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	_, err = relay.relayBatch(context.Background())
+	require.ErrorContains(t, err, "failed to delete outbox message")
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Len(t, client.published["composition-processing"], 1)
+}
+
+func TestOutboxRelayRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	client := &fakeQueueClient{}
+	relay := NewOutboxRelay(db, client, time.Hour)
+	now := time.Now()
+
+	// A full batch is followed by the next one at once
+	full := outboxRows()
+	for range outboxBatchSize {
+		full.AddRow("message", "composition-processing", []byte(`{}`), 0, nil, now, now)
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM "outbox_messages"`).WillReturnRows(full)
+	for range outboxBatchSize {
+		mock.ExpectExec(`DELETE FROM "outbox_messages"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM "outbox_messages"`).WillReturnRows(outboxRows())
+	mock.ExpectCommit()
+
+	// A notification relays the messages committed since, without waiting for the poll
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM "outbox_messages"`).
+		WillReturnRows(outboxRows().AddRow("notified", "composition-processing.interactive", []byte(`{}`), 0, nil, now, now))
+	mock.ExpectExec(`DELETE FROM "outbox_messages"`).WithArgs("notified").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		relay.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		return client.count("composition-processing") == outboxBatchSize
+	}, time.Second, time.Millisecond)
+	relay.Notify()
+	require.Eventually(t, func() bool {
+		return mock.ExpectationsWereMet() == nil
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+	require.Equal(t, 1, client.count("composition-processing.interactive"))
+}
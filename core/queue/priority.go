@@ -0,0 +1,82 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog/log"
+)
+
+// Priority is the processing priority of a job, a higher one is processed first
+type Priority int
+
+const (
+	PriorityBatch Priority = iota
+	PriorityRender
+	PriorityInteractive
+)
+
+// Priorities lists the priorities from the highest to the lowest
+var Priorities = []Priority{PriorityInteractive, PriorityRender, PriorityBatch}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityInteractive:
+		return "interactive"
+	case PriorityBatch:
+		return "batch"
+	default:
+		return "render"
+	}
+}
+
+// PriorityQueueName returns the queue holding the jobs of a priority. Renders
+// keep the base queue so the messages published before priorities carry on.
+func PriorityQueueName(queueName string, priority Priority) string {
+	if priority == PriorityRender {
+		return queueName
+	}
+	return queueName + "." + priority.String()
+}
+
+// DeferQueueName returns the delay queue holding the messages of a queue put
+// aside until their user has a free job slot. The delay is part of the name so
+// a new delay declares a new queue rather than conflicting with the old one.
+func DeferQueueName(queueName string, delay time.Duration) string {
+	return fmt.Sprintf("%s.deferred.%s", queueName, delay)
+}
+
+// DeclareDeferQueue declares the delay queue of a queue, its messages expire
+// back to the end of the queue after the delay
+func DeclareDeferQueue(ch *amqp.Channel, queueName string, delay time.Duration) error {
+	_, err := ch.QueueDeclare(
+		DeferQueueName(queueName, delay), // name
+		true,                             // durable
+		false,                            // delete when unused
+		false,                            // exclusive
+		false,                            // no-wait
+		delayQueueArgs(queueName, delay),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare defer queue: %w", err)
+	}
+	return nil
+}
+
+// Defer publishes a delivery to the delay queue of its queue without counting
+// an attempt, the jobs of other users go ahead meanwhile. The caller acks the
+// delivery once it is published.
+func Defer(ctx context.Context, ch Publisher, d amqp.Delivery, queueName string, delay time.Duration, reason error) error {
+	err := republish(ctx, ch, d, "", DeferQueueName(queueName, delay), Attempt(d), reason)
+	if err != nil {
+		return fmt.Errorf("failed to publish deferred message: %w", err)
+	}
+
+	log.Info().
+		Str("queue", queueName).
+		Str("reason", reason.Error()).
+		Msg("Message deferred")
+	return nil
+}
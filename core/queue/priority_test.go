@@ -0,0 +1,49 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityQueueName(t *testing.T) {
+	require.Equal(t, "composition-processing.interactive", PriorityQueueName("composition-processing", PriorityInteractive))
+	require.Equal(t, "composition-processing", PriorityQueueName("composition-processing", PriorityRender), "renders keep the base queue")
+	require.Equal(t, "composition-processing.batch", PriorityQueueName("composition-processing", PriorityBatch))
+	require.Equal(t, "composition-processing.batch.deferred.15s", DeferQueueName(PriorityQueueName("composition-processing", PriorityBatch), 15*time.Second))
+
+	for i := 1; i < len(Priorities); i++ {
+		require.Greater(t, Priorities[i-1], Priorities[i], "priorities are listed from the highest")
+	}
+}
+
+func TestDefer(t *testing.T) {
+	queueName := PriorityQueueName("composition-processing", PriorityBatch)
+	delivery := amqp.Delivery{
+		Headers: amqp.Table{AttemptHeader: int32(2)},
+		Body:    []byte(`{"compositionId":"1"}`),
+	}
+	publisher := &fakePublisher{}
+	atCapacity := errors.New("user reached the cap of concurrent jobs")
+
+	// A deferred message waits in the defer queue without counting an attempt
+	require.NoError(t, Defer(context.Background(), publisher, delivery, queueName, 30*time.Second, atCapacity))
+	require.Len(t, publisher.published, 1)
+	deferred := publisher.published[0]
+	require.Equal(t, "", deferred.exchange)
+	require.Equal(t, "composition-processing.batch.deferred.30s", deferred.key)
+	require.Equal(t, int32(2), deferred.msg.Headers[AttemptHeader])
+	require.Equal(t, atCapacity.Error(), deferred.msg.Headers[FailureHeader])
+	require.Equal(t, delivery.Body, deferred.msg.Body)
+
+	// Once the delay expires, the defer queue dead-letters it back to the end of its queue
+	require.Equal(t, amqp.Table{
+		"x-message-ttl":             int64(30000),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": "composition-processing.batch",
+	}, delayQueueArgs(queueName, 30*time.Second))
+}
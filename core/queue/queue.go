@@ -3,6 +3,7 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -14,10 +15,19 @@ type QueueClient interface {
 	// PublishMessage publishes a message to a queue
 	PublishMessage(ctx context.Context, queueName string, message []byte) error
 
+	// QueueDepth returns the messages waiting in a queue and its consumers
+	QueueDepth(queueName string) (QueueDepth, error)
+
 	// Close closes the queue connection
 	Close() error
 }
 
+// QueueDepth is the state of a queue
+type QueueDepth struct {
+	Messages  int // Messages ready for a consumer
+	Consumers int
+}
+
 // RabbitMQClient implements QueueClient for RabbitMQ
 type RabbitMQClient struct {
 	conn    *amqp.Connection
@@ -87,6 +97,27 @@ func (c *RabbitMQClient) PublishMessage(ctx context.Context, queueName string, m
 	return nil
 }
 
+// QueueDepth inspects a queue without declaring it, a queue no worker declared
+// yet is empty. A missing queue closes the channel it is inspected on, so each
+// inspection opens its own.
+func (c *RabbitMQClient) QueueDepth(queueName string) (QueueDepth, error) {
+	ch, err := c.conn.Channel()
+	if err != nil {
+		return QueueDepth{}, fmt.Errorf("failed to open a channel: %w", err)
+	}
+	defer ch.Close()
+
+	q, err := ch.QueueDeclarePassive(queueName, true, false, false, false, nil)
+	if err != nil {
+		var amqpErr *amqp.Error
+		if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+			return QueueDepth{}, nil
+		}
+		return QueueDepth{}, fmt.Errorf("failed to inspect queue: %w", err)
+	}
+	return QueueDepth{Messages: q.Messages, Consumers: q.Consumers}, nil
+}
+
 // Close closes the connection to RabbitMQ
 func (c *RabbitMQClient) Close() error {
 	if c.channel != nil {
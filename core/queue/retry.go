@@ -0,0 +1,214 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// AttemptHeader holds the attempt a delivery is, starting at 1
+	AttemptHeader = "x-attempt"
+	// FailureHeader holds the error of the last failed attempt
+	FailureHeader = "x-failure"
+)
+
+// Publisher publishes the deliveries the queue settles again, an *amqp.Channel
+type Publisher interface {
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+}
+
+// PermanentError is a failure retrying can't fix, such as a deleted
+// composition or a corrupt image. The message is dead-lettered at once.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent marks an error as permanent
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent reports whether an error, or an error it wraps, is permanent
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+// RetryPolicy bounds the attempts of a message and spaces them out
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts before the message is dead-lettered, the first one included
+	BaseDelay   time.Duration // Wait after the first failed attempt, doubled after each next one
+}
+
+// DefaultRetryPolicy returns the policy used when the configuration has none
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 5, BaseDelay: 5 * time.Second}
+}
+
+// Delay returns the wait after the given failed attempt
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	return p.BaseDelay << max(0, attempt-1)
+}
+
+// RetryQueueName returns the delay queue holding messages for the given wait
+// before routing them back to the queue. The wait is part of the name so a
+// new policy declares new queues rather than conflicting with the old ones.
+func RetryQueueName(queueName string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%s", queueName, delay)
+}
+
+// DeadLetterExchangeName returns the exchange receiving the messages of a
+// queue that exhausted their attempts or failed permanently
+func DeadLetterExchangeName(queueName string) string {
+	return queueName + ".dlx"
+}
+
+// DeadLetterQueueName returns the queue keeping the dead-lettered messages of
+// a queue for inspection
+func DeadLetterQueueName(queueName string) string {
+	return queueName + ".dead-letter"
+}
+
+// DeclareRetryTopology declares a delay queue per retry of the policy and the
+// dead-letter exchange and queue of a queue. Delay queues hold no consumer,
+// their messages expire back into the queue.
+func DeclareRetryTopology(ch *amqp.Channel, queueName string, policy RetryPolicy) error {
+	for attempt := 1; attempt < policy.MaxAttempts; attempt++ {
+		delay := policy.Delay(attempt)
+		_, err := ch.QueueDeclare(
+			RetryQueueName(queueName, delay), // name
+			true,                             // durable
+			false,                            // delete when unused
+			false,                            // exclusive
+			false,                            // no-wait
+			delayQueueArgs(queueName, delay),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to declare retry queue: %w", err)
+		}
+	}
+
+	err := ch.ExchangeDeclare(
+		DeadLetterExchangeName(queueName), // name
+		amqp.ExchangeFanout,               // type
+		true,                              // durable
+		false,                             // auto-deleted
+		false,                             // internal
+		false,                             // no-wait
+		nil,                               // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare dead-letter exchange: %w", err)
+	}
+
+	_, err = ch.QueueDeclare(DeadLetterQueueName(queueName), true, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to declare dead-letter queue: %w", err)
+	}
+
+	err = ch.QueueBind(DeadLetterQueueName(queueName), "", DeadLetterExchangeName(queueName), false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to bind dead-letter queue: %w", err)
+	}
+	return nil
+}
+
+// delayQueueArgs configures a delay queue, its messages expire back to the end
+// of the queue after the delay
+func delayQueueArgs(queueName string, delay time.Duration) amqp.Table {
+	return amqp.Table{
+		"x-message-ttl":             delay.Milliseconds(),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": queueName,
+	}
+}
+
+// Attempt returns the attempt of a delivery, 1 for a message never retried
+func Attempt(d amqp.Delivery) int {
+	switch attempt := d.Headers[AttemptHeader].(type) {
+	case int32:
+		return int(attempt)
+	case int64:
+		return int(attempt)
+	default:
+		return 1
+	}
+}
+
+// Retry publishes a failed delivery to the delay queue of its attempt, it
+// comes back to the queue as the next attempt once the delay expires. The
+// caller acks the delivery once it is published.
+func Retry(ctx context.Context, ch Publisher, d amqp.Delivery, queueName string, policy RetryPolicy, failure error) error {
+	attempt := Attempt(d)
+	err := republish(ctx, ch, d, "", RetryQueueName(queueName, policy.Delay(attempt)), attempt+1, failure)
+	if err != nil {
+		return fmt.Errorf("failed to publish retry: %w", err)
+	}
+
+	log.Warn().
+		Err(failure).
+		Str("queue", queueName).
+		Int("attempt", attempt).
+		Int("maxAttempts", policy.MaxAttempts).
+		Dur("delay", policy.Delay(attempt)).
+		Msg("Message scheduled for retry")
+	return nil
+}
+
+// DeadLetter publishes a failed delivery to the dead-letter exchange of its
+// queue. The caller acks the delivery once it is published.
+func DeadLetter(ctx context.Context, ch Publisher, d amqp.Delivery, queueName string, failure error) error {
+	attempt := Attempt(d)
+	err := republish(ctx, ch, d, DeadLetterExchangeName(queueName), "", attempt, failure)
+	if err != nil {
+		return fmt.Errorf("failed to publish dead letter: %w", err)
+	}
+
+	log.Error().
+		Err(failure).
+		Str("queue", queueName).
+		Int("attempt", attempt).
+		Bool("permanent", IsPermanent(failure)).
+		Msg("Message dead-lettered")
+	return nil
+}
+
+// republish copies a delivery with its attempt and last failure
+func republish(ctx context.Context, ch Publisher, d amqp.Delivery, exchange, routingKey string, attempt int, failure error) error {
+	headers := amqp.Table{}
+	for key, value := range d.Headers {
+		headers[key] = value
+	}
+	headers[AttemptHeader] = int32(attempt)
+	headers[FailureHeader] = failure.Error()
+
+	return ch.PublishWithContext(
+		ctx,
+		exchange,   // exchange
+		routingKey, // routing key
+		false,      // mandatory
+		false,      // immediate
+		amqp.Publishing{
+			Headers:      headers,
+			ContentType:  d.ContentType,
+			DeliveryMode: amqp.Persistent,
+			Body:         d.Body,
+		},
+	)
+}
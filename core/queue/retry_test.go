@@ -0,0 +1,89 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/require"
+)
+
+// publishing is a message published by a fakePublisher
+type publishing struct {
+	exchange string
+	key      string
+	msg      amqp.Publishing
+}
+
+// fakePublisher records the deliveries the queue settles
+type fakePublisher struct {
+	published []publishing
+}
+
+func (p *fakePublisher) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	p.published = append(p.published, publishing{exchange: exchange, key: key, msg: msg})
+	return nil
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 4, BaseDelay: 5 * time.Second}
+	require.Equal(t, 5*time.Second, policy.Delay(1))
+	require.Equal(t, 10*time.Second, policy.Delay(2))
+	require.Equal(t, 20*time.Second, policy.Delay(3))
+
+	require.Equal(t, "composition-processing.retry.10s", RetryQueueName("composition-processing", policy.Delay(2)))
+	require.Equal(t, "composition-processing.dlx", DeadLetterExchangeName("composition-processing"))
+	require.Equal(t, "composition-processing.dead-letter", DeadLetterQueueName("composition-processing"))
+}
+
+func TestAttempt(t *testing.T) {
+	require.Equal(t, 1, Attempt(amqp.Delivery{}), "a new message is on its first attempt")
+	require.Equal(t, 3, Attempt(amqp.Delivery{Headers: amqp.Table{AttemptHeader: int32(3)}}))
+	require.Equal(t, 2, Attempt(amqp.Delivery{Headers: amqp.Table{AttemptHeader: int64(2)}}))
+}
+
+func TestPermanent(t *testing.T) {
+	require.Nil(t, Permanent(nil))
+	require.False(t, IsPermanent(errors.New("connection reset")))
+
+	err := fmt.Errorf("processing: %w", Permanent(fmt.Errorf("failed to get art: %w", sql.ErrNoRows)))
+	require.True(t, IsPermanent(err))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.Equal(t, "processing: failed to get art: sql: no rows in result set", err.Error())
+}
+
+func TestRetryAndDeadLetter(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 4, BaseDelay: 5 * time.Second}
+	delivery := amqp.Delivery{
+		Headers:     amqp.Table{AttemptHeader: int32(2), "x-trace": "trace"},
+		ContentType: "application/json",
+		Body:        []byte(`{"compositionId":"1"}`),
+	}
+	publisher := &fakePublisher{}
+
+	// A retry waits in the delay queue of its attempt and comes back as the next one
+	require.NoError(t, Retry(context.Background(), publisher, delivery, "composition-processing", policy, errors.New("timeout")))
+	require.NoError(t, DeadLetter(context.Background(), publisher, delivery, "composition-processing", errors.New("corrupt image")))
+	require.Len(t, publisher.published, 2)
+
+	retry := publisher.published[0]
+	require.Equal(t, "", retry.exchange)
+	require.Equal(t, "composition-processing.retry.10s", retry.key)
+	require.Equal(t, int32(3), retry.msg.Headers[AttemptHeader])
+	require.Equal(t, "timeout", retry.msg.Headers[FailureHeader])
+	require.Equal(t, "trace", retry.msg.Headers["x-trace"], "the other headers are kept")
+	require.Equal(t, delivery.Body, retry.msg.Body)
+	require.Equal(t, amqp.Persistent, retry.msg.DeliveryMode)
+
+	// A dead letter keeps its attempt
+	deadLetter := publisher.published[1]
+	require.Equal(t, "composition-processing.dlx", deadLetter.exchange)
+	require.Equal(t, "", deadLetter.key)
+	require.Equal(t, int32(2), deadLetter.msg.Headers[AttemptHeader])
+	require.Equal(t, "corrupt image", deadLetter.msg.Headers[FailureHeader])
+	require.Equal(t, int32(2), delivery.Headers[AttemptHeader], "the delivery is left as is")
+}
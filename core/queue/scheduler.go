@@ -0,0 +1,134 @@
+package queue
+
+import (
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Job is a delivery waiting for a worker, with the queue it came from, the
+// user it is processed for and the memory it is estimated to hold
+type Job struct {
+	Delivery amqp.Delivery
+	Queue    string
+	Priority Priority
+	User     string
+	Memory   int64
+
+	seq uint64
+}
+
+// Scheduler hands the deliveries of the consumers to their workers. A job
+// starts once the memory it needs is free. The highest priority goes first,
+// then the user with the fewest running jobs, then the oldest job. Jobs wait
+// in order rather than letting smaller ones through, so a large job is never
+// starved.
+type Scheduler struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	budget  int64 // Memory the running jobs may hold, 0 for no limit
+	used    int64
+	running map[string]int
+	total   int
+	pending []*Job
+	seq     uint64
+	closed  bool
+}
+
+// NewScheduler creates a scheduler sharing the memory budget between its jobs
+func NewScheduler(memoryBudget int64) *Scheduler {
+	s := &Scheduler{
+		budget:  memoryBudget,
+		running: make(map[string]int),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// Submit queues a job, false once the scheduler is closed
+func (s *Scheduler) Submit(job *Job) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	s.seq++
+	job.seq = s.seq
+	s.pending = append(s.pending, job)
+	s.cond.Broadcast()
+	return true
+}
+
+// Next blocks until a job can start and returns it, false once the scheduler
+// is closed. The worker calls Done when the job ends.
+func (s *Scheduler) Next() (*Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for !s.closed {
+		if job := s.admit(); job != nil {
+			return job, true
+		}
+		s.cond.Wait()
+	}
+	return nil, false
+}
+
+// Done releases the memory of a finished job
+func (s *Scheduler) Done(job *Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used -= job.Memory
+	s.running[job.User]--
+	if s.running[job.User] == 0 {
+		delete(s.running, job.User)
+	}
+	s.total--
+	s.cond.Broadcast()
+}
+
+// Close stops handing out jobs and returns the ones that never started, for
+// the caller to requeue. Running jobs carry on until they are done.
+func (s *Scheduler) Close() []*Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	waiting := s.pending
+	s.pending = nil
+	s.cond.Broadcast()
+	return waiting
+}
+
+// admit starts the next job in fairness order if its memory is free. A job
+// larger than the whole budget runs alone rather than never.
+func (s *Scheduler) admit() *Job {
+	if len(s.pending) == 0 {
+		return nil
+	}
+	index := 0
+	for i, job := range s.pending[1:] {
+		if s.before(job, s.pending[index]) {
+			index = i + 1
+		}
+	}
+	next := s.pending[index]
+	if s.budget > 0 && s.total > 0 && s.used+next.Memory > s.budget {
+		return nil
+	}
+
+	s.pending = append(s.pending[:index], s.pending[index+1:]...)
+	s.used += next.Memory
+	s.running[next.User]++
+	s.total++
+	return next
+}
+
+// before reports whether a job goes before another one
+func (s *Scheduler) before(job, other *Job) bool {
+	if job.Priority != other.Priority {
+		return job.Priority > other.Priority
+	}
+	if s.running[job.User] != s.running[other.User] {
+		return s.running[job.User] < s.running[other.User]
+	}
+	return job.seq < other.seq
+}
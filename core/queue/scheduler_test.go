@@ -0,0 +1,95 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerFairness(t *testing.T) {
+	s := NewScheduler(0)
+	for _, user := range []string{"a", "a", "a", "b", "c"} {
+		require.True(t, s.Submit(&Job{User: user}))
+	}
+
+	// Users with fewer running jobs go first, the oldest job breaks ties
+	var order []string
+	for range 5 {
+		job, ok := s.Next()
+		require.True(t, ok)
+		order = append(order, job.User)
+	}
+	require.Equal(t, []string{"a", "b", "c", "a", "a"}, order)
+}
+
+func TestSchedulerPriority(t *testing.T) {
+	s := NewScheduler(0)
+	s.Submit(&Job{User: "a", Priority: PriorityBatch})
+	s.Submit(&Job{User: "a", Priority: PriorityRender})
+	s.Submit(&Job{User: "b", Priority: PriorityInteractive})
+	s.Submit(&Job{User: "c", Priority: PriorityRender})
+
+	// Higher priorities go first, fairness orders the jobs of a priority
+	var order []string
+	for range 4 {
+		job, ok := s.Next()
+		require.True(t, ok)
+		order = append(order, job.Priority.String()+" "+job.User)
+	}
+	require.Equal(t, []string{"interactive b", "render a", "render c", "batch a"}, order)
+}
+
+func TestSchedulerMemoryAdmission(t *testing.T) {
+	s := NewScheduler(100)
+	small := &Job{User: "a", Memory: 60}
+	large := &Job{User: "b", Memory: 60}
+	s.Submit(small)
+	s.Submit(large)
+
+	first, ok := s.Next()
+	require.True(t, ok)
+	require.Same(t, small, first)
+
+	// The second job waits for the memory of the first
+	started := make(chan *Job)
+	go func() {
+		job, _ := s.Next()
+		started <- job
+	}()
+	select {
+	case <-started:
+		t.Fatal("a job started beyond the memory budget")
+	case <-time.After(50 * time.Millisecond):
+	}
+	s.Done(first)
+	require.Same(t, large, <-started)
+	s.Done(large)
+
+	// A job larger than the budget runs alone
+	oversized := &Job{User: "a", Memory: 500}
+	s.Submit(oversized)
+	job, ok := s.Next()
+	require.True(t, ok)
+	require.Same(t, oversized, job)
+}
+
+func TestSchedulerClose(t *testing.T) {
+	s := NewScheduler(100)
+	s.Submit(&Job{User: "a", Memory: 80})
+	s.Submit(&Job{User: "b", Memory: 80})
+	running, _ := s.Next()
+
+	stopped := make(chan bool)
+	go func() {
+		_, ok := s.Next()
+		stopped <- ok
+	}()
+
+	waiting := s.Close()
+	require.Len(t, waiting, 1)
+	require.Equal(t, "b", waiting[0].User)
+	require.False(t, <-stopped, "idle workers stop once the scheduler is closed")
+	require.False(t, s.Submit(&Job{User: "c"}))
+	s.Done(running)
+}
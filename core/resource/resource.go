@@ -7,9 +7,11 @@ import (
 )
 
 const (
-	UserResource        string = "users/{user}"
-	ArtResource         string = "users/{user}/arts/{art}"
-	CompositionResource string = "users/{user}/arts/{art}/compositions/{composition}"
+	UserResource          string = "users/{user}"
+	ArtResource           string = "users/{user}/arts/{art}"
+	CompositionResource   string = "users/{user}/arts/{art}/compositions/{composition}"
+	SweepResource         string = "users/{user}/arts/{art}/sweeps/{sweep}"
+	ThreadProfileResource string = "users/{user}/threadProfiles/{thread_profile}"
 )
 
 // ResourceParser interface for parsing resource names
@@ -43,6 +45,17 @@ type Composition struct {
 	CompositionID string
 }
 
+type ParameterSweep struct {
+	UserID  string
+	ArtID   string
+	SweepID string
+}
+
+type ThreadProfile struct {
+	UserID          string
+	ThreadProfileID string
+}
+
 // Builder functions for creating resource names
 func BuildUserResourceName(userID string) string {
 	return fmt.Sprintf("users/%s", userID)
@@ -56,6 +69,14 @@ func BuildCompositionResourceName(userID, artID, compositionID string) string {
 	return fmt.Sprintf("users/%s/arts/%s/compositions/%s", userID, artID, compositionID)
 }
 
+func BuildParameterSweepResourceName(userID, artID, sweepID string) string {
+	return fmt.Sprintf("users/%s/arts/%s/sweeps/%s", userID, artID, sweepID)
+}
+
+func BuildThreadProfileResourceName(userID, threadProfileID string) string {
+	return fmt.Sprintf("users/%s/threadProfiles/%s", userID, threadProfileID)
+}
+
 // Parse parses a resource name and returns the appropriate resource type
 func (p *Parser) Parse(resourceName string) (Resource, error) {
 	if err := validateResourceName(resourceName); err != nil {
@@ -74,6 +95,10 @@ func (p *Parser) Parse(resourceName string) (Resource, error) {
 		return p.parseArtResource(resourceName)
 	case CompositionResource:
 		return p.parseCompositionResource(resourceName)
+	case SweepResource:
+		return p.parseParameterSweepResource(resourceName)
+	case ThreadProfileResource:
+		return p.parseThreadProfileResource(resourceName)
 	default:
 		return nil, fmt.Errorf("invalid resource type")
 	}
@@ -88,6 +113,10 @@ func (p *Parser) ResourceType(resourceName string) (string, error) {
 		return ArtResource, nil
 	case resourcename.Match(CompositionResource, resourceName):
 		return CompositionResource, nil
+	case resourcename.Match(SweepResource, resourceName):
+		return SweepResource, nil
+	case resourcename.Match(ThreadProfileResource, resourceName):
+		return ThreadProfileResource, nil
 	default:
 		return "", fmt.Errorf("invalid resource name")
 	}
@@ -130,6 +159,33 @@ func (p *Parser) parseCompositionResource(resourceName string) (*Composition, er
 	}, nil
 }
 
+func (p *Parser) parseParameterSweepResource(resourceName string) (*ParameterSweep, error) {
+	var userID, artID, sweepID string
+	err := resourcename.Sscan(resourceName, SweepResource, &userID, &artID, &sweepID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ParameterSweep{
+		UserID:  userID,
+		ArtID:   artID,
+		SweepID: sweepID,
+	}, nil
+}
+
+func (p *Parser) parseThreadProfileResource(resourceName string) (*ThreadProfile, error) {
+	var userID, threadProfileID string
+	err := resourcename.Sscan(resourceName, ThreadProfileResource, &userID, &threadProfileID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ThreadProfile{
+		UserID:          userID,
+		ThreadProfileID: threadProfileID,
+	}, nil
+}
+
 func validateResourceName(name string) error {
 	return resourcename.Validate(name)
 }
@@ -158,6 +214,10 @@ func ExtractUserID(resourceName string) string {
 			return r.UserID
 		case *Composition:
 			return r.UserID
+		case *ParameterSweep:
+			return r.UserID
+		case *ThreadProfile:
+			return r.UserID
 		}
 	}
 
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	pbErrors "github.com/Damione1/thread-art-generator/core/errors"
+	"github.com/Damione1/thread-art-generator/core/middleware"
+	"github.com/Damione1/thread-art-generator/core/pb"
+	"github.com/Damione1/thread-art-generator/core/pbx"
+	"github.com/Damione1/thread-art-generator/core/queue"
+	"github.com/rs/zerolog/log"
+)
+
+// GetQueueStats reports the composition jobs waiting in each priority queue
+func (server *Server) GetQueueStats(ctx context.Context, req *pb.GetQueueStatsRequest) (*pb.QueueStats, error) {
+	// Get Firebase UID from context
+	firebaseUID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, pbErrors.PermissionDeniedError("user not authenticated")
+	}
+
+	// Get internal user from Firebase UID
+	user, err := server.getUserFromFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		log.Error().Err(err).Str("firebase_uid", firebaseUID).Msg("GetQueueStats: Failed to get user from Firebase UID")
+		return nil, pbErrors.InternalError("failed to get user", err)
+	}
+	if user.Role != models.RoleEnumAdmin && user.Role != models.RoleEnumSuperAdmin {
+		return nil, pbErrors.PermissionDeniedError("only administrators can view queue stats")
+	}
+
+	if server.queueClient == nil {
+		return nil, pbErrors.FailedPreconditionError("queue client not initialized")
+	}
+
+	queueName := server.config.Queue.CompositionProcessing
+	if queueName == "" {
+		queueName = "composition-processing"
+	}
+
+	stats := &pb.QueueStats{}
+	for _, priority := range queue.Priorities {
+		name := queue.PriorityQueueName(queueName, priority)
+		depth, err := server.queueClient.QueueDepth(name)
+		if err != nil {
+			return nil, pbErrors.InternalError("failed to inspect queue", err)
+		}
+		deferred, err := server.queueClient.QueueDepth(queue.DeferQueueName(name, server.config.Queue.DeferDelay))
+		if err != nil {
+			return nil, pbErrors.InternalError("failed to inspect defer queue", err)
+		}
+		stats.Queues = append(stats.Queues, &pb.QueueDepth{
+			Priority:         pbx.QueuePriorityToProto(priority),
+			Queue:            name,
+			ReadyMessages:    int32(depth.Messages),
+			DeferredMessages: int32(deferred.Messages),
+			Consumers:        int32(depth.Consumers),
+		})
+	}
+	return stats, nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	"github.com/Damione1/thread-art-generator/core/middleware"
+	"github.com/Damione1/thread-art-generator/core/pb"
+	"github.com/Damione1/thread-art-generator/core/queue"
+	"github.com/Damione1/thread-art-generator/core/util"
+)
+
+// fakeQueueClient reports fixed queue depths and refuses publishes, the
+// server only publishes through the outbox
+type fakeQueueClient struct {
+	depths map[string]queue.QueueDepth
+}
+
+func (c *fakeQueueClient) PublishMessage(ctx context.Context, queueName string, message []byte) error {
+	panic("messages are published by the outbox relay")
+}
+
+func (c *fakeQueueClient) QueueDepth(queueName string) (queue.QueueDepth, error) {
+	return c.depths[queueName], nil
+}
+
+func (c *fakeQueueClient) Close() error {
+	return nil
+}
+
+// newTestServer returns a server on a mocked database, authenticated as the
+// user of the Firebase UID
+func newTestServer(t *testing.T, firebaseUID string) (*Server, sqlmock.Sqlmock, context.Context) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	server := &Server{
+		config: util.Config{
+			DB:    db,
+			Queue: util.QueueConfig{CompositionProcessing: "composition-processing", DeferDelay: 15 * time.Second},
+		},
+	}
+	return server, mock, context.WithValue(context.Background(), middleware.AuthKey, firebaseUID)
+}
+
+// expectUser expects the lookup of the authenticated user
+func expectUser(mock sqlmock.Sqlmock, firebaseUID, userID string, role models.RoleEnum) {
+	mock.ExpectQuery(`SELECT "users"\.\* FROM "users" WHERE \("users"\."firebase_uid" = \$1\)`).
+		WithArgs(firebaseUID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "firebase_uid", "role"}).AddRow(userID, firebaseUID, role))
+}
+
+func TestGetQueueStats(t *testing.T) {
+	server, mock, ctx := newTestServer(t, "admin-uid")
+	server.queueClient = &fakeQueueClient{depths: map[string]queue.QueueDepth{
+		"composition-processing.interactive":              {Messages: 2, Consumers: 3},
+		"composition-processing":                          {Messages: 5, Consumers: 3},
+		"composition-processing.deferred.15s":             {Messages: 4},
+		"composition-processing.batch":                    {Messages: 40, Consumers: 3},
+		"composition-processing.batch.deferred.15s":       {Messages: 7},
+		"composition-processing.interactive.deferred.15s": {},
+	}}
+	expectUser(mock, "admin-uid", "admin", models.RoleEnumAdmin)
+
+	stats, err := server.GetQueueStats(ctx, &pb.GetQueueStatsRequest{})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// The queues are listed from the highest priority with their defer queues
+	require.Len(t, stats.GetQueues(), 3)
+	expected := []struct {
+		priority  pb.CompositionPriority
+		queue     string
+		ready     int32
+		deferred  int32
+		consumers int32
+	}{
+		{pb.CompositionPriority_COMPOSITION_PRIORITY_INTERACTIVE, "composition-processing.interactive", 2, 0, 3},
+		{pb.CompositionPriority_COMPOSITION_PRIORITY_RENDER, "composition-processing", 5, 4, 3},
+		{pb.CompositionPriority_COMPOSITION_PRIORITY_BATCH, "composition-processing.batch", 40, 7, 3},
+	}
+	for i, want := range expected {
+		depth := stats.GetQueues()[i]
+		require.Equal(t, want.priority, depth.GetPriority())
+		require.Equal(t, want.queue, depth.GetQueue())
+		require.Equal(t, want.ready, depth.GetReadyMessages())
+		require.Equal(t, want.deferred, depth.GetDeferredMessages())
+		require.Equal(t, want.consumers, depth.GetConsumers())
+	}
+}
+
+func TestGetQueueStatsPermissions(t *testing.T) {
+	// Only administrators see the queues
+	server, mock, ctx := newTestServer(t, "user-uid")
+	server.queueClient = &fakeQueueClient{}
+	expectUser(mock, "user-uid", "user", models.RoleEnumUser)
+
+	_, err := server.GetQueueStats(ctx, &pb.GetQueueStatsRequest{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// A server without a queue has nothing to report
+	server, mock, ctx = newTestServer(t, "admin-uid")
+	expectUser(mock, "admin-uid", "admin", models.RoleEnumSuperAdmin)
+
+	_, err = server.GetQueueStats(ctx, &pb.GetQueueStatsRequest{})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = server.GetQueueStats(context.Background(), &pb.GetQueueStatsRequest{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err), "anonymous callers are refused")
+}
@@ -1,9 +1,11 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
+	"image/png"
 	"time"
 
 	"github.com/Damione1/thread-art-generator/core/db/models"
@@ -12,6 +14,7 @@ import (
 	"github.com/Damione1/thread-art-generator/core/pb"
 	"github.com/Damione1/thread-art-generator/core/pbx"
 	"github.com/Damione1/thread-art-generator/core/resource"
+	"github.com/Damione1/thread-art-generator/threadGenerator"
 	"github.com/bufbuild/protovalidate-go"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -27,6 +30,12 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const (
+	// svgContentType is the upload type of SVG sources, kept in the private bucket
+	svgContentType = "image/svg+xml"
+	// svgRenderingSize is the side in pixels of the public rendering of an SVG source
+	svgRenderingSize = 2048
+)
 
 func (server *Server) CreateArt(ctx context.Context, req *pb.CreateArtRequest) (*pb.Art, error) {
 	// Get Firebase UID from context
@@ -382,6 +391,9 @@ func (server *Server) DeleteArt(ctx context.Context, req *pb.DeleteArtRequest) (
 			log.Error().Err(err).Msg(fmt.Sprintf("Failed to delete image %s", artDb.ImageID.String))
 			return &emptypb.Empty{}, nil // Don't return a public error if the image deletion fails
 		}
+		if err := server.deleteSVGSource(ctx, imageKey); err != nil {
+			log.Error().Err(err).Msg(fmt.Sprintf("Failed to delete svg source %s", artDb.ImageID.String))
+		}
 	}
 
 	return &emptypb.Empty{}, nil
@@ -463,7 +475,17 @@ func (server *Server) GetArtUploadUrl(ctx context.Context, req *pb.GetArtUploadU
 		ContentType: req.GetContentType(), // Include content type for validation
 	}
 
-	signedURL, err := server.storage.GetPublicStorage().SignedURL(ctx, imageKey, opts)
+	// SVG documents can carry scripts, so they are uploaded to the private
+	// bucket and the public image is their rendering. A bitmap upload replaces
+	// an earlier SVG source.
+	bucket := server.storage.GetPublicStorage()
+	if req.GetContentType() == svgContentType {
+		bucket = server.storage.GetPrivateStorage()
+	} else if err := server.deleteSVGSource(ctx, imageKey); err != nil {
+		return nil, pbErrors.InternalError("failed to delete previous svg source", err)
+	}
+
+	signedURL, err := bucket.SignedURL(ctx, imageKey, opts)
 	if err != nil {
 		return nil, pbErrors.InternalError("failed to generate signed URL", err)
 	}
@@ -541,6 +563,17 @@ func (server *Server) ConfirmArtImageUpload(ctx context.Context, req *pb.Confirm
 	// Verify the image exists in the bucket using resource builder
 	imageKey := resource.BuildArtResourceName(artDb.AuthorID, artDb.ImageID.String)
 
+	// An SVG source stays private, its rendering is the public image
+	svgSource, err := server.storage.GetPrivateStorage().Exists(ctx, imageKey)
+	if err != nil {
+		return nil, pbErrors.InternalError("failed to verify svg source exists", err)
+	}
+	if svgSource {
+		if err := server.publishSVGRendering(ctx, imageKey); err != nil {
+			return nil, err
+		}
+	}
+
 	exists, err := server.storage.GetPublicStorage().Exists(ctx, imageKey)
 	if err != nil {
 		return nil, pbErrors.InternalError("failed to verify image exists", err)
@@ -561,3 +594,39 @@ func (server *Server) ConfirmArtImageUpload(ctx context.Context, req *pb.Confirm
 
 	return pbx.ArtDbToProto(ctx, server.storage, artDb), nil
 }
+
+// deleteSVGSource deletes the private SVG source of an image, if any
+func (server *Server) deleteSVGSource(ctx context.Context, imageKey string) error {
+	exists, err := server.storage.GetPrivateStorage().Exists(ctx, imageKey)
+	if err != nil || !exists {
+		return err
+	}
+	return server.storage.DeletePrivate(ctx, imageKey)
+}
+
+// publishSVGRendering renders the private SVG source of an image into the PNG
+// served as the public image. The document itself is never served, the
+// worker reads it from the private bucket.
+func (server *Server) publishSVGRendering(ctx context.Context, imageKey string) error {
+	reader, err := server.storage.GetPrivateStorage().Download(ctx, imageKey)
+	if err != nil {
+		return pbErrors.InternalError("failed to download svg source", err)
+	}
+	defer reader.Close()
+
+	rendering, err := threadGenerator.RasterizeSVG(reader, svgRenderingSize)
+	if err != nil {
+		return pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("name", fmt.Errorf("the svg image can't be rendered: %w", err)),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, rendering); err != nil {
+		return pbErrors.InternalError("failed to encode svg rendering", err)
+	}
+	if err := server.storage.UploadPublic(ctx, imageKey, &buf, "image/png"); err != nil {
+		return pbErrors.InternalError("failed to upload svg rendering", err)
+	}
+	return nil
+}
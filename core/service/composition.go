@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/Damione1/thread-art-generator/core/db/models"
 	pbErrors "github.com/Damione1/thread-art-generator/core/errors"
@@ -12,6 +13,7 @@ import (
 	"github.com/Damione1/thread-art-generator/core/pbx"
 	"github.com/Damione1/thread-art-generator/core/queue"
 	"github.com/Damione1/thread-art-generator/core/resource"
+	"github.com/Damione1/thread-art-generator/threadGenerator"
 	"github.com/bufbuild/protovalidate-go"
 	"github.com/friendsofgo/errors"
 	"github.com/google/uuid"
@@ -24,6 +26,11 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+// maxInteractiveCompositions bounds the interactive compositions a user has
+// pending or processing. Interactive jobs go ahead of the renders of every
+// user, past the bound they are renders like the others.
+const maxInteractiveCompositions = 2
+
 // CreateComposition creates a new composition for an art
 func (server *Server) CreateComposition(ctx context.Context, req *pb.CreateCompositionRequest) (*pb.Composition, error) {
 	// Get Firebase UID from context
@@ -96,20 +103,54 @@ func (server *Server) CreateComposition(ctx context.Context, req *pb.CreateCompo
 		BrightnessFactor:  int(req.GetComposition().GetBrightnessFactor()),
 		ImageContrast:     float64(req.GetComposition().GetImageContrast()),
 		PhysicalRadius:    float64(req.GetComposition().GetPhysicalRadius()),
+		GcodeDialect:      pbx.GcodeDialectProtoToDb(req.GetComposition().GetGcodeDialect()),
+		SpoolLength:       float64(req.GetComposition().GetSpoolLength()),
+		LinearLight:       req.GetComposition().GetLinearLight(),
+		InputType:         pbx.InputTypeProtoToDb(req.GetComposition().GetInputType()),
+		Seed:              req.GetComposition().GetSeed(),
+		SymmetryFold:      int(req.GetComposition().GetSymmetryFold()),
+		MirrorSymmetry:    req.GetComposition().GetMirrorSymmetry(),
+		Priority:          pbx.CompositionPriorityProtoToDb(req.GetComposition().GetPriority()),
+	}
+	pbx.SetNailRings(compositionDb, req.GetComposition().GetRings())
+	if violations := validateNailRings(compositionDb, "composition.rings"); len(violations) > 0 {
+		return nil, pbErrors.InvalidArgumentError(violations)
+	}
+	if violations := validateSymmetry(compositionDb, "composition.symmetry_fold"); len(violations) > 0 {
+		return nil, pbErrors.InvalidArgumentError(violations)
+	}
+	compositionDb.ThreadProfileID, err = server.resolveThreadProfileID(ctx, user.ID, req.GetComposition().GetThreadProfile(), "composition.thread_profile")
+	if err != nil {
+		return nil, err
 	}
 
-	// Insert the composition
-	err = compositionDb.Insert(ctx, server.config.DB, boil.Infer())
+	// Insert the composition with its queue message, so a composition is never
+	// left pending without one
+	tx, err := server.config.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, pbErrors.InternalError("failed to insert composition", err)
+		return nil, pbErrors.InternalError("failed to start transaction", err)
+	}
+	defer tx.Rollback()
+
+	if compositionDb.Priority == models.CompositionPriorityEnumINTERACTIVE {
+		compositionDb.Priority, err = interactivePriority(ctx, tx, user.ID)
+		if err != nil {
+			return nil, pbErrors.InternalError("failed to check interactive compositions", err)
+		}
 	}
 
-	// Enqueue the composition for processing
-	err = server.enqueueCompositionForProcessing(ctx, compositionDb, artDb)
+	err = compositionDb.Insert(ctx, tx, boil.Infer())
 	if err != nil {
-		log.Error().Err(err).Str("compositionID", compositionDb.ID).Msg("Failed to enqueue composition for processing")
-		// We don't return an error here, as the composition is created and can be requeued later
+		return nil, pbErrors.InternalError("failed to insert composition", err)
+	}
+	err = server.enqueueCompositionForProcessing(ctx, tx, compositionDb, artDb)
+	if err != nil {
+		return nil, pbErrors.InternalError("failed to enqueue composition", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, pbErrors.InternalError("failed to commit composition", err)
 	}
+	server.notifyOutbox()
 
 	// Return the created composition
 	return pbx.CompositionDbToProto(ctx, server.storage, artDb, compositionDb), nil
@@ -176,6 +217,182 @@ func (server *Server) GetComposition(ctx context.Context, req *pb.GetComposition
 	return pbx.CompositionDbToProto(ctx, server.storage, artDb, compositionDb), nil
 }
 
+// GetCompositionGcodeFromStep generates the stringing G-code of a completed composition resuming at a path index
+func (server *Server) GetCompositionGcodeFromStep(ctx context.Context, req *pb.GetCompositionGcodeFromStepRequest) (*pb.GetCompositionGcodeFromStepResponse, error) {
+	// Get Firebase UID from context
+	firebaseUID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, pbErrors.PermissionDeniedError("user not authenticated")
+	}
+
+	// Get internal user from Firebase UID
+	user, err := server.getUserFromFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		log.Error().Err(err).Str("firebase_uid", firebaseUID).Msg("GetCompositionGcodeFromStep: Failed to get user from Firebase UID")
+		return nil, pbErrors.InternalError("failed to get user", err)
+	}
+
+	// Validate the request
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, pbErrors.ConvertProtoValidateError(err)
+	}
+
+	// Parse the composition resource name
+	compositionResource, err := resource.ParseResourceName(req.GetName())
+	if err != nil {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("name", errors.New("invalid resource name")),
+		})
+	}
+
+	composition, ok := compositionResource.(*resource.Composition)
+	if !ok {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("name", errors.New("invalid composition resource name")),
+		})
+	}
+
+	// Verify the user is authorized to get this composition
+	if composition.UserID != user.ID {
+		return nil, pbErrors.PermissionDeniedError("only the author can get this composition")
+	}
+
+	compositionDb, err := models.Compositions(
+		models.CompositionWhere.ID.EQ(composition.CompositionID),
+		models.CompositionWhere.ArtID.EQ(composition.ArtID),
+		qm.InnerJoin("arts ON arts.id = compositions.art_id AND arts.author_id = ?", user.ID),
+	).One(ctx, server.config.DB)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pbErrors.NotFoundError("composition not found or you don't have permission to view it")
+		}
+		return nil, pbErrors.InternalError("failed to get composition", err)
+	}
+
+	if compositionDb.Status != models.CompositionStatusEnumCOMPLETE || !compositionDb.PathlistURL.Valid {
+		return nil, pbErrors.FailedPreconditionError("composition must be complete to generate G-code")
+	}
+
+	// Load the paths list stored by the worker
+	reader, err := server.storage.GetPublicStorage().Download(ctx, compositionDb.PathlistURL.String)
+	if err != nil {
+		return nil, pbErrors.InternalError("failed to download paths list", err)
+	}
+	defer reader.Close()
+
+	// Compositions from before the path list document stored a bare array, both load
+	document, err := threadGenerator.ReadPathList(reader)
+	if err != nil {
+		return nil, pbErrors.InternalError("failed to decode paths list", err)
+	}
+	paths := document.Paths
+
+	step := int(req.GetStep())
+	if step >= len(paths) {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("step", fmt.Errorf("step must be lower than the number of paths (%d)", len(paths))),
+		})
+	}
+
+	generator := threadGenerator.NewThreadGenerator(pbx.CompositionToGeneratorConfig(compositionDb))
+	generator.SetPathsList(paths)
+	gcode, err := generator.GetGcodeFromStep(step)
+	if err != nil {
+		return nil, pbErrors.InternalError("failed to generate gcode", err)
+	}
+
+	return &pb.GetCompositionGcodeFromStepResponse{
+		Gcode:          strings.Join(gcode, "\n"),
+		Step:           int32(step),
+		RemainingPaths: int32(len(paths) - step),
+	}, nil
+}
+
+// GetCompositionCalibrationGcode generates a calibration program for the nail count and dialect of a composition
+func (server *Server) GetCompositionCalibrationGcode(ctx context.Context, req *pb.GetCompositionCalibrationGcodeRequest) (*pb.GetCompositionCalibrationGcodeResponse, error) {
+	// Get Firebase UID from context
+	firebaseUID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, pbErrors.PermissionDeniedError("user not authenticated")
+	}
+
+	// Get internal user from Firebase UID
+	user, err := server.getUserFromFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		log.Error().Err(err).Str("firebase_uid", firebaseUID).Msg("GetCompositionCalibrationGcode: Failed to get user from Firebase UID")
+		return nil, pbErrors.InternalError("failed to get user", err)
+	}
+
+	// Validate the request
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, pbErrors.ConvertProtoValidateError(err)
+	}
+
+	routine, ok := pbx.CalibrationRoutineProtoToGenerator(req.GetRoutine())
+	if !ok {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("routine", errors.New("unknown calibration routine")),
+		})
+	}
+
+	// Parse the composition resource name
+	compositionResource, err := resource.ParseResourceName(req.GetName())
+	if err != nil {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("name", errors.New("invalid resource name")),
+		})
+	}
+
+	composition, ok := compositionResource.(*resource.Composition)
+	if !ok {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("name", errors.New("invalid composition resource name")),
+		})
+	}
+
+	// Verify the user is authorized to get this composition
+	if composition.UserID != user.ID {
+		return nil, pbErrors.PermissionDeniedError("only the author can get this composition")
+	}
+
+	compositionDb, err := models.Compositions(
+		models.CompositionWhere.ID.EQ(composition.CompositionID),
+		models.CompositionWhere.ArtID.EQ(composition.ArtID),
+		qm.InnerJoin("arts ON arts.id = compositions.art_id AND arts.author_id = ?", user.ID),
+	).One(ctx, server.config.DB)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pbErrors.NotFoundError("composition not found or you don't have permission to view it")
+		}
+		return nil, pbErrors.InternalError("failed to get composition", err)
+	}
+
+	config := pbx.CompositionToGeneratorConfig(compositionDb)
+
+	// Stay within the machine limits when a machine profile is configured
+	settings := threadGenerator.DefaultCalibrationSettings()
+	if server.machineProfile != nil {
+		if err := server.machineProfile.Validate(config); err != nil {
+			return nil, pbErrors.FailedPreconditionError(fmt.Sprintf("composition does not match the machine profile: %v", err))
+		}
+		settings = server.machineProfile.CalibrationSettings()
+	}
+
+	generator := threadGenerator.NewThreadGenerator(config)
+	gcode, err := generator.GetCalibrationGcode(routine, settings)
+	if errors.Is(err, threadGenerator.ErrCalibrationMultiRing) {
+		return nil, pbErrors.FailedPreconditionError(err.Error())
+	}
+	if err != nil {
+		return nil, pbErrors.InternalError("failed to generate calibration gcode", err)
+	}
+
+	return &pb.GetCompositionCalibrationGcodeResponse{
+		Gcode:   strings.Join(gcode, "\n"),
+		Routine: req.GetRoutine(),
+	}, nil
+}
+
 // UpdateComposition updates an existing composition
 func (server *Server) UpdateComposition(ctx context.Context, req *pb.UpdateCompositionRequest) (*pb.Composition, error) {
 	// Since compositions are processed asynchronously and their config shouldn't change
@@ -256,9 +473,10 @@ func (server *Server) ListCompositions(ctx context.Context, req *pb.ListComposit
 		}
 	}
 
-	// Query compositions
+	// Query compositions, the ones generated by parameter sweeps are listed with their sweep
 	queryMods := []qm.QueryMod{
 		models.CompositionWhere.ArtID.EQ(art.ArtID),
+		models.CompositionWhere.SweepID.IsNull(),
 		qm.OrderBy(fmt.Sprintf("%s DESC", models.CompositionColumns.CreatedAt)), // Latest first
 		qm.Limit(pageSize + 1), // +1 to check if there are more
 		qm.Offset(offset),
@@ -295,6 +513,80 @@ func (server *Server) ListCompositions(ctx context.Context, req *pb.ListComposit
 	return response, nil
 }
 
+// CancelComposition stops a pending or processing composition. The worker
+// notices the status, aborts the generation and removes the files it uploaded.
+func (server *Server) CancelComposition(ctx context.Context, req *pb.CancelCompositionRequest) (*pb.Composition, error) {
+	// Get Firebase UID from context
+	firebaseUID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, pbErrors.PermissionDeniedError("user not authenticated")
+	}
+
+	// Get internal user from Firebase UID
+	user, err := server.getUserFromFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		log.Error().Err(err).Str("firebase_uid", firebaseUID).Msg("CancelComposition: Failed to get user from Firebase UID")
+		return nil, pbErrors.InternalError("failed to get user", err)
+	}
+
+	// Validate the request
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, pbErrors.ConvertProtoValidateError(err)
+	}
+
+	// Parse the composition resource name
+	compositionResource, err := resource.ParseResourceName(req.GetName())
+	if err != nil {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("name", errors.New("invalid resource name")),
+		})
+	}
+
+	composition, ok := compositionResource.(*resource.Composition)
+	if !ok {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("name", errors.New("invalid composition resource name")),
+		})
+	}
+
+	// Verify the user is authorized to cancel this composition
+	if composition.UserID != user.ID {
+		return nil, pbErrors.PermissionDeniedError("only the author can cancel this composition")
+	}
+
+	// Get the composition with art using join to verify ownership
+	compositionDb, err := models.Compositions(
+		models.CompositionWhere.ID.EQ(composition.CompositionID),
+		models.CompositionWhere.ArtID.EQ(composition.ArtID),
+		qm.InnerJoin("arts ON arts.id = compositions.art_id AND arts.author_id = ?", user.ID),
+		qm.Load(models.CompositionRels.Art),
+	).One(ctx, server.config.DB)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pbErrors.NotFoundError("composition not found or you don't have permission to cancel it")
+		}
+		return nil, pbErrors.InternalError("failed to get composition", err)
+	}
+
+	// Only cancel a composition still unfinished, the worker may complete it meanwhile
+	updated, err := models.Compositions(
+		models.CompositionWhere.ID.EQ(compositionDb.ID),
+		models.CompositionWhere.Status.IN([]models.CompositionStatusEnum{
+			models.CompositionStatusEnumPENDING,
+			models.CompositionStatusEnumPROCESSING,
+		}),
+	).UpdateAll(ctx, server.config.DB, models.M{models.CompositionColumns.Status: models.CompositionStatusEnumCANCELLED})
+	if err != nil {
+		return nil, pbErrors.InternalError("failed to cancel composition", err)
+	}
+	if updated == 0 {
+		return nil, pbErrors.FailedPreconditionError("only a pending or processing composition can be cancelled")
+	}
+	compositionDb.Status = models.CompositionStatusEnumCANCELLED
+
+	return pbx.CompositionDbToProto(ctx, server.storage, compositionDb.R.Art, compositionDb), nil
+}
+
 // DeleteComposition deletes a composition
 func (server *Server) DeleteComposition(ctx context.Context, req *pb.DeleteCompositionRequest) (*emptypb.Empty, error) {
 	// Get Firebase UID from context
@@ -376,16 +668,52 @@ func (server *Server) DeleteComposition(ctx context.Context, req *pb.DeleteCompo
 		}
 	}
 
+	if compositionDb.DrillGcodeURL.Valid {
+		err = server.storage.GetPublicStorage().Delete(ctx, compositionDb.DrillGcodeURL.String)
+		if err != nil {
+			log.Error().Err(err).Str("key", compositionDb.DrillGcodeURL.String).Msg("Failed to delete drill gcode file")
+		}
+	}
+
+	if compositionDb.HeatmapURL.Valid {
+		err = server.storage.GetPublicStorage().Delete(ctx, compositionDb.HeatmapURL.String)
+		if err != nil {
+			log.Error().Err(err).Str("key", compositionDb.HeatmapURL.String).Msg("Failed to delete heatmap file")
+		}
+	}
+
 	return &emptypb.Empty{}, nil
 }
 
-// Helper function to enqueue a composition for processing
-func (server *Server) enqueueCompositionForProcessing(ctx context.Context, composition *models.Composition, art *models.Art) error {
-	// Check if queue client is initialized
-	if server.queueClient == nil {
-		return fmt.Errorf("queue client not initialized")
+// interactivePriority returns the priority of an interactive composition of a
+// user, a render once the user has maxInteractiveCompositions pending or
+// processing. An advisory lock on the user serializes the checks of concurrent
+// requests until the transaction ends.
+func interactivePriority(ctx context.Context, tx *sql.Tx, userID string) (models.CompositionPriorityEnum, error) {
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", "interactive:"+userID); err != nil {
+		return "", fmt.Errorf("failed to lock user compositions: %w", err)
+	}
+	interactive, err := models.Compositions(
+		qm.InnerJoin("arts ON arts.id = compositions.art_id AND arts.author_id = ?", userID),
+		models.CompositionWhere.Priority.EQ(models.CompositionPriorityEnumINTERACTIVE),
+		models.CompositionWhere.Status.IN([]models.CompositionStatusEnum{
+			models.CompositionStatusEnumPENDING,
+			models.CompositionStatusEnumPROCESSING,
+		}),
+	).Count(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to count interactive compositions: %w", err)
+	}
+	if interactive >= maxInteractiveCompositions {
+		return models.CompositionPriorityEnumRENDER, nil
 	}
+	return models.CompositionPriorityEnumINTERACTIVE, nil
+}
 
+// enqueueCompositionForProcessing writes the queue message of a composition
+// to the outbox, in the transaction inserting the composition. The relay
+// publishes it once the transaction is committed.
+func (server *Server) enqueueCompositionForProcessing(ctx context.Context, exec boil.ContextExecutor, composition *models.Composition, art *models.Art) error {
 	// Create a queue message
 	message := queue.NewCompositionProcessingMessage(art.ID, composition.ID)
 
@@ -395,16 +723,17 @@ func (server *Server) enqueueCompositionForProcessing(ctx context.Context, compo
 		return fmt.Errorf("failed to serialize composition processing message: %w", err)
 	}
 
-	// Get queue name from config
+	// Get queue name from config, each priority has its own queue
 	queueName := server.config.Queue.CompositionProcessing
 	if queueName == "" {
 		queueName = "composition-processing" // Default queue name
 	}
+	queueName = queue.PriorityQueueName(queueName, pbx.CompositionPriorityDbToQueue(composition.Priority))
 
-	// Publish to queue
-	err = server.queueClient.PublishMessage(ctx, queueName, jsonData)
+	// Store the message for the relay
+	err = queue.EnqueueOutbox(ctx, exec, queueName, jsonData)
 	if err != nil {
-		return fmt.Errorf("failed to publish composition to queue: %w", err)
+		return fmt.Errorf("failed to store composition message: %w", err)
 	}
 
 	log.Info().
@@ -415,3 +744,40 @@ func (server *Server) enqueueCompositionForProcessing(ctx context.Context, compo
 
 	return nil
 }
+
+// notifyOutbox wakes the relay up for the messages just committed. Without a
+// queue they wait in the outbox for a server that has one.
+func (server *Server) notifyOutbox() {
+	if server.outbox != nil {
+		server.outbox.Notify()
+	}
+}
+
+// maxCompositionNails bounds the nails of all the rings, matching the single ring
+// limit, the generator precomputes a line for every pair of nails
+const maxCompositionNails = 1000
+
+// validateNailRings checks the rings of a composition fit on its frame
+func validateNailRings(composition *models.Composition, field string) []*errdetails.BadRequest_FieldViolation {
+	if !composition.Rings.Valid {
+		return nil
+	}
+	if err := pbx.CompositionToGeneratorConfig(composition).ValidateRings(); err != nil {
+		return []*errdetails.BadRequest_FieldViolation{pbErrors.FieldViolation(field, err)}
+	}
+	if composition.NailsQuantity > maxCompositionNails {
+		return []*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation(field, fmt.Errorf("the rings have %d nails, at most %d are allowed", composition.NailsQuantity, maxCompositionNails)),
+		}
+	}
+	return nil
+}
+
+// validateSymmetry checks the nails of every ring split evenly in the sectors
+// of the symmetry of a composition
+func validateSymmetry(composition *models.Composition, field string) []*errdetails.BadRequest_FieldViolation {
+	if err := pbx.CompositionToGeneratorConfig(composition).ValidateSymmetry(); err != nil {
+		return []*errdetails.BadRequest_FieldViolation{pbErrors.FieldViolation(field, err)}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	"github.com/Damione1/thread-art-generator/core/pb"
+	"github.com/Damione1/thread-art-generator/core/queue"
+)
+
+// processingMessage matches the queue message of a composition
+type processingMessage struct {
+	artID         string
+	compositionID *string
+}
+
+func (m processingMessage) Match(value driver.Value) bool {
+	data, ok := value.([]byte)
+	if !ok {
+		return false
+	}
+	var message queue.CompositionProcessingMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return false
+	}
+	*m.compositionID = message.CompositionID
+	return message.Type == queue.MessageTypeCompositionProcessing && message.ArtID == m.artID
+}
+
+func createCompositionRequest() *pb.CreateCompositionRequest {
+	return &pb.CreateCompositionRequest{
+		Parent: "users/author/arts/art",
+		Composition: &pb.Composition{
+			NailsQuantity:     300,
+			ImgSize:           800,
+			MaxPaths:          1000,
+			MinimumDifference: 10,
+			BrightnessFactor:  50,
+			ImageContrast:     40,
+			PhysicalRadius:    300,
+			Priority:          pb.CompositionPriority_COMPOSITION_PRIORITY_INTERACTIVE,
+		},
+	}
+}
+
+// insertedComposition returns the database defaults of an inserted composition
+func insertedComposition() *sqlmock.Rows {
+	columns := []string{
+		"starting_nail", "preview_url", "gcode_url", "pathlist_url", "thread_length", "total_lines",
+		"error_message", "drill_gcode_url", "sweep_id", "similarity_score", "spool_length", "segments",
+		"rings", "thread_profile_id", "linear_light", "analytics", "heatmap_url", "seed", "content_hash",
+		"symmetry_fold", "mirror_symmetry", "progress",
+	}
+	defaults := map[string]driver.Value{
+		"starting_nail": 0, "spool_length": 0.0, "linear_light": false, "seed": 0,
+		"symmetry_fold": 0, "mirror_symmetry": false,
+	}
+	values := make([]driver.Value, len(columns))
+	for i, column := range columns {
+		values[i] = defaults[column]
+	}
+	return sqlmock.NewRows(columns).AddRow(values...)
+}
+
+// expectArt expects the lookup of the art of the author, with an image
+func expectArt(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT "arts"\.\* FROM "arts"`).
+		WithArgs("art", "author").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "image_id"}).AddRow("art", "author", "image"))
+}
+
+const interactiveCountQuery = `SELECT COUNT\(\*\) FROM "compositions" INNER JOIN arts ON arts\.id = compositions\.art_id AND arts\.author_id = \$1 ` +
+	`WHERE \("compositions"\."priority" = \$2\) AND \("compositions"\."status" IN \(\$3,\$4\)\)`
+
+// expectInteractive expects the count of the interactive compositions of the
+// author pending or processing
+func expectInteractive(mock sqlmock.Sqlmock, count int) {
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\(\$1\)\)`).
+		WithArgs("interactive:author").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(interactiveCountQuery).
+		WithArgs("author", models.CompositionPriorityEnumINTERACTIVE, models.CompositionStatusEnumPENDING, models.CompositionStatusEnumPROCESSING).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+func TestCreateCompositionEnqueuesInTransaction(t *testing.T) {
+	server, mock, ctx := newTestServer(t, "author-uid")
+	server.queueClient = &fakeQueueClient{}
+	expectUser(mock, "author-uid", "author", models.RoleEnumUser)
+	expectArt(mock)
+
+	// The composition and its message are committed together, the relay
+	// publishes the message
+	var messageID string
+	mock.ExpectBegin()
+	expectInteractive(mock, 1)
+	mock.ExpectQuery(`INSERT INTO "compositions"`).
+		WillReturnRows(insertedComposition())
+	mock.ExpectQuery(`INSERT INTO "outbox_messages" \("id","queue","payload","created_at"\)`).
+		WithArgs(sqlmock.AnyArg(), "composition-processing.interactive", processingMessage{artID: "art", compositionID: &messageID}, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"attempts", "last_error", "next_attempt_at"}).AddRow(0, nil, time.Now()))
+	mock.ExpectCommit()
+
+	composition, err := server.CreateComposition(ctx, createCompositionRequest())
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Equal(t, "users/author/arts/art/compositions/"+messageID, composition.GetName(), "the message is for the inserted composition")
+	require.Equal(t, pb.CompositionStatus_COMPOSITION_STATUS_PENDING, composition.GetStatus())
+}
+
+func TestCreateCompositionRollsBackWithoutMessage(t *testing.T) {
+	server, mock, ctx := newTestServer(t, "author-uid")
+	expectUser(mock, "author-uid", "author", models.RoleEnumUser)
+	expectArt(mock)
+
+	// A composition whose message can't be stored is not created
+	mock.ExpectBegin()
+	expectInteractive(mock, 0)
+	mock.ExpectQuery(`INSERT INTO "compositions"`).
+		WillReturnRows(insertedComposition())
+	mock.ExpectQuery(`INSERT INTO "outbox_messages"`).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	_, err := server.CreateComposition(ctx, createCompositionRequest())
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateCompositionBoundsInteractive(t *testing.T) {
+	server, mock, ctx := newTestServer(t, "author-uid")
+	server.queueClient = &fakeQueueClient{}
+	expectUser(mock, "author-uid", "author", models.RoleEnumUser)
+	expectArt(mock)
+
+	// The author already has the most interactive compositions waiting, the
+	// new one is a render and does not go ahead of the renders of other users
+	mock.ExpectBegin()
+	expectInteractive(mock, maxInteractiveCompositions)
+	mock.ExpectQuery(`INSERT INTO "compositions"`).
+		WillReturnRows(insertedComposition())
+	mock.ExpectQuery(`INSERT INTO "outbox_messages"`).
+		WithArgs(sqlmock.AnyArg(), "composition-processing", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"attempts", "last_error", "next_attempt_at"}).AddRow(0, nil, time.Now()))
+	mock.ExpectCommit()
+
+	composition, err := server.CreateComposition(ctx, createCompositionRequest())
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, pb.CompositionPriority_COMPOSITION_PRIORITY_RENDER, composition.GetPriority())
+}
+
+const cancelQuery = `UPDATE "compositions" SET "status" = \$1 WHERE \("compositions"\."id" = \$2\) AND \("compositions"\."status" IN \(\$3,\$4\)\)`
+
+// expectComposition expects the lookup of a composition of the author with its art
+func expectComposition(mock sqlmock.Sqlmock, status models.CompositionStatusEnum) {
+	mock.ExpectQuery(`SELECT "compositions"\.\* FROM "compositions" INNER JOIN arts`).
+		WithArgs("author", "composition", "art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "art_id", "status"}).AddRow("composition", "art", status))
+	mock.ExpectQuery(`SELECT \* FROM "arts"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "image_id"}).AddRow("art", "author", "image"))
+}
+
+func TestCancelComposition(t *testing.T) {
+	for _, current := range []models.CompositionStatusEnum{models.CompositionStatusEnumPENDING, models.CompositionStatusEnumPROCESSING} {
+		t.Run(string(current), func(t *testing.T) {
+			server, mock, ctx := newTestServer(t, "author-uid")
+			expectUser(mock, "author-uid", "author", models.RoleEnumUser)
+			expectComposition(mock, current)
+			mock.ExpectExec(cancelQuery).
+				WithArgs(models.CompositionStatusEnumCANCELLED, "composition", models.CompositionStatusEnumPENDING, models.CompositionStatusEnumPROCESSING).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			composition, err := server.CancelComposition(ctx, &pb.CancelCompositionRequest{Name: "users/author/arts/art/compositions/composition"})
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+			require.Equal(t, pb.CompositionStatus_COMPOSITION_STATUS_CANCELLED, composition.GetStatus())
+		})
+	}
+}
+
+func TestCancelFinishedComposition(t *testing.T) {
+	server, mock, ctx := newTestServer(t, "author-uid")
+	expectUser(mock, "author-uid", "author", models.RoleEnumUser)
+	expectComposition(mock, models.CompositionStatusEnumCOMPLETE)
+	// The worker may complete the composition after the lookup, only the update
+	// tells whether it was still unfinished
+	mock.ExpectExec(cancelQuery).
+		WithArgs(models.CompositionStatusEnumCANCELLED, "composition", models.CompositionStatusEnumPENDING, models.CompositionStatusEnumPROCESSING).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err := server.CancelComposition(ctx, &pb.CancelCompositionRequest{Name: "users/author/arts/art/compositions/composition"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCompositionCalibrationGcodeMultiRing(t *testing.T) {
+	server, mock, ctx := newTestServer(t, "author-uid")
+	expectUser(mock, "author-uid", "author", models.RoleEnumUser)
+	mock.ExpectQuery(`SELECT "compositions"\.\* FROM "compositions" INNER JOIN arts`).
+		WithArgs("author", "composition", "art").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "art_id", "nails_quantity", "physical_radius", "rings"}).
+			AddRow("composition", "art", 300, 300.0, `[{"nails_quantity":200,"radius":300},{"nails_quantity":100,"radius":150}]`))
+
+	// Calibrating a multi ring layout is refused for the composition, it is
+	// not a server failure
+	_, err := server.GetCompositionCalibrationGcode(ctx, &pb.GetCompositionCalibrationGcodeRequest{
+		Name:    "users/author/arts/art/compositions/composition",
+		Routine: pb.CalibrationRoutine_CALIBRATION_ROUTINE_ROTARY,
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
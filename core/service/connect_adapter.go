@@ -174,6 +174,33 @@ func (a *ConnectAdapter) ListCompositions(ctx context.Context, req *connect.Requ
 	return connect.NewResponse(response), nil
 }
 
+// GetCompositionGcodeFromStep implements the Connect handler interface
+func (a *ConnectAdapter) GetCompositionGcodeFromStep(ctx context.Context, req *connect.Request[pb.GetCompositionGcodeFromStepRequest]) (*connect.Response[pb.GetCompositionGcodeFromStepResponse], error) {
+	response, err := a.server.GetCompositionGcodeFromStep(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(response), nil
+}
+
+// GetCompositionCalibrationGcode implements the Connect handler interface
+func (a *ConnectAdapter) GetCompositionCalibrationGcode(ctx context.Context, req *connect.Request[pb.GetCompositionCalibrationGcodeRequest]) (*connect.Response[pb.GetCompositionCalibrationGcodeResponse], error) {
+	response, err := a.server.GetCompositionCalibrationGcode(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(response), nil
+}
+
+// CancelComposition implements the Connect handler interface
+func (a *ConnectAdapter) CancelComposition(ctx context.Context, req *connect.Request[pb.CancelCompositionRequest]) (*connect.Response[pb.Composition], error) {
+	response, err := a.server.CancelComposition(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(response), nil
+}
+
 // DeleteComposition implements the Connect handler interface
 func (a *ConnectAdapter) DeleteComposition(ctx context.Context, req *connect.Request[pb.DeleteCompositionRequest]) (*connect.Response[emptypb.Empty], error) {
 	_, err := a.server.DeleteComposition(ctx, req.Msg)
@@ -182,3 +209,66 @@ func (a *ConnectAdapter) DeleteComposition(ctx context.Context, req *connect.Req
 	}
 	return connect.NewResponse(&emptypb.Empty{}), nil
 }
+
+// CreateParameterSweep implements the Connect handler interface
+func (a *ConnectAdapter) CreateParameterSweep(ctx context.Context, req *connect.Request[pb.CreateParameterSweepRequest]) (*connect.Response[pb.ParameterSweep], error) {
+	response, err := a.server.CreateParameterSweep(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(response), nil
+}
+
+// GetParameterSweep implements the Connect handler interface
+func (a *ConnectAdapter) GetParameterSweep(ctx context.Context, req *connect.Request[pb.GetParameterSweepRequest]) (*connect.Response[pb.ParameterSweep], error) {
+	response, err := a.server.GetParameterSweep(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(response), nil
+}
+
+// CreateThreadProfile implements the Connect handler interface
+func (a *ConnectAdapter) CreateThreadProfile(ctx context.Context, req *connect.Request[pb.CreateThreadProfileRequest]) (*connect.Response[pb.ThreadProfile], error) {
+	response, err := a.server.CreateThreadProfile(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(response), nil
+}
+
+// GetThreadProfile implements the Connect handler interface
+func (a *ConnectAdapter) GetThreadProfile(ctx context.Context, req *connect.Request[pb.GetThreadProfileRequest]) (*connect.Response[pb.ThreadProfile], error) {
+	response, err := a.server.GetThreadProfile(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(response), nil
+}
+
+// ListThreadProfiles implements the Connect handler interface
+func (a *ConnectAdapter) ListThreadProfiles(ctx context.Context, req *connect.Request[pb.ListThreadProfilesRequest]) (*connect.Response[pb.ListThreadProfilesResponse], error) {
+	response, err := a.server.ListThreadProfiles(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(response), nil
+}
+
+// DeleteThreadProfile implements the Connect handler interface
+func (a *ConnectAdapter) DeleteThreadProfile(ctx context.Context, req *connect.Request[pb.DeleteThreadProfileRequest]) (*connect.Response[emptypb.Empty], error) {
+	_, err := a.server.DeleteThreadProfile(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&emptypb.Empty{}), nil
+}
+
+// GetQueueStats implements the Connect handler interface
+func (a *ConnectAdapter) GetQueueStats(ctx context.Context, req *connect.Request[pb.GetQueueStatsRequest]) (*connect.Response[pb.QueueStats], error) {
+	response, err := a.server.GetQueueStats(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(response), nil
+}
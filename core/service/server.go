@@ -13,6 +13,7 @@ import (
 	"github.com/Damione1/thread-art-generator/core/queue"
 	"github.com/Damione1/thread-art-generator/core/storage"
 	"github.com/Damione1/thread-art-generator/core/util"
+	"github.com/Damione1/thread-art-generator/threadGenerator/fluidnc"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"github.com/volatiletech/null/v8"
@@ -24,6 +25,14 @@ type Server struct {
 	storage     *storage.DualBucketStorage
 	mailService mailService.MailService
 	queueClient queue.QueueClient
+
+	// outbox publishes the queued compositions, nil without a queue
+	outbox     *queue.OutboxRelay
+	stopOutbox context.CancelFunc
+	outboxDone chan struct{}
+
+	// machineProfile bounds the generated calibration programs, nil when not configured
+	machineProfile *fluidnc.Profile
 }
 
 func NewServer(config util.Config) (*Server, error) {
@@ -50,6 +59,24 @@ func NewServer(config util.Config) (*Server, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to create queue client: %v", err)
 		}
+
+		// Publish the outbox in the background
+		server.outbox = queue.NewOutboxRelay(config.DB, server.queueClient, config.Queue.OutboxPollInterval)
+		var relayCtx context.Context
+		relayCtx, server.stopOutbox = context.WithCancel(context.Background())
+		server.outboxDone = make(chan struct{})
+		go func() {
+			defer close(server.outboxDone)
+			server.outbox.Run(relayCtx)
+		}()
+	}
+
+	// Load the machine profile if configured
+	if config.Machine.ProfilePath != "" {
+		server.machineProfile, err = fluidnc.LoadProfile(config.Machine.ProfilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load machine profile: %v", err)
+		}
 	}
 
 	return server, nil
@@ -65,6 +92,12 @@ func (s *Server) Close() error {
 		}
 	}
 
+	// Stop the outbox relay before its queue connection
+	if s.outbox != nil {
+		s.stopOutbox()
+		<-s.outboxDone
+	}
+
 	// Close queue connection
 	if s.queueClient != nil {
 		if queueErr := s.queueClient.Close(); queueErr != nil {
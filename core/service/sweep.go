@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	pbErrors "github.com/Damione1/thread-art-generator/core/errors"
+	"github.com/Damione1/thread-art-generator/core/middleware"
+	"github.com/Damione1/thread-art-generator/core/pb"
+	"github.com/Damione1/thread-art-generator/core/pbx"
+	"github.com/Damione1/thread-art-generator/core/resource"
+	"github.com/bufbuild/protovalidate-go"
+	"github.com/friendsofgo/errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// maxSweepCompositions bounds the combinations of a sweep, each one is a full generation on the workers
+const maxSweepCompositions = 36
+
+// sweepParameterLimits are the largest values of the swept parameters, matching the composition validation
+var sweepParameterLimits = map[string]struct {
+	max     float64
+	integer bool
+}{
+	pbx.SweepParameterBrightnessFactor:  {max: 255, integer: true},
+	pbx.SweepParameterImageContrast:     {max: 100},
+	pbx.SweepParameterMinimumDifference: {max: 200, integer: true},
+	pbx.SweepParameterMaxPaths:          {max: 20000, integer: true},
+}
+
+// CreateParameterSweep creates a sweep and enqueues a composition for every combination of the swept parameters
+func (server *Server) CreateParameterSweep(ctx context.Context, req *pb.CreateParameterSweepRequest) (*pb.ParameterSweep, error) {
+	// Get Firebase UID from context
+	firebaseUID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, pbErrors.PermissionDeniedError("user not authenticated")
+	}
+
+	// Get internal user from Firebase UID
+	user, err := server.getUserFromFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		log.Error().Err(err).Str("firebase_uid", firebaseUID).Msg("CreateParameterSweep: Failed to get user from Firebase UID")
+		return nil, pbErrors.InternalError("failed to get user", err)
+	}
+
+	// Validate the request
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, pbErrors.ConvertProtoValidateError(err)
+	}
+
+	if req.GetParameterSweep().GetBaseComposition() == nil {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("parameter_sweep.base_composition", errors.New("base composition is required")),
+		})
+	}
+
+	ranges, violations := sweepRangesFromProto(req.GetParameterSweep().GetRanges())
+	if len(violations) > 0 {
+		return nil, pbErrors.InvalidArgumentError(violations)
+	}
+
+	base := pbx.ProtoCompositionToDb(req.GetParameterSweep().GetBaseComposition())
+	if violations := validateNailRings(base, "parameter_sweep.base_composition.rings"); len(violations) > 0 {
+		return nil, pbErrors.InvalidArgumentError(violations)
+	}
+
+	// Parse the art resource name to get the art ID
+	artResource, err := resource.ParseResourceName(req.GetParent())
+	if err != nil {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("parent", errors.New("invalid resource name")),
+		})
+	}
+
+	art, ok := artResource.(*resource.Art)
+	if !ok {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("parent", errors.New("invalid art resource name")),
+		})
+	}
+
+	// Verify the user is authorized to create a sweep for this art
+	if art.UserID != user.ID {
+		return nil, pbErrors.PermissionDeniedError("only the author can create parameter sweeps for this art")
+	}
+
+	artDb, err := models.Arts(
+		models.ArtWhere.ID.EQ(art.ArtID),
+		models.ArtWhere.AuthorID.EQ(user.ID),
+	).One(ctx, server.config.DB)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pbErrors.NotFoundError("art not found or you don't have permission to create parameter sweeps for it")
+		}
+		return nil, pbErrors.InternalError("failed to get art", err)
+	}
+
+	// Check if the art has an image
+	if !artDb.ImageID.Valid {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("parent", errors.New("art must have an image to create parameter sweeps")),
+		})
+	}
+
+	base.ThreadProfileID, err = server.resolveThreadProfileID(ctx, user.ID, req.GetParameterSweep().GetBaseComposition().GetThreadProfile(), "parameter_sweep.base_composition.thread_profile")
+	if err != nil {
+		return nil, err
+	}
+
+	rangesJSON, err := json.Marshal(ranges)
+	if err != nil {
+		return nil, pbErrors.InternalError("failed to encode sweep ranges", err)
+	}
+
+	sweepDb := &models.ParameterSweep{
+		ID:     uuid.New().String(),
+		ArtID:  artDb.ID,
+		Status: models.ParameterSweepStatusEnumPENDING,
+		Ranges: rangesJSON,
+	}
+
+	// One composition per combination, the sweep and its compositions are created together
+	var compositions []*models.Composition
+	for _, combination := range sweepCombinations(ranges) {
+		composition := *base
+		composition.ID = uuid.New().String()
+		composition.ArtID = artDb.ID
+		composition.Status = models.CompositionStatusEnumPENDING
+		composition.SweepID = null.StringFrom(sweepDb.ID)
+		composition.Priority = models.CompositionPriorityEnumBATCH
+		for i, value := range combination {
+			if err := pbx.SetSweepParameterValue(&composition, ranges[i].Parameter, value); err != nil {
+				return nil, pbErrors.InternalError("failed to apply sweep parameter", err)
+			}
+		}
+		// Swept nail counts must still split in the sectors of the symmetry
+		if violations := validateSymmetry(&composition, "parameter_sweep.ranges"); len(violations) > 0 {
+			return nil, pbErrors.InvalidArgumentError(violations)
+		}
+		compositions = append(compositions, &composition)
+	}
+
+	tx, err := server.config.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, pbErrors.InternalError("failed to start transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := sweepDb.Insert(ctx, tx, boil.Infer()); err != nil {
+		return nil, pbErrors.InternalError("failed to insert parameter sweep", err)
+	}
+	for _, composition := range compositions {
+		if err := composition.Insert(ctx, tx, boil.Infer()); err != nil {
+			return nil, pbErrors.InternalError("failed to insert sweep composition", err)
+		}
+		if err := server.enqueueCompositionForProcessing(ctx, tx, composition, artDb); err != nil {
+			return nil, pbErrors.InternalError("failed to enqueue sweep composition", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, pbErrors.InternalError("failed to commit parameter sweep", err)
+	}
+	server.notifyOutbox()
+
+	return pbx.ParameterSweepDbToProto(ctx, server.storage, artDb, sweepDb, compositions), nil
+}
+
+// GetParameterSweep retrieves a sweep with its compositions ranked by score
+func (server *Server) GetParameterSweep(ctx context.Context, req *pb.GetParameterSweepRequest) (*pb.ParameterSweep, error) {
+	// Get Firebase UID from context
+	firebaseUID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, pbErrors.PermissionDeniedError("user not authenticated")
+	}
+
+	// Get internal user from Firebase UID
+	user, err := server.getUserFromFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		log.Error().Err(err).Str("firebase_uid", firebaseUID).Msg("GetParameterSweep: Failed to get user from Firebase UID")
+		return nil, pbErrors.InternalError("failed to get user", err)
+	}
+
+	// Validate the request
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, pbErrors.ConvertProtoValidateError(err)
+	}
+
+	// Parse the sweep resource name
+	sweepResource, err := resource.ParseResourceName(req.GetName())
+	if err != nil {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("name", errors.New("invalid resource name")),
+		})
+	}
+
+	sweep, ok := sweepResource.(*resource.ParameterSweep)
+	if !ok {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("name", errors.New("invalid parameter sweep resource name")),
+		})
+	}
+
+	// Verify the user is authorized to get this sweep
+	if sweep.UserID != user.ID {
+		return nil, pbErrors.PermissionDeniedError("only the author can get this parameter sweep")
+	}
+
+	// Get the sweep with art using join to verify ownership
+	sweepDb, err := models.ParameterSweeps(
+		models.ParameterSweepWhere.ID.EQ(sweep.SweepID),
+		models.ParameterSweepWhere.ArtID.EQ(sweep.ArtID),
+		qm.InnerJoin("arts ON arts.id = parameter_sweeps.art_id AND arts.author_id = ?", user.ID),
+		qm.Load(models.ParameterSweepRels.Art),
+		qm.Load(models.ParameterSweepRels.SweepCompositions),
+	).One(ctx, server.config.DB)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pbErrors.NotFoundError("parameter sweep not found or you don't have permission to view it")
+		}
+		return nil, pbErrors.InternalError("failed to get parameter sweep", err)
+	}
+
+	return pbx.ParameterSweepDbToProto(ctx, server.storage, sweepDb.R.Art, sweepDb, sweepDb.R.SweepCompositions), nil
+}
+
+// sweepRangesFromProto checks the requested ranges against the composition
+// limits and the maximum number of combinations
+func sweepRangesFromProto(rangesPb []*pb.ParameterRange) ([]pbx.SweepRange, []*errdetails.BadRequest_FieldViolation) {
+	var (
+		ranges       []pbx.SweepRange
+		violations   []*errdetails.BadRequest_FieldViolation
+		seen         = map[string]bool{}
+		combinations = 1
+	)
+	for i, rangePb := range rangesPb {
+		field := fmt.Sprintf("parameter_sweep.ranges[%d]", i)
+		parameter, ok := pbx.SweepParameterProtoToDb(rangePb.GetParameter())
+		if !ok {
+			violations = append(violations, pbErrors.FieldViolation(field+".parameter", errors.New("unknown sweep parameter")))
+			continue
+		}
+		if seen[parameter] {
+			violations = append(violations, pbErrors.FieldViolation(field+".parameter", fmt.Errorf("%s is swept more than once", parameter)))
+			continue
+		}
+		seen[parameter] = true
+
+		limits := sweepParameterLimits[parameter]
+		if rangePb.GetMax() > limits.max {
+			violations = append(violations, pbErrors.FieldViolation(field+".max", fmt.Errorf("%s must be at most %g", parameter, limits.max)))
+			continue
+		}
+		if limits.integer && (rangePb.GetMin() != math.Trunc(rangePb.GetMin()) || rangePb.GetStep() != math.Trunc(rangePb.GetStep())) {
+			violations = append(violations, pbErrors.FieldViolation(field, fmt.Errorf("%s takes whole numbers, min and step must be integers", parameter)))
+			continue
+		}
+
+		sweepRange := pbx.SweepRange{
+			Parameter: parameter,
+			Min:       rangePb.GetMin(),
+			Max:       rangePb.GetMax(),
+			Step:      rangePb.GetStep(),
+		}
+		// Counted without building the values, capped past the limit so the
+		// product can't overflow
+		combinations = min(combinations*sweepRange.Count(), maxSweepCompositions+1)
+		ranges = append(ranges, sweepRange)
+	}
+
+	if len(violations) == 0 && combinations > maxSweepCompositions {
+		violations = append(violations, pbErrors.FieldViolation("parameter_sweep.ranges", fmt.Errorf("the ranges make more than %d compositions", maxSweepCompositions)))
+	}
+	return ranges, violations
+}
+
+// sweepCombinations returns every combination of the range values, one value per range in order
+func sweepCombinations(ranges []pbx.SweepRange) [][]float64 {
+	combinations := [][]float64{{}}
+	for _, sweepRange := range ranges {
+		var next [][]float64
+		for _, combination := range combinations {
+			for _, value := range sweepRange.Values() {
+				next = append(next, append(append([]float64(nil), combination...), value))
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
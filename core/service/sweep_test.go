@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Damione1/thread-art-generator/core/pb"
+)
+
+func TestSweepRangesFromProto(t *testing.T) {
+	ranges, violations := sweepRangesFromProto([]*pb.ParameterRange{
+		{Parameter: pb.SweepParameter_SWEEP_PARAMETER_IMAGE_CONTRAST, Min: 0, Max: 1, Step: 0.1},
+		{Parameter: pb.SweepParameter_SWEEP_PARAMETER_MAX_PATHS, Min: 1000, Max: 3000, Step: 1000},
+	})
+	require.Empty(t, violations)
+	require.Len(t, ranges, 2)
+	require.Equal(t, 11, ranges[0].Count(), "float steps reach max")
+	require.Len(t, ranges[0].Values(), 11)
+	require.Len(t, sweepCombinations(ranges), 33)
+
+	_, violations = sweepRangesFromProto([]*pb.ParameterRange{
+		{Parameter: pb.SweepParameter_SWEEP_PARAMETER_IMAGE_CONTRAST, Min: 0, Max: 1, Step: 0.1},
+		{Parameter: pb.SweepParameter_SWEEP_PARAMETER_MAX_PATHS, Min: 1000, Max: 4000, Step: 1000},
+	})
+	require.Len(t, violations, 1)
+	require.Equal(t, "parameter_sweep.ranges", violations[0].GetField())
+}
+
+func TestSweepRangesFromProtoTinyStep(t *testing.T) {
+	// Would be 1e11 values, rejected from the count without building them
+	_, violations := sweepRangesFromProto([]*pb.ParameterRange{
+		{Parameter: pb.SweepParameter_SWEEP_PARAMETER_IMAGE_CONTRAST, Min: 0, Max: 100, Step: 1e-9},
+		{Parameter: pb.SweepParameter_SWEEP_PARAMETER_BRIGHTNESS_FACTOR, Min: 0, Max: 255, Step: 1},
+	})
+	require.Len(t, violations, 1)
+	require.Contains(t, violations[0].GetDescription(), "more than 36 compositions")
+
+	_, violations = sweepRangesFromProto([]*pb.ParameterRange{
+		{Parameter: pb.SweepParameter_SWEEP_PARAMETER_IMAGE_CONTRAST, Min: 0, Max: 100, Step: 1e-300},
+	})
+	require.Len(t, violations, 1)
+}
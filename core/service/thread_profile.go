@@ -0,0 +1,285 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/Damione1/thread-art-generator/core/db/models"
+	pbErrors "github.com/Damione1/thread-art-generator/core/errors"
+	"github.com/Damione1/thread-art-generator/core/middleware"
+	"github.com/Damione1/thread-art-generator/core/pb"
+	"github.com/Damione1/thread-art-generator/core/pbx"
+	"github.com/Damione1/thread-art-generator/core/resource"
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+	"github.com/bufbuild/protovalidate-go"
+	"github.com/disintegration/imaging"
+	"github.com/friendsofgo/errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// CreateThreadProfile measures the bands of a calibration photo and stores the fitted thread profile
+func (server *Server) CreateThreadProfile(ctx context.Context, req *pb.CreateThreadProfileRequest) (*pb.ThreadProfile, error) {
+	// Get Firebase UID from context
+	firebaseUID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, pbErrors.PermissionDeniedError("user not authenticated")
+	}
+
+	// Get internal user from Firebase UID
+	user, err := server.getUserFromFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		log.Error().Err(err).Str("firebase_uid", firebaseUID).Msg("CreateThreadProfile: Failed to get user from Firebase UID")
+		return nil, pbErrors.InternalError("failed to get user", err)
+	}
+
+	// Validate the request
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, pbErrors.ConvertProtoValidateError(err)
+	}
+
+	// Verify the user is creating the profile for themselves
+	if resource.ExtractUserID(req.GetParent()) != user.ID {
+		return nil, pbErrors.PermissionDeniedError("thread profiles can only be created for your own account")
+	}
+
+	profilePb := req.GetThreadProfile()
+	photo, err := imaging.Decode(bytes.NewReader(profilePb.GetCalibrationPhoto()), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("thread_profile.calibration_photo", errors.New("calibration photo must be a PNG or JPEG image")),
+		})
+	}
+
+	bands, err := threadGenerator.MeasureCalibrationBands(photo, profilePb.GetDensities())
+	if err != nil {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("thread_profile.densities", err),
+		})
+	}
+
+	fitted, fitError, err := threadGenerator.FitThreadProfile(bands)
+	if err != nil {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation("thread_profile.densities", err),
+		})
+	}
+
+	exists, err := models.ThreadProfiles(
+		models.ThreadProfileWhere.UserID.EQ(user.ID),
+		models.ThreadProfileWhere.Name.EQ(profilePb.GetDisplayName()),
+	).Exists(ctx, server.config.DB)
+	if err != nil {
+		return nil, pbErrors.InternalError("failed to check thread profile name", err)
+	}
+	if exists {
+		return nil, pbErrors.AlreadyExistsError("a thread profile with this name already exists", "thread_profile.display_name")
+	}
+
+	bandsJSON, err := json.Marshal(bands)
+	if err != nil {
+		return nil, pbErrors.InternalError("failed to encode calibration bands", err)
+	}
+
+	profileDb := &models.ThreadProfile{
+		ID:          uuid.New().String(),
+		UserID:      user.ID,
+		Name:        profilePb.GetDisplayName(),
+		Opacity:     fitted.Opacity,
+		ThreadWidth: fitted.Width,
+		Bands:       bandsJSON,
+		FitError:    fitError,
+	}
+	if err := profileDb.Insert(ctx, server.config.DB, boil.Infer()); err != nil {
+		return nil, pbErrors.InternalError("failed to insert thread profile", err)
+	}
+
+	log.Info().
+		Str("threadProfileID", profileDb.ID).
+		Float64("opacity", fitted.Opacity).
+		Float64("threadWidth", fitted.Width).
+		Float64("fitError", fitError).
+		Msg("Fitted thread profile")
+
+	return pbx.ThreadProfileDbToProto(profileDb), nil
+}
+
+// GetThreadProfile retrieves a thread profile by name
+func (server *Server) GetThreadProfile(ctx context.Context, req *pb.GetThreadProfileRequest) (*pb.ThreadProfile, error) {
+	// Get Firebase UID from context
+	firebaseUID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, pbErrors.PermissionDeniedError("user not authenticated")
+	}
+
+	// Get internal user from Firebase UID
+	user, err := server.getUserFromFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		log.Error().Err(err).Str("firebase_uid", firebaseUID).Msg("GetThreadProfile: Failed to get user from Firebase UID")
+		return nil, pbErrors.InternalError("failed to get user", err)
+	}
+
+	// Validate the request
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, pbErrors.ConvertProtoValidateError(err)
+	}
+
+	profileDb, err := server.getUserThreadProfile(ctx, user.ID, req.GetName(), "name")
+	if err != nil {
+		return nil, err
+	}
+
+	return pbx.ThreadProfileDbToProto(profileDb), nil
+}
+
+// ListThreadProfiles lists the thread profiles of a user by name
+func (server *Server) ListThreadProfiles(ctx context.Context, req *pb.ListThreadProfilesRequest) (*pb.ListThreadProfilesResponse, error) {
+	// Get Firebase UID from context
+	firebaseUID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, pbErrors.PermissionDeniedError("user not authenticated")
+	}
+
+	// Get internal user from Firebase UID
+	user, err := server.getUserFromFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		log.Error().Err(err).Str("firebase_uid", firebaseUID).Msg("ListThreadProfiles: Failed to get user from Firebase UID")
+		return nil, pbErrors.InternalError("failed to get user", err)
+	}
+
+	// Validate the request
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, pbErrors.ConvertProtoValidateError(err)
+	}
+
+	// Verify the user is listing their own profiles
+	if resource.ExtractUserID(req.GetParent()) != user.ID {
+		return nil, pbErrors.PermissionDeniedError("only the owner can list these thread profiles")
+	}
+
+	const defaultPageSize = 50
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	// Parse page token to get offset
+	offset := 0
+	if req.GetPageToken() != "" {
+		offset, err = parseInt32PageToken(req.GetPageToken())
+		if err != nil {
+			return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+				pbErrors.FieldViolation("page_token", errors.New("invalid page token")),
+			})
+		}
+	}
+
+	profiles, err := models.ThreadProfiles(
+		models.ThreadProfileWhere.UserID.EQ(user.ID),
+		qm.OrderBy(models.ThreadProfileColumns.Name+" ASC"),
+		qm.Limit(pageSize+1),
+		qm.Offset(offset),
+	).All(ctx, server.config.DB)
+	if err != nil {
+		return nil, pbErrors.InternalError("failed to get thread profiles", err)
+	}
+
+	response := &pb.ListThreadProfilesResponse{}
+	if len(profiles) > pageSize {
+		profiles = profiles[:pageSize]
+		response.NextPageToken = createPageToken(offset + pageSize)
+	}
+	for _, profileDb := range profiles {
+		response.ThreadProfiles = append(response.ThreadProfiles, pbx.ThreadProfileDbToProto(profileDb))
+	}
+
+	return response, nil
+}
+
+// DeleteThreadProfile deletes a thread profile, the compositions using it fall back to the default darkening
+func (server *Server) DeleteThreadProfile(ctx context.Context, req *pb.DeleteThreadProfileRequest) (*emptypb.Empty, error) {
+	// Get Firebase UID from context
+	firebaseUID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil, pbErrors.PermissionDeniedError("user not authenticated")
+	}
+
+	// Get internal user from Firebase UID
+	user, err := server.getUserFromFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		log.Error().Err(err).Str("firebase_uid", firebaseUID).Msg("DeleteThreadProfile: Failed to get user from Firebase UID")
+		return nil, pbErrors.InternalError("failed to get user", err)
+	}
+
+	// Validate the request
+	if err := protovalidate.Validate(req); err != nil {
+		return nil, pbErrors.ConvertProtoValidateError(err)
+	}
+
+	profileDb, err := server.getUserThreadProfile(ctx, user.ID, req.GetName(), "name")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := profileDb.Delete(ctx, server.config.DB); err != nil {
+		return nil, pbErrors.InternalError("failed to delete thread profile", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// getUserThreadProfile loads a thread profile by resource name, the field is
+// the request field holding the name for the error details
+func (server *Server) getUserThreadProfile(ctx context.Context, userID string, name string, field string) (*models.ThreadProfile, error) {
+	profileResource, err := resource.ParseResourceName(name)
+	if err != nil {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation(field, errors.New("invalid resource name")),
+		})
+	}
+
+	profile, ok := profileResource.(*resource.ThreadProfile)
+	if !ok {
+		return nil, pbErrors.InvalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			pbErrors.FieldViolation(field, errors.New("invalid thread profile resource name")),
+		})
+	}
+
+	// Verify the user owns this profile
+	if profile.UserID != userID {
+		return nil, pbErrors.PermissionDeniedError("only the owner can use this thread profile")
+	}
+
+	profileDb, err := models.ThreadProfiles(
+		models.ThreadProfileWhere.ID.EQ(profile.ThreadProfileID),
+		models.ThreadProfileWhere.UserID.EQ(userID),
+	).One(ctx, server.config.DB)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pbErrors.NotFoundError("thread profile not found or you don't have permission to use it")
+		}
+		return nil, pbErrors.InternalError("failed to get thread profile", err)
+	}
+
+	return profileDb, nil
+}
+
+// resolveThreadProfileID returns the ID of the thread profile a composition
+// refers to, null when the composition uses the default darkening
+func (server *Server) resolveThreadProfileID(ctx context.Context, userID string, name string, field string) (null.String, error) {
+	if name == "" {
+		return null.String{}, nil
+	}
+	profileDb, err := server.getUserThreadProfile(ctx, userID, name, field)
+	if err != nil {
+		return null.String{}, err
+	}
+	return null.StringFrom(profileDb.ID), nil
+}
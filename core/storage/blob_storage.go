@@ -216,11 +216,12 @@ func (b *BlobStorage) s3SignedURL(ctx context.Context, key string, opts *blob.Si
 		
 		// Validate image content types
 		validImageTypes := map[string]bool{
-			"image/jpeg": true,
-			"image/jpg":  true,
-			"image/png":  true,
-			"image/gif":  true,
-			"image/webp": true,
+			"image/jpeg":    true,
+			"image/jpg":     true,
+			"image/png":     true,
+			"image/gif":     true,
+			"image/webp":    true,
+			"image/svg+xml": true,
 		}
 		
 		if !validImageTypes[opts.ContentType] {
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 
+	"gocloud.dev/blob/memblob"
+
 	"github.com/Damione1/thread-art-generator/core/util"
 )
 
@@ -58,6 +60,15 @@ func NewDualBucketStorage(ctx context.Context, config util.StorageConfig) (*Dual
 	}, nil
 }
 
+// NewMemoryDualBucketStorage creates a dual bucket storage keeping both
+// buckets in memory, for tests
+func NewMemoryDualBucketStorage() *DualBucketStorage {
+	return &DualBucketStorage{
+		publicStorage:  &BlobStorage{Bucket: memblob.OpenBucket(nil)},
+		privateStorage: &BlobStorage{Bucket: memblob.OpenBucket(nil)},
+	}
+}
+
 // GetPublicStorage returns the public bucket storage for CDN-cacheable content
 func (d *DualBucketStorage) GetPublicStorage() *BlobStorage {
 	return d.publicStorage
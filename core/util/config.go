@@ -3,6 +3,7 @@ package util
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -32,10 +33,26 @@ type StorageConfig struct {
 
 // QueueConfig stores queue-specific configuration
 type QueueConfig struct {
-	URL                   string `mapstructure:"RABBITMQ_URL"`
-	User                  string `mapstructure:"RABBITMQ_USER"`
-	Password              string `mapstructure:"RABBITMQ_PASSWORD"`
-	CompositionProcessing string `mapstructure:"QUEUE_COMPOSITION_PROCESSING"`
+	URL                   string        `mapstructure:"RABBITMQ_URL"`
+	User                  string        `mapstructure:"RABBITMQ_USER"`
+	Password              string        `mapstructure:"RABBITMQ_PASSWORD"`
+	CompositionProcessing string        `mapstructure:"QUEUE_COMPOSITION_PROCESSING"`
+	MaxAttempts           int           `mapstructure:"QUEUE_MAX_ATTEMPTS"`         // Attempts of a message before it is dead-lettered
+	RetryBaseDelay        time.Duration `mapstructure:"QUEUE_RETRY_BASE_DELAY"`     // Wait after the first failed attempt, doubled after each next one
+	WorkerConcurrency     int           `mapstructure:"QUEUE_WORKER_CONCURRENCY"`   // Jobs a worker processes at once
+	WorkerMemoryMB        int           `mapstructure:"QUEUE_WORKER_MEMORY_MB"`     // Memory the jobs of a worker may hold together, 0 for no limit
+	MaxJobsPerUser        int           `mapstructure:"QUEUE_MAX_JOBS_PER_USER"`    // Compositions of a user processing at once across the workers
+	DeferDelay            time.Duration `mapstructure:"QUEUE_DEFER_DELAY"`          // Wait of a message whose user is at the cap before it is tried again
+	OutboxPollInterval    time.Duration `mapstructure:"QUEUE_OUTBOX_POLL_INTERVAL"` // Wait between two checks of the outbox for messages to publish
+}
+
+// MachineConfig stores the machine service configuration
+type MachineConfig struct {
+	ControllerAddress string `mapstructure:"MACHINE_CONTROLLER_ADDRESS"`
+	ServerPort        string `mapstructure:"MACHINE_SERVER_PORT"`
+	StateDir          string `mapstructure:"MACHINE_STATE_DIR"`
+	RxBufferSize      int    `mapstructure:"MACHINE_RX_BUFFER_SIZE"`
+	ProfilePath       string `mapstructure:"MACHINE_PROFILE_PATH"`
 }
 
 // Config stores all configuration of the application.
@@ -63,6 +80,7 @@ type Config struct {
 	Firebase            FirebaseConfig `mapstructure:",squash"`
 	Storage             StorageConfig  `mapstructure:",squash"`
 	Queue               QueueConfig    `mapstructure:",squash"`
+	Machine             MachineConfig  `mapstructure:",squash"`
 }
 
 // LoadConfig reads configuration from file or environment variables.
@@ -112,6 +130,20 @@ func LoadConfig() (config Config, err error) {
 	viper.BindEnv("RABBITMQ_USER")
 	viper.BindEnv("RABBITMQ_PASSWORD")
 	viper.BindEnv("QUEUE_COMPOSITION_PROCESSING")
+	viper.BindEnv("QUEUE_MAX_ATTEMPTS")
+	viper.BindEnv("QUEUE_RETRY_BASE_DELAY")
+	viper.BindEnv("QUEUE_WORKER_CONCURRENCY")
+	viper.BindEnv("QUEUE_WORKER_MEMORY_MB")
+	viper.BindEnv("QUEUE_MAX_JOBS_PER_USER")
+	viper.BindEnv("QUEUE_DEFER_DELAY")
+	viper.BindEnv("QUEUE_OUTBOX_POLL_INTERVAL")
+
+	// Machine configuration
+	viper.BindEnv("MACHINE_CONTROLLER_ADDRESS")
+	viper.BindEnv("MACHINE_SERVER_PORT")
+	viper.BindEnv("MACHINE_STATE_DIR")
+	viper.BindEnv("MACHINE_RX_BUFFER_SIZE")
+	viper.BindEnv("MACHINE_PROFILE_PATH")
 
 	if err = viper.Unmarshal(&config); err != nil {
 		return Config{}, fmt.Errorf("failed to unmarshal config: %w", err)
@@ -154,6 +186,37 @@ func (c *Config) applyDefaults() {
 	if c.Storage.PrivateBucket == "" {
 		c.Storage.PrivateBucket = "local-private"
 	}
+
+	// Queue defaults
+	if c.Queue.MaxAttempts <= 0 {
+		c.Queue.MaxAttempts = 5
+	}
+	if c.Queue.RetryBaseDelay <= 0 {
+		c.Queue.RetryBaseDelay = 5 * time.Second
+	}
+	if c.Queue.WorkerConcurrency <= 0 {
+		c.Queue.WorkerConcurrency = 1
+	}
+	if c.Queue.WorkerMemoryMB < 0 {
+		c.Queue.WorkerMemoryMB = 0
+	}
+	if c.Queue.MaxJobsPerUser <= 0 {
+		c.Queue.MaxJobsPerUser = 2
+	}
+	if c.Queue.DeferDelay <= 0 {
+		c.Queue.DeferDelay = 15 * time.Second
+	}
+	if c.Queue.OutboxPollInterval <= 0 {
+		c.Queue.OutboxPollInterval = 5 * time.Second
+	}
+
+	// Machine defaults
+	if c.Machine.ServerPort == "" {
+		c.Machine.ServerPort = "9092"
+	}
+	if c.Machine.StateDir == "" {
+		c.Machine.StateDir = "/var/lib/thread-art-machine"
+	}
 }
 
 // GetPostgresDSN builds the PostgreSQL connection string from configuration
@@ -0,0 +1,190 @@
+package threadGenerator
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+const (
+	// heaviestNailsCount is the number of nails listed as the most loaded
+	heaviestNailsCount = 10
+	// overloadedNailRatio is the share of the mean wraps above which a nail
+	// carries enough tension to risk pulling out
+	overloadedNailRatio = 2.0
+	// histogramBins is the number of bins of the hits and chord length histograms
+	histogramBins = 10
+	// heatmapCells is the number of cells across the density heatmap, pixels are
+	// grouped so the map shows the density of the board rather than single lines
+	heatmapCells = 64
+)
+
+type (
+	// PathAnalytics reports how a paths list loads the nails and covers the board
+	PathAnalytics struct {
+		NailHits        []int          `json:"nail_hits"`         // Wraps on each nail, by nail number
+		HitsHistogram   []HistogramBin `json:"hits_histogram"`    // Nails by number of wraps
+		MeanHits        float64        `json:"mean_hits"`         // Mean wraps per nail
+		HeaviestNails   []NailLoad     `json:"heaviest_nails"`    // Most wrapped nails, the most loaded first
+		OverloadedNails int            `json:"overloaded_nails"`  // Nails wrapped more than twice the mean
+		ChordLengths    []HistogramBin `json:"chord_lengths"`     // Paths by chord length in mm
+		MeanChordLength float64        `json:"mean_chord_length"` // Mean chord length in mm
+	}
+
+	// HistogramBin counts the values from Min included to Max excluded, the
+	// last bin of a histogram also holds its Max
+	HistogramBin struct {
+		Min   float64 `json:"min"`
+		Max   float64 `json:"max"`
+		Count int     `json:"count"`
+	}
+
+	// NailLoad is the number of wraps on a nail
+	NailLoad struct {
+		Nail  int     `json:"nail"`  // Nail number of the paths list
+		Ring  int     `json:"ring"`  // Ring of the nail, 0 for single ring layouts
+		Index int     `json:"index"` // Index of the nail on its ring
+		Hits  int     `json:"hits"`  // Wraps on the nail
+		Ratio float64 `json:"ratio"` // Wraps relative to the mean of all nails
+	}
+)
+
+// GetPathAnalytics returns the analytics of the generated paths list
+func (tg *ThreadGenerator) GetPathAnalytics() PathAnalytics {
+	return AnalyzePaths(tg.pathsList, tg.nailRings())
+}
+
+// AnalyzePaths computes the analytics of a paths list on the ring geometry, so
+// they also hold for a paths list loaded from storage. The thread wraps the
+// starting nail of the first path then the ending nail of every path.
+func AnalyzePaths(paths []Path, rings []Ring) PathAnalytics {
+	nailsQuantity := 0
+	for _, ring := range rings {
+		nailsQuantity += ring.NailsQuantity
+	}
+
+	analytics := PathAnalytics{NailHits: make([]int, nailsQuantity)}
+	wrap := func(nail int) {
+		if nail >= 0 && nail < nailsQuantity {
+			analytics.NailHits[nail]++
+		}
+	}
+	lengths := make([]float64, len(paths))
+	for i, path := range paths {
+		if i == 0 {
+			wrap(path.StartingNail)
+		}
+		wrap(path.EndingNail)
+		lengths[i] = chordLength(rings, path.StartingNail, path.EndingNail)
+		analytics.MeanChordLength += lengths[i]
+	}
+	if len(paths) > 0 {
+		analytics.MeanChordLength /= float64(len(paths))
+	}
+
+	hits := make([]float64, nailsQuantity)
+	totalHits := 0
+	for nail, count := range analytics.NailHits {
+		hits[nail] = float64(count)
+		totalHits += count
+	}
+	if nailsQuantity > 0 {
+		analytics.MeanHits = float64(totalHits) / float64(nailsQuantity)
+	}
+
+	loads := make([]NailLoad, nailsQuantity)
+	for nail, count := range analytics.NailHits {
+		ring, index := locateNail(rings, nail)
+		loads[nail] = NailLoad{Nail: nail, Ring: ring, Index: index, Hits: count}
+		if analytics.MeanHits > 0 {
+			loads[nail].Ratio = float64(count) / analytics.MeanHits
+			if loads[nail].Ratio > overloadedNailRatio {
+				analytics.OverloadedNails++
+			}
+		}
+	}
+	sort.SliceStable(loads, func(i, j int) bool { return loads[i].Hits > loads[j].Hits })
+	analytics.HeaviestNails = loads[:min(heaviestNailsCount, len(loads))]
+
+	maxHits := 0.0
+	for _, count := range hits {
+		maxHits = math.Max(maxHits, count)
+	}
+	analytics.HitsHistogram = histogram(hits, 0, maxHits)
+
+	// The longest chord is the diameter of the outer ring
+	maxRadius := 0.0
+	for _, ring := range rings {
+		maxRadius = math.Max(maxRadius, ring.Radius)
+	}
+	analytics.ChordLengths = histogram(lengths, 0, 2*maxRadius)
+
+	return analytics
+}
+
+// histogram counts the values in bins of equal width between low and high
+func histogram(values []float64, low, high float64) []HistogramBin {
+	if high <= low {
+		high = low + 1
+	}
+	width := (high - low) / histogramBins
+	bins := make([]HistogramBin, histogramBins)
+	for i := range bins {
+		bins[i] = HistogramBin{Min: low + float64(i)*width, Max: low + float64(i+1)*width}
+	}
+	for _, value := range values {
+		bin := int((value - low) / width)
+		bins[max(0, min(histogramBins-1, bin))].Count++
+	}
+	return bins
+}
+
+// GenerateDensityHeatmap renders the density of lines across the board, from
+// black where no thread crosses to white on the densest cell
+func (tg *ThreadGenerator) GenerateDensityHeatmap() (image.Image, error) {
+	if len(tg.pathsDictionary) == 0 {
+		return nil, errors.New("Dictionary is empty")
+	}
+
+	bounds := image.Rect(0, 0, tg.imgSize, tg.imgSize)
+	lineCounts := tg.pathsLineCounts(bounds)
+
+	// Mean number of lines crossing the pixels of each cell
+	cells := min(heatmapCells, tg.imgSize)
+	density := make([]float64, cells*cells)
+	pixels := make([]int, cells*cells)
+	for y := 0; y < tg.imgSize; y++ {
+		for x := 0; x < tg.imgSize; x++ {
+			cell := (y*cells/tg.imgSize)*cells + x*cells/tg.imgSize
+			density[cell] += float64(lineCounts[y*tg.imgSize+x])
+			pixels[cell]++
+		}
+	}
+	maxDensity := 0.0
+	for i := range density {
+		density[i] /= float64(pixels[i])
+		maxDensity = math.Max(maxDensity, density[i])
+	}
+
+	heatmap := image.NewNRGBA(bounds)
+	for y := 0; y < tg.imgSize; y++ {
+		for x := 0; x < tg.imgSize; x++ {
+			value := 0.0
+			if maxDensity > 0 {
+				value = density[(y*cells/tg.imgSize)*cells+x*cells/tg.imgSize] / maxDensity
+			}
+			heatmap.SetNRGBA(x, y, heatColor(value))
+		}
+	}
+	return heatmap, nil
+}
+
+// heatColor maps a density from 0 to 1 to black, red, yellow then white
+func heatColor(value float64) color.NRGBA {
+	channel := func(start float64) uint8 {
+		return uint8(math.Round(255 * math.Min(1, math.Max(0, (value-start)*3))))
+	}
+	return color.NRGBA{R: channel(0), G: channel(1.0 / 3), B: channel(2.0 / 3), A: 255}
+}
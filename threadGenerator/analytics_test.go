@@ -0,0 +1,66 @@
+package threadGenerator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzePaths(t *testing.T) {
+	rings := []Ring{{NailsQuantity: 10, Radius: 100}}
+	// Back and forth between nails 0 and 5 then once to nail 2
+	paths := []Path{
+		{StartingNail: 0, EndingNail: 5},
+		{StartingNail: 5, EndingNail: 0},
+		{StartingNail: 0, EndingNail: 5},
+		{StartingNail: 5, EndingNail: 2},
+	}
+
+	analytics := AnalyzePaths(paths, rings)
+	require.Equal(t, []int{2, 0, 1, 0, 0, 2, 0, 0, 0, 0}, analytics.NailHits)
+	require.InDelta(t, 0.5, analytics.MeanHits, 1e-9)
+	require.Equal(t, 2, analytics.OverloadedNails, "nails 0 and 5 carry four times the mean")
+	require.Len(t, analytics.HeaviestNails, 10)
+	require.Equal(t, NailLoad{Nail: 0, Hits: 2, Ratio: 4}, analytics.HeaviestNails[0])
+	require.Equal(t, NailLoad{Nail: 5, Index: 5, Hits: 2, Ratio: 4}, analytics.HeaviestNails[1])
+
+	hitsTotal, chordsTotal := 0, 0
+	for _, bin := range analytics.HitsHistogram {
+		hitsTotal += bin.Count
+	}
+	for _, bin := range analytics.ChordLengths {
+		chordsTotal += bin.Count
+	}
+	require.Equal(t, 10, hitsTotal, "every nail is in the hits histogram")
+	require.Equal(t, len(paths), chordsTotal, "every path is in the chord lengths histogram")
+	require.Equal(t, 3, analytics.ChordLengths[len(analytics.ChordLengths)-1].Count, "diameters fall in the last bin")
+	require.InDelta(t, 200.0, analytics.ChordLengths[len(analytics.ChordLengths)-1].Max, 1e-9)
+}
+
+func TestAnalyzePathsOnRings(t *testing.T) {
+	rings := []Ring{{NailsQuantity: 8, Radius: 100}, {NailsQuantity: 4, Radius: 50}}
+	paths := []Path{{StartingNail: 0, EndingNail: 9}, {StartingNail: 9, EndingNail: 4}}
+
+	analytics := AnalyzePaths(paths, rings)
+	require.Len(t, analytics.NailHits, 12)
+	require.Equal(t, NailLoad{Nail: 9, Ring: 1, Index: 1, Hits: 1, Ratio: 4}, analytics.HeaviestNails[2], "ties keep the nail order")
+}
+
+func TestDensityHeatmap(t *testing.T) {
+	tg := NewThreadGenerator(Config{NailsQuantity: 40, ImgSize: 100, PhysicalRadius: 100})
+	_, err := tg.GenerateDensityHeatmap()
+	require.Error(t, err, "the heatmap needs the lines dictionary")
+
+	tg.generateDictionary(tg.getNailsListFromImage(gradientImage(100)))
+	tg.SetPathsList([]Path{{StartingNail: 0, EndingNail: 20}, {StartingNail: 20, EndingNail: 10}, {StartingNail: 10, EndingNail: 30}})
+
+	heatmap, err := tg.GenerateDensityHeatmap()
+	require.NoError(t, err)
+	require.Equal(t, 100, heatmap.Bounds().Dx())
+
+	// The center is crossed by both diameters, a corner by none
+	r, _, _, _ := heatmap.At(50, 50).RGBA()
+	require.NotZero(t, r)
+	r, g, b, _ := heatmap.At(0, 0).RGBA()
+	require.Zero(t, r+g+b)
+}
@@ -0,0 +1,191 @@
+package threadGenerator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCalibrationMultiRing is returned when calibrating a multi ring layout
+var ErrCalibrationMultiRing = errors.New("calibration runs on single ring layouts, calibrate with the outer ring only")
+
+// CalibrationRoutine is a program run on a new machine before the first piece
+type CalibrationRoutine string
+
+const (
+	// CalibrationRotary visits the quarter nails to verify the rotary steps per nail
+	CalibrationRotary CalibrationRoutine = "rotary"
+	// CalibrationNeedleDepth steps the needle through depths next to a nail
+	CalibrationNeedleDepth CalibrationRoutine = "needle_depth"
+	// CalibrationBacklash approaches a nail from both sides to measure the rotary backlash
+	CalibrationBacklash CalibrationRoutine = "backlash"
+	// CalibrationSpindleDrill drills a few holes in a scrap ring
+	CalibrationSpindleDrill CalibrationRoutine = "spindle_drill"
+)
+
+// CalibrationRoutines lists every routine, in the order they should be run on a new build
+var CalibrationRoutines = []CalibrationRoutine{
+	CalibrationRotary,
+	CalibrationNeedleDepth,
+	CalibrationBacklash,
+	CalibrationSpindleDrill,
+}
+
+// CalibrationSettings are the machine specific values of the calibration
+// programs, the defaults match the moves of the stringing and drilling programs
+type CalibrationSettings struct {
+	RotaryFeedRate   int       // Feed rate between nails
+	NeedleFeedRate   int       // Feed rate of the needle
+	NeedleClear      float64   // Needle position where the ring turns without catching the nails
+	NeedleDepths     []float64 // Needle positions tested by the depth routine, the deepest is where the thread wraps
+	BacklashDistance float64   // Nails travelled before coming back to the reference nail
+	BacklashSweeps   int       // Back and forth sweeps of the backlash routine
+	DrillFeedRateIn  int       // Plunge feed rate of the spindle
+	DrillFeedRateOut int       // Retract feed rate of the spindle
+	DrillDepth       float64   // Spindle position at the bottom of a hole
+	DrillRetracted   float64   // Spindle position clear of the ring
+	DrillHoles       int       // Holes drilled in the scrap ring
+}
+
+// DefaultCalibrationSettings returns the settings matching the generated programs
+func DefaultCalibrationSettings() CalibrationSettings {
+	return CalibrationSettings{
+		RotaryFeedRate:   3000,
+		NeedleFeedRate:   2000,
+		NeedleClear:      0,
+		NeedleDepths:     []float64{-2, -4, -6, -8, -10},
+		BacklashDistance: 1,
+		BacklashSweeps:   3,
+		DrillFeedRateIn:  170,
+		DrillFeedRateOut: 1000,
+		DrillDepth:       -3.2,
+		DrillRetracted:   -0.5,
+		DrillHoles:       3,
+	}
+}
+
+// ParseCalibrationRoutine returns the routine matching a name
+func ParseCalibrationRoutine(name string) (CalibrationRoutine, error) {
+	for _, routine := range CalibrationRoutines {
+		if string(routine) == name {
+			return routine, nil
+		}
+	}
+	return "", fmt.Errorf("unknown calibration routine %q", name)
+}
+
+// GetCalibrationGcode returns a calibration program rendered for the configured dialect
+func (tg *ThreadGenerator) GetCalibrationGcode(routine CalibrationRoutine, settings CalibrationSettings) ([]string, error) {
+	program, err := tg.GetCalibrationProgram(routine, settings)
+	if err != nil {
+		return nil, err
+	}
+	return tg.gcodeWriter().Write(program), nil
+}
+
+// GetCalibrationProgram builds the dialect independent calibration program
+func (tg *ThreadGenerator) GetCalibrationProgram(routine CalibrationRoutine, settings CalibrationSettings) (*GcodeProgram, error) {
+	if tg.multiRing() {
+		return nil, ErrCalibrationMultiRing
+	}
+	if tg.nailsQuantity < 4 {
+		return nil, fmt.Errorf("calibration needs at least 4 nails, got %d", tg.nailsQuantity)
+	}
+
+	program := &GcodeProgram{}
+	program.Comment(fmt.Sprintf("Calibration: %s on %d nails", routine, tg.nailsQuantity))
+
+	switch routine {
+	case CalibrationRotary:
+		tg.rotaryCalibration(program, settings)
+	case CalibrationNeedleDepth:
+		if len(settings.NeedleDepths) == 0 {
+			return nil, fmt.Errorf("needle depth calibration needs at least one depth")
+		}
+		tg.needleDepthCalibration(program, settings)
+	case CalibrationBacklash:
+		if settings.BacklashDistance <= 0 || settings.BacklashSweeps <= 0 {
+			return nil, fmt.Errorf("backlash calibration needs a positive distance and sweeps")
+		}
+		tg.backlashCalibration(program, settings)
+	case CalibrationSpindleDrill:
+		if settings.DrillHoles <= 0 {
+			return nil, fmt.Errorf("spindle drill calibration needs at least one hole")
+		}
+		tg.spindleDrillCalibration(program, settings)
+	default:
+		return nil, fmt.Errorf("unknown calibration routine %q", routine)
+	}
+	return program, nil
+}
+
+// rotaryCalibration visits nails 0, N/4, N/2 and 3N/4 then completes the turn,
+// the needle must point at the same spot of each nail if the steps per nail are right
+func (tg *ThreadGenerator) rotaryCalibration(program *GcodeProgram, settings CalibrationSettings) {
+	program.Home(AxisValue{tg.needleAxis, 5}, AxisValue{tg.spindleAxis, 0}, AxisValue{tg.rotationAxis, 0})
+	program.Move(settings.NeedleFeedRate, "Clear the nails", AxisValue{tg.needleAxis, settings.NeedleClear})
+
+	for quarter := 0; quarter < 4; quarter++ {
+		nail := quarter * tg.nailsQuantity / 4
+		program.Move(settings.RotaryFeedRate, fmt.Sprintf("Move to nail %d", nail), AxisValue{tg.rotationAxis, float64(nail)})
+		program.Pause(fmt.Sprintf("Check the needle points at nail %d", nail))
+	}
+
+	program.Move(settings.RotaryFeedRate, "Complete the turn", AxisValue{tg.rotationAxis, float64(tg.nailsQuantity)})
+	program.Pause("Check the needle points at nail 0 again, adjust the steps per nail if it drifted")
+	program.SetPosition("Back to nail 0", AxisValue{tg.rotationAxis, 0})
+}
+
+// needleDepthCalibration lowers the needle between two nails at each depth so
+// the operator checks where the thread catches the nails without hitting the ring
+func (tg *ThreadGenerator) needleDepthCalibration(program *GcodeProgram, settings CalibrationSettings) {
+	program.Home(AxisValue{tg.needleAxis, 5}, AxisValue{tg.spindleAxis, 0}, AxisValue{tg.rotationAxis, 0})
+	program.Move(settings.NeedleFeedRate, "Clear the nails", AxisValue{tg.needleAxis, settings.NeedleClear})
+
+	// Between two nails, where the needle goes down during a wrap
+	program.Move(settings.RotaryFeedRate, "Move between nail 0 and 1", AxisValue{tg.rotationAxis, 0.5})
+	for _, depth := range settings.NeedleDepths {
+		program.Move(settings.NeedleFeedRate, fmt.Sprintf("Needle at %g", depth), AxisValue{tg.needleAxis, depth})
+		program.Pause(fmt.Sprintf("Needle at %g: check the thread goes below the nail heads without touching the ring", depth))
+		program.Move(settings.NeedleFeedRate, "Clear the nails", AxisValue{tg.needleAxis, settings.NeedleClear})
+	}
+}
+
+// backlashCalibration stops on the same nail coming alternately from below and
+// above, the gap between both stops is the rotary backlash
+func (tg *ThreadGenerator) backlashCalibration(program *GcodeProgram, settings CalibrationSettings) {
+	reference := float64(tg.nailsQuantity / 2)
+	below := reference - settings.BacklashDistance
+	above := reference + settings.BacklashDistance
+
+	program.Home(AxisValue{tg.needleAxis, 5}, AxisValue{tg.spindleAxis, 0}, AxisValue{tg.rotationAxis, 0})
+	program.Move(settings.NeedleFeedRate, "Clear the nails", AxisValue{tg.needleAxis, settings.NeedleClear})
+
+	program.Move(settings.RotaryFeedRate, "", AxisValue{tg.rotationAxis, below})
+	program.Move(settings.RotaryFeedRate, fmt.Sprintf("Approach nail %g from below", reference), AxisValue{tg.rotationAxis, reference})
+	program.Pause("Mark where the needle points, this is the reference")
+
+	for sweep := 1; sweep <= settings.BacklashSweeps; sweep++ {
+		program.Move(settings.RotaryFeedRate, "", AxisValue{tg.rotationAxis, above})
+		program.Move(settings.RotaryFeedRate, fmt.Sprintf("Approach nail %g from above", reference), AxisValue{tg.rotationAxis, reference})
+		program.Pause(fmt.Sprintf("Sweep %d: measure the gap to the mark, it is the backlash", sweep))
+
+		program.Move(settings.RotaryFeedRate, "", AxisValue{tg.rotationAxis, below})
+		program.Move(settings.RotaryFeedRate, fmt.Sprintf("Approach nail %g from below", reference), AxisValue{tg.rotationAxis, reference})
+		program.Pause(fmt.Sprintf("Sweep %d: the needle must be back on the mark", sweep))
+	}
+}
+
+// spindleDrillCalibration drills a few holes spread on a scrap ring to check
+// the spindle depth and the feed rates before drilling the real ring
+func (tg *ThreadGenerator) spindleDrillCalibration(program *GcodeProgram, settings CalibrationSettings) {
+	program.Home(AxisValue{tg.spindleAxis, 0}, AxisValue{tg.rotationAxis, 0})
+	program.Pause("Mount a scrap ring and start the spindle")
+
+	for i := 0; i < settings.DrillHoles; i++ {
+		nail := i * tg.nailsQuantity / settings.DrillHoles
+		program.Move(settings.RotaryFeedRate, fmt.Sprintf("Move to nail %d", nail), AxisValue{tg.rotationAxis, float64(nail)})
+		program.Move(settings.DrillFeedRateIn, fmt.Sprintf("Drill hole at nail %d", nail), AxisValue{tg.spindleAxis, settings.DrillDepth})
+		program.Move(settings.DrillFeedRateOut, "Retract spindle", AxisValue{tg.spindleAxis, settings.DrillRetracted})
+	}
+	program.Pause("Check the depth and the edges of the holes")
+}
@@ -0,0 +1,65 @@
+package threadGenerator
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+
+	"github.com/disintegration/imaging"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	contactSheetMargin     = 8
+	contactSheetLineHeight = 15
+)
+
+// ContactSheetTile is one image of a contact sheet with its caption, one line per "\n"
+type ContactSheetTile struct {
+	Image   image.Image
+	Caption string
+}
+
+// ContactSheet lays the tiles out on a grid of columns, in order, each image
+// scaled to tileSize with its caption below it
+func ContactSheet(tiles []ContactSheetTile, columns, tileSize int) image.Image {
+	if columns < 1 {
+		columns = 1
+	}
+	if columns > len(tiles) {
+		columns = max(len(tiles), 1)
+	}
+	rows := (len(tiles) + columns - 1) / columns
+
+	captionLines := 0
+	for _, tile := range tiles {
+		captionLines = max(captionLines, len(strings.Split(tile.Caption, "\n")))
+	}
+	cellWidth := tileSize + contactSheetMargin
+	cellHeight := tileSize + captionLines*contactSheetLineHeight + contactSheetMargin
+
+	sheet := image.NewRGBA(image.Rect(0, 0, columns*cellWidth+contactSheetMargin, rows*cellHeight+contactSheetMargin))
+	draw.Draw(sheet, sheet.Bounds(), image.White, image.Point{}, draw.Src)
+
+	drawer := &font.Drawer{
+		Dst:  sheet,
+		Src:  image.NewUniform(color.Black),
+		Face: basicfont.Face7x13,
+	}
+	for i, tile := range tiles {
+		left := contactSheetMargin + (i%columns)*cellWidth
+		top := contactSheetMargin + (i/columns)*cellHeight
+
+		scaled := imaging.Fit(tile.Image, tileSize, tileSize, imaging.Lanczos)
+		draw.Draw(sheet, image.Rect(left, top, left+tileSize, top+tileSize), scaled, image.Point{}, draw.Src)
+
+		for line, text := range strings.Split(tile.Caption, "\n") {
+			drawer.Dot = fixed.P(left, top+tileSize+(line+1)*contactSheetLineHeight-3)
+			drawer.DrawString(text)
+		}
+	}
+	return sheet
+}
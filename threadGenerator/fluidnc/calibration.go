@@ -0,0 +1,46 @@
+package fluidnc
+
+import (
+	"math"
+
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+)
+
+// CalibrationSettings returns the calibration settings bounded by the axes of
+// the profile, so the programs never exceed a rate or a travel the firmware refuses
+func (p *Profile) CalibrationSettings() threadGenerator.CalibrationSettings {
+	settings := threadGenerator.DefaultCalibrationSettings()
+
+	if rotation := p.Axis(RoleRotation); rotation != nil {
+		settings.RotaryFeedRate = boundedRate(settings.RotaryFeedRate, rotation.MaxRate)
+	}
+
+	if needle := p.Axis(RoleNeedle); needle != nil {
+		settings.NeedleFeedRate = boundedRate(settings.NeedleFeedRate, needle.MaxRate)
+		if needle.MaxTravel > 0 {
+			depths := make([]float64, 0, len(settings.NeedleDepths))
+			for _, depth := range settings.NeedleDepths {
+				if depth >= -needle.MaxTravel {
+					depths = append(depths, depth)
+				}
+			}
+			settings.NeedleDepths = depths
+		}
+	}
+
+	if spindle := p.Axis(RoleSpindle); spindle != nil {
+		settings.DrillFeedRateIn = boundedRate(settings.DrillFeedRateIn, spindle.MaxRate)
+		settings.DrillFeedRateOut = boundedRate(settings.DrillFeedRateOut, spindle.MaxRate)
+		if spindle.MaxTravel > 0 {
+			settings.DrillDepth = math.Max(settings.DrillDepth, -spindle.MaxTravel)
+		}
+	}
+	return settings
+}
+
+func boundedRate(rate int, maxRate float64) int {
+	if maxRate > 0 && float64(rate) > maxRate {
+		return int(maxRate)
+	}
+	return rate
+}
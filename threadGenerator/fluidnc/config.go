@@ -0,0 +1,201 @@
+package fluidnc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+	"gopkg.in/yaml.v3"
+)
+
+// The FluidNC configuration schema, fields are declared in the order the
+// firmware documentation lists them so the output reads like a hand written file
+type (
+	config struct {
+		Board    string      `yaml:"board"`
+		Name     string      `yaml:"name"`
+		Meta     string      `yaml:"meta,omitempty"`
+		I2SO     *I2SO       `yaml:"i2so,omitempty"`
+		SPI      *SPI        `yaml:"spi,omitempty"`
+		SDCard   *SDCard     `yaml:"sdcard,omitempty"`
+		Stepping Stepping    `yaml:"stepping"`
+		Axes     axesConfig  `yaml:"axes"`
+		Macros   *Macros     `yaml:"macros,omitempty"`
+		Start    startConfig `yaml:"start"`
+	}
+
+	axesConfig struct {
+		X *axisConfig `yaml:"x,omitempty"`
+		Y *axisConfig `yaml:"y,omitempty"`
+		Z *axisConfig `yaml:"z,omitempty"`
+		A *axisConfig `yaml:"a,omitempty"`
+		B *axisConfig `yaml:"b,omitempty"`
+		C *axisConfig `yaml:"c,omitempty"`
+	}
+
+	axisConfig struct {
+		StepsPerMm   float64      `yaml:"steps_per_mm"`
+		MaxRate      float64      `yaml:"max_rate_mm_per_min"`
+		Acceleration float64      `yaml:"acceleration_mm_per_sec2"`
+		MaxTravel    float64      `yaml:"max_travel_mm"`
+		SoftLimits   bool         `yaml:"soft_limits"`
+		Homing       *Homing      `yaml:"homing,omitempty"`
+		Motor0       *motorConfig `yaml:"motor0"`
+	}
+
+	motorConfig struct {
+		LimitNegPin string         `yaml:"limit_neg_pin,omitempty"`
+		LimitPosPin string         `yaml:"limit_pos_pin,omitempty"`
+		LimitAllPin string         `yaml:"limit_all_pin,omitempty"`
+		HardLimits  bool           `yaml:"hard_limits,omitempty"`
+		PulloffMm   float64        `yaml:"pulloff_mm,omitempty"`
+		Stepstick   *stepstick     `yaml:"stepstick,omitempty"`
+		RcServo     *rcServoConfig `yaml:"rc_servo,omitempty"`
+	}
+
+	stepstick struct {
+		StepPin      string `yaml:"step_pin"`
+		DirectionPin string `yaml:"direction_pin"`
+		DisablePin   string `yaml:"disable_pin,omitempty"`
+	}
+
+	rcServoConfig struct {
+		PwmHz      int    `yaml:"pwm_hz"`
+		OutputPin  string `yaml:"output_pin"`
+		MinPulseUs int    `yaml:"min_pulse_us"`
+		MaxPulseUs int    `yaml:"max_pulse_us"`
+	}
+
+	startConfig struct {
+		MustHome bool `yaml:"must_home"`
+	}
+)
+
+// Generate validates the profile against the generator configuration and
+// renders the FluidNC config.yaml
+func Generate(profile *Profile, generatorConfig threadGenerator.Config) ([]byte, error) {
+	if err := profile.Validate(generatorConfig); err != nil {
+		return nil, err
+	}
+
+	out := config{
+		Board:    profile.Board,
+		Name:     profile.Name,
+		Meta:     profile.Meta,
+		I2SO:     profile.I2SO,
+		SPI:      profile.SPI,
+		SDCard:   profile.SDCard,
+		Stepping: profile.Stepping,
+		Macros:   profile.Macros,
+		Start:    startConfig{MustHome: profile.MustHome},
+	}
+	for _, axis := range profile.Axes {
+		converted := axisToConfig(axis, profile.NailsQuantity)
+		switch strings.ToUpper(axis.Letter) {
+		case "X":
+			out.Axes.X = converted
+		case "Y":
+			out.Axes.Y = converted
+		case "Z":
+			out.Axes.Z = converted
+		case "A":
+			out.Axes.A = converted
+		case "B":
+			out.Axes.B = converted
+		case "C":
+			out.Axes.C = converted
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated from the %s machine profile by the thread art generator, edit the profile instead\n", profile.Name)
+	fmt.Fprintf(&buf, "# Axes: %s\n", axesSummary(profile))
+	fmt.Fprintf(&buf, "# Documentation: http://wiki.fluidnc.com/\n")
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(out); err != nil {
+		return nil, fmt.Errorf("failed to encode FluidNC config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode FluidNC config: %w", err)
+	}
+	// yaml.v3 quotes y as it is a boolean in YAML 1.1, the FluidNC parser only accepts plain keys
+	return bytes.ReplaceAll(buf.Bytes(), []byte("\n  \"y\":\n"), []byte("\n  y:\n")), nil
+}
+
+func axisToConfig(axis Axis, nailsQuantity int) *axisConfig {
+	converted := &axisConfig{
+		StepsPerMm:   axis.StepsPerMm,
+		MaxRate:      axis.MaxRate,
+		Acceleration: axis.Acceleration,
+		MaxTravel:    axis.MaxTravel,
+		SoftLimits:   axis.SoftLimits,
+		Homing:       axis.Homing,
+		Motor0:       &motorConfig{},
+	}
+
+	// One unit of the rotation axis is one nail so the G-code can address nails directly
+	if axis.Role == RoleRotation {
+		if converted.StepsPerMm == 0 {
+			gearRatio := axis.Stepper.GearRatio
+			if gearRatio == 0 {
+				gearRatio = 1
+			}
+			converted.StepsPerMm = axis.Stepper.StepsPerRevolution * gearRatio / float64(nailsQuantity)
+		}
+		if converted.MaxTravel == 0 {
+			converted.MaxTravel = float64(nailsQuantity)
+		}
+	}
+
+	if axis.Endstop != nil {
+		switch axis.Endstop.Kind {
+		case EndstopNegative:
+			converted.Motor0.LimitNegPin = axis.Endstop.Pin
+		case EndstopPositive:
+			converted.Motor0.LimitPosPin = axis.Endstop.Pin
+		case EndstopAll:
+			converted.Motor0.LimitAllPin = axis.Endstop.Pin
+		}
+		converted.Motor0.HardLimits = axis.Endstop.HardLimits
+		converted.Motor0.PulloffMm = axis.Endstop.PulloffMm
+	}
+
+	if axis.Stepper != nil {
+		converted.Motor0.Stepstick = &stepstick{
+			StepPin:      axis.Stepper.StepPin,
+			DirectionPin: axis.Stepper.DirectionPin,
+			DisablePin:   axis.Stepper.DisablePin,
+		}
+	}
+	if axis.Servo != nil {
+		servo := &rcServoConfig{
+			PwmHz:      axis.Servo.PwmHz,
+			OutputPin:  axis.Servo.OutputPin,
+			MinPulseUs: axis.Servo.MinPulseUs,
+			MaxPulseUs: axis.Servo.MaxPulseUs,
+		}
+		// Standard hobby servo timings
+		if servo.PwmHz == 0 {
+			servo.PwmHz = 50
+		}
+		if servo.MinPulseUs == 0 {
+			servo.MinPulseUs = 1000
+		}
+		if servo.MaxPulseUs == 0 {
+			servo.MaxPulseUs = 2000
+		}
+		converted.Motor0.RcServo = servo
+	}
+	return converted
+}
+
+func axesSummary(profile *Profile) string {
+	parts := make([]string, 0, len(profile.Axes))
+	for _, axis := range profile.Axes {
+		parts = append(parts, fmt.Sprintf("%s is the %s", strings.ToUpper(axis.Letter), axis.Role))
+	}
+	return strings.Join(parts, ", ")
+}
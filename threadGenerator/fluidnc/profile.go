@@ -0,0 +1,347 @@
+// Package fluidnc builds FluidNC configuration files from a machine profile so
+// builders do not have to edit the firmware YAML by hand.
+package fluidnc
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+	"gopkg.in/yaml.v3"
+)
+
+// Axis roles of the string art machine
+const (
+	RoleNeedle   = "needle"
+	RoleSpindle  = "spindle"
+	RoleRotation = "rotation"
+	RoleRing     = "ring" // Moves the needle between the rings of a multi ring frame
+)
+
+// Endstop kinds, matching the FluidNC limit pins
+const (
+	EndstopNegative = "neg"
+	EndstopPositive = "pos"
+	EndstopAll      = "all"
+)
+
+var (
+	axisLetters = []string{"X", "Y", "Z", "A", "B", "C"}
+	pinPattern  = regexp.MustCompile(`^(gpio|i2so)\.\d+(:(low|high|pu|pd))*$`)
+)
+
+type (
+	// Profile describes a machine: its board, the drivers of each axis and how
+	// they are wired
+	Profile struct {
+		Board         string   `yaml:"board"`
+		Name          string   `yaml:"name"`
+		Meta          string   `yaml:"meta,omitempty"`
+		NailsQuantity int      `yaml:"nails_quantity"` // Nails on the ring, one rotation unit is one nail. 360 on a multi ring frame, the unit is a degree
+		I2SO          *I2SO    `yaml:"i2so,omitempty"`
+		SPI           *SPI     `yaml:"spi,omitempty"`
+		SDCard        *SDCard  `yaml:"sdcard,omitempty"`
+		Stepping      Stepping `yaml:"stepping"`
+		Axes          []Axis   `yaml:"axes"`
+		Macros        *Macros  `yaml:"macros,omitempty"`
+		MustHome      bool     `yaml:"must_home"`
+	}
+
+	// I2SO is the I2S output shift register used by some boards for the stepper pins
+	I2SO struct {
+		BckPin  string `yaml:"bck_pin"`
+		DataPin string `yaml:"data_pin"`
+		WsPin   string `yaml:"ws_pin"`
+	}
+
+	// SPI is the bus used by the SD card
+	SPI struct {
+		MisoPin string `yaml:"miso_pin"`
+		MosiPin string `yaml:"mosi_pin"`
+		SckPin  string `yaml:"sck_pin"`
+	}
+
+	// SDCard holds the SD card pins
+	SDCard struct {
+		CsPin         string `yaml:"cs_pin"`
+		CardDetectPin string `yaml:"card_detect_pin,omitempty"`
+	}
+
+	// Stepping holds the step pulse settings
+	Stepping struct {
+		Engine         string `yaml:"engine"`
+		IdleMs         int    `yaml:"idle_ms"`
+		PulseUs        int    `yaml:"pulse_us"`
+		DirDelayUs     int    `yaml:"dir_delay_us"`
+		DisableDelayUs int    `yaml:"disable_delay_us"`
+	}
+
+	// Axis describes one axis of the machine and the motor driving it
+	Axis struct {
+		Letter       string   `yaml:"letter"`
+		Role         string   `yaml:"role"`
+		StepsPerMm   float64  `yaml:"steps_per_mm,omitempty"` // Computed from the gearing for the rotation axis when empty
+		MaxRate      float64  `yaml:"max_rate_mm_per_min"`
+		Acceleration float64  `yaml:"acceleration_mm_per_sec2"`
+		MaxTravel    float64  `yaml:"max_travel_mm,omitempty"` // The number of nails for the rotation axis when empty
+		SoftLimits   bool     `yaml:"soft_limits"`
+		Homing       *Homing  `yaml:"homing,omitempty"`
+		Endstop      *Endstop `yaml:"endstop,omitempty"`
+		Stepper      *Stepper `yaml:"stepper,omitempty"`
+		Servo        *Servo   `yaml:"servo,omitempty"`
+	}
+
+	// Homing holds the homing cycle settings of an axis
+	Homing struct {
+		Cycle             int     `yaml:"cycle"`
+		AllowSingleAxis   bool    `yaml:"allow_single_axis,omitempty"`
+		PositiveDirection bool    `yaml:"positive_direction"`
+		MposMm            float64 `yaml:"mpos_mm"`
+		FeedMmPerMin      float64 `yaml:"feed_mm_per_min,omitempty"`
+		SeekMmPerMin      float64 `yaml:"seek_mm_per_min,omitempty"`
+		SettleMs          int     `yaml:"settle_ms,omitempty"`
+		SeekScaler        float64 `yaml:"seek_scaler,omitempty"`
+		FeedScaler        float64 `yaml:"feed_scaler,omitempty"`
+	}
+
+	// Endstop is the limit switch of an axis
+	Endstop struct {
+		Pin        string  `yaml:"pin"`
+		Kind       string  `yaml:"kind"` // neg, pos or all
+		HardLimits bool    `yaml:"hard_limits,omitempty"`
+		PulloffMm  float64 `yaml:"pulloff_mm,omitempty"`
+	}
+
+	// Stepper is a step/direction driver
+	Stepper struct {
+		StepPin            string  `yaml:"step_pin"`
+		DirectionPin       string  `yaml:"direction_pin"`
+		DisablePin         string  `yaml:"disable_pin,omitempty"`
+		StepsPerRevolution float64 `yaml:"steps_per_revolution,omitempty"` // Motor steps, microstepping included
+		GearRatio          float64 `yaml:"gear_ratio,omitempty"`           // Motor turns per turn of the ring
+	}
+
+	// Servo is an RC servo, used for the needle and the spindle
+	Servo struct {
+		OutputPin  string `yaml:"output_pin"`
+		PwmHz      int    `yaml:"pwm_hz,omitempty"`
+		MinPulseUs int    `yaml:"min_pulse_us,omitempty"`
+		MaxPulseUs int    `yaml:"max_pulse_us,omitempty"`
+	}
+
+	// Macros are the startup lines and the macro buttons of the controller
+	Macros struct {
+		StartupLine0 string `yaml:"startup_line0,omitempty"`
+		StartupLine1 string `yaml:"startup_line1,omitempty"`
+		Macro0       string `yaml:"macro0,omitempty"`
+		Macro1       string `yaml:"macro1,omitempty"`
+		Macro2       string `yaml:"macro2,omitempty"`
+		Macro3       string `yaml:"macro3,omitempty"`
+	}
+
+	// ValidationError lists every problem found in a profile
+	ValidationError struct {
+		Problems []string
+	}
+)
+
+func (e *ValidationError) Error() string {
+	return "invalid machine profile:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// LoadProfile reads a YAML machine profile
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read machine profile: %w", err)
+	}
+	return ParseProfile(data)
+}
+
+// ParseProfile decodes a YAML machine profile, unknown fields are rejected to catch typos
+func ParseProfile(data []byte) (*Profile, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+
+	profile := &Profile{}
+	if err := decoder.Decode(profile); err != nil {
+		return nil, fmt.Errorf("failed to decode machine profile: %w", err)
+	}
+	return profile, nil
+}
+
+// Axis returns the axis with the given role, or nil
+func (p *Profile) Axis(role string) *Axis {
+	for i := range p.Axes {
+		if p.Axes[i].Role == role {
+			return &p.Axes[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks the profile is complete and matches the axes and nails the
+// generator writes G-code for
+func (p *Profile) Validate(config threadGenerator.Config) error {
+	var problems []string
+	addProblem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if p.Board == "" {
+		addProblem("board is required")
+	}
+	if p.NailsQuantity <= 0 {
+		addProblem("nails_quantity must be positive")
+	} else if len(config.NailRings()) > 1 && p.NailsQuantity != config.RotaryUnitsPerTurn() {
+		addProblem("nails_quantity is %d but multi ring layouts turn the ring in degrees, it must be %d", p.NailsQuantity, config.RotaryUnitsPerTurn())
+	} else if len(config.NailRings()) <= 1 && config.NailsQuantity > 0 && p.NailsQuantity != config.NailsQuantity {
+		addProblem("nails_quantity is %d but the generator uses %d nails", p.NailsQuantity, config.NailsQuantity)
+	}
+
+	letters := map[string]bool{}
+	roles := map[string]string{}
+	pins := map[string]string{}
+	usePin := func(owner, pin string, required bool) {
+		if pin == "" {
+			if required {
+				addProblem("%s is required", owner)
+			}
+			return
+		}
+		if !pinPattern.MatchString(pin) {
+			addProblem("%s %q is not a valid pin, e.g. gpio.15 or i2so.13:low", owner, pin)
+			return
+		}
+		name := strings.SplitN(pin, ":", 2)[0]
+		if strings.HasPrefix(name, "i2so.") && p.I2SO == nil {
+			addProblem("%s uses %s but the profile has no i2so block", owner, name)
+		}
+		if other, ok := pins[name]; ok {
+			addProblem("%s uses %s which is already used by %s", owner, name, other)
+			return
+		}
+		pins[name] = owner
+	}
+
+	if p.I2SO != nil {
+		usePin("i2so.bck_pin", p.I2SO.BckPin, true)
+		usePin("i2so.data_pin", p.I2SO.DataPin, true)
+		usePin("i2so.ws_pin", p.I2SO.WsPin, true)
+	}
+	if p.SPI != nil {
+		usePin("spi.miso_pin", p.SPI.MisoPin, true)
+		usePin("spi.mosi_pin", p.SPI.MosiPin, true)
+		usePin("spi.sck_pin", p.SPI.SckPin, true)
+	}
+	if p.SDCard != nil {
+		if p.SPI == nil {
+			addProblem("sdcard requires the spi pins")
+		}
+		usePin("sdcard.cs_pin", p.SDCard.CsPin, true)
+		usePin("sdcard.card_detect_pin", p.SDCard.CardDetectPin, false)
+	}
+
+	for i, axis := range p.Axes {
+		name := fmt.Sprintf("axes[%d]", i)
+		letter := strings.ToUpper(axis.Letter)
+		if !slices.Contains(axisLetters, letter) {
+			addProblem("%s letter %q must be one of %s", name, axis.Letter, strings.Join(axisLetters, ", "))
+		} else {
+			name = "axis " + letter
+			if letters[letter] {
+				addProblem("%s is defined twice", name)
+			}
+			letters[letter] = true
+		}
+
+		switch axis.Role {
+		case RoleNeedle, RoleSpindle, RoleRotation, RoleRing:
+			if other, ok := roles[axis.Role]; ok {
+				addProblem("%s and axis %s both have the %s role", name, other, axis.Role)
+			}
+			roles[axis.Role] = letter
+		default:
+			addProblem("%s role %q must be one of %s, %s, %s or %s", name, axis.Role, RoleNeedle, RoleSpindle, RoleRotation, RoleRing)
+		}
+
+		if axis.StepsPerMm <= 0 && !(axis.Role == RoleRotation && axis.Stepper != nil && axis.Stepper.StepsPerRevolution > 0) {
+			addProblem("%s steps_per_mm must be positive", name)
+		}
+		if axis.MaxRate <= 0 {
+			addProblem("%s max_rate_mm_per_min must be positive", name)
+		}
+		if axis.Acceleration <= 0 {
+			addProblem("%s acceleration_mm_per_sec2 must be positive", name)
+		}
+
+		switch {
+		case axis.Stepper != nil && axis.Servo != nil:
+			addProblem("%s must have either a stepper or a servo, not both", name)
+		case axis.Stepper != nil:
+			usePin(name+" stepper.step_pin", axis.Stepper.StepPin, true)
+			usePin(name+" stepper.direction_pin", axis.Stepper.DirectionPin, true)
+			usePin(name+" stepper.disable_pin", axis.Stepper.DisablePin, false)
+			if axis.Stepper.StepsPerRevolution < 0 || axis.Stepper.GearRatio < 0 {
+				addProblem("%s stepper gearing must not be negative", name)
+			}
+		case axis.Servo != nil:
+			usePin(name+" servo.output_pin", axis.Servo.OutputPin, true)
+			if axis.Role == RoleRotation {
+				addProblem("%s is the rotation axis and needs a stepper, a servo cannot turn the ring", name)
+			}
+			if axis.Servo.MinPulseUs > 0 && axis.Servo.MaxPulseUs > 0 && axis.Servo.MinPulseUs >= axis.Servo.MaxPulseUs {
+				addProblem("%s servo min_pulse_us must be lower than max_pulse_us", name)
+			}
+		default:
+			addProblem("%s needs a stepper or a servo", name)
+		}
+
+		if axis.Endstop != nil {
+			usePin(name+" endstop.pin", axis.Endstop.Pin, true)
+			switch axis.Endstop.Kind {
+			case EndstopNegative, EndstopPositive, EndstopAll:
+			default:
+				addProblem("%s endstop kind %q must be one of %s, %s or %s", name, axis.Endstop.Kind, EndstopNegative, EndstopPositive, EndstopAll)
+			}
+		}
+		if axis.Homing != nil && axis.Homing.Cycle > 0 && axis.Endstop == nil && axis.Servo == nil {
+			addProblem("%s homes in cycle %d but has no endstop", name, axis.Homing.Cycle)
+		}
+	}
+
+	// The generator writes G-code for these letters, the firmware must drive them
+	expectedAxes := []struct {
+		role   string
+		letter string
+		field  string
+	}{
+		{RoleRotation, config.RotationAxis, "RotationAxis"},
+		{RoleNeedle, config.NeedleAxis, "NeedleAxis"},
+		{RoleSpindle, config.SpindleAxis, "SpindleAxis"},
+	}
+	if len(config.NailRings()) > 1 {
+		expectedAxes = append(expectedAxes, struct {
+			role   string
+			letter string
+			field  string
+		}{RoleRing, config.RingAxis, "RingAxis"})
+	}
+	for _, expected := range expectedAxes {
+		letter, ok := roles[expected.role]
+		switch {
+		case !ok:
+			addProblem("no axis has the %s role", expected.role)
+		case expected.letter != "" && !strings.EqualFold(letter, expected.letter):
+			addProblem("the %s axis is %s but the generator %s is %s", expected.role, letter, expected.field, expected.letter)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package fluidnc
+
+import (
+	"testing"
+
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateTinyBeeProfile(t *testing.T) {
+	profile, err := LoadProfile("../../machine/electronic/MKS_TinyBee_1_profile.yml")
+	require.NoError(t, err)
+
+	output, err := Generate(profile, threadGenerator.DefaultConfig())
+	require.NoError(t, err)
+	require.Contains(t, string(output), "\n  y:\n")
+
+	// The values of the hand maintained config must survive the round trip
+	var config map[string]any
+	require.NoError(t, yaml.Unmarshal(output, &config))
+	axes := config["axes"].(map[string]any)
+	rotation := axes["a"].(map[string]any)
+	require.Equal(t, 80, rotation["steps_per_mm"])
+	require.Equal(t, 300, rotation["max_travel_mm"])
+	require.Equal(t, "gpio.32:low", rotation["motor0"].(map[string]any)["limit_all_pin"])
+	needle := axes["x"].(map[string]any)["motor0"].(map[string]any)
+	require.Equal(t, "gpio.15", needle["rc_servo"].(map[string]any)["output_pin"])
+	require.Equal(t, true, config["start"].(map[string]any)["must_home"])
+}
+
+func TestValidateProfile(t *testing.T) {
+	profile, err := LoadProfile("../../machine/electronic/MKS_TinyBee_1_profile.yml")
+	require.NoError(t, err)
+
+	config := threadGenerator.DefaultConfig()
+	config.NailsQuantity = 200
+	config.NeedleAxis = "Z"
+	profile.Axes[1].Servo.OutputPin = "gpio.15"
+	profile.Axes[2].Letter = "X"
+
+	err = profile.Validate(config)
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.ElementsMatch(t, []string{
+		"nails_quantity is 300 but the generator uses 200 nails",
+		"axis Y servo.output_pin uses gpio.15 which is already used by axis X servo.output_pin",
+		"axis X is defined twice",
+		"the rotation axis is X but the generator RotationAxis is A",
+		"the needle axis is X but the generator NeedleAxis is Z",
+	}, validationErr.Problems)
+}
+
+func TestValidateMultiRingProfile(t *testing.T) {
+	profile, err := LoadProfile("../../machine/electronic/MKS_TinyBee_1_profile.yml")
+	require.NoError(t, err)
+
+	config := threadGenerator.DefaultConfig()
+	config.Rings = []threadGenerator.Ring{{NailsQuantity: 300, Radius: 600}, {NailsQuantity: 200, Radius: 400}}
+
+	err = profile.Validate(config)
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.ElementsMatch(t, []string{
+		"nails_quantity is 300 but multi ring layouts turn the ring in degrees, it must be 360",
+		"no axis has the ring role",
+	}, validationErr.Problems)
+}
+
+func TestParseProfileRejectsUnknownFields(t *testing.T) {
+	_, err := ParseProfile([]byte("board: test\nstep_per_mm: 10\n"))
+	require.ErrorContains(t, err, "step_per_mm")
+}
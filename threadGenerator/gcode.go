@@ -0,0 +1,304 @@
+package threadGenerator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	// GcodeDialect identifies the firmware flavour a G-code program is written for
+	GcodeDialect string
+
+	// CommandType identifies the kind of a G-code command in a program
+	CommandType int
+
+	// AxisValue is a single axis word, e.g. A12.5
+	AxisValue struct {
+		Axis  string
+		Value float64
+	}
+
+	// GcodeCommand is a single dialect independent G-code instruction
+	GcodeCommand struct {
+		Type    CommandType
+		Axes    []AxisValue
+		Feed    int
+		Comment string
+	}
+
+	// GcodeProgram is an ordered list of commands that can be rendered for any dialect
+	GcodeProgram struct {
+		Commands []GcodeCommand
+	}
+
+	// GcodeWriter renders a GcodeProgram into text lines for one dialect
+	GcodeWriter struct {
+		backend gcodeBackend
+	}
+
+	// gcodeBackend holds the firmware specific parts of the rendering
+	gcodeBackend interface {
+		home(cmd GcodeCommand) []string
+		move(cmd GcodeCommand) string
+		pause(cmd GcodeCommand) string
+		comment(text string) string
+		axis(name string) string
+	}
+
+	fluidNCBackend struct{}
+	grblBackend    struct{}
+	marlinBackend  struct {
+		ijkAxes bool // Marlin 2.0.x names the extra axes I, J and K
+	}
+)
+
+const (
+	DialectFluidNC GcodeDialect = "fluidnc"
+	DialectGRBL    GcodeDialect = "grbl"
+	DialectMarlin  GcodeDialect = "marlin"
+	// DialectMarlinIJK is Marlin as built by 2.0.x, where the 4th to 6th axes
+	// are always named I, J and K
+	DialectMarlinIJK GcodeDialect = "marlin-ijk"
+)
+
+const (
+	CommandHome CommandType = iota
+	CommandMove
+	CommandAbsoluteMode
+	CommandRelativeMode
+	CommandSetPosition
+	CommandPause
+	CommandComment
+)
+
+// ParseGcodeDialect converts a dialect name to a GcodeDialect
+func ParseGcodeDialect(name string) (GcodeDialect, error) {
+	switch GcodeDialect(strings.ToLower(name)) {
+	case DialectFluidNC, "":
+		return DialectFluidNC, nil
+	case DialectGRBL:
+		return DialectGRBL, nil
+	case DialectMarlin:
+		return DialectMarlin, nil
+	case DialectMarlinIJK:
+		return DialectMarlinIJK, nil
+	default:
+		return "", fmt.Errorf("unknown gcode dialect %q", name)
+	}
+}
+
+// Home appends a homing command for the given axes
+func (p *GcodeProgram) Home(axes ...AxisValue) {
+	p.Commands = append(p.Commands, GcodeCommand{Type: CommandHome, Axes: axes})
+}
+
+// Move appends a linear move
+func (p *GcodeProgram) Move(feed int, comment string, axes ...AxisValue) {
+	p.Commands = append(p.Commands, GcodeCommand{Type: CommandMove, Axes: axes, Feed: feed, Comment: comment})
+}
+
+// Absolute switches the program to absolute positioning
+func (p *GcodeProgram) Absolute(comment string) {
+	p.Commands = append(p.Commands, GcodeCommand{Type: CommandAbsoluteMode, Comment: comment})
+}
+
+// Relative switches the program to relative positioning
+func (p *GcodeProgram) Relative(comment string) {
+	p.Commands = append(p.Commands, GcodeCommand{Type: CommandRelativeMode, Comment: comment})
+}
+
+// SetPosition redefines the current position of the given axes without moving
+func (p *GcodeProgram) SetPosition(comment string, axes ...AxisValue) {
+	p.Commands = append(p.Commands, GcodeCommand{Type: CommandSetPosition, Axes: axes, Comment: comment})
+}
+
+// Pause appends an operator acknowledged pause
+func (p *GcodeProgram) Pause(comment string) {
+	p.Commands = append(p.Commands, GcodeCommand{Type: CommandPause, Comment: comment})
+}
+
+// Comment appends a comment-only line
+func (p *GcodeProgram) Comment(text string) {
+	p.Commands = append(p.Commands, GcodeCommand{Type: CommandComment, Comment: text})
+}
+
+// NewGcodeWriter creates a writer for the given dialect
+func NewGcodeWriter(dialect GcodeDialect) (*GcodeWriter, error) {
+	switch dialect {
+	case DialectFluidNC, "":
+		return &GcodeWriter{backend: fluidNCBackend{}}, nil
+	case DialectGRBL:
+		return &GcodeWriter{backend: grblBackend{}}, nil
+	case DialectMarlin:
+		return &GcodeWriter{backend: marlinBackend{}}, nil
+	case DialectMarlinIJK:
+		return &GcodeWriter{backend: marlinBackend{ijkAxes: true}}, nil
+	default:
+		return nil, fmt.Errorf("unknown gcode dialect %q", dialect)
+	}
+}
+
+// Write renders the program into G-code lines
+func (w *GcodeWriter) Write(program *GcodeProgram) []string {
+	lines := make([]string, 0, len(program.Commands))
+	for _, cmd := range program.Commands {
+		switch cmd.Type {
+		case CommandHome:
+			lines = append(lines, w.backend.home(cmd)...)
+		case CommandMove:
+			lines = append(lines, w.withComment(w.backend.move(cmd), cmd.Comment))
+		case CommandAbsoluteMode:
+			lines = append(lines, w.withComment("G90", cmd.Comment))
+		case CommandRelativeMode:
+			lines = append(lines, w.withComment("G91", cmd.Comment))
+		case CommandSetPosition:
+			lines = append(lines, w.withComment("G92 "+axisWords(w.backend, cmd.Axes), cmd.Comment))
+		case CommandPause:
+			lines = append(lines, w.backend.pause(cmd))
+		case CommandComment:
+			lines = append(lines, w.backend.comment(cmd.Comment))
+		}
+	}
+	return lines
+}
+
+func (w *GcodeWriter) withComment(line, comment string) string {
+	if comment == "" {
+		return line
+	}
+	return line + " " + w.backend.comment(comment)
+}
+
+// axisWords renders axis words using the backend's axis naming
+func axisWords(backend gcodeBackend, axes []AxisValue) string {
+	words := make([]string, len(axes))
+	for i, axis := range axes {
+		words[i] = backend.axis(axis.Axis) + formatGcodeNumber(axis.Value)
+	}
+	return strings.Join(words, " ")
+}
+
+// formatGcodeNumber prints a coordinate with at most 3 decimals and no trailing zeros
+func formatGcodeNumber(value float64) string {
+	formatted := strconv.FormatFloat(value, 'f', 3, 64)
+	formatted = strings.TrimRight(formatted, "0")
+	formatted = strings.TrimSuffix(formatted, ".")
+	if formatted == "-0" {
+		return "0"
+	}
+	return formatted
+}
+
+// moveLine renders a linear move with the given command word
+func moveLine(backend gcodeBackend, code string, cmd GcodeCommand) string {
+	line := code + " " + axisWords(backend, cmd.Axes)
+	if cmd.Feed > 0 {
+		line += fmt.Sprintf(" F%d", cmd.Feed)
+	}
+	return line
+}
+
+// FluidNC accepts G28 with axis words and semicolon comments, and is what the machine in machine/ runs
+
+func (b fluidNCBackend) home(cmd GcodeCommand) []string {
+	return []string{strings.TrimSpace("G28 " + axisWords(b, cmd.Axes))}
+}
+
+func (b fluidNCBackend) move(cmd GcodeCommand) string {
+	return moveLine(b, "G01", cmd)
+}
+
+func (b fluidNCBackend) pause(cmd GcodeCommand) string {
+	if cmd.Comment == "" {
+		return "M0"
+	}
+	return "M0 " + b.comment(cmd.Comment)
+}
+
+func (fluidNCBackend) comment(text string) string {
+	return "; " + text
+}
+
+func (fluidNCBackend) axis(name string) string {
+	return name
+}
+
+// GRBL homes with $H, uses parenthesis comments and then needs the work
+// coordinates to be set with G92 to match the requested home position
+
+func (b grblBackend) home(cmd GcodeCommand) []string {
+	lines := []string{"$H"}
+	if len(cmd.Axes) > 0 {
+		lines = append(lines, "G92 "+axisWords(b, cmd.Axes)+" "+b.comment("Set home position"))
+	}
+	return lines
+}
+
+func (b grblBackend) move(cmd GcodeCommand) string {
+	return moveLine(b, "G1", cmd)
+}
+
+func (b grblBackend) pause(cmd GcodeCommand) string {
+	if cmd.Comment == "" {
+		return "M0"
+	}
+	return "M0 " + b.comment(cmd.Comment)
+}
+
+func (grblBackend) comment(text string) string {
+	// GRBL does not allow nested parenthesis inside a comment
+	text = strings.NewReplacer("(", "[", ")", "]").Replace(text)
+	return "(" + text + ")"
+}
+
+func (grblBackend) axis(name string) string {
+	return name
+}
+
+// Marlin homes with bare axis letters and shows the M0 message on the LCD.
+// Marlin 2.1 names the extra axes after AXIS4_NAME to AXIS6_NAME, A, B and C by
+// default, while 2.0.x builds always name them I, J and K.
+
+func (b marlinBackend) home(cmd GcodeCommand) []string {
+	axes := make([]string, len(cmd.Axes))
+	for i, axis := range cmd.Axes {
+		axes[i] = b.axis(axis.Axis)
+	}
+	lines := []string{strings.TrimSpace("G28 " + strings.Join(axes, " "))}
+	if len(cmd.Axes) > 0 {
+		lines = append(lines, "G92 "+axisWords(b, cmd.Axes)+" "+b.comment("Set home position"))
+	}
+	return lines
+}
+
+func (b marlinBackend) move(cmd GcodeCommand) string {
+	return moveLine(b, "G1", cmd)
+}
+
+func (marlinBackend) pause(cmd GcodeCommand) string {
+	if cmd.Comment == "" {
+		return "M0"
+	}
+	return "M0 " + cmd.Comment
+}
+
+func (marlinBackend) comment(text string) string {
+	return "; " + text
+}
+
+func (b marlinBackend) axis(name string) string {
+	if !b.ijkAxes {
+		return name
+	}
+	switch strings.ToUpper(name) {
+	case "A":
+		return "I"
+	case "B":
+		return "J"
+	case "C":
+		return "K"
+	default:
+		return name
+	}
+}
@@ -0,0 +1,154 @@
+package threadGenerator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// dialectProgram uses every command type once
+func dialectProgram() *GcodeProgram {
+	program := &GcodeProgram{}
+	program.Home(AxisValue{Axis: "X", Value: 0}, AxisValue{Axis: "A", Value: 0})
+	program.Absolute("Absolute positioning")
+	program.Move(1000, "Nail 3 (top)", AxisValue{Axis: "X", Value: 10}, AxisValue{Axis: "A", Value: 1.25})
+	program.SetPosition("Reset rotation", AxisValue{Axis: "A", Value: 0})
+	program.Pause("Load thread (red)")
+	program.Comment("Step 1")
+	program.Relative("")
+	program.Move(0, "", AxisValue{Axis: "B", Value: -0.0001}, AxisValue{Axis: "C", Value: 2.5})
+	program.Home()
+	return program
+}
+
+func TestGcodeWriterDialects(t *testing.T) {
+	tests := []struct {
+		dialect GcodeDialect
+		lines   []string
+	}{
+		{
+			// FluidNC homes the given axes with G28 and uses semicolon comments
+			dialect: DialectFluidNC,
+			lines: []string{
+				"G28 X0 A0",
+				"G90 ; Absolute positioning",
+				"G01 X10 A1.25 F1000 ; Nail 3 (top)",
+				"G92 A0 ; Reset rotation",
+				"M0 ; Load thread (red)",
+				"; Step 1",
+				"G91",
+				"G01 B0 C2.5",
+				"G28",
+			},
+		},
+		{
+			// GRBL homes every axis with $H then sets the home position, its
+			// comments can't nest parenthesis
+			dialect: DialectGRBL,
+			lines: []string{
+				"$H",
+				"G92 X0 A0 (Set home position)",
+				"G90 (Absolute positioning)",
+				"G1 X10 A1.25 F1000 (Nail 3 [top])",
+				"G92 A0 (Reset rotation)",
+				"M0 (Load thread [red])",
+				"(Step 1)",
+				"G91",
+				"G1 B0 C2.5",
+				"$H",
+			},
+		},
+		{
+			// Marlin homes bare axis letters and shows the pause text on its
+			// screen, 2.1 names the extra axes A, B and C by default
+			dialect: DialectMarlin,
+			lines: []string{
+				"G28 X A",
+				"G92 X0 A0 ; Set home position",
+				"G90 ; Absolute positioning",
+				"G1 X10 A1.25 F1000 ; Nail 3 (top)",
+				"G92 A0 ; Reset rotation",
+				"M0 Load thread (red)",
+				"; Step 1",
+				"G91",
+				"G1 B0 C2.5",
+				"G28",
+			},
+		},
+		{
+			// Marlin 2.0.x names A, B and C as I, J and K
+			dialect: DialectMarlinIJK,
+			lines: []string{
+				"G28 X I",
+				"G92 X0 I0 ; Set home position",
+				"G90 ; Absolute positioning",
+				"G1 X10 I1.25 F1000 ; Nail 3 (top)",
+				"G92 I0 ; Reset rotation",
+				"M0 Load thread (red)",
+				"; Step 1",
+				"G91",
+				"G1 J0 K2.5",
+				"G28",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			writer, err := NewGcodeWriter(tt.dialect)
+			require.NoError(t, err)
+			require.Equal(t, tt.lines, writer.Write(dialectProgram()))
+		})
+	}
+}
+
+func TestGcodeWriterPauseWithoutComment(t *testing.T) {
+	program := &GcodeProgram{}
+	program.Pause("")
+	for _, dialect := range []GcodeDialect{DialectFluidNC, DialectGRBL, DialectMarlin, DialectMarlinIJK} {
+		writer, err := NewGcodeWriter(dialect)
+		require.NoError(t, err)
+		require.Equal(t, []string{"M0"}, writer.Write(program), dialect)
+	}
+}
+
+func TestParseGcodeDialect(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect GcodeDialect
+		err     bool
+	}{
+		{name: "", dialect: DialectFluidNC},
+		{name: "fluidnc", dialect: DialectFluidNC},
+		{name: "GRBL", dialect: DialectGRBL},
+		{name: "Marlin", dialect: DialectMarlin},
+		{name: "marlin-ijk", dialect: DialectMarlinIJK},
+		{name: "reprap", err: true},
+	}
+	for _, tt := range tests {
+		dialect, err := ParseGcodeDialect(tt.name)
+		if tt.err {
+			require.Error(t, err, tt.name)
+			continue
+		}
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.dialect, dialect, tt.name)
+	}
+
+	_, err := NewGcodeWriter("reprap")
+	require.Error(t, err)
+}
+
+func TestFormatGcodeNumber(t *testing.T) {
+	tests := map[float64]string{
+		0:       "0",
+		10:      "10",
+		1.25:    "1.25",
+		-3.5:    "-3.5",
+		1.23456: "1.235",
+		-0.0001: "0",
+		100.1:   "100.1",
+	}
+	for value, expected := range tests {
+		require.Equal(t, expected, formatGcodeNumber(value), value)
+	}
+}
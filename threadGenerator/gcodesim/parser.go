@@ -0,0 +1,177 @@
+package gcodesim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+)
+
+type (
+	// ParseError points at a G-code line that could not be understood
+	ParseError struct {
+		Line    int
+		Text    string
+		Message string
+	}
+
+	// word is a single letter/number pair, Number is empty for bare axis letters
+	word struct {
+		Letter byte
+		Number string
+	}
+)
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("line %d %q: %s", e.Line, e.Text, e.Message)
+}
+
+// Parse reads G-code lines written for the given dialect back into a program.
+// Feed rates are modal like on the controllers, so every move carries the
+// feed it will actually run at.
+func Parse(lines []string, dialect threadGenerator.GcodeDialect) (*threadGenerator.GcodeProgram, error) {
+	dialect, err := threadGenerator.ParseGcodeDialect(string(dialect))
+	if err != nil {
+		return nil, err
+	}
+
+	program := &threadGenerator.GcodeProgram{}
+	feed := 0
+	for i, line := range lines {
+		code, comment := splitComment(line, dialect)
+		code = strings.TrimSpace(code)
+		if code == "" {
+			if comment != "" {
+				program.Comment(comment)
+			}
+			continue
+		}
+
+		if strings.EqualFold(code, "$H") {
+			program.Home()
+			continue
+		}
+
+		words, err := tokenize(code)
+		if err != nil {
+			return nil, ParseError{Line: i + 1, Text: line, Message: err.Error()}
+		}
+
+		command := words[0]
+		var axes []threadGenerator.AxisValue
+		for _, w := range words[1:] {
+			if w.Letter == 'F' {
+				value, err := strconv.ParseFloat(w.Number, 64)
+				if err != nil {
+					return nil, ParseError{Line: i + 1, Text: line, Message: "invalid feed rate"}
+				}
+				feed = int(value)
+				continue
+			}
+			axis := axisName(string(w.Letter), dialect)
+			value := 0.0
+			if w.Number != "" {
+				value, err = strconv.ParseFloat(w.Number, 64)
+				if err != nil {
+					return nil, ParseError{Line: i + 1, Text: line, Message: fmt.Sprintf("invalid value for axis %s", axis)}
+				}
+			} else if !(command.Letter == 'G' && command.Number == "28") {
+				return nil, ParseError{Line: i + 1, Text: line, Message: fmt.Sprintf("missing value for axis %s", axis)}
+			}
+			axes = append(axes, threadGenerator.AxisValue{Axis: axis, Value: value})
+		}
+
+		switch code := normalizeCode(command); code {
+		case "G0", "G1":
+			program.Move(feed, comment, axes...)
+		case "G28":
+			program.Home(axes...)
+		case "G90":
+			program.Absolute(comment)
+		case "G91":
+			program.Relative(comment)
+		case "G92":
+			program.SetPosition(comment, axes...)
+		case "M0", "M1":
+			program.Pause(comment)
+		default:
+			return nil, ParseError{Line: i + 1, Text: line, Message: fmt.Sprintf("unsupported command %s", code)}
+		}
+	}
+	return program, nil
+}
+
+// splitComment separates the code from its comment. Marlin shows whatever
+// follows M0 on the LCD, so that text is treated as the comment as well.
+func splitComment(line string, dialect threadGenerator.GcodeDialect) (string, string) {
+	if idx := strings.Index(line, ";"); idx >= 0 {
+		return line[:idx], strings.TrimSpace(line[idx+1:])
+	}
+	if idx := strings.Index(line, "("); idx >= 0 {
+		end := strings.LastIndex(line, ")")
+		if end < idx {
+			end = len(line)
+		}
+		return line[:idx] + line[min(end+1, len(line)):], strings.TrimSpace(line[idx+1 : end])
+	}
+	if dialect == threadGenerator.DialectMarlin || dialect == threadGenerator.DialectMarlinIJK {
+		fields := strings.Fields(line)
+		if len(fields) > 1 && (strings.EqualFold(fields[0], "M0") || strings.EqualFold(fields[0], "M1")) {
+			return fields[0], strings.Join(fields[1:], " ")
+		}
+	}
+	return line, ""
+}
+
+// tokenize splits a line of code into words, e.g. "G01 A-19.5 F3000"
+func tokenize(code string) ([]word, error) {
+	code = strings.ToUpper(code)
+	var words []word
+	for i := 0; i < len(code); {
+		c := code[i]
+		if c == ' ' || c == '\t' {
+			i++
+			continue
+		}
+		if c < 'A' || c > 'Z' {
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+		j := i + 1
+		for j < len(code) && (code[j] == '-' || code[j] == '+' || code[j] == '.' || (code[j] >= '0' && code[j] <= '9')) {
+			j++
+		}
+		words = append(words, word{Letter: c, Number: code[i+1 : j]})
+		i = j
+	}
+	if len(words) == 0 || (words[0].Letter != 'G' && words[0].Letter != 'M') {
+		return nil, fmt.Errorf("line does not start with a G or M command")
+	}
+	return words, nil
+}
+
+// normalizeCode strips leading zeros so G01 and G1 compare equal
+func normalizeCode(w word) string {
+	number, err := strconv.Atoi(w.Number)
+	if err != nil {
+		return string(w.Letter) + w.Number
+	}
+	return fmt.Sprintf("%c%d", w.Letter, number)
+}
+
+// axisName maps a dialect axis letter back to the generator's axis name
+func axisName(letter string, dialect threadGenerator.GcodeDialect) string {
+	if dialect != threadGenerator.DialectMarlinIJK {
+		return letter
+	}
+	switch letter {
+	case "I":
+		return "A"
+	case "J":
+		return "B"
+	case "K":
+		return "C"
+	default:
+		return letter
+	}
+}
@@ -0,0 +1,273 @@
+// Package gcodesim replays generated G-code against a model of the string art
+// machine and checks that it wraps the nails the generator asked for.
+package gcodesim
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+)
+
+type (
+	// Limit is the allowed travel of an axis in machine units
+	Limit struct {
+		Min float64
+		Max float64
+	}
+
+	// Machine describes the kinematics of the rotary ring and the needle
+	Machine struct {
+		NailsQuantity   int
+		Rings           []int // Nails of each ring of a multi ring layout, the rotation axis is then in degrees
+		RotationAxis    string
+		NeedleAxis      string
+		RingAxis        string           // Axis whose position is the index of the ring the needle is on
+		NeedleRetracted float64          // The needle clears the nails at or below this position
+		Limits          map[string]Limit // Soft limits per axis, axes without an entry are unlimited
+	}
+
+	// Mismatch is a path that differs between the G-code and the generator
+	Mismatch struct {
+		Index    int
+		Expected *threadGenerator.Path
+		Actual   *threadGenerator.Path
+	}
+
+	// LimitViolation is a move that leaves the allowed travel of an axis
+	LimitViolation struct {
+		Command int
+		Axis    string
+		Value   float64
+		Limit   Limit
+	}
+
+	// Report is the outcome of a simulation
+	Report struct {
+		StartingNail    int
+		Paths           []threadGenerator.Path
+		Mismatches      []Mismatch
+		LimitViolations []LimitViolation
+		Motion          threadGenerator.MotionStats // Travel and estimated run time
+	}
+)
+
+// MachineFromConfig builds the machine model matching a generator configuration
+func MachineFromConfig(config threadGenerator.Config) Machine {
+	machine := Machine{
+		NailsQuantity:   config.NailsQuantity,
+		RotationAxis:    config.RotationAxis,
+		NeedleAxis:      config.NeedleAxis,
+		NeedleRetracted: -5,
+		Limits: map[string]Limit{
+			config.NeedleAxis:  {Min: -10, Max: 5},
+			config.SpindleAxis: {Min: -5, Max: 0},
+		},
+	}
+
+	if rings := config.NailRings(); len(rings) > 1 {
+		machine.NailsQuantity = 0
+		for _, ring := range rings {
+			machine.Rings = append(machine.Rings, ring.NailsQuantity)
+			machine.NailsQuantity += ring.NailsQuantity
+		}
+		machine.RingAxis = config.RingAxis
+		machine.Limits[config.RingAxis] = Limit{Min: 0, Max: float64(len(rings) - 1)}
+	}
+	return machine
+}
+
+// Simulate runs the program on the machine and reconstructs the wrapped nails.
+// A nail is wrapped when the ring turns past it while the needle is retracted.
+func Simulate(program *threadGenerator.GcodeProgram, machine Machine) (*Report, error) {
+	if machine.NailsQuantity <= 0 {
+		return nil, fmt.Errorf("machine needs a positive number of nails")
+	}
+
+	report := &Report{StartingNail: -1}
+	positions := map[string]float64{}
+	offsets := map[string]float64{} // Machine position minus work coordinate, changed by G92
+	absolute := true
+	current := -1
+
+	for i, cmd := range program.Commands {
+		switch cmd.Type {
+		case threadGenerator.CommandHome:
+			if len(cmd.Axes) == 0 {
+				// A bare home ($H) zeroes every axis
+				positions = map[string]float64{}
+				offsets = map[string]float64{}
+			}
+			for _, axis := range cmd.Axes {
+				positions[axis.Axis] = axis.Value
+				offsets[axis.Axis] = 0
+			}
+		case threadGenerator.CommandSetPosition:
+			for _, axis := range cmd.Axes {
+				offsets[axis.Axis] += positions[axis.Axis] - axis.Value
+				positions[axis.Axis] = axis.Value
+			}
+		case threadGenerator.CommandAbsoluteMode:
+			absolute = true
+		case threadGenerator.CommandRelativeMode:
+			absolute = false
+		case threadGenerator.CommandPause:
+			if report.StartingNail < 0 {
+				report.StartingNail = machine.nailAt(machine.ringAt(positions), positions[machine.RotationAxis]+offsets[machine.RotationAxis])
+				current = report.StartingNail
+			}
+		case threadGenerator.CommandMove:
+			retracted := positions[machine.NeedleAxis] <= machine.NeedleRetracted
+			from := positions[machine.RotationAxis] + offsets[machine.RotationAxis]
+			for _, axis := range cmd.Axes {
+				target := axis.Value
+				if !absolute {
+					target += positions[axis.Axis]
+				}
+				positions[axis.Axis] = target
+				if limit, ok := machine.Limits[axis.Axis]; ok && (target < limit.Min || target > limit.Max) {
+					report.LimitViolations = append(report.LimitViolations, LimitViolation{Command: i, Axis: axis.Axis, Value: target, Limit: limit})
+				}
+			}
+			retracted = retracted && positions[machine.NeedleAxis] <= machine.NeedleRetracted
+			to := positions[machine.RotationAxis] + offsets[machine.RotationAxis]
+			if !retracted || current < 0 {
+				continue
+			}
+			for _, nail := range machine.nailsCrossed(machine.ringAt(positions), from, to) {
+				report.Paths = append(report.Paths, threadGenerator.Path{StartingNail: current, EndingNail: nail})
+				current = nail
+			}
+		}
+	}
+
+	report.Motion = threadGenerator.AnalyzeProgram(program, machine.RotationAxis, machine.unitsPerTurn())
+	return report, nil
+}
+
+// Verify parses and simulates the G-code, then compares the wrapped nails
+// with the expected paths
+func Verify(lines []string, dialect threadGenerator.GcodeDialect, machine Machine, expected []threadGenerator.Path) (*Report, error) {
+	program, err := Parse(lines, dialect)
+	if err != nil {
+		return nil, err
+	}
+	report, err := Simulate(program, machine)
+	if err != nil {
+		return nil, err
+	}
+	report.Compare(expected)
+	return report, nil
+}
+
+// Compare records every index where the reconstructed paths differ from expected
+func (r *Report) Compare(expected []threadGenerator.Path) {
+	r.Mismatches = nil
+	for i := 0; i < max(len(expected), len(r.Paths)); i++ {
+		var want, got *threadGenerator.Path
+		if i < len(expected) {
+			want = &expected[i]
+		}
+		if i < len(r.Paths) {
+			got = &r.Paths[i]
+		}
+		if want != nil && got != nil && *want == *got {
+			continue
+		}
+		r.Mismatches = append(r.Mismatches, Mismatch{Index: i, Expected: want, Actual: got})
+	}
+}
+
+// Err summarises the mismatches and limit violations, or returns nil when the
+// G-code reproduces the paths within the machine limits
+func (r *Report) Err() error {
+	var problems []string
+	if len(r.Mismatches) > 0 {
+		problems = append(problems, fmt.Sprintf("%d path mismatches, first %s", len(r.Mismatches), r.Mismatches[0]))
+	}
+	if len(r.LimitViolations) > 0 {
+		problems = append(problems, fmt.Sprintf("%d axis limit violations, first %s", len(r.LimitViolations), r.LimitViolations[0]))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("gcode verification failed: %s", strings.Join(problems, "; "))
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("at path %d: expected %s, got %s", m.Index, formatPath(m.Expected), formatPath(m.Actual))
+}
+
+func (v LimitViolation) String() string {
+	return fmt.Sprintf("command %d moves %s to %.3f outside [%.3f, %.3f]", v.Command, v.Axis, v.Value, v.Limit.Min, v.Limit.Max)
+}
+
+func formatPath(path *threadGenerator.Path) string {
+	if path == nil {
+		return "nothing"
+	}
+	return fmt.Sprintf("%d->%d", path.StartingNail, path.EndingNail)
+}
+
+// nailAt returns the nail of the ring closest to a machine rotary position
+func (m Machine) nailAt(ring int, position float64) int {
+	return m.wrap(ring, int(math.Round(m.toRingNails(ring, position))))
+}
+
+// nailsCrossed lists the nails of the ring strictly between two machine rotary
+// positions, in the order the ring passes them
+func (m Machine) nailsCrossed(ring int, from, to float64) []int {
+	from, to = m.toRingNails(ring, from), m.toRingNails(ring, to)
+	var nails []int
+	if to > from {
+		for k := math.Floor(from) + 1; k < to; k++ {
+			if k > from {
+				nails = append(nails, m.wrap(ring, int(k)))
+			}
+		}
+	} else {
+		for k := math.Ceil(from) - 1; k > to; k-- {
+			if k < from {
+				nails = append(nails, m.wrap(ring, int(k)))
+			}
+		}
+	}
+	return nails
+}
+
+// wrap brings a nail index of the ring within a turn and numbers it ring after ring
+func (m Machine) wrap(ring, nail int) int {
+	if len(m.Rings) == 0 {
+		return ((nail % m.NailsQuantity) + m.NailsQuantity) % m.NailsQuantity
+	}
+	first := 0
+	for _, nails := range m.Rings[:ring] {
+		first += nails
+	}
+	return first + ((nail%m.Rings[ring])+m.Rings[ring])%m.Rings[ring]
+}
+
+// ringAt returns the ring the needle is on, always 0 on a single ring machine
+func (m Machine) ringAt(positions map[string]float64) int {
+	if len(m.Rings) == 0 {
+		return 0
+	}
+	ring := int(math.Round(positions[m.RingAxis]))
+	return max(0, min(ring, len(m.Rings)-1))
+}
+
+// toRingNails converts a rotary position to nails of the ring
+func (m Machine) toRingNails(ring int, position float64) float64 {
+	if len(m.Rings) == 0 {
+		return position
+	}
+	return position * float64(m.Rings[ring]) / 360
+}
+
+func (m Machine) unitsPerTurn() int {
+	if len(m.Rings) == 0 {
+		return m.NailsQuantity
+	}
+	return 360
+}
@@ -0,0 +1,165 @@
+package gcodesim
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Damione1/thread-art-generator/threadGenerator"
+	"github.com/stretchr/testify/require"
+)
+
+func generate(t *testing.T, config threadGenerator.Config) *threadGenerator.ThreadGenerator {
+	img := image.NewGray(image.Rect(0, 0, 120, 120))
+	for y := 0; y < 120; y++ {
+		for x := 0; x < 120; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8((x * y) % 256)})
+		}
+	}
+	imagePath := filepath.Join(t.TempDir(), "source.png")
+	file, err := os.Create(imagePath)
+	require.NoError(t, err)
+	require.NoError(t, png.Encode(file, img))
+	require.NoError(t, file.Close())
+
+	generator := threadGenerator.NewThreadGenerator(config)
+	_, err = generator.Generate(threadGenerator.Args{ImageName: imagePath})
+	require.NoError(t, err)
+	require.NotEmpty(t, generator.GetPathsList())
+	return generator
+}
+
+func TestVerifyRoundTrip(t *testing.T) {
+	for _, dialect := range []threadGenerator.GcodeDialect{threadGenerator.DialectFluidNC, threadGenerator.DialectGRBL, threadGenerator.DialectMarlin, threadGenerator.DialectMarlinIJK} {
+		t.Run(string(dialect), func(t *testing.T) {
+			config := threadGenerator.DefaultConfig()
+			config.NailsQuantity = 60
+			config.ImgSize = 120
+			config.MaxPaths = 300
+			config.MinimumDifference = 5
+			config.StartingNail = 7
+			config.MaxTwistTurns = 1
+			config.GcodeDialect = dialect
+			generator := generate(t, config)
+
+			report, err := Verify(generator.GetGcode(), dialect, MachineFromConfig(config), generator.GetPathsList())
+			require.NoError(t, err)
+			require.NoError(t, report.Err())
+			require.Equal(t, config.StartingNail, report.StartingNail)
+			require.LessOrEqual(t, report.Motion.MaxTwistTurns, 1.0+1.0/60)
+			require.Positive(t, report.Motion.EstimatedRunTime)
+		})
+	}
+}
+
+func TestVerifyMultiRing(t *testing.T) {
+	for _, dialect := range []threadGenerator.GcodeDialect{threadGenerator.DialectFluidNC, threadGenerator.DialectGRBL, threadGenerator.DialectMarlin, threadGenerator.DialectMarlinIJK} {
+		t.Run(string(dialect), func(t *testing.T) {
+			config := threadGenerator.DefaultConfig()
+			config.Rings = []threadGenerator.Ring{
+				{NailsQuantity: 60, Radius: config.PhysicalRadius},
+				{NailsQuantity: 36, Radius: config.PhysicalRadius / 2, MinimumDifference: 3},
+			}
+			config.ImgSize = 120
+			config.MaxPaths = 300
+			config.MinimumDifference = 5
+			config.GcodeDialect = dialect
+			generator := generate(t, config)
+
+			rings := map[bool]bool{}
+			for _, path := range generator.GetPathsList() {
+				rings[(path.StartingNail < 60) == (path.EndingNail < 60)] = true
+			}
+			require.True(t, rings[false], "some paths must cross rings")
+
+			report, err := Verify(generator.GetGcode(), dialect, MachineFromConfig(config), generator.GetPathsList())
+			require.NoError(t, err)
+			require.NoError(t, report.Err())
+		})
+	}
+}
+
+func TestVerifyResumeFromStep(t *testing.T) {
+	config := threadGenerator.DefaultConfig()
+	config.NailsQuantity = 60
+	config.ImgSize = 120
+	config.MaxPaths = 100
+	config.MinimumDifference = 5
+	generator := generate(t, config)
+	paths := generator.GetPathsList()
+	step := len(paths) / 2
+
+	gcode, err := generator.GetGcodeFromStep(step)
+	require.NoError(t, err)
+
+	report, err := Verify(gcode, config.GcodeDialect, MachineFromConfig(config), paths[step:])
+	require.NoError(t, err)
+	require.NoError(t, report.Err())
+	require.Equal(t, paths[step].StartingNail, report.StartingNail)
+
+	// The program names the step it resumes at and marks every path after it
+	program := strings.Join(gcode, "\n")
+	require.Contains(t, program, fmt.Sprintf("Resuming at step %d", step))
+	require.Contains(t, program, threadGenerator.StepMarker(step))
+	require.Contains(t, program, threadGenerator.StepMarker(len(paths)-1))
+
+	_, err = generator.GetGcodeFromStep(len(paths))
+	require.Error(t, err)
+}
+
+func TestVerifyDetectsMismatch(t *testing.T) {
+	config := threadGenerator.DefaultConfig()
+	config.NailsQuantity = 60
+	config.ImgSize = 120
+	config.MaxPaths = 50
+	config.MinimumDifference = 5
+	generator := generate(t, config)
+
+	gcode := generator.GetGcode()
+	for i, line := range gcode {
+		// Skip one wrap so every following path is off by one nail
+		if strings.HasPrefix(line, "G01 X-10") {
+			gcode[i] = "G01 X0 F2000"
+			break
+		}
+	}
+
+	report, err := Verify(gcode, config.GcodeDialect, MachineFromConfig(config), generator.GetPathsList())
+	require.NoError(t, err)
+	require.NotEmpty(t, report.Mismatches)
+	require.Error(t, report.Err())
+}
+
+func TestParseRejectsUnknownCommand(t *testing.T) {
+	_, err := Parse([]string{"G28 X5 Y0 A0", "G02 A10 F300"}, threadGenerator.DialectFluidNC)
+	require.ErrorContains(t, err, "unsupported command G2")
+}
+
+func TestCalibrationProgramsNeverWrap(t *testing.T) {
+	for _, dialect := range []threadGenerator.GcodeDialect{threadGenerator.DialectFluidNC, threadGenerator.DialectGRBL, threadGenerator.DialectMarlin, threadGenerator.DialectMarlinIJK} {
+		for _, routine := range threadGenerator.CalibrationRoutines {
+			t.Run(string(dialect)+"/"+string(routine), func(t *testing.T) {
+				config := threadGenerator.DefaultConfig()
+				config.NailsQuantity = 200
+				config.GcodeDialect = dialect
+				generator := threadGenerator.NewThreadGenerator(config)
+
+				lines, err := generator.GetCalibrationGcode(routine, threadGenerator.DefaultCalibrationSettings())
+				require.NoError(t, err)
+				program, err := Parse(lines, dialect)
+				require.NoError(t, err)
+
+				// Calibration runs without thread, but must not hook a nail if one is attached
+				report, err := Simulate(program, MachineFromConfig(config))
+				require.NoError(t, err)
+				require.Empty(t, report.Paths)
+				require.Empty(t, report.LimitViolations)
+			})
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package threadGenerator
+
+import (
+	"image"
+	"math"
+)
+
+// srgbToLinearTable maps the 8 bit sRGB encoded gray levels to linear light
+var srgbToLinearTable = func() [256]float32 {
+	var table [256]float32
+	for i := range table {
+		table[i] = float32(decodeSRGB(float64(i) / 255))
+	}
+	return table
+}()
+
+// decodeSRGB converts an sRGB encoded value from 0 to 1 to linear light
+func decodeSRGB(value float64) float64 {
+	if value <= 0.04045 {
+		return value / 12.92
+	}
+	return math.Pow((value+0.055)/1.055, 2.4)
+}
+
+// encodeSRGB converts linear light from 0 to 1 to an sRGB encoded value
+func encodeSRGB(light float64) float64 {
+	if light <= 0.0031308 {
+		return light * 12.92
+	}
+	return 1.055*math.Pow(light, 1/2.4) - 0.055
+}
+
+// linearToGray encodes linear light to an 8 bit sRGB gray level
+func linearToGray(light float64) uint8 {
+	return uint8(math.Round(255 * encodeSRGB(math.Min(1, math.Max(0, light)))))
+}
+
+// lightCanvas is the light of the source image still to be covered by
+// threads, in linear light. Threads are subtractive, each one removes a share
+// of the light reaching the eye, so the canvas is brightened by that share
+// once a thread is chosen.
+type lightCanvas struct {
+	bounds image.Rectangle
+	light  []float32
+}
+
+func newLightCanvas(img image.Image) *lightCanvas {
+	bounds := img.Bounds()
+	canvas := &lightCanvas{bounds: bounds, light: make([]float32, bounds.Dx()*bounds.Dy())}
+	gray := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			gray.Set(x, y, img.At(x, y))
+		}
+	}
+	for i, value := range gray.Pix {
+		canvas.light[i] = srgbToLinearTable[value]
+	}
+	return canvas
+}
+
+func (c *lightCanvas) index(point image.Point) int {
+	return (point.Y-c.bounds.Min.Y)*c.bounds.Dx() + point.X - c.bounds.Min.X
+}
+
+// darkness returns the mean light still missing along a line, from 0 to 1.
+// Nails on the edge of the image put a few points of their lines outside.
+func (c *lightCanvas) darkness(line []image.Point) float64 {
+	sum, count := 0.0, 0
+	for _, point := range line {
+		if point.In(c.bounds) {
+			sum += 1 - float64(c.light[c.index(point)])
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// brighten adds the light removed by a thread to a pixel of the canvas
+func (c *lightCanvas) brighten(point image.Point, amount float32) {
+	if !point.In(c.bounds) {
+		return
+	}
+	i := c.index(point)
+	c.light[i] = min(1, c.light[i]+amount)
+}
+
+// factorLinearDarkening returns the linear light removed by a thread that
+// darkens a white pixel by the brightness factor
+func (tg *ThreadGenerator) factorLinearDarkening() float32 {
+	return 1 - srgbToLinearTable[255-min(255, tg.brightnessFactor)]
+}
+
+// linearDarkening returns the linear light a thread removes from a pixel and
+// counts the thread
+func (tg *ThreadGenerator) linearDarkening(lineCounts []int, pixel image.Point) float32 {
+	if tg.threadProfile == nil {
+		return tg.factorLinearDarkening()
+	}
+	index := pixel.Y*tg.imgSize + pixel.X
+	if pixel.X < 0 || pixel.Y < 0 || pixel.X >= tg.imgSize || index >= len(lineCounts) {
+		return 0
+	}
+	before := tg.threadProfile.lineBrightness(lineCounts[index], tg.imagePixelSize()) / 255
+	lineCounts[index]++
+	after := tg.threadProfile.lineBrightness(lineCounts[index], tg.imagePixelSize()) / 255
+	return float32(decodeSRGB(before) - decodeSRGB(after))
+}
+
+// linearPathsImage draws the paths with the subtractive thread model in linear
+// light, then encodes the preview in sRGB
+func (tg *ThreadGenerator) linearPathsImage(pathsImage *image.Gray) *image.Gray {
+	darkening := float64(tg.factorLinearDarkening())
+	for i, count := range tg.pathsLineCounts(pathsImage.Bounds()) {
+		if count > 0 {
+			pathsImage.Pix[i] = linearToGray(1 - float64(count)*darkening)
+		}
+	}
+	return pathsImage
+}
@@ -0,0 +1,82 @@
+package threadGenerator
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSRGBRoundTrip(t *testing.T) {
+	for value := 0; value < 256; value++ {
+		require.Equal(t, uint8(value), linearToGray(float64(srgbToLinearTable[value])))
+	}
+	// Middle gray reflects about a fifth of the light
+	require.InDelta(t, 0.216, srgbToLinearTable[128], 0.001)
+}
+
+// rampBandsError generates the ramp and returns the root mean square error
+// between the light of the physical piece and the ramp, over vertical bands.
+// Each thread removes the light of the brightness factor from a pixel, the
+// way real threads add up.
+func rampBandsError(t *testing.T, imagePath string, linearLight bool) float64 {
+	tg := NewThreadGenerator(Config{
+		NailsQuantity:     150,
+		ImgSize:           160,
+		MaxPaths:          6000,
+		MinimumDifference: 10,
+		BrightnessFactor:  10,
+		PhysicalRadius:    100,
+		LinearLight:       linearLight,
+	})
+	_, err := tg.Generate(Args{ImageName: imagePath})
+	require.NoError(t, err)
+
+	reference, err := tg.loadCircleImage(0)
+	require.NoError(t, err)
+	lineCounts := tg.pathsLineCounts(reference.Bounds())
+	darkening := float64(tg.factorLinearDarkening())
+
+	// Bands across the middle of the ring, away from the nails
+	const bands = 8
+	left, right, top, bottom := 30, 130, 55, 105
+	sum := 0.0
+	for band := 0; band < bands; band++ {
+		target, piece, count := 0.0, 0.0, 0
+		for y := top; y < bottom; y++ {
+			for x := left + band*(right-left)/bands; x < left+(band+1)*(right-left)/bands; x++ {
+				target += float64(srgbToLinearTable[reference.NRGBAAt(x, y).R])
+				piece += math.Max(0, 1-float64(lineCounts[y*tg.imgSize+x])*darkening)
+				count++
+			}
+		}
+		diff := (piece - target) / float64(count)
+		sum += diff * diff
+	}
+	return math.Sqrt(sum / bands)
+}
+
+func TestLinearLightRampReproduction(t *testing.T) {
+	// Horizontal gray ramp, black on the left to white on the right
+	ramp := image.NewGray(image.Rect(0, 0, 160, 160))
+	for y := 0; y < 160; y++ {
+		for x := 0; x < 160; x++ {
+			ramp.SetGray(x, y, color.Gray{Y: uint8(x * 255 / 159)})
+		}
+	}
+	imagePath := filepath.Join(t.TempDir(), "ramp.png")
+	file, err := os.Create(imagePath)
+	require.NoError(t, err)
+	require.NoError(t, png.Encode(file, ramp))
+	require.NoError(t, file.Close())
+
+	gammaError := rampBandsError(t, imagePath, false)
+	linearError := rampBandsError(t, imagePath, true)
+	t.Logf("ramp error: sRGB %.4f, linear light %.4f", gammaError, linearError)
+	require.Less(t, linearError, gammaError)
+}
@@ -0,0 +1,276 @@
+package threadGenerator
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// InputType is the kind of source the threads reproduce
+type InputType string
+
+const (
+	// InputPhoto matches the tones of a photograph
+	InputPhoto InputType = "photo"
+	// InputLogo follows the outlines of the filled shapes of a logo
+	InputLogo InputType = "logo"
+	// InputLineDrawing follows the center lines of the strokes of a drawing
+	InputLineDrawing InputType = "line_drawing"
+)
+
+const (
+	// strokeTolerance is the distance in pixels from a stroke within which a
+	// thread covers it, straight threads can't follow a stroke pixel by pixel
+	strokeTolerance = 1
+	// backgroundPenalty is the cost of a thread crossing the background,
+	// relative to the reward of covering a stroke
+	backgroundPenalty = 0.05
+	// minimumStrokeCover is the number of stroke pixels a thread must cover to
+	// be strung, so stray pixels left at the end don't draw lines
+	minimumStrokeCover = 3
+)
+
+// ParseInputType converts an input type name to an InputType
+func ParseInputType(name string) (InputType, error) {
+	switch InputType(strings.ToLower(name)) {
+	case InputPhoto, "":
+		return InputPhoto, nil
+	case InputLogo:
+		return InputLogo, nil
+	case InputLineDrawing:
+		return InputLineDrawing, nil
+	default:
+		return "", fmt.Errorf("unknown input type %q", name)
+	}
+}
+
+// lineArt reports whether the threads follow strokes instead of tones
+func (tg *ThreadGenerator) lineArt() bool {
+	return tg.inputType == InputLogo || tg.inputType == InputLineDrawing
+}
+
+// loadSourceImage decodes the source, rasterizing SVG documents at the
+// generation size
+func (tg *ThreadGenerator) loadSourceImage() (image.Image, error) {
+	data, err := os.ReadFile(tg.imageName)
+	if err != nil {
+		return nil, err
+	}
+	if isSVG(data) {
+		return RasterizeSVG(bytes.NewReader(data), tg.imgSize)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// loadLineArtTarget loads the strokes the threads should cover, black on
+// white. The source is fitted in the ring rather than cropped so no part of a
+// logo is lost, and transparent areas are read as the white background.
+func (tg *ThreadGenerator) loadLineArtTarget() (*image.NRGBA, error) {
+	img, err := tg.loadSourceImage()
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := image.Rect(0, 0, tg.imgSize, tg.imgSize)
+	fitted := imaging.Fit(img, tg.imgSize, tg.imgSize, imaging.Lanczos)
+	square := image.NewGray(bounds)
+	draw.Draw(square, bounds, image.White, image.Point{}, draw.Src)
+	offset := image.Pt((tg.imgSize-fitted.Bounds().Dx())/2, (tg.imgSize-fitted.Bounds().Dy())/2)
+	draw.Draw(square, fitted.Bounds().Add(offset), fitted, fitted.Bounds().Min, draw.Over)
+
+	ink := inkMask(square)
+	if tg.inputType == InputLogo {
+		ink = maskEdges(ink, tg.imgSize)
+	} else {
+		ink = maskSkeleton(ink, tg.imgSize)
+	}
+
+	// Strokes outside the ring can't be reached by any thread
+	target := image.NewNRGBA(bounds)
+	radius := float64(tg.imgSize) / 2
+	for y := 0; y < tg.imgSize; y++ {
+		for x := 0; x < tg.imgSize; x++ {
+			dx, dy := float64(x)+0.5-radius, float64(y)+0.5-radius
+			if ink[y*tg.imgSize+x] && dx*dx+dy*dy <= radius*radius {
+				target.SetNRGBA(x, y, color.NRGBA{0, 0, 0, 255})
+			} else {
+				target.SetNRGBA(x, y, color.NRGBA{255, 255, 255, 255})
+			}
+		}
+	}
+	return target, nil
+}
+
+// inkMask separates the ink from the background with the threshold of Otsu,
+// the one that best splits the gray levels in two groups
+func inkMask(gray *image.Gray) []bool {
+	var histogram [256]int
+	for _, value := range gray.Pix {
+		histogram[value]++
+	}
+	total, sum := len(gray.Pix), 0.0
+	for value, count := range histogram {
+		sum += float64(value * count)
+	}
+
+	threshold, bestVariance := 128, -1.0
+	backgroundCount, backgroundSum := 0, 0.0
+	for value := 0; value < 255; value++ {
+		backgroundCount += histogram[value]
+		backgroundSum += float64(value * histogram[value])
+		foregroundCount := total - backgroundCount
+		if backgroundCount == 0 || foregroundCount == 0 {
+			continue
+		}
+		meanDiff := backgroundSum/float64(backgroundCount) - (sum-backgroundSum)/float64(foregroundCount)
+		variance := float64(backgroundCount) * float64(foregroundCount) * meanDiff * meanDiff
+		if variance > bestVariance {
+			threshold, bestVariance = value, variance
+		}
+	}
+
+	mask := make([]bool, total)
+	for i, value := range gray.Pix {
+		mask[i] = int(value) <= threshold
+	}
+	return mask
+}
+
+// maskEdges keeps the ink pixels touching the background
+func maskEdges(mask []bool, size int) []bool {
+	edges := make([]bool, len(mask))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if !mask[y*size+x] {
+				continue
+			}
+			for _, n := range [][2]int{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+				if n[0] >= 0 && n[1] >= 0 && n[0] < size && n[1] < size && !mask[n[1]*size+n[0]] {
+					edges[y*size+x] = true
+					break
+				}
+			}
+		}
+	}
+	return edges
+}
+
+// maskSkeleton thins the ink down to lines of one pixel with the algorithm of
+// Zhang and Suen
+func maskSkeleton(mask []bool, size int) []bool {
+	skeleton := append([]bool(nil), mask...)
+	at := func(x, y int) bool {
+		return x >= 0 && y >= 0 && x < size && y < size && skeleton[y*size+x]
+	}
+	for changed := true; changed; {
+		changed = false
+		for pass := 0; pass < 2; pass++ {
+			var removed []int
+			for y := 0; y < size; y++ {
+				for x := 0; x < size; x++ {
+					if !skeleton[y*size+x] {
+						continue
+					}
+					// Neighbours clockwise from the top
+					n := [8]bool{at(x, y-1), at(x+1, y-1), at(x+1, y), at(x+1, y+1), at(x, y+1), at(x-1, y+1), at(x-1, y), at(x-1, y-1)}
+					neighbours, transitions := 0, 0
+					for i := range n {
+						if n[i] {
+							neighbours++
+						}
+						if !n[i] && n[(i+1)%8] {
+							transitions++
+						}
+					}
+					if neighbours < 2 || neighbours > 6 || transitions != 1 {
+						continue
+					}
+					if pass == 0 && (n[0] && n[2] && n[4] || n[2] && n[4] && n[6]) {
+						continue
+					}
+					if pass == 1 && (n[0] && n[2] && n[6] || n[0] && n[4] && n[6]) {
+						continue
+					}
+					removed = append(removed, y*size+x)
+				}
+			}
+			for _, i := range removed {
+				skeleton[i] = false
+			}
+			changed = changed || len(removed) > 0
+		}
+	}
+	return skeleton
+}
+
+// strokeCanvas holds the strokes of a line art target still to be covered.
+// A thread is rewarded for the strokes it covers and pays for the background
+// it crosses, tones play no part.
+type strokeCanvas struct {
+	bounds  image.Rectangle
+	strokes []int8 // 1 for a stroke to cover, 0 once covered, -1 for the background
+}
+
+func newStrokeCanvas(target *image.Gray) *strokeCanvas {
+	bounds := target.Bounds()
+	canvas := &strokeCanvas{bounds: bounds, strokes: make([]int8, bounds.Dx()*bounds.Dy())}
+	for i := range canvas.strokes {
+		canvas.strokes[i] = -1
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if target.GrayAt(x, y).Y >= 128 {
+				continue
+			}
+			for ny := y - strokeTolerance; ny <= y+strokeTolerance; ny++ {
+				for nx := x - strokeTolerance; nx <= x+strokeTolerance; nx++ {
+					if point := image.Pt(nx, ny); point.In(bounds) {
+						canvas.strokes[canvas.index(point)] = 1
+					}
+				}
+			}
+		}
+	}
+	return canvas
+}
+
+func (c *strokeCanvas) index(point image.Point) int {
+	return (point.Y-c.bounds.Min.Y)*c.bounds.Dx() + point.X - c.bounds.Min.X
+}
+
+// weight returns the share of a line covering strokes less the penalty of the
+// background it crosses, shifted by the penalty so lines crossing the
+// background to reach other strokes stay positive. It is 0 when the line
+// covers too few strokes.
+func (c *strokeCanvas) weight(line []image.Point) float64 {
+	covered, background := 0, 0
+	for _, point := range line {
+		if !point.In(c.bounds) {
+			continue
+		}
+		switch c.strokes[c.index(point)] {
+		case 1:
+			covered++
+		case -1:
+			background++
+		}
+	}
+	if covered < minimumStrokeCover {
+		return 0
+	}
+	return (float64(covered)-backgroundPenalty*float64(background))/float64(len(line)) + backgroundPenalty
+}
+
+// cover marks the strokes under a pixel of a chosen thread as covered
+func (c *strokeCanvas) cover(point image.Point) {
+	if point.In(c.bounds) && c.strokes[c.index(point)] == 1 {
+		c.strokes[c.index(point)] = 0
+	}
+}
@@ -0,0 +1,90 @@
+package threadGenerator
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskSkeleton(t *testing.T) {
+	// A bar seven pixels thick thins down to one pixel per column
+	const size = 40
+	mask := make([]bool, size*size)
+	for y := 10; y < 17; y++ {
+		for x := 5; x < 35; x++ {
+			mask[y*size+x] = true
+		}
+	}
+	skeleton := maskSkeleton(mask, size)
+	for x := 10; x < 30; x++ {
+		count := 0
+		for y := 0; y < size; y++ {
+			if skeleton[y*size+x] {
+				count++
+				require.Equal(t, 13, y, "the skeleton runs through the middle of the bar")
+			}
+		}
+		require.Equal(t, 1, count)
+	}
+
+	edges := maskEdges(mask, size)
+	require.True(t, edges[10*size+20])
+	require.False(t, edges[13*size+20], "the inside of the bar is no edge")
+}
+
+// strokePrecision returns the share of the thread pixels lying on the strokes
+// of the target, within the stroke tolerance
+func strokePrecision(tg *ThreadGenerator, target *image.NRGBA) float64 {
+	gray := image.NewGray(target.Bounds())
+	for i := range gray.Pix {
+		gray.Pix[i] = target.Pix[i*4]
+	}
+	strokes := newStrokeCanvas(gray)
+	onStrokes, total := 0, 0
+	for _, path := range tg.GetPathsList() {
+		for _, point := range tg.pathsDictionary[tg.getPairKey(path.StartingNail, path.EndingNail)] {
+			if point.In(strokes.bounds) {
+				total++
+				if strokes.strokes[strokes.index(point)] == 1 {
+					onStrokes++
+				}
+			}
+		}
+	}
+	return float64(onStrokes) / float64(total)
+}
+
+func TestLogoFollowsOutlines(t *testing.T) {
+	logoPath := filepath.Join(t.TempDir(), "logo.svg")
+	require.NoError(t, os.WriteFile(logoPath, []byte(`<svg viewBox="0 0 100 100">
+  <rect x="25" y="25" width="50" height="50" transform="rotate(20 50 50)"/>
+</svg>`), 0o644))
+
+	precision := map[InputType]float64{}
+	var target *image.NRGBA
+	for _, inputType := range []InputType{InputPhoto, InputLogo} {
+		tg := NewThreadGenerator(Config{
+			NailsQuantity:     120,
+			ImgSize:           150,
+			MaxPaths:          300,
+			MinimumDifference: 10,
+			BrightnessFactor:  50,
+			PhysicalRadius:    100,
+			InputType:         inputType,
+		})
+		_, err := tg.Generate(Args{ImageName: logoPath})
+		require.NoError(t, err)
+		require.NotEmpty(t, tg.GetPathsList())
+		if target == nil {
+			tg.inputType = InputLogo
+			target, err = tg.loadLineArtTarget()
+			require.NoError(t, err)
+		}
+		precision[inputType] = strokePrecision(tg, target)
+	}
+	t.Logf("share of thread on the outlines: photo %.3f, logo %.3f", precision[InputPhoto], precision[InputLogo])
+	require.Greater(t, precision[InputLogo], 2*precision[InputPhoto])
+}
@@ -0,0 +1,33 @@
+package threadGenerator
+
+import "math"
+
+const (
+	// pointBytes is the size of a pixel of a line in the dictionary, with the
+	// spare capacity left by growing the slice
+	pointBytes = 24
+	// pairBytes is the map entry, key and slice header of a line in the dictionary
+	pairBytes = 128
+	// pixelBytes covers the source, canvas and line count images of a pixel and
+	// the copies made while loading the image
+	pixelBytes = 32
+	// baseMemory covers the rest of a generation, whatever its size
+	baseMemory = 32 << 20
+)
+
+// EstimatedMemory estimates the heap a generation holds, in bytes. The line
+// dictionary keeping the pixels of every pair of nails dominates, the images of
+// the generation size come next.
+func (c Config) EstimatedMemory() int64 {
+	nails := 0
+	for _, ring := range c.NailRings() {
+		nails += ring.NailsQuantity
+	}
+	pairs := int64(nails) * int64(max(0, nails-1)) / 2
+	// A chord between two random points of a circle is 4/π of its radius long
+	// on average, the ring spans the generation size
+	chordPixels := int64(math.Ceil(2*float64(c.ImgSize)/math.Pi)) + 1
+	pixels := int64(c.ImgSize) * int64(c.ImgSize)
+
+	return baseMemory + pairs*(chordPixels*pointBytes+pairBytes) + pixels*pixelBytes
+}
@@ -0,0 +1,39 @@
+package threadGenerator
+
+import (
+	"image"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimatedMemory(t *testing.T) {
+	config := Config{NailsQuantity: 120, ImgSize: 300, PhysicalRadius: 300}
+
+	// The estimate covers the line dictionary it is mostly made of
+	tg := NewThreadGenerator(config)
+	nails := tg.getNailsListFromImage(image.NewGray(image.Rect(0, 0, config.ImgSize, config.ImgSize)))
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	dictionary := tg.generateDictionary(nails)
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(dictionary)
+
+	held := int64(after.HeapAlloc) - int64(before.HeapAlloc)
+	estimate := config.EstimatedMemory()
+	require.Greater(t, estimate, held)
+	require.Less(t, estimate, 3*held, "the estimate stays close to the memory held")
+
+	// Rings add up their nails
+	rings := config
+	rings.Rings = []Ring{{NailsQuantity: 80, Radius: 300}, {NailsQuantity: 40, Radius: 200}}
+	require.Equal(t, estimate, rings.EstimatedMemory())
+
+	// The dictionary grows with the square of the nails
+	denser := config
+	denser.NailsQuantity = 240
+	require.Greater(t, denser.EstimatedMemory(), 2*estimate)
+}
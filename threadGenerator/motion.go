@@ -0,0 +1,225 @@
+package threadGenerator
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+type (
+	// MotionStats summarises the machine motion of a stringing program
+	MotionStats struct {
+		Moves            int           // Number of move commands in the program
+		MergedMoves      int           // Moves removed or merged by the optimizer
+		RotaryTravel     float64       // Total rotary travel in rotation axis units
+		RotaryTurns      float64       // Total rotary travel in full turns
+		MaxTwistTurns    float64       // Largest accumulated rotation away from home, in turns
+		EstimatedRunTime time.Duration // Sum of the move durations at their feed rates
+	}
+
+	// motionPlanner tracks the unbounded rotary position of the ring so that
+	// every approach takes the shortest way around without twisting the feed
+	motionPlanner struct {
+		unitsPerTurn int     // Rotation axis units of a full turn, the nails of a single ring layout
+		maxTwist     float64 // Largest allowed |position| in rotation units, 0 for no limit
+		position     float64 // Accumulated rotary position in rotation units
+		ring         int     // Ring the needle is lined up with on a multi ring layout
+	}
+)
+
+func newMotionPlanner(unitsPerTurn int, maxTwistTurns float64) *motionPlanner {
+	maxTwist := 0.0
+	if maxTwistTurns > 0 {
+		// Less than a full turn would leave some nails unreachable
+		maxTwist = math.Max(maxTwistTurns, 1) * float64(unitsPerTurn)
+	}
+	return &motionPlanner{unitsPerTurn: unitsPerTurn, maxTwist: maxTwist}
+}
+
+// approach moves to the given rotary coordinate (modulo a turn) and returns
+// the absolute rotary coordinate to send to the machine
+func (p *motionPlanner) approach(target float64) float64 {
+	n := float64(p.unitsPerTurn)
+	forward := math.Mod(target-p.position, n)
+	if forward < 0 {
+		forward += n
+	}
+	backward := forward - n
+
+	shortest, other := forward, backward
+	if math.Abs(backward) < forward {
+		shortest, other = backward, forward
+	}
+
+	delta := shortest
+	if p.exceedsTwist(p.position + shortest) {
+		// Unwind the feed by going the long way around
+		delta = other
+		if p.exceedsTwist(p.position+other) && math.Abs(p.position+shortest) < math.Abs(p.position+other) {
+			delta = shortest
+		}
+	}
+
+	p.position += delta
+	return p.position
+}
+
+// advance moves by a fixed delta, used for the wrap around a nail which must
+// always happen in the same direction
+func (p *motionPlanner) advance(delta float64) float64 {
+	p.position += delta
+	return p.position
+}
+
+func (p *motionPlanner) exceedsTwist(position float64) bool {
+	return p.maxTwist > 0 && math.Abs(position) > p.maxTwist
+}
+
+// OptimizeProgram removes moves that do not change any axis and merges
+// consecutive single axis moves going the same direction at the same feed.
+// It returns the number of moves removed.
+func OptimizeProgram(program *GcodeProgram) int {
+	optimized := make([]GcodeCommand, 0, len(program.Commands))
+	positions := map[string]float64{}
+	absolute := true
+	removed := 0
+	lastStart := 0.0 // Start position of the last emitted single axis move
+	lastMove := -1   // Index in optimized of the last move still open for merging
+
+	for _, cmd := range program.Commands {
+		switch cmd.Type {
+		case CommandHome, CommandSetPosition:
+			for _, axis := range cmd.Axes {
+				positions[axis.Axis] = axis.Value
+			}
+			lastMove = -1
+		case CommandAbsoluteMode:
+			absolute = true
+			lastMove = -1
+		case CommandRelativeMode:
+			absolute = false
+			lastMove = -1
+		case CommandMove:
+			if !absolute {
+				// Relative moves are left alone, the tracked positions become unknown
+				positions = map[string]float64{}
+				break
+			}
+			if !movesAnyAxis(cmd, positions) {
+				removed++
+				continue
+			}
+			if lastMove >= 0 && lastMove == len(optimized)-1 && canMerge(optimized[lastMove], cmd, lastStart, positions) {
+				optimized[lastMove].Axes = []AxisValue{cmd.Axes[0]}
+				positions[cmd.Axes[0].Axis] = cmd.Axes[0].Value
+				removed++
+				continue
+			}
+			lastMove = -1
+			if len(cmd.Axes) == 1 {
+				if current, known := positions[cmd.Axes[0].Axis]; known {
+					lastStart = current
+					lastMove = len(optimized)
+				}
+			}
+			for _, axis := range cmd.Axes {
+				positions[axis.Axis] = axis.Value
+			}
+		default:
+			lastMove = -1
+		}
+		optimized = append(optimized, cmd)
+	}
+
+	program.Commands = optimized
+	return removed
+}
+
+// movesAnyAxis reports whether the move changes at least one known axis position
+func movesAnyAxis(cmd GcodeCommand, positions map[string]float64) bool {
+	for _, axis := range cmd.Axes {
+		current, known := positions[axis.Axis]
+		if !known || current != axis.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// canMerge reports whether next can be folded into prev without changing the
+// path: both move the same single axis, in the same direction, at the same feed
+func canMerge(prev, next GcodeCommand, prevStart float64, positions map[string]float64) bool {
+	if len(prev.Axes) != 1 || len(next.Axes) != 1 {
+		return false
+	}
+	if prev.Axes[0].Axis != next.Axes[0].Axis || prev.Feed != next.Feed {
+		return false
+	}
+	end := positions[prev.Axes[0].Axis]
+	return (end-prevStart)*(next.Axes[0].Value-end) > 0
+}
+
+// AnalyzeProgram walks a program and computes its rotary travel, accumulated
+// twist and estimated run time. Feed rates are in axis units per minute.
+func AnalyzeProgram(program *GcodeProgram, rotationAxis string, unitsPerTurn int) MotionStats {
+	stats := MotionStats{}
+	positions := map[string]float64{}
+	absolute := true
+	home := 0.0
+	offset := 0.0 // Rotary distance hidden by G92 resets
+
+	for _, cmd := range program.Commands {
+		switch cmd.Type {
+		case CommandHome:
+			for _, axis := range cmd.Axes {
+				positions[axis.Axis] = axis.Value
+				if axis.Axis == rotationAxis {
+					home = axis.Value
+					offset = 0
+				}
+			}
+		case CommandSetPosition:
+			for _, axis := range cmd.Axes {
+				if axis.Axis == rotationAxis {
+					offset += positions[axis.Axis] - axis.Value
+				}
+				positions[axis.Axis] = axis.Value
+			}
+		case CommandAbsoluteMode:
+			absolute = true
+		case CommandRelativeMode:
+			absolute = false
+		case CommandMove:
+			stats.Moves++
+			longest := 0.0
+			for _, axis := range cmd.Axes {
+				target := axis.Value
+				if !absolute {
+					target = positions[axis.Axis] + axis.Value
+				}
+				distance := math.Abs(target - positions[axis.Axis])
+				longest = math.Max(longest, distance)
+				positions[axis.Axis] = target
+				if axis.Axis == rotationAxis {
+					stats.RotaryTravel += distance
+					twist := math.Abs(target+offset-home) / float64(unitsPerTurn)
+					stats.MaxTwistTurns = math.Max(stats.MaxTwistTurns, twist)
+				}
+			}
+			if cmd.Feed > 0 {
+				stats.EstimatedRunTime += time.Duration(longest / float64(cmd.Feed) * float64(time.Minute))
+			}
+		}
+	}
+
+	if unitsPerTurn > 0 {
+		stats.RotaryTurns = stats.RotaryTravel / float64(unitsPerTurn)
+	}
+	return stats
+}
+
+// String formats the stats for logs and G-code headers
+func (s MotionStats) String() string {
+	return fmt.Sprintf("%d moves, rotary travel %.1f turns, max twist %.2f turns, estimated run time %s",
+		s.Moves, s.RotaryTurns, s.MaxTwistTurns, s.EstimatedRunTime.Round(time.Second))
+}
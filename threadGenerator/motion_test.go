@@ -0,0 +1,182 @@
+package threadGenerator
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMotionPlannerApproach(t *testing.T) {
+	// Without a limit every approach takes the shortest way around
+	planner := newMotionPlanner(200, 0)
+	require.Equal(t, 10.0, planner.approach(10))
+	require.Equal(t, -10.0, planner.approach(190), "going back past home is shorter")
+	require.Equal(t, 90.0, planner.approach(90), "half a turn goes forward")
+	for range 5 {
+		planner.approach(planner.position + 150)
+	}
+	require.Equal(t, -160.0, planner.position, "the shortest ways add up")
+
+	// A limit unwinds the feed by going the long way around
+	planner = newMotionPlanner(200, 1)
+	require.Equal(t, 90.0, planner.approach(90))
+	require.Equal(t, 180.0, planner.approach(180))
+	require.Equal(t, 70.0, planner.approach(70), "forward would twist past a turn")
+	require.Equal(t, -10.0, planner.approach(190))
+
+	// The wrap around a nail ignores the limit, the next approach unwinds it
+	planner = newMotionPlanner(200, 1)
+	require.Equal(t, 100.0, planner.approach(100))
+	require.Equal(t, 195.0, planner.approach(195))
+	require.Equal(t, 205.0, planner.advance(10))
+	require.Equal(t, 10.0, planner.approach(10))
+}
+
+func TestMotionPlannerTwistLimit(t *testing.T) {
+	tests := []struct {
+		maxTwistTurns float64
+		maxTwist      float64
+	}{
+		{maxTwistTurns: 0.25, maxTwist: 300}, // Less than a turn is raised to one
+		{maxTwistTurns: 1, maxTwist: 300},
+		{maxTwistTurns: 2.5, maxTwist: 750},
+	}
+	for _, tt := range tests {
+		planner := newMotionPlanner(300, tt.maxTwistTurns)
+		require.Equal(t, tt.maxTwist, planner.maxTwist)
+
+		random := rand.New(rand.NewSource(int64(tt.maxTwist)))
+		for range 5000 {
+			target := float64(random.Intn(300))
+			position := planner.approach(target)
+			require.LessOrEqual(t, math.Abs(position), tt.maxTwist, "the twist stays within the limit")
+			require.Equal(t, target, math.Mod(math.Mod(position, 300)+300, 300), "the approach reaches the nail")
+		}
+	}
+}
+
+// homed starts a program homed at zero on both axes in absolute mode
+func homed() *GcodeProgram {
+	program := &GcodeProgram{}
+	program.Home(AxisValue{Axis: "X", Value: 0}, AxisValue{Axis: "A", Value: 0})
+	program.Absolute("")
+	return program
+}
+
+func TestOptimizeProgram(t *testing.T) {
+	x := func(value float64) AxisValue { return AxisValue{Axis: "X", Value: value} }
+	a := func(value float64) AxisValue { return AxisValue{Axis: "A", Value: value} }
+
+	tests := []struct {
+		name    string
+		build   func(program *GcodeProgram)
+		moves   [][]AxisValue // Axes of the remaining moves
+		removed int
+	}{
+		{
+			name: "moves to the current position are removed",
+			build: func(p *GcodeProgram) {
+				p.Move(1000, "", a(0))
+				p.Move(1000, "", x(5))
+				p.Move(1000, "", x(5), a(0))
+			},
+			moves:   [][]AxisValue{{x(5)}},
+			removed: 2,
+		},
+		{
+			name: "same direction moves are merged",
+			build: func(p *GcodeProgram) {
+				p.Move(1000, "", a(10))
+				p.Move(1000, "", a(20))
+				p.Move(1000, "", a(35))
+			},
+			moves:   [][]AxisValue{{a(35)}},
+			removed: 2,
+		},
+		{
+			name: "a reversal is kept",
+			build: func(p *GcodeProgram) {
+				p.Move(1000, "", a(10))
+				p.Move(1000, "", a(5))
+			},
+			moves: [][]AxisValue{{a(10)}, {a(5)}},
+		},
+		{
+			name: "a feed change is kept",
+			build: func(p *GcodeProgram) {
+				p.Move(1000, "", a(10))
+				p.Move(500, "", a(20))
+			},
+			moves: [][]AxisValue{{a(10)}, {a(20)}},
+		},
+		{
+			name: "moves of other axes are kept",
+			build: func(p *GcodeProgram) {
+				p.Move(1000, "", a(10))
+				p.Move(1000, "", x(10))
+				p.Move(1000, "", x(20), a(20))
+				p.Move(1000, "", x(30), a(30))
+			},
+			moves: [][]AxisValue{{a(10)}, {x(10)}, {x(20), a(20)}, {x(30), a(30)}},
+		},
+		{
+			name: "a pause between moves is kept",
+			build: func(p *GcodeProgram) {
+				p.Move(1000, "", a(10))
+				p.Pause("Check the thread")
+				p.Move(1000, "", a(20))
+			},
+			moves: [][]AxisValue{{a(10)}, {a(20)}},
+		},
+		{
+			name: "relative moves are left alone",
+			build: func(p *GcodeProgram) {
+				p.Relative("")
+				p.Move(1000, "", a(10))
+				p.Move(1000, "", a(10))
+				p.Absolute("")
+				p.Move(1000, "", a(20))
+				p.Move(1000, "", a(20))
+			},
+			moves:   [][]AxisValue{{a(10)}, {a(10)}, {a(20)}},
+			removed: 1,
+		},
+		{
+			name: "a set position resets the tracked position",
+			build: func(p *GcodeProgram) {
+				p.Move(1000, "", a(210))
+				p.SetPosition("", a(10))
+				p.Move(1000, "", a(10))
+				p.Move(1000, "", a(20))
+			},
+			moves:   [][]AxisValue{{a(210)}, {a(20)}},
+			removed: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := homed()
+			tt.build(program)
+			require.Equal(t, tt.removed, OptimizeProgram(program))
+
+			var moves [][]AxisValue
+			for _, cmd := range program.Commands {
+				if cmd.Type == CommandMove {
+					moves = append(moves, cmd.Axes)
+				}
+			}
+			require.Equal(t, tt.moves, moves)
+		})
+	}
+}
+
+func TestOptimizeProgramKeepsUnknownStart(t *testing.T) {
+	// Without a home the start of a move is unknown, it can't be merged
+	program := &GcodeProgram{}
+	program.Move(1000, "", AxisValue{Axis: "A", Value: 10})
+	program.Move(1000, "", AxisValue{Axis: "A", Value: 20})
+	require.Equal(t, 0, OptimizeProgram(program))
+	require.Len(t, program.Commands, 2)
+}
@@ -0,0 +1,405 @@
+package threadGenerator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PathListFormat is the encoding of a path list document
+type PathListFormat string
+
+const (
+	PathListJSON   PathListFormat = "json"
+	PathListCSV    PathListFormat = "csv"
+	PathListBinary PathListFormat = "binary"
+)
+
+const (
+	// pathListKind identifies path list documents in every encoding
+	pathListKind = "thread-art-paths"
+	// PathListVersion is the version of the document written by WritePathList,
+	// bare arrays of paths from before the document are read as version 0
+	PathListVersion = 1
+	// pathListMagic starts the binary encoding
+	pathListMagic = "TAPL"
+	// maxBinaryLength bounds the lengths read from a binary header, so a corrupt
+	// file fails instead of allocating gigabytes
+	maxBinaryLength = 1 << 24
+)
+
+type (
+	// PathListDocument is a paths list with everything needed to interpret it
+	// without the composition it was generated for
+	PathListDocument struct {
+		Kind             string         `json:"kind"`
+		Version          int            `json:"version"`
+		GeneratorVersion string         `json:"generator_version"`
+		Config           Config         `json:"config"`
+		Layout           PathListLayout `json:"layout"`
+		Paths            []Path         `json:"-"`
+	}
+
+	// PathListLayout is the nail layout the paths refer to
+	PathListLayout struct {
+		Rings []Ring         `json:"rings"`
+		Nails []NailPosition `json:"nails"`
+	}
+
+	// NailPosition is the physical position of a nail, in mm from the center of
+	// the frame with the y axis pointing down as in the preview
+	NailPosition struct {
+		Ring  int     `json:"ring"`
+		Index int     `json:"index"` // Index of the nail on its ring
+		X     float64 `json:"x"`
+		Y     float64 `json:"y"`
+	}
+
+	// pathListJSON is the JSON encoding of a document, paths are compact pairs
+	pathListJSON struct {
+		PathListDocument
+		Paths [][2]int `json:"paths"`
+	}
+)
+
+// PathListFormats returns the supported encodings
+func PathListFormats() []PathListFormat {
+	return []PathListFormat{PathListJSON, PathListCSV, PathListBinary}
+}
+
+// ParsePathListFormat converts a format name, empty for JSON
+func ParsePathListFormat(name string) (PathListFormat, error) {
+	switch PathListFormat(strings.ToLower(name)) {
+	case "", PathListJSON:
+		return PathListJSON, nil
+	case PathListCSV:
+		return PathListCSV, nil
+	case PathListBinary:
+		return PathListBinary, nil
+	default:
+		return "", fmt.Errorf("unknown path list format %q, expected json, csv or binary", name)
+	}
+}
+
+// Extension returns the file extension of the format
+func (f PathListFormat) Extension() string {
+	switch f {
+	case PathListCSV:
+		return ".csv"
+	case PathListBinary:
+		return ".bin"
+	default:
+		return ".json"
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f PathListFormat) ContentType() string {
+	switch f {
+	case PathListCSV:
+		return "text/csv"
+	case PathListBinary:
+		return "application/octet-stream"
+	default:
+		return "application/json"
+	}
+}
+
+// Config returns the configuration the generator currently runs with
+func (tg *ThreadGenerator) Config() Config {
+	return Config{
+		NailsQuantity:     tg.nailsQuantity,
+		ImgSize:           tg.imgSize,
+		MaxPaths:          tg.maxPaths,
+		StartingNail:      tg.startingNail,
+		MinimumDifference: tg.minimumDifference,
+		BrightnessFactor:  tg.brightnessFactor,
+		ImageContrast:     tg.imageContrast,
+		PhysicalRadius:    tg.physicalRadius,
+		RotationAxis:      tg.rotationAxis,
+		NeedleAxis:        tg.needleAxis,
+		SpindleAxis:       tg.spindleAxis,
+		GcodeDialect:      tg.gcodeDialect,
+		MaxTwistTurns:     tg.maxTwistTurns,
+		SpoolLength:       tg.spoolLength,
+		Rings:             tg.rings,
+		RingAxis:          tg.ringAxis,
+		ThreadProfile:     tg.threadProfile,
+		LinearLight:       tg.linearLight,
+		InputType:         tg.inputType,
+		Seed:              tg.seed,
+		SymmetryFold:      tg.symmetryFold,
+		MirrorSymmetry:    tg.mirrorSymmetry,
+	}
+}
+
+// GetPathListDocument returns the paths list with the generator configuration and nail layout
+func (tg *ThreadGenerator) GetPathListDocument() *PathListDocument {
+	return NewPathListDocument(tg.Config(), tg.pathsList)
+}
+
+// NewPathListDocument wraps a paths list generated with the given configuration
+func NewPathListDocument(config Config, paths []Path) *PathListDocument {
+	rings := config.NailRings()
+	return &PathListDocument{
+		Kind:             pathListKind,
+		Version:          PathListVersion,
+		GeneratorVersion: Version,
+		Config:           config,
+		Layout:           PathListLayout{Rings: rings, Nails: nailPositions(rings)},
+		Paths:            paths,
+	}
+}
+
+// nailPositions returns the positions of the nails, numbered ring after ring
+func nailPositions(rings []Ring) []NailPosition {
+	var nails []NailPosition
+	for ringIndex, ring := range rings {
+		for i := 0; i < ring.NailsQuantity; i++ {
+			alpha := float64(i) * 2 * math.Pi / float64(ring.NailsQuantity)
+			nails = append(nails, NailPosition{
+				Ring:  ringIndex,
+				Index: i,
+				X:     roundMicrons(ring.Radius * math.Cos(alpha)),
+				Y:     roundMicrons(ring.Radius * math.Sin(alpha)),
+			})
+		}
+	}
+	return nails
+}
+
+// roundMicrons keeps the coordinates readable, nails are not placed more precisely
+func roundMicrons(mm float64) float64 {
+	return math.Round(mm*1000) / 1000
+}
+
+// WritePathList encodes a document in the given format
+func WritePathList(w io.Writer, document *PathListDocument, format PathListFormat) error {
+	switch format {
+	case PathListJSON:
+		return writePathListJSON(w, document)
+	case PathListCSV:
+		return writePathListCSV(w, document)
+	case PathListBinary:
+		return writePathListBinary(w, document)
+	default:
+		return fmt.Errorf("unknown path list format %q", format)
+	}
+}
+
+// ReadPathList decodes a document in any format, detected from its first
+// bytes. A bare JSON array of paths is read as a version 0 document with
+// only the paths.
+func ReadPathList(r io.Reader) (*PathListDocument, error) {
+	reader := bufio.NewReader(r)
+	head, err := reader.Peek(len(pathListMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read path list: %w", err)
+	}
+	if string(head) == pathListMagic {
+		return readPathListBinary(reader)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read path list: %w", err)
+	}
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0:
+		return nil, errors.New("path list is empty")
+	case trimmed[0] == '[':
+		var paths []Path
+		if err := json.Unmarshal(trimmed, &paths); err != nil {
+			return nil, fmt.Errorf("failed to decode paths list: %w", err)
+		}
+		return &PathListDocument{Paths: paths}, nil
+	case trimmed[0] == '{':
+		return readPathListJSON(trimmed)
+	default:
+		return readPathListCSV(trimmed)
+	}
+}
+
+func writePathListJSON(w io.Writer, document *PathListDocument) error {
+	encoded := pathListJSON{PathListDocument: *document, Paths: make([][2]int, len(document.Paths))}
+	for i, path := range document.Paths {
+		encoded.Paths[i] = [2]int{path.StartingNail, path.EndingNail}
+	}
+	return json.NewEncoder(w).Encode(encoded)
+}
+
+func readPathListJSON(data []byte) (*PathListDocument, error) {
+	var decoded pathListJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode path list document: %w", err)
+	}
+	document := decoded.PathListDocument
+	if err := document.checkVersion(); err != nil {
+		return nil, err
+	}
+	document.Paths = make([]Path, len(decoded.Paths))
+	for i, pair := range decoded.Paths {
+		document.Paths[i] = Path{StartingNail: pair[0], EndingNail: pair[1]}
+	}
+	return &document, nil
+}
+
+// metadata returns the document without its paths, as stored in the CSV
+// comments and the binary header
+func (d *PathListDocument) metadata() ([]byte, error) {
+	metadata := *d
+	metadata.Paths = nil
+	return json.Marshal(metadata)
+}
+
+func (d *PathListDocument) checkVersion() error {
+	if d.Kind != pathListKind {
+		return fmt.Errorf("not a path list document, kind is %q", d.Kind)
+	}
+	if d.Version < 1 || d.Version > PathListVersion {
+		return fmt.Errorf("unsupported path list version %d, this generator reads up to %d", d.Version, PathListVersion)
+	}
+	return nil
+}
+
+// writePathListCSV writes one row per path after a comment holding the metadata
+func writePathListCSV(w io.Writer, document *PathListDocument) error {
+	metadata, err := document.metadata()
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "# %s\n", metadata); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"step", "starting_nail", "ending_nail"}); err != nil {
+		return err
+	}
+	for i, path := range document.Paths {
+		if err := writer.Write([]string{strconv.Itoa(i), strconv.Itoa(path.StartingNail), strconv.Itoa(path.EndingNail)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func readPathListCSV(data []byte) (*PathListDocument, error) {
+	document := &PathListDocument{}
+	if metadata, ok := bytes.CutPrefix(data, []byte("# ")); ok {
+		line, _, _ := bytes.Cut(metadata, []byte("\n"))
+		if err := json.Unmarshal(line, document); err != nil {
+			return nil, fmt.Errorf("failed to decode path list metadata: %w", err)
+		}
+		if err := document.checkVersion(); err != nil {
+			return nil, err
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 3
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode path list rows: %w", err)
+	}
+	for i, record := range records {
+		if i == 0 && record[0] == "step" {
+			continue
+		}
+		start, err := strconv.Atoi(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("row %d has an invalid starting nail: %w", i, err)
+		}
+		end, err := strconv.Atoi(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("row %d has an invalid ending nail: %w", i, err)
+		}
+		document.Paths = append(document.Paths, Path{StartingNail: start, EndingNail: end})
+	}
+	return document, nil
+}
+
+// writePathListBinary writes the magic, the version, the metadata as length
+// prefixed JSON, then the paths as pairs of 16 bit nails, all little endian.
+// The nail positions are left out, they are computed back from the rings.
+func writePathListBinary(w io.Writer, document *PathListDocument) error {
+	compact := *document
+	compact.Layout.Nails = nil
+	metadata, err := compact.metadata()
+	if err != nil {
+		return err
+	}
+
+	buffer := bytes.NewBufferString(pathListMagic)
+	binary.Write(buffer, binary.LittleEndian, uint16(PathListVersion))
+	binary.Write(buffer, binary.LittleEndian, uint32(len(metadata)))
+	buffer.Write(metadata)
+	binary.Write(buffer, binary.LittleEndian, uint32(len(document.Paths)))
+	for i, path := range document.Paths {
+		if path.StartingNail < 0 || path.EndingNail < 0 || path.StartingNail > math.MaxUint16 || path.EndingNail > math.MaxUint16 {
+			return fmt.Errorf("path %d joins nails out of the binary range", i)
+		}
+		binary.Write(buffer, binary.LittleEndian, [2]uint16{uint16(path.StartingNail), uint16(path.EndingNail)})
+	}
+
+	_, err = buffer.WriteTo(w)
+	return err
+}
+
+func readPathListBinary(r io.Reader) (*PathListDocument, error) {
+	var header struct {
+		Magic    [4]byte
+		Version  uint16
+		Metadata uint32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("failed to read path list header: %w", err)
+	}
+	if header.Metadata > maxBinaryLength {
+		return nil, fmt.Errorf("path list metadata of %d bytes is too long", header.Metadata)
+	}
+
+	metadata := make([]byte, header.Metadata)
+	if _, err := io.ReadFull(r, metadata); err != nil {
+		return nil, fmt.Errorf("failed to read path list metadata: %w", err)
+	}
+	document := &PathListDocument{}
+	if err := json.Unmarshal(metadata, document); err != nil {
+		return nil, fmt.Errorf("failed to decode path list metadata: %w", err)
+	}
+	if int(header.Version) != document.Version {
+		return nil, fmt.Errorf("path list header version %d does not match the metadata version %d", header.Version, document.Version)
+	}
+	if err := document.checkVersion(); err != nil {
+		return nil, err
+	}
+	document.Layout.Nails = nailPositions(document.Layout.Rings)
+
+	var count uint32
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, fmt.Errorf("failed to read path count: %w", err)
+	}
+	if count > maxBinaryLength {
+		return nil, fmt.Errorf("path list of %d paths is too long", count)
+	}
+	pairs := make([][2]uint16, count)
+	if err := binary.Read(r, binary.LittleEndian, pairs); err != nil {
+		return nil, fmt.Errorf("failed to read paths: %w", err)
+	}
+	document.Paths = make([]Path, count)
+	for i, pair := range pairs {
+		document.Paths[i] = Path{StartingNail: int(pair[0]), EndingNail: int(pair[1])}
+	}
+	return document, nil
+}
@@ -0,0 +1,47 @@
+package threadGenerator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathListRoundTrip(t *testing.T) {
+	config := DefaultConfig()
+	config.Rings = []Ring{{NailsQuantity: 8, Radius: 600}, {NailsQuantity: 4, Radius: 300, MinimumDifference: 1}}
+	paths := []Path{{0, 4}, {4, 9}, {9, 2}, {2, 11}}
+	document := NewPathListDocument(config, paths)
+
+	require.Equal(t, PathListVersion, document.Version)
+	require.Len(t, document.Layout.Nails, 12)
+	require.Equal(t, NailPosition{Ring: 1, Index: 1, X: 0, Y: 300}, document.Layout.Nails[9])
+	require.Equal(t, 10, document.Layout.Rings[0].MinimumDifference, "rings are resolved with the composition minimum difference")
+
+	for _, format := range PathListFormats() {
+		t.Run(string(format), func(t *testing.T) {
+			var encoded bytes.Buffer
+			require.NoError(t, WritePathList(&encoded, document, format))
+
+			decoded, err := ReadPathList(&encoded)
+			require.NoError(t, err)
+			require.Equal(t, document, decoded)
+		})
+	}
+}
+
+func TestReadLegacyPathList(t *testing.T) {
+	decoded, err := ReadPathList(strings.NewReader(`[{"StartingNail":0,"EndingNail":150},{"StartingNail":150,"EndingNail":12}]`))
+	require.NoError(t, err)
+	require.Equal(t, 0, decoded.Version)
+	require.Equal(t, []Path{{0, 150}, {150, 12}}, decoded.Paths)
+}
+
+func TestReadPathListRejectsNewerVersions(t *testing.T) {
+	_, err := ReadPathList(strings.NewReader(`{"kind":"thread-art-paths","version":99,"paths":[]}`))
+	require.ErrorContains(t, err, "unsupported path list version 99")
+
+	_, err = ReadPathList(strings.NewReader(`{"version":1}`))
+	require.ErrorContains(t, err, "not a path list document")
+}
@@ -0,0 +1,71 @@
+package threadGenerator
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+)
+
+// DevelVersion is the version of a build that did not set Version
+const DevelVersion = "devel"
+
+// Version identifies the generator code in path lists and content hashes.
+// Builds set it with
+// -ldflags "-X github.com/Damione1/thread-art-generator/threadGenerator.Version=<version>",
+// go run, tests and plain builds are DevelVersion.
+var Version = DevelVersion
+
+// ReproducibleVersion reports whether Version identifies the generator code,
+// devel builds and builds of uncommitted changes share their version with
+// other code so their hashes can't vouch for a reproduction
+func ReproducibleVersion() bool {
+	return Version != DevelVersion && !strings.HasSuffix(Version, "-dirty")
+}
+
+// ContentHash identifies everything a generation depends on: the bytes of the
+// source image, the configuration and the generator version. Generations with
+// the same hash produce the same paths list.
+func ContentHash(source []byte, config Config) string {
+	hash := sha256.New()
+	// Plain values, the encoding can't fail
+	encoded, _ := json.Marshal(config)
+	fmt.Fprintf(hash, "%s\n%s\n", Version, encoded)
+	hash.Write(source)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// ContentHash returns the content hash of the source image and configuration
+// of the generator
+func (tg *ThreadGenerator) ContentHash() (string, error) {
+	source, err := os.ReadFile(tg.imageName)
+	if err != nil {
+		return "", err
+	}
+	return ContentHash(source, tg.Config()), nil
+}
+
+// newTieBreaker returns the random source drawing among equal weights, nil
+// without a seed
+func (tg *ThreadGenerator) newTieBreaker() *rand.Rand {
+	if tg.seed == 0 {
+		return nil
+	}
+	return rand.New(rand.NewPCG(uint64(tg.seed), 0))
+}
+
+// breakTie picks among lines of equal weight. The lines arrive in the order
+// their goroutines finish, so they are sorted by nail first: the lowest nail
+// wins, or a nail drawn from the seed when the generator is seeded.
+func breakTie(ties []weightResult, random *rand.Rand) weightResult {
+	slices.SortFunc(ties, func(a, b weightResult) int { return cmp.Compare(a.NailIdx, b.NailIdx) })
+	if random == nil {
+		return ties[0]
+	}
+	return ties[random.IntN(len(ties))]
+}
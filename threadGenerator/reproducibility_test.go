@@ -0,0 +1,111 @@
+package threadGenerator
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// flatImagePath writes a uniform gray image, every line of it weighs the same
+func flatImagePath(t *testing.T) string {
+	img := image.NewGray(image.Rect(0, 0, 120, 120))
+	for i := range img.Pix {
+		img.Pix[i] = 128
+	}
+	imagePath := filepath.Join(t.TempDir(), "flat.png")
+	file, err := os.Create(imagePath)
+	require.NoError(t, err)
+	defer file.Close()
+	require.NoError(t, png.Encode(file, img))
+	return imagePath
+}
+
+func generatePaths(t *testing.T, imagePath string, seed int64) []Path {
+	return generatePathsWith(t, imagePath, seed, 0)
+}
+
+// generatePathsWith generates on the given number of scoring goroutines
+func generatePathsWith(t *testing.T, imagePath string, seed int64, scoringWorkers int) []Path {
+	tg := NewThreadGenerator(Config{
+		NailsQuantity:     80,
+		ImgSize:           120,
+		MaxPaths:          150,
+		MinimumDifference: 5,
+		BrightnessFactor:  30,
+		PhysicalRadius:    100,
+		Seed:              seed,
+		ScoringWorkers:    scoringWorkers,
+	})
+	_, err := tg.Generate(Args{ImageName: imagePath})
+	require.NoError(t, err)
+	return tg.GetPathsList()
+}
+
+func TestGenerationIsDeterministic(t *testing.T) {
+	imagePath := flatImagePath(t)
+
+	reference := generatePaths(t, imagePath, 0)
+	require.Len(t, reference, 150)
+	require.Equal(t, 5, reference[0].EndingNail, "the first tie goes to the lowest nail far enough")
+	for run := 0; run < 5; run++ {
+		require.Equal(t, reference, generatePaths(t, imagePath, 0))
+	}
+
+	seeded := generatePaths(t, imagePath, 42)
+	require.Equal(t, seeded, generatePaths(t, imagePath, 42))
+	require.NotEqual(t, reference, seeded, "the seed draws among the ties")
+	require.NotEqual(t, seeded, generatePaths(t, imagePath, 43))
+}
+
+func TestGenerationIgnoresScoringWorkers(t *testing.T) {
+	imagePath := flatImagePath(t)
+
+	// The pool scoring the nails only changes the order the weights arrive in
+	reference := generatePaths(t, imagePath, 42)
+	for _, workers := range []int{1, 3, 200} {
+		require.Equal(t, reference, generatePathsWith(t, imagePath, 42, workers), "%d workers", workers)
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	config := DefaultConfig()
+	source := []byte("image bytes")
+	hash := ContentHash(source, config)
+	require.Len(t, hash, 64)
+	require.Equal(t, hash, ContentHash(source, config))
+
+	require.NotEqual(t, hash, ContentHash([]byte("other bytes"), config))
+	config.Seed = 1
+	require.NotEqual(t, hash, ContentHash(source, config))
+
+	tg := NewThreadGenerator(config)
+	tg.SetImage(filepath.Join(t.TempDir(), "missing.png"))
+	_, err := tg.ContentHash()
+	require.Error(t, err)
+}
+
+func TestContentHashVersion(t *testing.T) {
+	t.Cleanup(func() { Version = DevelVersion })
+	config := DefaultConfig()
+	source := []byte("image bytes")
+
+	require.False(t, ReproducibleVersion(), "tests run a devel build")
+	devel := ContentHash(source, config)
+
+	Version = "v1.4.0"
+	require.True(t, ReproducibleVersion())
+	release := ContentHash(source, config)
+	require.NotEqual(t, devel, release, "the hash changes with the generator version")
+
+	Version = "v1.4.0-3-gabc1234-dirty"
+	require.False(t, ReproducibleVersion(), "uncommitted changes are not identified by their version")
+}
+
+func TestBreakTie(t *testing.T) {
+	ties := []weightResult{{NailIdx: 7}, {NailIdx: 3}, {NailIdx: 5}}
+	require.Equal(t, 3, breakTie(ties, nil).NailIdx)
+}
@@ -0,0 +1,141 @@
+package threadGenerator
+
+import (
+	"fmt"
+	"math"
+)
+
+// degreesPerTurn is the rotary unit of multi ring layouts, rings with
+// different nail counts can't share a rotary unit of one nail
+const degreesPerTurn = 360
+
+// Ring is a circle of evenly spaced nails, a frame may hold several concentric rings
+type Ring struct {
+	NailsQuantity     int     `json:"nails_quantity"`     // Number of nails on the ring
+	Radius            float64 `json:"radius"`             // Radius of the ring in mm
+	MinimumDifference int     `json:"minimum_difference"` // Minimum difference between nails of the ring, 0 for the composition one
+}
+
+// NailRings returns the rings of the layout. Without explicit rings the
+// layout is a single ring of NailsQuantity nails at PhysicalRadius.
+func (c Config) NailRings() []Ring {
+	return resolveRings(c.Rings, c.NailsQuantity, c.PhysicalRadius, c.MinimumDifference)
+}
+
+// RotaryUnitsPerTurn returns the rotation axis units of a full turn: one unit
+// per nail on a single ring, degrees on a multi ring layout
+func (c Config) RotaryUnitsPerTurn() int {
+	return rotaryUnitsPerTurn(c.NailRings())
+}
+
+// ValidateRings checks the rings fit on the frame and can be told apart by the needle
+func (c Config) ValidateRings() error {
+	for i, ring := range c.Rings {
+		if ring.NailsQuantity <= 0 {
+			return fmt.Errorf("ring %d needs a positive number of nails", i)
+		}
+		if ring.Radius <= 0 || ring.Radius > c.PhysicalRadius {
+			return fmt.Errorf("ring %d radius must be between 0 and the physical radius %.1f mm", i, c.PhysicalRadius)
+		}
+		for j := 0; j < i; j++ {
+			if c.Rings[j].Radius == ring.Radius {
+				return fmt.Errorf("rings %d and %d have the same radius", j, i)
+			}
+		}
+	}
+	return nil
+}
+
+func resolveRings(rings []Ring, nailsQuantity int, physicalRadius float64, minimumDifference int) []Ring {
+	if len(rings) == 0 {
+		return []Ring{{NailsQuantity: nailsQuantity, Radius: physicalRadius, MinimumDifference: minimumDifference}}
+	}
+	resolved := make([]Ring, len(rings))
+	for i, ring := range rings {
+		if ring.MinimumDifference <= 0 {
+			ring.MinimumDifference = minimumDifference
+		}
+		resolved[i] = ring
+	}
+	return resolved
+}
+
+func rotaryUnitsPerTurn(rings []Ring) int {
+	if len(rings) > 1 {
+		return degreesPerTurn
+	}
+	return rings[0].NailsQuantity
+}
+
+// locateNail returns the ring of a nail and its index on the ring. Nails are
+// numbered ring after ring, in the order of the layout.
+func locateNail(rings []Ring, nail int) (int, int) {
+	for i, ring := range rings {
+		if nail < ring.NailsQuantity {
+			return i, nail
+		}
+		nail -= ring.NailsQuantity
+	}
+	return len(rings) - 1, nail
+}
+
+// nailAngle returns the angle of a nail in radians
+func nailAngle(rings []Ring, nail int) float64 {
+	ring, index := locateNail(rings, nail)
+	return float64(index) * 2 * math.Pi / float64(rings[ring].NailsQuantity)
+}
+
+// chordLength returns the distance between two nails in mm
+func chordLength(rings []Ring, from, to int) float64 {
+	fromRing, _ := locateNail(rings, from)
+	toRing, _ := locateNail(rings, to)
+	r1, r2 := rings[fromRing].Radius, rings[toRing].Radius
+	angle := nailAngle(rings, to) - nailAngle(rings, from)
+	return math.Sqrt(math.Max(0, r1*r1+r2*r2-2*r1*r2*math.Cos(angle)))
+}
+
+// allowedPath reports whether a path may join two nails. On the same ring the
+// nails must be at least the ring minimum difference apart. Across rings the
+// angle between both nails must span the minimum difference of the ring the
+// path starts from.
+func allowedPath(rings []Ring, from, to int) bool {
+	fromRing, fromIndex := locateNail(rings, from)
+	toRing, toIndex := locateNail(rings, to)
+	ring := rings[fromRing]
+
+	if fromRing == toRing {
+		difference := int(math.Abs(float64(toIndex - fromIndex)))
+		return difference >= ring.MinimumDifference && difference <= ring.NailsQuantity-ring.MinimumDifference
+	}
+
+	gap := math.Abs(float64(fromIndex)/float64(ring.NailsQuantity) - float64(toIndex)/float64(rings[toRing].NailsQuantity))
+	gap = math.Min(gap, 1-gap)
+	return gap*float64(ring.NailsQuantity) >= float64(ring.MinimumDifference)
+}
+
+// nailRings returns the rings of the generator, the single ring built from the
+// generator settings when the layout has no explicit rings
+func (tg *ThreadGenerator) nailRings() []Ring {
+	return resolveRings(tg.rings, tg.nailsQuantity, tg.physicalRadius, tg.minimumDifference)
+}
+
+// multiRing reports whether the G-code addresses a ring index and an angle
+func (tg *ThreadGenerator) multiRing() bool {
+	return len(tg.rings) > 1
+}
+
+// rotaryPosition returns the rotation axis coordinate of a nail, within one turn
+func (tg *ThreadGenerator) rotaryPosition(nail int) float64 {
+	rings := tg.nailRings()
+	ring, index := locateNail(rings, nail)
+	// Multiplied before dividing so single ring positions stay whole nails
+	return float64(index) * float64(rotaryUnitsPerTurn(rings)) / float64(rings[ring].NailsQuantity)
+}
+
+// nailSpacing returns the rotation axis distance between two neighbour nails
+// of the ring holding the given nail
+func (tg *ThreadGenerator) nailSpacing(nail int) float64 {
+	rings := tg.nailRings()
+	ring, _ := locateNail(rings, nail)
+	return float64(rotaryUnitsPerTurn(rings)) / float64(rings[ring].NailsQuantity)
+}
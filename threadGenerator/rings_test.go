@@ -0,0 +1,42 @@
+package threadGenerator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowedPathPerRing(t *testing.T) {
+	rings := resolveRings([]Ring{
+		{NailsQuantity: 100, Radius: 600},
+		{NailsQuantity: 40, Radius: 300, MinimumDifference: 2},
+	}, 0, 600, 10)
+
+	ring, index := locateNail(rings, 105)
+	require.Equal(t, 1, ring)
+	require.Equal(t, 5, index)
+
+	// Outer ring uses the composition minimum difference
+	require.False(t, allowedPath(rings, 0, 9))
+	require.True(t, allowedPath(rings, 0, 10))
+	require.False(t, allowedPath(rings, 0, 95))
+
+	// Inner ring uses its own
+	require.False(t, allowedPath(rings, 100, 101))
+	require.True(t, allowedPath(rings, 100, 102))
+	require.True(t, allowedPath(rings, 100, 138))
+	require.False(t, allowedPath(rings, 100, 139))
+
+	// Across rings the angle must span the minimum difference of the starting ring
+	require.False(t, allowedPath(rings, 0, 102)) // 5% of a turn from the outer ring
+	require.True(t, allowedPath(rings, 0, 104))  // 10% of a turn
+	require.True(t, allowedPath(rings, 102, 0))  // 2 inner nails apart
+}
+
+func TestChordLength(t *testing.T) {
+	rings := []Ring{{NailsQuantity: 4, Radius: 100}, {NailsQuantity: 4, Radius: 50}}
+
+	require.InDelta(t, 200, chordLength(rings, 0, 2), 1e-9)
+	require.InDelta(t, 50, chordLength(rings, 0, 4), 1e-9)
+	require.InDelta(t, 150, chordLength(rings, 0, 6), 1e-9)
+}
@@ -0,0 +1,101 @@
+package threadGenerator
+
+import (
+	"errors"
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// similarityResolution is the side of the images compared, the threads
+	// only read as tones from a distance so finer details are noise
+	similarityResolution = 200
+	// similarityBlur is the gaussian blur sigma blending the threads into tones
+	similarityBlur = 2.0
+	// similarityWindow is the side of the windows compared by the SSIM
+	similarityWindow = 8
+)
+
+// ImageSimilarity scores how close a candidate looks to a reference image, from
+// 0 for unrelated images to 1 for identical ones. Both images are compared in
+// gray levels, blurred, with the mean structural similarity (SSIM) of the
+// windows inside the ring.
+func ImageSimilarity(reference, candidate image.Image) float64 {
+	ref := similarityPixels(reference)
+	cand := similarityPixels(candidate)
+
+	const (
+		c1 = (0.01 * 255) * (0.01 * 255)
+		c2 = (0.03 * 255) * (0.03 * 255)
+	)
+	center := float64(similarityResolution) / 2
+	step := similarityWindow / 2
+	n := float64(similarityWindow * similarityWindow)
+
+	var total float64
+	var windows int
+	for top := 0; top+similarityWindow <= similarityResolution; top += step {
+		for left := 0; left+similarityWindow <= similarityResolution; left += step {
+			// Only windows fully inside the ring, the outside is white on both images
+			dx := math.Max(math.Abs(float64(left)-center), math.Abs(float64(left+similarityWindow)-center))
+			dy := math.Max(math.Abs(float64(top)-center), math.Abs(float64(top+similarityWindow)-center))
+			if dx*dx+dy*dy > center*center {
+				continue
+			}
+
+			var sumRef, sumCand, sumRefSq, sumCandSq, sumCross float64
+			for y := top; y < top+similarityWindow; y++ {
+				for x := left; x < left+similarityWindow; x++ {
+					r, c := ref[y*similarityResolution+x], cand[y*similarityResolution+x]
+					sumRef += r
+					sumCand += c
+					sumRefSq += r * r
+					sumCandSq += c * c
+					sumCross += r * c
+				}
+			}
+			meanRef, meanCand := sumRef/n, sumCand/n
+			varRef := sumRefSq/n - meanRef*meanRef
+			varCand := sumCandSq/n - meanCand*meanCand
+			covariance := sumCross/n - meanRef*meanCand
+
+			total += ((2*meanRef*meanCand + c1) * (2*covariance + c2)) /
+				((meanRef*meanRef + meanCand*meanCand + c1) * (varRef + varCand + c2))
+			windows++
+		}
+	}
+	if windows == 0 {
+		return 0
+	}
+	return math.Min(math.Max(total/float64(windows), 0), 1)
+}
+
+// similarityPixels returns the gray levels of an image resized and blurred for the comparison
+func similarityPixels(img image.Image) []float64 {
+	prepared := imaging.Blur(imaging.Resize(imaging.Grayscale(img), similarityResolution, similarityResolution, imaging.Lanczos), similarityBlur)
+	pixels := make([]float64, similarityResolution*similarityResolution)
+	for i := range pixels {
+		pixels[i] = float64(prepared.Pix[i*4])
+	}
+	return pixels
+}
+
+// SimilarityScore compares the generated paths with the source image. The
+// contrast adjustment is left out so different settings are scored against the
+// same original.
+func (tg *ThreadGenerator) SimilarityScore() (float64, error) {
+	if len(tg.pathsList) == 0 {
+		return 0, errors.New("no paths generated")
+	}
+	reference, err := tg.loadCircleImage(0)
+	if err != nil {
+		return 0, err
+	}
+	preview, err := tg.GeneratePathsImage()
+	if err != nil {
+		return 0, err
+	}
+	return ImageSimilarity(reference, preview), nil
+}
@@ -0,0 +1,81 @@
+package threadGenerator
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// gradientImage fades from black on the left to white on the right
+func gradientImage(size int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(x * 255 / size)})
+		}
+	}
+	return img
+}
+
+func invert(img *image.Gray) *image.Gray {
+	inverted := image.NewGray(img.Bounds())
+	for i, value := range img.Pix {
+		inverted.Pix[i] = 255 - value
+	}
+	return inverted
+}
+
+func TestImageSimilarity(t *testing.T) {
+	reference := gradientImage(160)
+
+	require.InDelta(t, 1, ImageSimilarity(reference, reference), 1e-9)
+
+	blank := image.NewGray(reference.Bounds())
+	for i := range blank.Pix {
+		blank.Pix[i] = 255
+	}
+	inverted := ImageSimilarity(reference, invert(reference))
+	require.Less(t, inverted, ImageSimilarity(reference, blank))
+	require.GreaterOrEqual(t, inverted, 0.0)
+}
+
+func TestSimilarityScoreRanksMorePaths(t *testing.T) {
+	imagePath := filepath.Join(t.TempDir(), "source.png")
+	file, err := os.Create(imagePath)
+	require.NoError(t, err)
+	require.NoError(t, png.Encode(file, gradientImage(160)))
+	require.NoError(t, file.Close())
+
+	score := func(maxPaths int) float64 {
+		config := DefaultConfig()
+		config.NailsQuantity = 80
+		config.ImgSize = 160
+		config.MinimumDifference = 5
+		config.MaxPaths = maxPaths
+		generator := NewThreadGenerator(config)
+		_, err := generator.Generate(Args{ImageName: imagePath})
+		require.NoError(t, err)
+		score, err := generator.SimilarityScore()
+		require.NoError(t, err)
+		return score
+	}
+	require.Greater(t, score(400), score(10))
+}
+
+func TestContactSheet(t *testing.T) {
+	tiles := []ContactSheetTile{
+		{Image: gradientImage(100), Caption: "#1 0.812"},
+		{Image: gradientImage(50), Caption: "#2 0.640\nbrightness 40"},
+		{Image: gradientImage(100), Caption: "#3 0.512"},
+	}
+	sheet := ContactSheet(tiles, 2, 64)
+
+	// Two columns and two rows of 64px tiles with two caption lines
+	require.Equal(t, 2*(64+contactSheetMargin)+contactSheetMargin, sheet.Bounds().Dx())
+	require.Equal(t, 2*(64+2*contactSheetLineHeight+contactSheetMargin)+contactSheetMargin, sheet.Bounds().Dy())
+}
@@ -0,0 +1,110 @@
+package threadGenerator
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// nailWrapLength is the thread used by one wrap around a nail, in mm
+	nailWrapLength = 6.0
+	// knotReserve is the thread kept on each spool to tie both of its ends, in mm
+	knotReserve = 300.0
+	// maxKnotWraps is the number of wraps on a nail above which a knot is not
+	// acceptable, it would be too thick to stay under the nail head
+	maxKnotWraps = 3
+	// maxKnotWaste is the share of a spool that may be left unused to end it on
+	// a nail where a knot is acceptable
+	maxKnotWaste = 0.1
+)
+
+// SpoolSegment is a run of consecutive paths strung with the thread of one spool
+type SpoolSegment struct {
+	StartStep int     `json:"start_step"` // Index of the first path of the segment
+	EndStep   int     `json:"end_step"`   // Index after the last path of the segment
+	StartNail int     `json:"start_nail"` // Nail the thread is tied to before the first path
+	Length    float64 `json:"length"`     // Thread used in meters, knots included
+	// Forced is set when no nail where a knot is acceptable was close enough to
+	// the end of the previous spool, the thread is tied on a crowded nail
+	Forced bool `json:"forced,omitempty"`
+}
+
+// Paths returns the number of paths of the segment
+func (s SpoolSegment) Paths() int {
+	return s.EndStep - s.StartStep
+}
+
+// GetSpoolSegments splits the paths list into segments that each fit on a
+// spool of the configured length. Without a spool length the whole piece is one segment.
+func (tg *ThreadGenerator) GetSpoolSegments() ([]SpoolSegment, error) {
+	return PlanSpoolSegments(tg.pathsList, tg.nailRings(), tg.spoolLength)
+}
+
+// PlanSpoolSegments splits a paths list into segments that each fit on a spool
+// of spoolLength meters, 0 for an unlimited spool. The lengths are measured on
+// the ring geometry so they also hold for a paths list loaded from storage.
+//
+// A segment ends once the next path would not fit on the spool. It is moved
+// back to the last nail where a knot is acceptable, a nail with few wraps, as
+// long as it wastes less than a tenth of the spool, otherwise the next segment
+// is marked as forced.
+func PlanSpoolSegments(paths []Path, rings []Ring, spoolLength float64) ([]SpoolSegment, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	capacity := math.Inf(1)
+	if spoolLength > 0 {
+		capacity = spoolLength*1000 - knotReserve
+	}
+
+	// used[i] is the thread of the paths before step i, knots[i] whether a knot
+	// is acceptable on the nail reached at step i
+	used := make([]float64, len(paths)+1)
+	knots := make([]bool, len(paths)+1)
+	wraps := make(map[int]int)
+	for i, path := range paths {
+		length := chordLength(rings, path.StartingNail, path.EndingNail) + nailWrapLength
+		if length > capacity {
+			return nil, fmt.Errorf("path %d is %.2f m long, longer than a %.2f m spool", i, length/1000, spoolLength)
+		}
+		used[i+1] = used[i] + length
+		wraps[path.EndingNail]++
+		knots[i+1] = wraps[path.EndingNail] <= maxKnotWraps
+	}
+
+	var segments []SpoolSegment
+	start, forced := 0, false
+	for i := range paths {
+		// Moving the end back carries paths into the next segment, which may
+		// then overflow as well
+		for used[i+1]-used[start] > capacity {
+			end, endForced := i, true
+			for step := i; step > start && used[i]-used[step] <= capacity*maxKnotWaste; step-- {
+				if knots[step] {
+					end, endForced = step, false
+					break
+				}
+			}
+			segments = append(segments, SpoolSegment{
+				StartStep: start,
+				EndStep:   end,
+				StartNail: paths[start].StartingNail,
+				Length:    (used[end] - used[start] + knotReserve) / 1000,
+				Forced:    forced,
+			})
+			start, forced = end, endForced
+		}
+	}
+
+	last := SpoolSegment{
+		StartStep: start,
+		EndStep:   len(paths),
+		StartNail: paths[start].StartingNail,
+		Length:    (used[len(paths)] - used[start]) / 1000,
+		Forced:    forced,
+	}
+	if spoolLength > 0 {
+		last.Length += knotReserve / 1000
+	}
+	return append(segments, last), nil
+}
@@ -0,0 +1,137 @@
+package threadGenerator
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// diameterPaths crosses the ring back and forth through its center
+func diameterPaths(count, nailsQuantity int) []Path {
+	paths := make([]Path, count)
+	nail := 0
+	for i := range paths {
+		next := (nail + nailsQuantity/2 + i%7) % nailsQuantity
+		paths[i] = Path{StartingNail: nail, EndingNail: next}
+		nail = next
+	}
+	return paths
+}
+
+func TestPlanSpoolSegments(t *testing.T) {
+	paths := diameterPaths(400, 200)
+	rings := []Ring{{NailsQuantity: 200, Radius: 500}}
+
+	segments, err := PlanSpoolSegments(paths, rings, 0)
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+	require.Equal(t, SpoolSegment{StartStep: 0, EndStep: 400, StartNail: 0, Length: segments[0].Length}, segments[0])
+
+	segments, err = PlanSpoolSegments(paths, rings, 100)
+	require.NoError(t, err)
+	require.Greater(t, len(segments), 3)
+
+	next := 0
+	for _, segment := range segments {
+		require.Equal(t, next, segment.StartStep, "segments must be contiguous")
+		require.Greater(t, segment.Paths(), 0)
+		require.LessOrEqual(t, segment.Length, 100.0)
+		require.Equal(t, paths[segment.StartStep].StartingNail, segment.StartNail)
+		next = segment.EndStep
+	}
+	require.Equal(t, len(paths), next)
+
+	_, err = PlanSpoolSegments(paths, rings, 1)
+	require.Error(t, err)
+}
+
+// carriedOverflowPaths ends a spool past a knot nail followed by a few paths
+// on crowded nails and a path across the ring. Moving the end back to the
+// knot nail carries the crowded paths, which no longer fit with the long one.
+func carriedOverflowPaths() []Path {
+	var paths []Path
+	// Nails 0 and 1 are crowded after their third wrap
+	for i := 0; i < 30; i++ {
+		paths = append(paths, Path{StartingNail: i % 2, EndingNail: (i + 1) % 2})
+	}
+	paths = append(paths,
+		Path{StartingNail: 0, EndingNail: 3}, // First wrap on nail 3, a knot is acceptable
+		Path{StartingNail: 3, EndingNail: 1},
+		Path{StartingNail: 1, EndingNail: 0},
+		Path{StartingNail: 0, EndingNail: 1},
+		Path{StartingNail: 1, EndingNail: 101}, // Across the ring
+	)
+	return paths
+}
+
+func TestPlanSpoolSegmentsCarriedOverflow(t *testing.T) {
+	rings := []Ring{{NailsQuantity: 200, Radius: 500}}
+	spoolLength := 1.35
+
+	segments, err := PlanSpoolSegments(carriedOverflowPaths(), rings, spoolLength)
+	require.NoError(t, err)
+	require.Len(t, segments, 3)
+	for _, segment := range segments {
+		require.LessOrEqual(t, segment.Length, spoolLength, "segment %d-%d does not fit on the spool", segment.StartStep, segment.EndStep)
+	}
+
+	// The first spool ends on the knot nail
+	require.Equal(t, 31, segments[0].EndStep)
+	require.False(t, segments[1].Forced)
+
+	// No knot nail is close enough to the long path, the boundary is forced
+	require.Equal(t, 34, segments[1].EndStep)
+	require.True(t, segments[2].Forced)
+	require.Equal(t, 1, segments[2].StartNail)
+
+	tg := NewThreadGenerator(Config{NailsQuantity: 200, PhysicalRadius: 500, RotationAxis: "A", NeedleAxis: "X", SpindleAxis: "Y", GcodeDialect: DialectFluidNC, SpoolLength: spoolLength})
+	tg.SetPathsList(carriedOverflowPaths())
+	require.Contains(t, tg.GetGcode(), "M0 ; Spool 3/3: tie a new thread on nail 1, a crowded nail so keep the knot small")
+}
+
+func TestLineLengthMatchesSpoolPlanning(t *testing.T) {
+	tg := NewThreadGenerator(Config{NailsQuantity: 200, PhysicalRadius: 500, ImgSize: 400})
+	require.InDelta(t, 1000, tg.lineLength(0, 100), 1e-9, "a diameter")
+	require.InDelta(t, 1000*math.Sqrt2/2, tg.lineLength(0, 50), 1e-9, "a diagonal")
+	// The image spans the diameter of the frame
+	require.Equal(t, 2.5, tg.imagePixelSize())
+
+	// The thread length is the spool length without the nail wraps
+	paths := diameterPaths(400, 200)
+	segments, err := PlanSpoolSegments(paths, tg.nailRings(), 0)
+	require.NoError(t, err)
+	threadLength := 0.0
+	for _, path := range paths {
+		threadLength += tg.lineLength(path.StartingNail, path.EndingNail)
+	}
+	require.InDelta(t, segments[0].Length*1000, threadLength+nailWrapLength*float64(len(paths)), 1e-6)
+}
+
+func TestSpoolPausesInGcode(t *testing.T) {
+	tg := NewThreadGenerator(Config{NailsQuantity: 200, PhysicalRadius: 500, RotationAxis: "A", NeedleAxis: "X", SpindleAxis: "Y", GcodeDialect: DialectFluidNC, SpoolLength: 100})
+	tg.SetPathsList(diameterPaths(400, 200))
+
+	segments, err := tg.GetSpoolSegments()
+	require.NoError(t, err)
+
+	pauses := 0
+	for _, line := range tg.GetGcode() {
+		if strings.HasPrefix(line, "M0") && strings.Contains(line, "Spool") {
+			pauses++
+		}
+	}
+	require.Equal(t, len(segments)-1, pauses)
+
+	// Resuming after the first boundary keeps only the later pauses
+	resumed, err := tg.GetGcodeFromStep(segments[1].StartStep + 1)
+	require.NoError(t, err)
+	pauses = 0
+	for _, line := range resumed {
+		if strings.HasPrefix(line, "M0") && strings.Contains(line, "Spool") {
+			pauses++
+		}
+	}
+	require.Equal(t, len(segments)-2, pauses)
+}
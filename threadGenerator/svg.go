@@ -0,0 +1,751 @@
+package threadGenerator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/vector"
+)
+
+// The SVG support covers what logo exports use: paths, basic shapes, groups
+// with transforms, and flat fill and stroke colors set by attributes or the
+// style attribute. Style sheets, gradients, text, clipping and masks are
+// ignored, gradients are painted black.
+
+const (
+	// svgCurveSteps is the number of segments a Bézier curve is flattened to
+	svgCurveSteps = 16
+	// svgCircleSteps is the number of segments a full circle is flattened to
+	svgCircleSteps = 64
+)
+
+type (
+	svgPoint struct{ X, Y float64 }
+
+	// svgSubpath is a polyline of a shape, closed back to its first point when closed
+	svgSubpath struct {
+		points []svgPoint
+		closed bool
+	}
+
+	// svgTransform is the affine matrix a b c d e f, mapping x y to
+	// a*x + c*y + e, b*x + d*y + f
+	svgTransform [6]float64
+
+	// svgStyle is the paint of a shape, nil colors are not painted
+	svgStyle struct {
+		fill        color.Color
+		stroke      color.Color
+		strokeWidth float64
+	}
+
+	svgFrame struct {
+		transform svgTransform
+		style     svgStyle
+	}
+)
+
+var svgIdentity = svgTransform{1, 0, 0, 1, 0, 0}
+
+// svgSkippedElements hold definitions or content the rasterizer doesn't draw
+var svgSkippedElements = map[string]bool{
+	"defs": true, "symbol": true, "clipPath": true, "mask": true, "pattern": true,
+	"linearGradient": true, "radialGradient": true, "marker": true, "style": true,
+	"title": true, "desc": true, "metadata": true, "text": true, "script": true,
+}
+
+var svgNamedColors = map[string]color.Gray{
+	"black": {0}, "white": {255}, "gray": {128}, "grey": {128}, "silver": {192},
+	"red": {76}, "green": {75}, "lime": {150}, "blue": {29}, "navy": {15},
+	"yellow": {226}, "orange": {173}, "purple": {53},
+}
+
+// isSVG reports whether a source file is an SVG document rather than a bitmap
+func isSVG(data []byte) bool {
+	return bytes.Contains(bytes.ToLower(data[:min(len(data), 4096)]), []byte("<svg"))
+}
+
+// RasterizeSVG renders an SVG document in gray levels on a white square of
+// the given size, the view box fitted and centered in the square. The
+// rendering is a plain bitmap, safe to serve where the document is not.
+func RasterizeSVG(r io.Reader, size int) (*image.Gray, error) {
+	canvas := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(canvas, canvas.Bounds(), image.White, image.Point{}, draw.Src)
+	rasterizer := vector.NewRasterizer(size, size)
+
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+
+	var frames []svgFrame
+	rooted := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse svg: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			if svgSkippedElements[element.Name.Local] {
+				if err := decoder.Skip(); err != nil {
+					return nil, fmt.Errorf("failed to parse svg: %w", err)
+				}
+				continue
+			}
+			attrs := make(map[string]string, len(element.Attr))
+			for _, attr := range element.Attr {
+				attrs[attr.Name.Local] = attr.Value
+			}
+
+			parent := svgFrame{transform: svgIdentity, style: svgStyle{fill: color.Black, strokeWidth: 1}}
+			if len(frames) > 0 {
+				parent = frames[len(frames)-1]
+			}
+			transform, err := parseSVGTransform(attrs["transform"])
+			if err != nil {
+				return nil, err
+			}
+			if element.Name.Local == "svg" && !rooted {
+				fit, err := svgViewBoxTransform(attrs, size)
+				if err != nil {
+					return nil, err
+				}
+				transform = fit.mul(transform)
+				rooted = true
+			}
+			frame := svgFrame{transform: parent.transform.mul(transform), style: parent.style.apply(attrs)}
+			frames = append(frames, frame)
+
+			subpaths, err := svgElementSubpaths(element.Name.Local, attrs)
+			if err != nil {
+				return nil, err
+			}
+			if rooted && len(subpaths) > 0 {
+				drawSVGShape(rasterizer, canvas, subpaths, frame)
+			}
+		case xml.EndElement:
+			if len(frames) > 0 {
+				frames = frames[:len(frames)-1]
+			}
+		}
+	}
+	if !rooted {
+		return nil, errors.New("the document has no svg element")
+	}
+
+	gray := image.NewGray(canvas.Bounds())
+	draw.Draw(gray, gray.Bounds(), canvas, image.Point{}, draw.Src)
+	return gray, nil
+}
+
+// svgViewBoxTransform maps the view box of the root element, or its width and
+// height, into a square of the given size
+func svgViewBoxTransform(attrs map[string]string, size int) (svgTransform, error) {
+	var minX, minY, width, height float64
+	if viewBox, ok := attrs["viewBox"]; ok {
+		values, err := parseSVGNumbers(viewBox)
+		if err != nil || len(values) != 4 {
+			return svgIdentity, fmt.Errorf("invalid svg view box %q", viewBox)
+		}
+		minX, minY, width, height = values[0], values[1], values[2], values[3]
+	} else {
+		width, height = svgLength(attrs["width"]), svgLength(attrs["height"])
+	}
+	if width <= 0 || height <= 0 {
+		return svgIdentity, errors.New("the svg has no view box nor size")
+	}
+	scale := float64(size) / math.Max(width, height)
+	return svgTransform{
+		scale, 0, 0, scale,
+		(float64(size)-width*scale)/2 - minX*scale,
+		(float64(size)-height*scale)/2 - minY*scale,
+	}, nil
+}
+
+// drawSVGShape fills then strokes the subpaths of a shape on the canvas
+func drawSVGShape(rasterizer *vector.Rasterizer, canvas *image.RGBA, subpaths []svgSubpath, frame svgFrame) {
+	size := canvas.Bounds().Size()
+	transformed := make([]svgSubpath, len(subpaths))
+	for i, subpath := range subpaths {
+		transformed[i].closed = subpath.closed
+		for _, point := range subpath.points {
+			transformed[i].points = append(transformed[i].points, frame.transform.apply(point))
+		}
+	}
+
+	if frame.style.fill != nil {
+		rasterizer.Reset(size.X, size.Y)
+		for _, subpath := range transformed {
+			if len(subpath.points) < 3 {
+				continue
+			}
+			rasterizer.MoveTo(float32(subpath.points[0].X), float32(subpath.points[0].Y))
+			for _, point := range subpath.points[1:] {
+				rasterizer.LineTo(float32(point.X), float32(point.Y))
+			}
+			rasterizer.ClosePath()
+		}
+		rasterizer.Draw(canvas, canvas.Bounds(), image.NewUniform(frame.style.fill), image.Point{})
+	}
+
+	halfWidth := frame.style.strokeWidth * frame.transform.scale() / 2
+	if frame.style.stroke == nil || halfWidth <= 0 {
+		return
+	}
+	// Every segment is a quad of the stroke width and every vertex a round
+	// join. The quads all turn the same way so overlaps don't cancel out.
+	rasterizer.Reset(size.X, size.Y)
+	for _, subpath := range transformed {
+		points := subpath.points
+		if subpath.closed && len(points) > 1 {
+			points = append(points, points[0])
+		}
+		for i, point := range points {
+			svgPolygon(rasterizer, svgRegularPolygon(point, halfWidth, 8))
+			if i == 0 {
+				continue
+			}
+			from := points[i-1]
+			length := math.Hypot(point.X-from.X, point.Y-from.Y)
+			if length == 0 {
+				continue
+			}
+			nx, ny := -(point.Y-from.Y)/length*halfWidth, (point.X-from.X)/length*halfWidth
+			svgPolygon(rasterizer, []svgPoint{
+				{from.X - nx, from.Y - ny}, {point.X - nx, point.Y - ny},
+				{point.X + nx, point.Y + ny}, {from.X + nx, from.Y + ny},
+			})
+		}
+	}
+	rasterizer.Draw(canvas, canvas.Bounds(), image.NewUniform(frame.style.stroke), image.Point{})
+}
+
+func svgPolygon(rasterizer *vector.Rasterizer, points []svgPoint) {
+	rasterizer.MoveTo(float32(points[0].X), float32(points[0].Y))
+	for _, point := range points[1:] {
+		rasterizer.LineTo(float32(point.X), float32(point.Y))
+	}
+	rasterizer.ClosePath()
+}
+
+func svgRegularPolygon(center svgPoint, radius float64, sides int) []svgPoint {
+	points := make([]svgPoint, sides)
+	for i := range points {
+		angle := float64(i) * 2 * math.Pi / float64(sides)
+		points[i] = svgPoint{center.X + radius*math.Cos(angle), center.Y + radius*math.Sin(angle)}
+	}
+	return points
+}
+
+// svgElementSubpaths returns the outline of a shape element, nil for the
+// elements that are not shapes
+func svgElementSubpaths(name string, attrs map[string]string) ([]svgSubpath, error) {
+	number := func(key string) float64 { return svgLength(attrs[key]) }
+	switch name {
+	case "path":
+		return parseSVGPath(attrs["d"])
+	case "rect":
+		x, y, w, h := number("x"), number("y"), number("width"), number("height")
+		if w <= 0 || h <= 0 {
+			return nil, nil
+		}
+		return []svgSubpath{{points: []svgPoint{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}, closed: true}}, nil
+	case "circle":
+		return svgEllipse(number("cx"), number("cy"), number("r"), number("r")), nil
+	case "ellipse":
+		return svgEllipse(number("cx"), number("cy"), number("rx"), number("ry")), nil
+	case "line":
+		return []svgSubpath{{points: []svgPoint{{number("x1"), number("y1")}, {number("x2"), number("y2")}}}}, nil
+	case "polyline", "polygon":
+		values, err := parseSVGNumbers(attrs["points"])
+		if err != nil {
+			return nil, err
+		}
+		subpath := svgSubpath{closed: name == "polygon"}
+		for i := 0; i+1 < len(values); i += 2 {
+			subpath.points = append(subpath.points, svgPoint{values[i], values[i+1]})
+		}
+		return []svgSubpath{subpath}, nil
+	}
+	return nil, nil
+}
+
+func svgEllipse(cx, cy, rx, ry float64) []svgSubpath {
+	if rx <= 0 || ry <= 0 {
+		return nil
+	}
+	subpath := svgSubpath{points: make([]svgPoint, svgCircleSteps), closed: true}
+	for i := range subpath.points {
+		angle := float64(i) * 2 * math.Pi / svgCircleSteps
+		subpath.points[i] = svgPoint{cx + rx*math.Cos(angle), cy + ry*math.Sin(angle)}
+	}
+	return []svgSubpath{subpath}
+}
+
+// parseSVGPath flattens the path data of a path element into polylines
+func parseSVGPath(data string) ([]svgSubpath, error) {
+	var (
+		subpaths      []svgSubpath
+		current       = -1 // Index of the subpath being drawn
+		cursor        svgPoint
+		start         svgPoint
+		control       svgPoint // Last control point, reflected by the smooth curves
+		command       byte
+		previousCurve byte
+	)
+	scanner := &svgScanner{data: data}
+	lineTo := func(point svgPoint) {
+		if current < 0 || subpaths[current].closed {
+			subpaths = append(subpaths, svgSubpath{points: []svgPoint{cursor}})
+			current = len(subpaths) - 1
+		}
+		subpaths[current].points = append(subpaths[current].points, point)
+		cursor = point
+	}
+
+	for {
+		if next, ok := scanner.command(); ok {
+			command = next
+		} else if !scanner.more() {
+			if scanner.pos < len(data) {
+				return nil, fmt.Errorf("invalid svg path %q at %d", data, scanner.pos)
+			}
+			break
+		} else if command == 0 {
+			return nil, fmt.Errorf("svg path %q has numbers without a command", data)
+		}
+
+		relative := command >= 'a'
+		point := func() (svgPoint, error) {
+			x, err := scanner.number()
+			if err != nil {
+				return svgPoint{}, err
+			}
+			y, err := scanner.number()
+			if err != nil {
+				return svgPoint{}, err
+			}
+			if relative {
+				return svgPoint{cursor.X + x, cursor.Y + y}, nil
+			}
+			return svgPoint{x, y}, nil
+		}
+
+		var err error
+		curve := byte(0)
+		switch command | 0x20 {
+		case 'm':
+			var to svgPoint
+			if to, err = point(); err != nil {
+				break
+			}
+			subpaths = append(subpaths, svgSubpath{points: []svgPoint{to}})
+			current = len(subpaths) - 1
+			cursor, start = to, to
+			// Following coordinate pairs are implicit line commands
+			command = 'L' | (command & 0x20)
+		case 'l':
+			var to svgPoint
+			if to, err = point(); err == nil {
+				lineTo(to)
+			}
+		case 'h':
+			var x float64
+			if x, err = scanner.number(); err == nil {
+				if relative {
+					x += cursor.X
+				}
+				lineTo(svgPoint{x, cursor.Y})
+			}
+		case 'v':
+			var y float64
+			if y, err = scanner.number(); err == nil {
+				if relative {
+					y += cursor.Y
+				}
+				lineTo(svgPoint{cursor.X, y})
+			}
+		case 'c', 's':
+			var c1, c2, to svgPoint
+			if command|0x20 == 'c' {
+				c1, err = point()
+			} else {
+				c1 = cursor
+				if previousCurve == 'c' {
+					c1 = svgPoint{2*cursor.X - control.X, 2*cursor.Y - control.Y}
+				}
+			}
+			if err == nil {
+				c2, err = point()
+			}
+			if err == nil {
+				to, err = point()
+			}
+			if err == nil {
+				from := cursor
+				for step := 1; step <= svgCurveSteps; step++ {
+					lineTo(cubicPoint(from, c1, c2, to, float64(step)/svgCurveSteps))
+				}
+				control, curve = c2, 'c'
+			}
+		case 'q', 't':
+			var c, to svgPoint
+			if command|0x20 == 'q' {
+				c, err = point()
+			} else {
+				c = cursor
+				if previousCurve == 'q' {
+					c = svgPoint{2*cursor.X - control.X, 2*cursor.Y - control.Y}
+				}
+			}
+			if err == nil {
+				to, err = point()
+			}
+			if err == nil {
+				from := cursor
+				for step := 1; step <= svgCurveSteps; step++ {
+					t := float64(step) / svgCurveSteps
+					lineTo(svgPoint{
+						(1-t)*(1-t)*from.X + 2*(1-t)*t*c.X + t*t*to.X,
+						(1-t)*(1-t)*from.Y + 2*(1-t)*t*c.Y + t*t*to.Y,
+					})
+				}
+				control, curve = c, 'q'
+			}
+		case 'a':
+			var values [3]float64
+			var large, sweep bool
+			var to svgPoint
+			for i := range values {
+				if values[i], err = scanner.number(); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				large, err = scanner.flag()
+			}
+			if err == nil {
+				sweep, err = scanner.flag()
+			}
+			if err == nil {
+				to, err = point()
+			}
+			if err == nil {
+				for _, arcPoint := range svgArc(cursor, values[0], values[1], values[2], large, sweep, to) {
+					lineTo(arcPoint)
+				}
+			}
+		case 'z':
+			if current >= 0 {
+				subpaths[current].closed = true
+			}
+			cursor = start
+			// Close takes no values, numbers after it need a new command
+			command = 0
+		default:
+			return nil, fmt.Errorf("unknown svg path command %q", command)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid svg path %q: %w", data, err)
+		}
+		previousCurve = curve
+	}
+	return subpaths, nil
+}
+
+func cubicPoint(p0, p1, p2, p3 svgPoint, t float64) svgPoint {
+	u := 1 - t
+	return svgPoint{
+		u*u*u*p0.X + 3*u*u*t*p1.X + 3*u*t*t*p2.X + t*t*t*p3.X,
+		u*u*u*p0.Y + 3*u*u*t*p1.Y + 3*u*t*t*p2.Y + t*t*t*p3.Y,
+	}
+}
+
+// svgArc flattens an elliptical arc, converted from the endpoint to the center
+// parameterization of the SVG implementation notes
+func svgArc(from svgPoint, rx, ry, rotation float64, large, sweep bool, to svgPoint) []svgPoint {
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 || from == to {
+		return []svgPoint{to}
+	}
+	sin, cos := math.Sincos(rotation * math.Pi / 180)
+	dx, dy := (from.X-to.X)/2, (from.Y-to.Y)/2
+	x1, y1 := cos*dx+sin*dy, -sin*dx+cos*dy
+
+	// Radii too small to join both points are scaled up
+	if lambda := x1*x1/(rx*rx) + y1*y1/(ry*ry); lambda > 1 {
+		rx, ry = rx*math.Sqrt(lambda), ry*math.Sqrt(lambda)
+	}
+	numerator := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	denominator := rx*rx*y1*y1 + ry*ry*x1*x1
+	coefficient := math.Sqrt(math.Max(0, numerator/denominator))
+	if large == sweep {
+		coefficient = -coefficient
+	}
+	cx1, cy1 := coefficient*rx*y1/ry, -coefficient*ry*x1/rx
+	cx := cos*cx1 - sin*cy1 + (from.X+to.X)/2
+	cy := sin*cx1 + cos*cy1 + (from.Y+to.Y)/2
+
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	ux, uy := (x1-cx1)/rx, (y1-cy1)/ry
+	theta := angle(1, 0, ux, uy)
+	delta := angle(ux, uy, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	steps := max(1, int(math.Ceil(math.Abs(delta)/(2*math.Pi)*svgCircleSteps)))
+	points := make([]svgPoint, steps)
+	for i := range points {
+		t := theta + delta*float64(i+1)/float64(steps)
+		points[i] = svgPoint{
+			cx + rx*math.Cos(t)*cos - ry*math.Sin(t)*sin,
+			cy + rx*math.Cos(t)*sin + ry*math.Sin(t)*cos,
+		}
+	}
+	points[steps-1] = to
+	return points
+}
+
+func (t svgTransform) apply(point svgPoint) svgPoint {
+	return svgPoint{t[0]*point.X + t[2]*point.Y + t[4], t[1]*point.X + t[3]*point.Y + t[5]}
+}
+
+// mul returns the transform applying o then t
+func (t svgTransform) mul(o svgTransform) svgTransform {
+	return svgTransform{
+		t[0]*o[0] + t[2]*o[1],
+		t[1]*o[0] + t[3]*o[1],
+		t[0]*o[2] + t[2]*o[3],
+		t[1]*o[2] + t[3]*o[3],
+		t[0]*o[4] + t[2]*o[5] + t[4],
+		t[1]*o[4] + t[3]*o[5] + t[5],
+	}
+}
+
+// scale returns the mean scaling of lengths, used for the stroke widths
+func (t svgTransform) scale() float64 {
+	return math.Sqrt(math.Abs(t[0]*t[3] - t[1]*t[2]))
+}
+
+// parseSVGTransform parses a transform attribute, the transforms applied from
+// the last to the first
+func parseSVGTransform(value string) (svgTransform, error) {
+	result := svgIdentity
+	for rest := strings.TrimSpace(value); rest != ""; rest = strings.TrimLeft(rest, " ,\t\n\r") {
+		open, closing := strings.IndexByte(rest, '('), strings.IndexByte(rest, ')')
+		if open < 0 || closing < open {
+			return svgIdentity, fmt.Errorf("invalid svg transform %q", value)
+		}
+		name := strings.TrimSpace(rest[:open])
+		args, err := parseSVGNumbers(rest[open+1 : closing])
+		if err != nil {
+			return svgIdentity, err
+		}
+		arg := func(i int, fallback float64) float64 {
+			if i < len(args) {
+				return args[i]
+			}
+			return fallback
+		}
+
+		var transform svgTransform
+		switch name {
+		case "matrix":
+			if len(args) != 6 {
+				return svgIdentity, fmt.Errorf("invalid svg transform %q", value)
+			}
+			copy(transform[:], args)
+		case "translate":
+			transform = svgTransform{1, 0, 0, 1, arg(0, 0), arg(1, 0)}
+		case "scale":
+			transform = svgTransform{arg(0, 1), 0, 0, arg(1, arg(0, 1)), 0, 0}
+		case "rotate":
+			sin, cos := math.Sincos(arg(0, 0) * math.Pi / 180)
+			cx, cy := arg(1, 0), arg(2, 0)
+			transform = svgTransform{1, 0, 0, 1, cx, cy}.
+				mul(svgTransform{cos, sin, -sin, cos, 0, 0}).
+				mul(svgTransform{1, 0, 0, 1, -cx, -cy})
+		case "skewX":
+			transform = svgTransform{1, 0, math.Tan(arg(0, 0) * math.Pi / 180), 1, 0, 0}
+		case "skewY":
+			transform = svgTransform{1, math.Tan(arg(0, 0) * math.Pi / 180), 0, 1, 0, 0}
+		default:
+			return svgIdentity, fmt.Errorf("unknown svg transform %q", name)
+		}
+		result = result.mul(transform)
+		rest = rest[closing+1:]
+	}
+	return result, nil
+}
+
+// apply returns the style inherited by an element with the given attributes,
+// the style attribute overriding the presentation attributes
+func (s svgStyle) apply(attrs map[string]string) svgStyle {
+	properties := map[string]string{}
+	for _, key := range []string{"fill", "stroke", "stroke-width"} {
+		if value, ok := attrs[key]; ok {
+			properties[key] = value
+		}
+	}
+	for _, declaration := range strings.Split(attrs["style"], ";") {
+		if key, value, ok := strings.Cut(declaration, ":"); ok {
+			properties[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	if value, ok := properties["fill"]; ok {
+		s.fill = parseSVGColor(value)
+	}
+	if value, ok := properties["stroke"]; ok {
+		s.stroke = parseSVGColor(value)
+	}
+	if value, ok := properties["stroke-width"]; ok {
+		s.strokeWidth = svgLength(value)
+	}
+	return s
+}
+
+// parseSVGColor parses a paint, nil for none. Paints that are not flat colors,
+// like gradients, are painted black so they still read as ink.
+func parseSVGColor(value string) color.Color {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case value == "none" || value == "transparent":
+		return nil
+	case strings.HasPrefix(value, "#"):
+		hex := value[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if rgb, err := strconv.ParseUint(hex, 16, 32); err == nil && len(hex) == 6 {
+			return color.RGBA{uint8(rgb >> 16), uint8(rgb >> 8), uint8(rgb), 255}
+		}
+	case strings.HasPrefix(value, "rgb(") && strings.HasSuffix(value, ")"):
+		var channels [3]uint8
+		parts := strings.Split(value[4:len(value)-1], ",")
+		for i := 0; i < len(parts) && i < 3; i++ {
+			part := strings.TrimSpace(parts[i])
+			scale := 1.0
+			if strings.HasSuffix(part, "%") {
+				part, scale = strings.TrimSuffix(part, "%"), 2.55
+			}
+			channel, _ := strconv.ParseFloat(part, 64)
+			channels[i] = uint8(math.Min(255, math.Max(0, math.Round(channel*scale))))
+		}
+		return color.RGBA{channels[0], channels[1], channels[2], 255}
+	default:
+		if named, ok := svgNamedColors[value]; ok {
+			return named
+		}
+	}
+	return color.Black
+}
+
+// svgLength parses a length in user units, the unit suffix ignored
+func svgLength(value string) float64 {
+	value = strings.TrimRight(strings.TrimSpace(value), "abcdefghijklmnopqrstuvwxyz%")
+	length, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return length
+}
+
+func parseSVGNumbers(value string) ([]float64, error) {
+	scanner := &svgScanner{data: value}
+	var numbers []float64
+	for scanner.more() {
+		number, err := scanner.number()
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, nil
+}
+
+// svgScanner reads the commands and numbers of path data, where numbers may
+// be glued together like 1-2 or .5.5
+type svgScanner struct {
+	data string
+	pos  int
+}
+
+func (s *svgScanner) skipSeparators() {
+	for s.pos < len(s.data) && strings.IndexByte(" ,\t\n\r", s.data[s.pos]) >= 0 {
+		s.pos++
+	}
+}
+
+// more reports whether a number follows
+func (s *svgScanner) more() bool {
+	s.skipSeparators()
+	return s.pos < len(s.data) && strings.IndexByte("+-.0123456789", s.data[s.pos]) >= 0
+}
+
+func (s *svgScanner) command() (byte, bool) {
+	s.skipSeparators()
+	if s.pos < len(s.data) && strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", s.data[s.pos]) >= 0 {
+		s.pos++
+		return s.data[s.pos-1], true
+	}
+	return 0, false
+}
+
+func (s *svgScanner) number() (float64, error) {
+	s.skipSeparators()
+	start := s.pos
+	digits := func() {
+		for s.pos < len(s.data) && s.data[s.pos] >= '0' && s.data[s.pos] <= '9' {
+			s.pos++
+		}
+	}
+	if s.pos < len(s.data) && (s.data[s.pos] == '+' || s.data[s.pos] == '-') {
+		s.pos++
+	}
+	digits()
+	if s.pos < len(s.data) && s.data[s.pos] == '.' {
+		s.pos++
+		digits()
+	}
+	if s.pos < len(s.data) && (s.data[s.pos] == 'e' || s.data[s.pos] == 'E') {
+		s.pos++
+		if s.pos < len(s.data) && (s.data[s.pos] == '+' || s.data[s.pos] == '-') {
+			s.pos++
+		}
+		digits()
+	}
+	number, err := strconv.ParseFloat(s.data[start:s.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected a number at %d", start)
+	}
+	return number, nil
+}
+
+// flag reads an arc flag, which may be glued to the next value
+func (s *svgScanner) flag() (bool, error) {
+	s.skipSeparators()
+	if s.pos < len(s.data) && (s.data[s.pos] == '0' || s.data[s.pos] == '1') {
+		s.pos++
+		return s.data[s.pos-1] == '1', nil
+	}
+	return false, fmt.Errorf("expected a flag at %d", s.pos)
+}
@@ -0,0 +1,68 @@
+package threadGenerator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRasterizeSVG(t *testing.T) {
+	document := `<?xml version="1.0"?>
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 100 50">
+  <defs><rect id="hidden" width="100" height="50"/></defs>
+  <g transform="translate(50 0) scale(2)">
+    <rect width="10" height="10" style="fill:#000"/>
+  </g>
+  <path d="M10 40h20v-10" fill="none" stroke="black" stroke-width="2"/>
+  <circle cx="85" cy="35" r="5" fill="white"/>
+</svg>`
+
+	gray, err := RasterizeSVG(strings.NewReader(document), 100)
+	require.NoError(t, err)
+	require.Equal(t, 100, gray.Bounds().Dx())
+
+	// The view box is twice as wide as high, centered vertically in the square
+	require.Equal(t, uint8(255), gray.GrayAt(5, 10).Y, "nothing is drawn above the view box")
+	require.Equal(t, uint8(0), gray.GrayAt(60, 35).Y, "the group is translated then scaled")
+	require.Equal(t, uint8(255), gray.GrayAt(45, 35).Y)
+	require.Equal(t, uint8(0), gray.GrayAt(20, 65).Y, "the stroke is drawn")
+	require.Equal(t, uint8(255), gray.GrayAt(25, 60).Y, "an open path with no fill is not filled")
+
+	_, err = RasterizeSVG(strings.NewReader(`<html><body/></html>`), 100)
+	require.Error(t, err)
+}
+
+func TestParseSVGPath(t *testing.T) {
+	subpaths, err := parseSVGPath("M0,0 10,0 l0-10zm20 0h5.5.5 Q30 5 35 0 A5 5 0 0 1 45 0")
+	require.NoError(t, err)
+	require.Len(t, subpaths, 2)
+	require.Equal(t, []svgPoint{{0, 0}, {10, 0}, {10, -10}}, subpaths[0].points)
+	require.True(t, subpaths[0].closed)
+
+	second := subpaths[1].points
+	require.Equal(t, svgPoint{20, 0}, second[0], "the move is relative to the start of the closed subpath")
+	require.Equal(t, svgPoint{26, 0}, second[2], "glued numbers are split")
+	require.Equal(t, svgPoint{45, 0}, second[len(second)-1])
+	// The arc is the upper half circle between both points
+	for _, point := range second[len(second)-svgCircleSteps/2 : len(second)-1] {
+		require.InDelta(t, 25, (point.X-40)*(point.X-40)+point.Y*point.Y, 0.01)
+		require.Less(t, point.Y, 0.0)
+	}
+
+	_, err = parseSVGPath("10 10")
+	require.Error(t, err)
+	_, err = parseSVGPath("M0 0Z 5 5")
+	require.Error(t, err)
+}
+
+func TestParseSVGTransform(t *testing.T) {
+	transform, err := parseSVGTransform("translate(10, 20) rotate(90)")
+	require.NoError(t, err)
+	point := transform.apply(svgPoint{1, 0})
+	require.InDelta(t, 10, point.X, 1e-9)
+	require.InDelta(t, 21, point.Y, 1e-9)
+
+	_, err = parseSVGTransform("perspective(2)")
+	require.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package threadGenerator
+
+import "fmt"
+
+// symmetry maps nails onto their images under the rotations and mirror of a
+// symmetric piece. Every ring is split in fold sectors, nail indices are
+// rotated by a sector on their own ring so multi ring layouts stay aligned.
+type symmetry struct {
+	rings  []Ring
+	fold   int  // Rotational order, 1 for no rotational symmetry
+	mirror bool // Mirror across the vertical axis of the frame
+}
+
+// ValidateSymmetry checks every ring splits evenly in the symmetric sectors
+func (c Config) ValidateSymmetry() error {
+	if c.SymmetryFold < 0 {
+		return fmt.Errorf("symmetry fold must not be negative")
+	}
+	fold := max(1, c.SymmetryFold)
+	for i, ring := range c.NailRings() {
+		name := "the ring"
+		if len(c.Rings) > 0 {
+			name = fmt.Sprintf("ring %d", i)
+		}
+		if ring.NailsQuantity%fold != 0 {
+			return fmt.Errorf("%s has %d nails, not a multiple of the %d-fold symmetry", name, ring.NailsQuantity, fold)
+		}
+		if c.MirrorSymmetry && ring.NailsQuantity%2 != 0 {
+			return fmt.Errorf("%s has %d nails, mirror symmetry needs an even number", name, ring.NailsQuantity)
+		}
+	}
+	return nil
+}
+
+// symmetry returns the symmetry of the generator
+func (tg *ThreadGenerator) symmetry() symmetry {
+	return symmetry{rings: tg.nailRings(), fold: max(1, tg.symmetryFold), mirror: tg.mirrorSymmetry}
+}
+
+// order returns the number of images of a line, itself included
+func (s symmetry) order() int {
+	if s.mirror {
+		return 2 * s.fold
+	}
+	return s.fold
+}
+
+// transform returns the image of a nail mirrored first when asked, then turned
+// by the given number of sectors. The mirror maps nail i of a ring of n nails
+// to n/2 - i, swapping the left and right halves of the frame.
+func (s symmetry) transform(nail, turn int, mirrored bool) int {
+	ring, index := locateNail(s.rings, nail)
+	quantity := s.rings[ring].NailsQuantity
+	first := nail - index
+	if mirrored {
+		index = quantity/2 - index
+	}
+	index = ((index+turn*quantity/s.fold)%quantity + quantity) % quantity
+	return first + index
+}
+
+// images returns the distinct images of a path, the path itself first. Lines
+// on an axis of the symmetry are their own images.
+func (s symmetry) images(path Path) []Path {
+	images := make([]Path, 0, s.order())
+	seen := make(map[Path]bool, s.order())
+	mirrors := []bool{false}
+	if s.mirror {
+		mirrors = append(mirrors, true)
+	}
+	for _, mirrored := range mirrors {
+		for turn := 0; turn < s.fold; turn++ {
+			mapped := Path{s.transform(path.StartingNail, turn, mirrored), s.transform(path.EndingNail, turn, mirrored)}
+			key := Path{min(mapped.StartingNail, mapped.EndingNail), max(mapped.StartingNail, mapped.EndingNail)}
+			if !seen[key] {
+				seen[key] = true
+				images = append(images, mapped)
+			}
+		}
+	}
+	return images
+}
+
+// assemble strings the images of the paths generated in one sector as a single
+// thread. With a mirror the sector is followed by a bridge across the axis and
+// its mirror image walked backwards. The sector is then repeated on every turn,
+// each joined to the next by a line whose images are the other joins, so the
+// joins are symmetric too and the thread ends on the nail it started from.
+// The generator ends the sector on a nail whose joins pass joinsAllowed.
+func (s symmetry) assemble(sector []Path) []Path {
+	if s.order() == 1 || len(sector) == 0 {
+		return sector
+	}
+
+	block := append([]Path(nil), sector...)
+	start, end := sector[0].StartingNail, sector[len(sector)-1].EndingNail
+	if s.mirror {
+		block = appendJoin(block, end, s.transform(end, 0, true))
+		for i := len(sector) - 1; i >= 0; i-- {
+			block = append(block, Path{s.transform(sector[i].EndingNail, 0, true), s.transform(sector[i].StartingNail, 0, true)})
+		}
+		end = s.transform(start, 0, true)
+	}
+
+	paths := make([]Path, 0, s.fold*(len(block)+1))
+	for turn := 0; turn < s.fold; turn++ {
+		for _, path := range block {
+			paths = append(paths, Path{s.transform(path.StartingNail, turn, false), s.transform(path.EndingNail, turn, false)})
+		}
+		paths = appendJoin(paths, s.transform(end, turn, false), s.transform(start, turn+1, false))
+	}
+	return paths
+}
+
+// joins returns the lines assemble adds to the images of a sector going from
+// start to end: the bridges across the mirror axis and the joins between turns
+func (s symmetry) joins(start, end int) []Path {
+	if s.order() == 1 {
+		return nil
+	}
+
+	var joins []Path
+	if s.mirror {
+		for turn := 0; turn < s.fold; turn++ {
+			joins = appendJoin(joins, s.transform(end, turn, false), s.transform(end, turn, true))
+		}
+		end = s.transform(start, 0, true)
+	}
+	for turn := 0; turn < s.fold; turn++ {
+		joins = appendJoin(joins, s.transform(end, turn, false), s.transform(start, turn+1, false))
+	}
+	return joins
+}
+
+// joinsAllowed reports whether a sector from start to end can be assembled:
+// every join must be a line the generator could pick, strung only once and
+// apart from the lines already strung and the given ones
+func (tg *ThreadGenerator) joinsAllowed(s symmetry, start, end int, usedPaths map[string]bool, strung []Path) bool {
+	seen := make(map[string]bool, len(strung))
+	for _, path := range strung {
+		seen[tg.getPairKey(path.StartingNail, path.EndingNail)] = true
+	}
+	for _, join := range s.joins(start, end) {
+		key := tg.getPairKey(join.StartingNail, join.EndingNail)
+		if !allowedPath(s.rings, join.StartingNail, join.EndingNail) || usedPaths[key] || seen[key] {
+			return false
+		}
+		seen[key] = true
+	}
+	return true
+}
+
+// appendJoin appends the path between two nails unless they are the same nail
+func appendJoin(paths []Path, from, to int) []Path {
+	if from == to {
+		return paths
+	}
+	return append(paths, Path{from, to})
+}
@@ -0,0 +1,184 @@
+package threadGenerator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// lineCounts counts the paths joining each pair of nails, in either direction
+func lineCounts(paths []Path) map[Path]int {
+	counts := make(map[Path]int, len(paths))
+	for _, path := range paths {
+		counts[Path{min(path.StartingNail, path.EndingNail), max(path.StartingNail, path.EndingNail)}]++
+	}
+	return counts
+}
+
+func TestSymmetryTransform(t *testing.T) {
+	s := symmetry{rings: []Ring{{NailsQuantity: 12}, {NailsQuantity: 6}}, fold: 3, mirror: true}
+	require.Equal(t, 6, s.order())
+	require.Equal(t, 5, s.transform(1, 1, false))
+	require.Equal(t, 1, s.transform(1, 3, false), "a full turn maps a nail to itself")
+	require.Equal(t, 5, s.transform(1, 0, true), "the mirror swaps the left and right halves")
+	require.Equal(t, 12+2, s.transform(12, 1, false), "nails turn on their own ring")
+	require.Equal(t, 12+3, s.transform(12, 0, true))
+
+	// A diameter through the axis of the mirror is its own mirror image
+	require.Len(t, s.images(Path{3, 9}), 3)
+	require.Len(t, s.images(Path{0, 4}), 6)
+	require.Equal(t, Path{0, 4}, s.images(Path{0, 4})[0])
+}
+
+// requireSymmetricThread checks a symmetric thread strings only lines the
+// generator may pick, each at most once per image of the piece it is on
+func requireSymmetricThread(t *testing.T, tg *ThreadGenerator, paths []Path) {
+	layout := tg.symmetry()
+	for _, path := range paths {
+		require.True(t, allowedPath(layout.rings, path.StartingNail, path.EndingNail), "%v is too short", path)
+	}
+	for line, count := range lineCounts(paths) {
+		require.LessOrEqual(t, count, layout.order()/len(layout.images(line)), "%v is strung again", line)
+	}
+}
+
+func TestSymmetryJoins(t *testing.T) {
+	tg := NewThreadGenerator(Config{NailsQuantity: 12, MinimumDifference: 2, SymmetryFold: 3, PhysicalRadius: 100})
+	layout := tg.symmetry()
+
+	// Each turn is joined to the start of the next
+	require.Equal(t, []Path{{3, 4}, {7, 8}, {11, 0}}, layout.joins(0, 3))
+	require.False(t, tg.joinsAllowed(layout, 0, 3, nil, nil), "the joins are too short")
+	require.Equal(t, []Path{{8, 4}, {0, 8}, {4, 0}}, layout.joins(0, 8))
+	require.True(t, tg.joinsAllowed(layout, 0, 8, nil, nil))
+	require.False(t, tg.joinsAllowed(layout, 0, 8, map[string]bool{"4:8": true}, nil), "a join is already strung")
+	require.False(t, tg.joinsAllowed(layout, 0, 8, nil, []Path{{0, 8}}), "a join is about to be strung")
+
+	// The joins are what assemble adds to the images of the sector
+	sector := []Path{{0, 5}, {5, 8}}
+	assembled := lineCounts(layout.assemble(sector))
+	for _, path := range sector {
+		for _, image := range layout.images(path) {
+			assembled[Path{min(image.StartingNail, image.EndingNail), max(image.StartingNail, image.EndingNail)}]--
+		}
+	}
+	for line, count := range lineCounts(layout.joins(0, 8)) {
+		require.Equal(t, count, assembled[line], "%v", line)
+		delete(assembled, line)
+	}
+	for line, count := range assembled {
+		require.Zero(t, count, "%v", line)
+	}
+
+	// A mirror bridges every sector to its image across the axis
+	layout.mirror = true
+	require.Equal(t, []Path{{2, 4}, {6, 8}, {10, 0}, {6, 4}, {10, 8}, {2, 0}}, layout.joins(0, 2))
+	require.True(t, tg.joinsAllowed(layout, 0, 2, nil, nil))
+	require.False(t, tg.joinsAllowed(layout, 0, 2, map[string]bool{"2:4": true}, nil), "a bridge is already strung")
+	require.Equal(t, []Path{{6, 4}, {10, 8}, {2, 0}}, layout.joins(0, 3), "a sector ending on the axis needs no bridge")
+}
+
+func TestSymmetricGeneration(t *testing.T) {
+	imagePath := flatImagePath(t)
+
+	for _, test := range []struct {
+		name   string
+		fold   int
+		mirror bool
+	}{
+		{name: "rotation", fold: 4},
+		{name: "mirror", mirror: true},
+		{name: "rotation and mirror", fold: 5, mirror: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tg := NewThreadGenerator(Config{
+				NailsQuantity:     80,
+				ImgSize:           120,
+				MaxPaths:          200,
+				StartingNail:      3,
+				MinimumDifference: 5,
+				BrightnessFactor:  30,
+				PhysicalRadius:    100,
+				SymmetryFold:      test.fold,
+				MirrorSymmetry:    test.mirror,
+			})
+			_, err := tg.Generate(Args{ImageName: imagePath})
+			require.NoError(t, err)
+
+			paths := tg.GetPathsList()
+			require.NotEmpty(t, paths)
+			require.LessOrEqual(t, len(paths), 200)
+			require.Equal(t, 3, paths[0].StartingNail)
+			require.Equal(t, 3, paths[len(paths)-1].EndingNail, "the thread ends where it started")
+			for i := 1; i < len(paths); i++ {
+				require.Equal(t, paths[i-1].EndingNail, paths[i].StartingNail, "the thread is continuous")
+			}
+
+			requireSymmetricThread(t, tg, paths)
+
+			// Every image of the piece strings the same lines as many times
+			counts := lineCounts(paths)
+			layout := tg.symmetry()
+			for _, mirrored := range []bool{false, test.mirror} {
+				for turn := 0; turn < layout.fold; turn++ {
+					mapped := make([]Path, len(paths))
+					for i, path := range paths {
+						mapped[i] = Path{layout.transform(path.StartingNail, turn, mirrored), layout.transform(path.EndingNail, turn, mirrored)}
+					}
+					require.Equal(t, counts, lineCounts(mapped))
+				}
+			}
+		})
+	}
+}
+
+func TestSymmetricGenerationJoins(t *testing.T) {
+	imagePath := flatImagePath(t)
+
+	// The sector ends on a nail whose joins the generator would pick too, from
+	// any starting nail
+	for _, fold := range []int{1, 2, 5} {
+		for _, mirror := range []bool{false, true} {
+			if fold == 1 && !mirror {
+				continue
+			}
+			for _, startingNail := range []int{7, 28, 49} {
+				tg := NewThreadGenerator(Config{
+					NailsQuantity:     80,
+					ImgSize:           120,
+					MaxPaths:          200,
+					StartingNail:      startingNail,
+					MinimumDifference: 5,
+					BrightnessFactor:  30,
+					PhysicalRadius:    100,
+					SymmetryFold:      fold,
+					MirrorSymmetry:    mirror,
+				})
+				_, err := tg.Generate(Args{ImageName: imagePath})
+				require.NoError(t, err)
+
+				paths := tg.GetPathsList()
+				require.NotEmpty(t, paths)
+				require.Equal(t, startingNail, paths[len(paths)-1].EndingNail)
+				requireSymmetricThread(t, tg, paths)
+			}
+		}
+	}
+}
+
+func TestValidateSymmetry(t *testing.T) {
+	config := DefaultConfig()
+	config.SymmetryFold = 6
+	require.NoError(t, config.ValidateSymmetry())
+	config.SymmetryFold = 7
+	require.ErrorContains(t, config.ValidateSymmetry(), "not a multiple of the 7-fold symmetry")
+
+	config.SymmetryFold = 0
+	config.MirrorSymmetry = true
+	config.Rings = []Ring{{NailsQuantity: 200, Radius: 600}, {NailsQuantity: 99, Radius: 300}}
+	require.ErrorContains(t, config.ValidateSymmetry(), "ring 1 has 99 nails")
+
+	tg := NewThreadGenerator(config)
+	_, err := tg.Generate(Args{ImageName: "unused.png"})
+	require.Error(t, err, "the symmetry is checked before loading the image")
+}
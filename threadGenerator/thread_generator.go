@@ -1,12 +1,13 @@
 package threadGenerator
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"image"
 	"image/color"
 	"math"
-	"os"
+	"runtime"
 	"sync"
 	"time"
 
@@ -29,12 +30,29 @@ type (
 		pathsDictionary   map[string][]Nail
 		pathsList         []Path
 		nailsList         []Nail
-		pixelSize         float64
 		threadLength      float64 // Length of the thread in mm
 		rotationAxis      string
 		needleAxis        string
 		spindleAxis       string
-	}
+		gcodeDialect      GcodeDialect
+		maxTwistTurns     float64 // Largest accumulated rotation allowed in one direction
+		spoolLength       float64 // Length of thread on one spool in meters, 0 for unlimited
+		rings             []Ring  // Concentric nail rings, empty for a single ring of nailsQuantity nails
+		ringAxis          string
+		threadProfile     *ThreadProfile // Calibrated darkness of the thread, nil for the brightness factor
+		linearLight       bool           // Score lines and render previews in linear light
+		inputType         InputType      // Kind of source the threads reproduce
+		seed              int64          // Seed drawing among lines of equal weight, 0 for the lowest nail
+		symmetryFold      int            // Rotational symmetry order, 0 or 1 for none
+		mirrorSymmetry    bool           // Mirror the piece across its vertical axis
+		scoringWorkers    int            // Goroutines scoring the next nails, 0 for GOMAXPROCS
+		progress          ProgressFunc   // Called after each line placed, nil to not report progress
+	}
+
+	// ProgressFunc receives the lines placed so far and the most lines the
+	// generation places. It is called from the generation loop, so it must
+	// return quickly.
+	ProgressFunc func(placed, total int)
 
 	Path struct {
 		StartingNail int
@@ -54,17 +72,29 @@ type (
 
 	// Config holds all possible configuration options for ThreadGenerator
 	Config struct {
-		NailsQuantity     int     // Number of nails around the circle
-		ImgSize           int     // Size of the image in pixels
-		MaxPaths          int     // Maximum number of paths to generate
-		StartingNail      int     // Starting nail index
-		MinimumDifference int     // Minimum difference between nails
-		BrightnessFactor  int     // Brightness factor for line drawing
-		ImageContrast     float64 // Image contrast adjustment
-		PhysicalRadius    float64 // Physical radius in mm
-		RotationAxis      string  // Rotation axis name
-		NeedleAxis        string  // Needle axis name
-		SpindleAxis       string  // Spindle axis name
+		NailsQuantity     int            `json:"nails_quantity"`            // Number of nails around the circle
+		ImgSize           int            `json:"img_size"`                  // Size of the image in pixels
+		MaxPaths          int            `json:"max_paths"`                 // Maximum number of paths to generate
+		StartingNail      int            `json:"starting_nail"`             // Starting nail index
+		MinimumDifference int            `json:"minimum_difference"`        // Minimum difference between nails
+		BrightnessFactor  int            `json:"brightness_factor"`         // Brightness factor for line drawing
+		ImageContrast     float64        `json:"image_contrast"`            // Image contrast adjustment
+		PhysicalRadius    float64        `json:"physical_radius"`           // Physical radius in mm
+		RotationAxis      string         `json:"rotation_axis"`             // Rotation axis name
+		NeedleAxis        string         `json:"needle_axis"`               // Needle axis name
+		SpindleAxis       string         `json:"spindle_axis"`              // Spindle axis name
+		GcodeDialect      GcodeDialect   `json:"gcode_dialect"`             // Firmware flavour of the generated G-code
+		MaxTwistTurns     float64        `json:"max_twist_turns"`           // Turns the ring may accumulate in one direction, 0 for no limit
+		SpoolLength       float64        `json:"spool_length"`              // Thread on one spool in meters, 0 for unlimited
+		Rings             []Ring         `json:"rings,omitempty"`           // Concentric nail rings, replacing NailsQuantity when set
+		RingAxis          string         `json:"ring_axis"`                 // Axis selecting the ring of a multi ring layout
+		ThreadProfile     *ThreadProfile `json:"thread_profile,omitempty"`  // Calibrated darkness of the thread, replacing BrightnessFactor when set
+		LinearLight       bool           `json:"linear_light"`              // Model the thread darkening in linear light instead of sRGB gray levels
+		InputType         InputType      `json:"input_type,omitempty"`      // Kind of source, photo when empty
+		Seed              int64          `json:"seed"`                      // Seed drawing among lines of equal weight, 0 to pick the lowest nail
+		SymmetryFold      int            `json:"symmetry_fold,omitempty"`   // Rotational symmetry order, every ring must split in as many sectors, 0 or 1 for none
+		MirrorSymmetry    bool           `json:"mirror_symmetry,omitempty"` // Mirror the piece across its vertical axis, every ring needs an even number of nails
+		ScoringWorkers    int            `json:"-"`                         // Goroutines scoring the next nails, 0 for GOMAXPROCS, the paths don't depend on it
 	}
 
 	OutputStats struct {
@@ -74,8 +104,7 @@ type (
 	}
 
 	weightResult struct {
-		Weight  int
-		Line    []image.Point
+		Weight  float64
 		NailIdx int
 	}
 )
@@ -94,12 +123,15 @@ func DefaultConfig() Config {
 		RotationAxis:      "A",
 		NeedleAxis:        "X",
 		SpindleAxis:       "Y",
+		GcodeDialect:      DialectFluidNC,
+		MaxTwistTurns:     2,
+		RingAxis:          "Z",
 	}
 }
 
 // NewThreadGenerator creates a new ThreadGenerator with the given configuration
 func NewThreadGenerator(config Config) *ThreadGenerator {
-	return &ThreadGenerator{
+	tg := &ThreadGenerator{
 		nailsQuantity:     config.NailsQuantity,
 		imgSize:           config.ImgSize,
 		maxPaths:          config.MaxPaths,
@@ -111,8 +143,28 @@ func NewThreadGenerator(config Config) *ThreadGenerator {
 		rotationAxis:      config.RotationAxis,
 		needleAxis:        config.NeedleAxis,
 		spindleAxis:       config.SpindleAxis,
-		pixelSize:         config.PhysicalRadius / float64(config.ImgSize),
+		gcodeDialect:      config.GcodeDialect,
+		maxTwistTurns:     config.MaxTwistTurns,
+		spoolLength:       config.SpoolLength,
+		ringAxis:          config.RingAxis,
+		threadProfile:     config.ThreadProfile,
+		linearLight:       config.LinearLight,
+		inputType:         config.InputType,
+		seed:              config.Seed,
+		symmetryFold:      config.SymmetryFold,
+		mirrorSymmetry:    config.MirrorSymmetry,
+		scoringWorkers:    config.ScoringWorkers,
+	}
+
+	// Nails are numbered ring after ring
+	if len(config.Rings) > 0 {
+		tg.rings = config.NailRings()
+		tg.nailsQuantity = 0
+		for _, ring := range tg.rings {
+			tg.nailsQuantity += ring.NailsQuantity
+		}
 	}
+	return tg
 }
 
 // SetImage sets the image to process
@@ -120,6 +172,11 @@ func (tg *ThreadGenerator) SetImage(imagePath string) {
 	tg.imageName = imagePath
 }
 
+// SetProgressFunc sets the function reporting the progress of the generation
+func (tg *ThreadGenerator) SetProgressFunc(progress ProgressFunc) {
+	tg.progress = progress
+}
+
 func (tg *ThreadGenerator) getDefaults() {
 	tg.nailsQuantity = 300
 	tg.imgSize = 800
@@ -132,6 +189,9 @@ func (tg *ThreadGenerator) getDefaults() {
 	tg.rotationAxis = "A"
 	tg.needleAxis = "X"
 	tg.spindleAxis = "Y"
+	tg.gcodeDialect = DialectFluidNC
+	tg.maxTwistTurns = 2
+	tg.ringAxis = "Z"
 }
 
 func (tg *ThreadGenerator) mergeArgs(args Args) error {
@@ -160,11 +220,6 @@ func (tg *ThreadGenerator) mergeArgs(args Args) error {
 		tg.physicalRadius = args.PhysicalRadius
 	}
 
-	// Recalculate pixelSize if either imgSize or physicalRadius changed
-	if args.ImgSize > 0 || args.PhysicalRadius > 0 {
-		tg.pixelSize = tg.physicalRadius / float64(tg.imgSize)
-	}
-
 	if args.ImageName != "" {
 		tg.imageName = args.ImageName
 	} else {
@@ -176,6 +231,12 @@ func (tg *ThreadGenerator) mergeArgs(args Args) error {
 
 // Generate processes the image and creates thread art based on configuration
 func (tg *ThreadGenerator) Generate(args Args) (*OutputStats, error) {
+	return tg.GenerateWithContext(context.Background(), args)
+}
+
+// GenerateWithContext is Generate stopping with the context error once the
+// context is done, checked before each line is placed
+func (tg *ThreadGenerator) GenerateWithContext(ctx context.Context, args Args) (*OutputStats, error) {
 	start := time.Now()
 
 	// If only ImageName is provided, don't modify other settings
@@ -197,14 +258,20 @@ func (tg *ThreadGenerator) Generate(args Args) (*OutputStats, error) {
 		}
 	}
 
-	sourceImage, err := tg.getSourceImage()
+	if err := tg.Config().ValidateSymmetry(); err != nil {
+		return nil, err
+	}
+
+	sourceImage, err := tg.loadCircleImage(tg.imageContrast)
 	if err != nil {
 		return nil, err
 	}
 
 	nailsList := tg.getNailsListFromImage(sourceImage)
 
-	tg.computePathsListFromImage(sourceImage, nailsList)
+	if _, err := tg.computePathsListFromImage(ctx, sourceImage, nailsList); err != nil {
+		return nil, err
+	}
 
 	return &OutputStats{
 		TotalLines:   len(tg.pathsList),
@@ -213,20 +280,22 @@ func (tg *ThreadGenerator) Generate(args Args) (*OutputStats, error) {
 	}, nil
 }
 
-func (tg *ThreadGenerator) getSourceImage() (*image.NRGBA, error) {
-	file, err := os.Open(tg.imageName)
-	if err != nil {
-		return nil, err
+// loadCircleImage loads the source image in gray levels with the contrast
+// adjustment, cropped into the ring and resized to the generation size. Line
+// art sources are loaded as the strokes to cover instead.
+func (tg *ThreadGenerator) loadCircleImage(contrast float64) (*image.NRGBA, error) {
+	if tg.lineArt() {
+		return tg.loadLineArtTarget()
 	}
 
-	img, _, err := image.Decode(file)
+	img, err := tg.loadSourceImage()
 	if err != nil {
 		return nil, err
 	}
 
 	imgGray := imaging.Grayscale(img)
 
-	imgGray = imaging.AdjustContrast(imgGray, float64(tg.imageContrast))
+	imgGray = imaging.AdjustContrast(imgGray, contrast)
 
 	imgSquare := imgGray
 	bounds := imgSquare.Bounds()
@@ -253,23 +322,32 @@ func (tg *ThreadGenerator) getSourceImage() (*image.NRGBA, error) {
 	return circleImgMin, nil
 }
 
-// getNailsListFromImage generates a list of nails from the source image in a circle
+// getNailsListFromImage generates a list of nails from the source image, on
+// one circle per ring. The image spans the physical radius.
 func (tg *ThreadGenerator) getNailsListFromImage(sourceImage image.Image) []Nail {
 	centerX := sourceImage.Bounds().Dx() / 2
 	centerY := sourceImage.Bounds().Dy() / 2
 	radius := math.Min(float64(centerX), float64(centerY))
-	tg.nailsList = make([]image.Point, tg.nailsQuantity)
-	for i := 0; i < tg.nailsQuantity; i++ {
-		alpha := float64(i) * 2 * math.Pi / float64(tg.nailsQuantity)
-		x := centerX + int(radius*math.Cos(alpha))
-		y := centerY + int(radius*math.Sin(alpha))
-		tg.nailsList[i] = Nail{X: x, Y: y}
+	tg.nailsList = make([]image.Point, 0, tg.nailsQuantity)
+	for _, ring := range tg.nailRings() {
+		ringRadius := radius
+		if len(tg.rings) > 0 {
+			ringRadius = radius * ring.Radius / tg.physicalRadius
+		}
+		for i := 0; i < ring.NailsQuantity; i++ {
+			alpha := float64(i) * 2 * math.Pi / float64(ring.NailsQuantity)
+			x := centerX + int(ringRadius*math.Cos(alpha))
+			y := centerY + int(ringRadius*math.Sin(alpha))
+			tg.nailsList = append(tg.nailsList, Nail{X: x, Y: y})
+		}
 	}
 	return tg.nailsList
 }
 
-// computePathsListFromImage generates a list of paths from the source image
-func (tg *ThreadGenerator) computePathsListFromImage(sourceImage image.Image, nailsList []Nail) []Path {
+// computePathsListFromImage generates a list of paths from the source image.
+// A symmetric piece is generated one sector at a time: every line is scored
+// and strung with its images, then the sector is assembled into the thread.
+func (tg *ThreadGenerator) computePathsListFromImage(ctx context.Context, sourceImage image.Image, nailsList []Nail) ([]Path, error) {
 	sourceImageBounds := sourceImage.Bounds()
 	canvas := image.NewGray(sourceImageBounds)
 	for y := sourceImageBounds.Min.Y; y < sourceImageBounds.Max.Y; y++ {
@@ -277,102 +355,208 @@ func (tg *ThreadGenerator) computePathsListFromImage(sourceImage image.Image, na
 			canvas.Set(x, y, sourceImage.At(x, y))
 		}
 	}
+	var (
+		light   *lightCanvas
+		strokes *strokeCanvas
+	)
+	if tg.lineArt() {
+		strokes = newStrokeCanvas(canvas)
+	} else if tg.linearLight {
+		light = newLightCanvas(canvas)
+	}
 
 	tg.generateDictionary(nailsList)
 
 	var nailIndex = tg.startingNail
 	var pathsList = []Path{}
 	usedPaths := make(map[string]bool)
+	rings := tg.nailRings()
+	random := tg.newTieBreaker()
+	layoutSymmetry := tg.symmetry()
+	// Each image of the sector also needs a join to the next one
+	steps := tg.maxPaths
+	if layoutSymmetry.order() > 1 {
+		steps = tg.maxPaths/layoutSymmetry.order() - 1
+	}
+	// Threads already crossing each pixel, the darkening of a thread profile depends on it
+	lineCounts := make([]int, sourceImageBounds.Dx()*sourceImageBounds.Dy())
+	workers := tg.scoringWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	for i := 0; i < steps; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
-	for i := 0; i < tg.maxPaths; i++ {
 		// create a channel to gather results
 		channel := make(chan weightResult, len(nailsList)-1)
 
+		// Score the possible next nails on a bounded pool, each worker takes
+		// every workers-th nail
 		var wg sync.WaitGroup
-		// loop through all possible next nails
-		for nextnailIndex := 0; nextnailIndex < len(nailsList); nextnailIndex++ {
-			// skip if the nail is the same
-			if nailIndex == nextnailIndex {
-				continue
-			}
-
-			wg.Add(1) // add a waitgroup before goroutine
-
-			// calculate weight in a goroutine
-			go func(nailIdx, nextnailIdx int) {
+		for worker := 0; worker < workers; worker++ {
+			wg.Add(1)
+			go func(nailIdx, first int) {
 				defer wg.Done()
-				weight := 0
-				var line []image.Point
-				difference := int(math.Abs(float64(nextnailIdx) - float64(nailIdx)))
-
-				if difference < tg.minimumDifference || difference > (len(nailsList)-tg.minimumDifference) {
-					return
-				}
-
-				if _, exists := usedPaths[tg.getPairKey(nextnailIdx, nailIdx)]; exists {
-					return
-				}
-
-				line = tg.pathsDictionary[tg.getPairKey(nailIdx, nextnailIdx)]
-				weight = len(line) * 255
-
-				for _, pixelPosition := range line {
-					pixelColor := canvas.GrayAt(pixelPosition.X, pixelPosition.Y).Y
-					weight -= int(pixelColor)
-				}
-
-				weight = weight / len(line)
-
-				if weight == 0 {
-					return
-				}
 
-				// send the result through the channel
-				channel <- weightResult{
-					Weight:  weight,
-					Line:    line,
-					NailIdx: nextnailIdx,
+				for nextnailIdx := first; nextnailIdx < len(nailsList); nextnailIdx += workers {
+					// skip if the nail is the same
+					if nailIdx == nextnailIdx {
+						continue
+					}
+
+					if !allowedPath(rings, nailIdx, nextnailIdx) {
+						continue
+					}
+
+					if _, exists := usedPaths[tg.getPairKey(nextnailIdx, nailIdx)]; exists {
+						continue
+					}
+
+					// The last line of a sector must end where its joins can start
+					if i == steps-1 && !tg.joinsAllowed(layoutSymmetry, tg.startingNail, nextnailIdx, usedPaths, layoutSymmetry.images(Path{nailIdx, nextnailIdx})) {
+						continue
+					}
+
+					weight := tg.imagesWeight(canvas, light, strokes, layoutSymmetry.images(Path{nailIdx, nextnailIdx}))
+					if weight == 0 {
+						continue
+					}
+
+					// send the result through the channel
+					channel <- weightResult{
+						Weight:  weight,
+						NailIdx: nextnailIdx,
+					}
 				}
-
-				return
-			}(nailIndex, nextnailIndex) // pass nextnailIndex as an argument to avoid data race
+			}(nailIndex, worker)
 		}
 
 		//initialize maxWeight outside the loop
-		maxWeight := 0
-		var maxLine = []image.Point{}
+		maxWeight := 0.0
 		var maxnailIndex = 0
 		wg.Wait() // wait for all goroutines to finish
 		close(channel)
 
 		// read from channel after closing it
+		var ties []weightResult
 		for res := range channel {
-			if res.Weight > maxWeight {
+			switch {
+			case res.Weight > maxWeight:
 				maxWeight = res.Weight
-				maxLine = res.Line
-				maxnailIndex = res.NailIdx
+				ties = append(ties[:0], res)
+			case res.Weight == maxWeight:
+				ties = append(ties, res)
 			}
 		}
+		if len(ties) > 0 {
+			maxnailIndex = breakTie(ties, random).NailIdx
+		}
 
 		if nailIndex == maxnailIndex {
 			break
 		}
 
-		usedPaths[tg.getPairKey(nailIndex, maxnailIndex)] = true
 		pathsList = append(pathsList, Path{nailIndex, maxnailIndex})
-		tg.threadLength += tg.lineLength(nailIndex, maxnailIndex)
+		for _, path := range layoutSymmetry.images(Path{nailIndex, maxnailIndex}) {
+			key := tg.getPairKey(path.StartingNail, path.EndingNail)
+			usedPaths[key] = true
+
+			// Brighthen brightness of chosen line
+			for _, pixelPosition := range tg.pathsDictionary[key] {
+				if strokes != nil {
+					strokes.cover(pixelPosition)
+					continue
+				}
+				if light != nil {
+					light.brighten(pixelPosition, tg.linearDarkening(lineCounts, pixelPosition))
+					continue
+				}
+				var pixel = int(canvas.GrayAt(pixelPosition.X, pixelPosition.Y).Y)
+				value := uint8(min(255, pixel+tg.lineDarkening(lineCounts, pixelPosition)))
+				canvas.SetGray(pixelPosition.X, pixelPosition.Y, color.Gray{value})
+			}
+		}
 		nailIndex = maxnailIndex
 
-		// Brighthen brightness of chosen line
-		for _, pixelPosition := range maxLine {
-			var pixel = int(canvas.GrayAt(pixelPosition.X, pixelPosition.Y).Y)
-			value := uint8(min(255, pixel+tg.brightnessFactor))
-			canvas.SetGray(pixelPosition.X, pixelPosition.Y, color.Gray{value})
+		if tg.progress != nil {
+			tg.progress((i+1)*layoutSymmetry.order(), tg.maxPaths)
+		}
+	}
+
+	// A sector cut short may end on a nail its joins can't leave, it is then
+	// shortened until they can
+	for len(pathsList) > 0 && !tg.joinsAllowed(layoutSymmetry, tg.startingNail, nailIndex, usedPaths, nil) {
+		last := pathsList[len(pathsList)-1]
+		pathsList = pathsList[:len(pathsList)-1]
+		for _, path := range layoutSymmetry.images(last) {
+			delete(usedPaths, tg.getPairKey(path.StartingNail, path.EndingNail))
+		}
+		nailIndex = last.StartingNail
+	}
+
+	tg.pathsList = layoutSymmetry.assemble(pathsList)
+	for _, path := range tg.pathsList {
+		tg.threadLength += tg.lineLength(path.StartingNail, path.EndingNail)
+	}
+	return tg.pathsList, nil
+}
+
+// imagesWeight scores the images of a line jointly, as their mean weight
+func (tg *ThreadGenerator) imagesWeight(canvas *image.Gray, light *lightCanvas, strokes *strokeCanvas, images []Path) float64 {
+	weight := 0.0
+	for _, path := range images {
+		weight += tg.lineWeight(canvas, light, strokes, tg.pathsDictionary[tg.getPairKey(path.StartingNail, path.EndingNail)])
+	}
+	return weight / float64(len(images))
+}
+
+// lineWeight returns the mean darkness left along a line, 0 when the line would
+// not darken the piece by at least one gray level. In linear light the darkness
+// is read from the light canvas, for line art the strokes left to cover are
+// scored instead.
+func (tg *ThreadGenerator) lineWeight(canvas *image.Gray, light *lightCanvas, strokes *strokeCanvas, line []image.Point) float64 {
+	if strokes != nil {
+		return strokes.weight(line) * 255
+	}
+	if light != nil {
+		weight := light.darkness(line) * 255
+		if weight < 1 {
+			return 0
 		}
+		return weight
+	}
 
+	weight := len(line) * 255
+	for _, pixelPosition := range line {
+		pixelColor := canvas.GrayAt(pixelPosition.X, pixelPosition.Y).Y
+		weight -= int(pixelColor)
 	}
-	tg.pathsList = pathsList
-	return pathsList
+	return float64(weight / len(line))
+}
+
+// lineDarkening returns how much darker a pixel gets with one more thread and
+// counts the thread. Without a thread profile every thread darkens by the brightness factor.
+func (tg *ThreadGenerator) lineDarkening(lineCounts []int, pixel image.Point) int {
+	if tg.threadProfile == nil {
+		return tg.brightnessFactor
+	}
+	index := pixel.Y*tg.imgSize + pixel.X
+	if pixel.X < 0 || pixel.Y < 0 || pixel.X >= tg.imgSize || index >= len(lineCounts) {
+		return 0
+	}
+	before := tg.threadProfile.lineBrightness(lineCounts[index], tg.imagePixelSize())
+	lineCounts[index]++
+	after := tg.threadProfile.lineBrightness(lineCounts[index], tg.imagePixelSize())
+	return int(math.Round(before - after))
+}
+
+// imagePixelSize returns the size of a pixel of the processed image in mm,
+// the image spans the diameter of the frame
+func (tg *ThreadGenerator) imagePixelSize() float64 {
+	return 2 * tg.physicalRadius / float64(tg.imgSize)
 }
 
 // GenerateDictionary generates a dictionary of all possible lines between nails
@@ -462,6 +646,13 @@ func (tg *ThreadGenerator) GeneratePathsImage() (image.Image, error) {
 		}
 	}
 
+	if tg.threadProfile != nil {
+		return tg.predictedPathsImage(pathsImage), nil
+	}
+	if tg.linearLight {
+		return tg.linearPathsImage(pathsImage), nil
+	}
+
 	for i := 0; i < len(tg.pathsList); i++ {
 		line := tg.pathsDictionary[tg.getPairKey(tg.pathsList[i].StartingNail, tg.pathsList[i].EndingNail)]
 		for _, point := range line {
@@ -474,83 +665,230 @@ func (tg *ThreadGenerator) GeneratePathsImage() (image.Image, error) {
 	return pathsImage, nil
 }
 
+// predictedPathsImage draws the paths with the thread profile, so the preview
+// predicts the brightness of the physical piece
+func (tg *ThreadGenerator) predictedPathsImage(pathsImage *image.Gray) *image.Gray {
+	pixelSize := tg.imagePixelSize()
+	for i, count := range tg.pathsLineCounts(pathsImage.Bounds()) {
+		if count > 0 {
+			pathsImage.Pix[i] = uint8(math.Round(tg.threadProfile.lineBrightness(count, pixelSize)))
+		}
+	}
+	return pathsImage
+}
+
+// pathsLineCounts returns the number of paths crossing each pixel of the bounds
+func (tg *ThreadGenerator) pathsLineCounts(bounds image.Rectangle) []int {
+	lineCounts := make([]int, bounds.Dx()*bounds.Dy())
+	for _, path := range tg.pathsList {
+		for _, point := range tg.pathsDictionary[tg.getPairKey(path.StartingNail, path.EndingNail)] {
+			if point.In(bounds) {
+				lineCounts[point.Y*bounds.Dx()+point.X]++
+			}
+		}
+	}
+	return lineCounts
+}
+
 func (tg *ThreadGenerator) GetPathsList() []Path {
 	return tg.pathsList
 }
 
+// SetPathsList replaces the paths list, e.g. with one loaded from a previous run,
+// so G-code can be produced without generating again
+func (tg *ThreadGenerator) SetPathsList(paths []Path) {
+	tg.pathsList = paths
+}
+
+// GetGcode returns the stringing program rendered for the configured dialect
 func (tg *ThreadGenerator) GetGcode() []string {
-	gCodeLines := []string{fmt.Sprintf("G28 %s5 %s0 %s0", tg.needleAxis, tg.spindleAxis, tg.rotationAxis)} // GCode for homing
+	return tg.gcodeWriter().Write(tg.GetGcodeProgram())
+}
+
+// GetGcodeProgram builds the dialect independent stringing program
+func (tg *ThreadGenerator) GetGcodeProgram() *GcodeProgram {
+	program, _ := tg.planGcodeProgram(0)
+	return program
+}
+
+// GetMotionStats returns the travel and run time estimate of the stringing program
+func (tg *ThreadGenerator) GetMotionStats() MotionStats {
+	_, stats := tg.planGcodeProgram(0)
+	return stats
+}
+
+// GetGcodeFromStep returns a stringing program that resumes at the given path
+// index, e.g. after the thread broke. It re-homes the machine, moves to the
+// nail the path starts from and pauses so the thread can be tied again.
+func (tg *ThreadGenerator) GetGcodeFromStep(step int) ([]string, error) {
+	program, err := tg.GetGcodeProgramFromStep(step)
+	if err != nil {
+		return nil, err
+	}
+	return tg.gcodeWriter().Write(program), nil
+}
+
+// GetGcodeProgramFromStep builds the dialect independent program resuming at the given path index
+func (tg *ThreadGenerator) GetGcodeProgramFromStep(step int) (*GcodeProgram, error) {
+	if step < 0 || step >= len(tg.pathsList) {
+		return nil, fmt.Errorf("step %d is out of range, the paths list has %d paths", step, len(tg.pathsList))
+	}
+	program, _ := tg.planGcodeProgram(step)
+	return program, nil
+}
+
+// StepMarker returns the comment written before the commands of a path, the
+// machine service finds the step a job stopped at with it
+func StepMarker(step int) string {
+	return fmt.Sprintf("Step %d", step)
+}
+
+// ParseStepMarker returns the step of a step marker comment
+func ParseStepMarker(comment string) (int, bool) {
+	var step int
+	if _, err := fmt.Sscanf(comment, "Step %d", &step); err != nil {
+		return 0, false
+	}
+	return step, true
+}
+
+// ParseResumeMarker returns the step a program built by GetGcodeFromStep
+// resumes at, from its header comment
+func ParseResumeMarker(comment string) (int, bool) {
+	var step int
+	if _, err := fmt.Sscanf(comment, "Resuming at step %d", &step); err != nil {
+		return 0, false
+	}
+	return step, true
+}
+
+// planGcodeProgram plans the rotary moves for the paths list from the given
+// step, optimizes the resulting program and computes its motion statistics
+func (tg *ThreadGenerator) planGcodeProgram(step int) (*GcodeProgram, MotionStats) {
+	program := &GcodeProgram{}
+	program.Home(tg.homeAxes()...)
+	planner := newMotionPlanner(rotaryUnitsPerTurn(tg.nailRings()), tg.maxTwistTurns)
 	feedRate := 3000
-	var nailOffset float32 = 0.5
-	for i, path := range tg.pathsList {
+	nailOffset := 0.5
+
+	// The worker rejects compositions whose paths don't fit on a spool before
+	// building the program, a planning error here only drops the spool pauses
+	segments, _ := tg.GetSpoolSegments()
+	segmentStarts := make(map[int]int)
+	for i, segment := range segments {
+		if i > 0 && segment.StartStep > step {
+			segmentStarts[segment.StartStep] = i
+		}
+	}
+
+	for i, path := range tg.pathsList[step:] {
+		program.Comment(StepMarker(step + i))
+		if index, ok := segmentStarts[step+i]; ok {
+			program.Comment(fmt.Sprintf("Spool %d/%d: %d paths, %.1f m", index+1, len(segments), segments[index].Paths(), segments[index].Length))
+			message := fmt.Sprintf("Spool %d/%d: tie a new thread on nail %d", index+1, len(segments), path.StartingNail)
+			if segments[index].Forced {
+				message += ", a crowded nail so keep the knot small"
+			}
+			program.Pause(message)
+		}
 		if i == 0 {
-			gCodeLines = append(gCodeLines, fmt.Sprintf("G01 %s%d F%d; Move to nail %d", tg.needleAxis, path.StartingNail, feedRate, path.StartingNail))
-			gCodeLines = append(gCodeLines, "M0 ; Pausing to allow for thread to be attached")
+			tg.moveToPin(program, planner, path.StartingNail, feedRate, 0)
+			if step == 0 {
+				program.Pause("Pausing to allow for thread to be attached")
+			} else {
+				program.Pause(fmt.Sprintf("Tie the thread to nail %d to resume at step %d", path.StartingNail, step))
+			}
 		}
-		// Calculate the delta between the starting and ending nails
-		fromPin := path.StartingNail % tg.nailsQuantity
-		toPin := path.EndingNail
-
-		delta := toPin - (fromPin % tg.nailsQuantity)
-
-		if abs(delta) < (tg.nailsQuantity / 2) {
-			// Move directly if less than half the number of nails.
-			move := tg.moveToPin(toPin, feedRate, nailOffset)
-			gCodeLines = append(gCodeLines, move)
-		} else {
-			// Move relatively if more than half the total nails.
-			gCodeLines = append(gCodeLines, "G91 ; Switch to relative positioning mode")
-			toPinRelative := tg.nailsQuantity - (fromPin % tg.nailsQuantity) + toPin
-			if delta > 0 {
-				toPinRelative = -(tg.nailsQuantity - abs(delta))
+		// Approach the nail from its lower side so the wrap always goes the same way
+		offset := nailOffset * tg.nailSpacing(path.EndingNail)
+		tg.moveToPin(program, planner, path.EndingNail, feedRate, offset)
+		tg.pinWrapGcode(program, planner, offset)
+	}
+
+	merged := OptimizeProgram(program)
+	stats := AnalyzeProgram(program, tg.rotationAxis, rotaryUnitsPerTurn(tg.nailRings()))
+	stats.MergedMoves = merged
+
+	header := &GcodeProgram{}
+	header.Comment(fmt.Sprintf("Thread art: %d paths on %d nails", len(tg.pathsList), tg.nailsQuantity))
+	if tg.multiRing() {
+		for i, ring := range tg.nailRings() {
+			header.Comment(fmt.Sprintf("Ring %d (%s%d): %d nails at %.1f mm, %s in degrees", i, tg.ringAxis, i, ring.NailsQuantity, ring.Radius, tg.rotationAxis))
+		}
+	}
+	if step > 0 {
+		header.Comment(fmt.Sprintf("Resuming at step %d, %d paths remaining", step, len(tg.pathsList)-step))
+	}
+	header.Comment(fmt.Sprintf("Estimated run time: %s", stats.EstimatedRunTime.Round(time.Second)))
+	header.Comment(fmt.Sprintf("Rotary travel: %.1f turns, max twist %.2f turns", stats.RotaryTurns, stats.MaxTwistTurns))
+	if len(segments) > 1 {
+		header.Comment(fmt.Sprintf("Thread: %d spools of %.1f m", len(segments), tg.spoolLength))
+		for i, segment := range segments {
+			text := fmt.Sprintf("Spool %d: steps %d to %d from nail %d, %.1f m", i+1, segment.StartStep, segment.EndStep-1, segment.StartNail, segment.Length)
+			if segment.Forced {
+				text += ", tied on a crowded nail"
 			}
-			gCodeLines = append(gCodeLines, tg.moveByDelta(toPinRelative, toPin, feedRate, nailOffset))
-			gCodeLines = append(gCodeLines, "G90 ; Switch back to absolute positioning mode")
-			gCodeLines = append(gCodeLines, fmt.Sprintf("G92 %s%.2f; Set current position to %.2f", tg.rotationAxis, float32(toPin)-nailOffset, float32(toPin)-nailOffset))
+			header.Comment(text)
 		}
-		// Generate GCode lines for the thread movement
-		gCodeLines = append(gCodeLines, tg.pinWrapGcode(fromPin, toPin, nailOffset)...)
 	}
-	return gCodeLines
+	program.Commands = append(header.Commands, program.Commands...)
+
+	return program, stats
+}
+
+// gcodeWriter returns a writer for the configured dialect, falling back to FluidNC
+func (tg *ThreadGenerator) gcodeWriter() *GcodeWriter {
+	writer, err := NewGcodeWriter(tg.gcodeDialect)
+	if err != nil {
+		writer, _ = NewGcodeWriter(DialectFluidNC)
+	}
+	return writer
 }
 
-func (tg *ThreadGenerator) pinWrapGcode(fromPin, toPin int, nailOffset float32) []string {
-	gCodeLines := []string{}
+func (tg *ThreadGenerator) pinWrapGcode(program *GcodeProgram, planner *motionPlanner, nailOffset float64) {
 	AxisXMax := -10
 	AxisXMin := 0
 	feedrateBetweenNails := 200
 	nailFeedRate := 2000
 
 	// Retract the needle
-	moveXMax := fmt.Sprintf("G01 %s%d F%d", tg.needleAxis, AxisXMax, nailFeedRate)
-	gCodeLines = append(gCodeLines, moveXMax)
+	program.Move(nailFeedRate, "", AxisValue{tg.needleAxis, float64(AxisXMax)})
 
-	// Move to the nail position plus the offset to pass the thread around the nail
-	endPos := fmt.Sprintf("G01 %s%.2f F%d", tg.rotationAxis, float32(toPin)+nailOffset, feedrateBetweenNails)
-	gCodeLines = append(gCodeLines, endPos)
+	// Move past the nail to the other side to pass the thread around it
+	program.Move(feedrateBetweenNails, "", AxisValue{tg.rotationAxis, planner.advance(2 * nailOffset)})
 
 	// Move back the needle to the starting position
-	moveXMin := fmt.Sprintf("G01 %s%d F%d", tg.needleAxis, AxisXMin, nailFeedRate)
-	gCodeLines = append(gCodeLines, moveXMin)
-
-	return gCodeLines
+	program.Move(nailFeedRate, "", AxisValue{tg.needleAxis, float64(AxisXMin)})
 }
 
-func (tg *ThreadGenerator) moveToPin(pin, feedrate int, nailOffset float32) string {
-	return fmt.Sprintf("G01 %s%.2f F%d; Move to nail %d", tg.rotationAxis, float32(pin)-nailOffset, feedrate, pin)
-}
+// moveToPin turns the ring to a nail, the offset is in rotation axis units.
+// On a multi ring layout the needle first moves to the ring of the nail.
+func (tg *ThreadGenerator) moveToPin(program *GcodeProgram, planner *motionPlanner, pin, feedrate int, nailOffset float64) {
+	if !tg.multiRing() {
+		position := planner.approach(tg.rotaryPosition(pin) - nailOffset)
+		program.Move(feedrate, fmt.Sprintf("Move to nail %d", pin), AxisValue{tg.rotationAxis, position})
+		return
+	}
 
-func (tg *ThreadGenerator) moveByDelta(delta, nail, feedrate int, nailOffset float32) string {
-	return fmt.Sprintf("G01 %s%.2f F%d; Move by delta %d (nail %d)", tg.rotationAxis, float32(delta)-nailOffset, feedrate, delta, nail)
+	ring, index := locateNail(tg.rings, pin)
+	if ring != planner.ring {
+		program.Move(feedrate, fmt.Sprintf("Move to ring %d", ring), AxisValue{tg.ringAxis, float64(ring)})
+		planner.ring = ring
+	}
+	position := planner.approach(tg.rotaryPosition(pin) - nailOffset)
+	program.Move(feedrate, fmt.Sprintf("Move to ring %d nail %d", ring, index), AxisValue{tg.rotationAxis, position})
 }
 
-func abs(x int) int {
-	if x < 0 {
-		return -x
+// homeAxes returns the axes homed before stringing, with the ring axis on a multi ring layout
+func (tg *ThreadGenerator) homeAxes() []AxisValue {
+	axes := []AxisValue{{tg.needleAxis, 5}, {tg.spindleAxis, 0}, {tg.rotationAxis, 0}}
+	if tg.multiRing() {
+		axes = append(axes, AxisValue{tg.ringAxis, 0})
 	}
-	return x
+	return axes
 }
 
+// GenerateHolesGcode returns the nail hole drilling program rendered for the configured dialect
 func (tg *ThreadGenerator) GenerateHolesGcode() []string {
 	rotationSpeed := 200
 	feedRateIn := 170
@@ -558,19 +896,29 @@ func (tg *ThreadGenerator) GenerateHolesGcode() []string {
 	AxisYMin := -0.5
 	AxisYMax := -3.20
 
-	gCodeLines := []string{fmt.Sprintf("G28 %s0 %s0", tg.spindleAxis, tg.rotationAxis)} // GCode for homing
+	program := &GcodeProgram{}
+	if tg.multiRing() {
+		program.Home(AxisValue{tg.spindleAxis, 0}, AxisValue{tg.rotationAxis, 0}, AxisValue{tg.ringAxis, 0})
+	} else {
+		program.Home(AxisValue{tg.spindleAxis, 0}, AxisValue{tg.rotationAxis, 0})
+	}
 
 	for i := 0; i < tg.nailsQuantity; i++ {
-		gCodeLines = append(gCodeLines, fmt.Sprintf("G01 %s%d F%d; Move to nail %d", tg.rotationAxis, i, rotationSpeed, i))
-		gCodeLines = append(gCodeLines, fmt.Sprintf("G01 %s%.2f F%d; Drill hole at nail %d", tg.spindleAxis, AxisYMax, feedRateIn, i))
-		gCodeLines = append(gCodeLines, fmt.Sprintf("G01 %s%.2f F%d; Retract needle", tg.spindleAxis, AxisYMin, feedRateOut))
+		ring, index := locateNail(tg.nailRings(), i)
+		if tg.multiRing() && index == 0 {
+			program.Move(rotationSpeed, fmt.Sprintf("Move to ring %d", ring), AxisValue{tg.ringAxis, float64(ring)})
+		}
+		program.Move(rotationSpeed, fmt.Sprintf("Move to nail %d", i), AxisValue{tg.rotationAxis, tg.rotaryPosition(i)})
+		program.Move(feedRateIn, fmt.Sprintf("Drill hole at nail %d", i), AxisValue{tg.spindleAxis, AxisYMax})
+		program.Move(feedRateOut, "Retract needle", AxisValue{tg.spindleAxis, AxisYMin})
 	}
 
-	return gCodeLines
+	return tg.gcodeWriter().Write(program)
 }
 
+// lineLength returns the length of a line in mm, measured on the ring geometry
+// like the spool planning. Counting the pixels of the line would undercount
+// diagonals.
 func (tg *ThreadGenerator) lineLength(startNail, endNail int) float64 {
-	pixels := tg.pathsDictionary[tg.getPairKey(startNail, endNail)]
-	distance := float64(len(pixels)) * tg.pixelSize // multiply with the size of a pixel
-	return distance
+	return chordLength(tg.nailRings(), startNail, endNail)
 }
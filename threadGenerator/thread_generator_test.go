@@ -0,0 +1,83 @@
+package threadGenerator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerationProgress(t *testing.T) {
+	imagePath := flatImagePath(t)
+
+	for _, test := range []struct {
+		name string
+		fold int
+		step int
+	}{
+		{name: "single lines", fold: 0, step: 1},
+		{name: "symmetric groups", fold: 4, step: 4},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tg := NewThreadGenerator(Config{
+				NailsQuantity:     80,
+				ImgSize:           120,
+				MaxPaths:          100,
+				MinimumDifference: 5,
+				BrightnessFactor:  30,
+				PhysicalRadius:    100,
+				SymmetryFold:      test.fold,
+			})
+			var placed []int
+			tg.SetProgressFunc(func(lines, total int) {
+				require.Equal(t, 100, total)
+				placed = append(placed, lines)
+			})
+			_, err := tg.Generate(Args{ImageName: imagePath})
+			require.NoError(t, err)
+
+			require.NotEmpty(t, placed)
+			for i, lines := range placed {
+				require.Equal(t, (i+1)*test.step, lines, "progress is reported after every line")
+			}
+			require.LessOrEqual(t, placed[len(placed)-1], len(tg.GetPathsList()))
+		})
+	}
+}
+
+func TestGenerationCancellation(t *testing.T) {
+	tg := NewThreadGenerator(Config{
+		NailsQuantity:     80,
+		ImgSize:           120,
+		MaxPaths:          100,
+		MinimumDifference: 5,
+		BrightnessFactor:  30,
+		PhysicalRadius:    100,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	var placed int
+	tg.SetProgressFunc(func(lines, total int) {
+		placed = lines
+		if lines == 10 {
+			cancel()
+		}
+	})
+
+	_, err := tg.GenerateWithContext(ctx, Args{ImageName: flatImagePath(t)})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 10, placed, "no line is placed once the context is done")
+}
+
+func TestStepMarkers(t *testing.T) {
+	step, ok := ParseStepMarker(StepMarker(42))
+	require.True(t, ok)
+	require.Equal(t, 42, step)
+	_, ok = ParseStepMarker("Move to nail 42")
+	require.False(t, ok)
+
+	step, ok = ParseResumeMarker("Resuming at step 17, 83 paths remaining")
+	require.True(t, ok)
+	require.Equal(t, 17, step)
+	_, ok = ParseResumeMarker(StepMarker(17))
+	require.False(t, ok)
+}
@@ -0,0 +1,140 @@
+package threadGenerator
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+type (
+	// ThreadProfile models how dark threads look on the frame. Threads of the
+	// apparent width land at random so they cover 1 - exp(-density * width) of
+	// an area, and each covered spot is darkened by the opacity.
+	ThreadProfile struct {
+		Opacity float64 `json:"opacity"` // Darkening of the background under a thread, from 0 to 1
+		Width   float64 `json:"width"`   // Apparent thread width in mm
+	}
+
+	// CalibrationBand is a band of the test piece strung with parallel threads
+	CalibrationBand struct {
+		Density    float64 `json:"density"`    // Threads per mm, 0 for the bare background
+		Brightness float64 `json:"brightness"` // Measured brightness relative to the background
+	}
+)
+
+// Brightness returns the brightness of an area crossed by threads at the
+// given density in threads per mm, relative to the bare background
+func (p ThreadProfile) Brightness(density float64) float64 {
+	return 1 - p.Opacity*(1-math.Exp(-density*p.Width))
+}
+
+// lineBrightness returns the gray level of a pixel crossed by the given number
+// of threads. A thread crossing a pixel is a density of one thread per pixel size.
+func (p ThreadProfile) lineBrightness(lines int, pixelSize float64) float64 {
+	return 255 * p.Brightness(float64(lines)/pixelSize)
+}
+
+// MeasureCalibrationBands measures the photo of a test piece made of vertical
+// bands of equal width, one per density from left to right. The photo must be
+// cropped to the test piece and hold at least one bare band of density 0.
+func MeasureCalibrationBands(photo image.Image, densities []float64) ([]CalibrationBand, error) {
+	if len(densities) < 2 {
+		return nil, errors.New("the test piece needs at least two bands")
+	}
+	gray := imaging.Grayscale(photo)
+	bounds := gray.Bounds()
+	bandWidth := bounds.Dx() / len(densities)
+	if bandWidth < 5 || bounds.Dy() < 5 {
+		return nil, fmt.Errorf("the photo is too small for %d bands", len(densities))
+	}
+
+	means := make([]float64, len(densities))
+	background, bareBands := 0.0, 0
+	for i, density := range densities {
+		// The center of each band, away from the edges and the neighbour bands
+		left := bounds.Min.X + i*bandWidth + bandWidth/5
+		right := bounds.Min.X + (i+1)*bandWidth - bandWidth/5
+		top := bounds.Min.Y + bounds.Dy()/5
+		bottom := bounds.Max.Y - bounds.Dy()/5
+
+		sum, count := 0.0, 0
+		for y := top; y < bottom; y++ {
+			for x := left; x < right; x++ {
+				sum += float64(gray.NRGBAAt(x, y).R)
+				count++
+			}
+		}
+		means[i] = sum / float64(count)
+		if density == 0 {
+			background += means[i]
+			bareBands++
+		}
+	}
+	if bareBands == 0 {
+		return nil, errors.New("the test piece needs a bare band of density 0 to measure the background")
+	}
+	background /= float64(bareBands)
+	if background == 0 {
+		return nil, errors.New("the bare band of the photo is black")
+	}
+
+	bands := make([]CalibrationBand, len(densities))
+	for i, density := range densities {
+		bands[i] = CalibrationBand{Density: density, Brightness: means[i] / background}
+	}
+	return bands, nil
+}
+
+// FitThreadProfile returns the profile closest to the measured bands, with
+// the root mean square of the brightness errors
+func FitThreadProfile(bands []CalibrationBand) (ThreadProfile, float64, error) {
+	threaded := 0
+	for _, band := range bands {
+		if band.Density < 0 {
+			return ThreadProfile{}, 0, fmt.Errorf("band density %g must not be negative", band.Density)
+		}
+		if band.Density > 0 {
+			threaded++
+		}
+	}
+	if threaded == 0 {
+		return ThreadProfile{}, 0, errors.New("the test piece needs at least one band with threads")
+	}
+
+	rms := func(profile ThreadProfile) float64 {
+		sum := 0.0
+		for _, band := range bands {
+			diff := profile.Brightness(band.Density) - band.Brightness
+			sum += diff * diff
+		}
+		return math.Sqrt(sum / float64(len(bands)))
+	}
+
+	// Coarse grid then refine around the best point, the error is smooth in
+	// opacity and in the log of the width
+	best := ThreadProfile{Opacity: 1, Width: 0.1}
+	bestErr := rms(best)
+	opacityStep, logWidthStep := 0.05, 0.25
+	minLogWidth, maxLogWidth := math.Log(0.005), math.Log(5.0)
+	opacityLow, opacityHigh := 0.0, 1.0
+	logWidthLow, logWidthHigh := minLogWidth, maxLogWidth
+	for round := 0; round < 6; round++ {
+		for opacity := opacityLow; opacity <= opacityHigh+1e-9; opacity += opacityStep {
+			for logWidth := logWidthLow; logWidth <= logWidthHigh+1e-9; logWidth += logWidthStep {
+				candidate := ThreadProfile{Opacity: math.Min(1, math.Max(0.001, opacity)), Width: math.Exp(logWidth)}
+				if err := rms(candidate); err < bestErr {
+					best, bestErr = candidate, err
+				}
+			}
+		}
+		opacityLow, opacityHigh = math.Max(0, best.Opacity-opacityStep), math.Min(1, best.Opacity+opacityStep)
+		logWidthLow = math.Max(minLogWidth, math.Log(best.Width)-logWidthStep)
+		logWidthHigh = math.Min(maxLogWidth, math.Log(best.Width)+logWidthStep)
+		opacityStep /= 4
+		logWidthStep /= 4
+	}
+	return best, bestErr, nil
+}
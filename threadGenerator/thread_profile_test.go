@@ -0,0 +1,57 @@
+package threadGenerator
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testPiecePhoto renders the photo of a test piece strung with the given profile
+func testPiecePhoto(profile ThreadProfile, densities []float64, paper uint8) image.Image {
+	bandWidth := 60
+	photo := image.NewGray(image.Rect(0, 0, bandWidth*len(densities), 80))
+	for i, density := range densities {
+		value := color.Gray{Y: uint8(float64(paper) * profile.Brightness(density))}
+		for y := 0; y < 80; y++ {
+			for x := i * bandWidth; x < (i+1)*bandWidth; x++ {
+				photo.SetGray(x, y, value)
+			}
+		}
+	}
+	return photo
+}
+
+func TestFitThreadProfile(t *testing.T) {
+	want := ThreadProfile{Opacity: 0.85, Width: 0.4}
+	densities := []float64{0, 0.5, 1, 2, 4, 8}
+
+	bands, err := MeasureCalibrationBands(testPiecePhoto(want, densities, 230), densities)
+	require.NoError(t, err)
+	require.InDelta(t, 1, bands[0].Brightness, 1e-9)
+
+	got, rms, err := FitThreadProfile(bands)
+	require.NoError(t, err)
+	require.Less(t, rms, 0.01)
+	require.InDelta(t, want.Opacity, got.Opacity, 0.03)
+	require.InDelta(t, want.Width, got.Width, 0.04)
+
+	_, err = MeasureCalibrationBands(testPiecePhoto(want, densities, 230), []float64{1, 2, 4})
+	require.ErrorContains(t, err, "bare band")
+}
+
+func TestThreadProfileDarkening(t *testing.T) {
+	profile := ThreadProfile{Opacity: 0.9, Width: 0.3}
+	tg := NewThreadGenerator(Config{ImgSize: 100, PhysicalRadius: 100, BrightnessFactor: 50, ThreadProfile: &profile})
+
+	counts := make([]int, 100*100)
+	first := tg.lineDarkening(counts, image.Point{X: 10, Y: 10})
+	second := tg.lineDarkening(counts, image.Point{X: 10, Y: 10})
+	require.Positive(t, second)
+	require.Greater(t, first, second, "each thread adds less darkness to an already covered pixel")
+	require.Equal(t, 2, counts[10*100+10])
+
+	tg.threadProfile = nil
+	require.Equal(t, 50, tg.lineDarkening(counts, image.Point{X: 10, Y: 10}))
+}